@@ -1,11 +1,18 @@
 package main
 
 import (
+	"VoteGolang/conf"
 	"VoteGolang/internals/app"
 	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/infrastructure/metrics"
+	"context"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 )
 
 // @title Online Election Vote
@@ -22,22 +29,79 @@ import (
 // @in header
 // @name Authorization
 func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	flags := conf.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+	config, err := conf.Load(flags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
 
-	kafkaBroker := os.Getenv("KAFKA_BROKER")
-	if kafkaBroker == "" {
-		kafkaBroker = "kafka:9092" // fallback
+	logger, sink := logging.New(config.Logging())
+	defer flushLogs(sink)
+	if sink != nil {
+		metrics.RegisterLogSink(sink)
 	}
+	logConfig(logger, config)
 
-	kafkaLogger := logging.NewKafkaLogger(kafkaBroker, "app-logs")
-	defer kafkaLogger.Close()
+	// SIGTERM (docker stop, Kubernetes) and Ctrl+C start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	appInstance, authUseCase, tokenManager, rdb, esClient, err := app.NewApp(kafkaLogger)
+	switch flag.Arg(0) {
+	case "":
+	case "migrate":
+		return runMigrate(ctx, config, logger, flag.Args()[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n%s\n", flag.Arg(0), migrateUsage)
+		return 2
+	}
+
+	appInstance, authUseCase, tokenManager, rdb, esClient, err := app.NewApp(config, logger)
 	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Failed to initialize application: %v", err))
-		log.Fatalf("Error initializing app: %v", err)
+		logger.Error("Failed to initialize application", logging.Err(err))
+		return 1
 	}
 
 	defer rdb.Close()
+	defer appInstance.Close()
+	defer shutdownTracing(logger, appInstance)
+
+	if err := appInstance.Run(ctx, authUseCase, tokenManager, logger, rdb, esClient); err != nil {
+		logger.Error("Server stopped", logging.Err(err))
+		return 1
+	}
+	return 0
+}
+
+// logConfig logs the configuration with its secrets redacted and warns about
+// development defaults.
+func logConfig(logger *slog.Logger, config *conf.Config) {
+	logger.Info("Configuration loaded", "env", config.Env, "config", config)
+	if dev := config.DevDefaults(); len(dev) > 0 {
+		logger.Warn("Using development defaults, set these before going to production", "settings", dev)
+	}
+}
 
-	appInstance.Run(authUseCase, tokenManager, kafkaLogger, rdb, esClient)
+// flushLogs gives buffered records a few seconds to reach Kafka.
+func flushLogs(sink *logging.KafkaSink) {
+	if sink == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sink.Close(ctx)
+}
+
+// shutdownTracing gives pending spans a few seconds to reach the collector.
+func shutdownTracing(logger *slog.Logger, a *app.App) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := a.ShutdownTracing(ctx); err != nil {
+		logger.Warn("Failed to flush traces", logging.Err(err))
+	}
 }
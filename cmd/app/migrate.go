@@ -0,0 +1,92 @@
+package main
+
+import (
+	"VoteGolang/conf"
+	"VoteGolang/internals/app/connect"
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/app/migrations"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+const migrateUsage = `usage: app [flags] migrate <command>
+
+commands:
+  up              apply every pending migration
+  down [n]        revert the last n migrations (1 by default)
+  status          list migrations and when they were applied
+  to <version>    migrate up or down to version (0 reverts everything)`
+
+// runMigrate runs the migrate subcommand.
+func runMigrate(ctx context.Context, config *conf.Config, logger *slog.Logger, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := connect.ConnectDB(config.DB, logger)
+	if err != nil {
+		return 1
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	migrator, err := migrations.NewMigrator(db, logger)
+	if err != nil {
+		logger.Error("Failed to load migrations", logging.Err(err))
+		return 1
+	}
+
+	switch cmd := args[0]; {
+	case cmd == "up" && len(args) == 1:
+		err = migrator.Up(ctx)
+	case cmd == "down" && len(args) <= 2:
+		steps := 1
+		if len(args) == 2 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				fmt.Fprintf(os.Stderr, "down: %q is not a positive number\n", args[1])
+				return 2
+			}
+		}
+		err = migrator.Down(ctx, steps)
+	case cmd == "to" && len(args) == 2:
+		version, perr := strconv.ParseUint(args[1], 10, 32)
+		if perr != nil {
+			fmt.Fprintf(os.Stderr, "to: %q is not a version\n", args[1])
+			return 2
+		}
+		err = migrator.To(ctx, uint(version))
+	case cmd == "status" && len(args) == 1:
+		err = printStatus(ctx, migrator)
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	if err != nil {
+		logger.Error("Migration failed", "command", args[0], logging.Err(err))
+		return 1
+	}
+	return 0
+}
+
+func printStatus(ctx context.Context, migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range statuses {
+		applied := "pending"
+		if s.AppliedAt != nil {
+			applied = s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+	}
+	return w.Flush()
+}
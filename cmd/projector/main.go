@@ -0,0 +1,133 @@
+// Command projector consumes vote events from Kafka and maintains the derived
+// projections: hourly tallies per region and turnout in MySQL, leaderboards
+// in Redis.
+//
+// Without flags it runs as a member of the consumer group. With -replay it
+// reads the event topics from -from-offset or -from-time up to their current
+// end and exits; add -reset to clear the projections first and rebuild them.
+package main
+
+import (
+	"VoteGolang/conf"
+	"VoteGolang/internals/app/connect"
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/app/migrations"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/events"
+	"VoteGolang/internals/infrastructure/projections"
+	"VoteGolang/internals/infrastructure/repositories"
+	"VoteGolang/internals/usecases/projection_usecase"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	replay := flag.Bool("replay", false, "replay the event topics up to their current end, then exit")
+	reset := flag.Bool("reset", false, "with -replay: clear the projections before replaying")
+	fromOffset := flag.Int64("from-offset", kafka.FirstOffset, "with -replay: offset to start every partition at (-2 for the earliest)")
+	fromTime := flag.String("from-time", "", "with -replay: start at the first event at or after this RFC3339 time")
+	flags := conf.RegisterFlags(flag.CommandLine)
+	flag.Parse()
+
+	var from time.Time
+	if *fromTime != "" {
+		t, err := time.Parse(time.RFC3339, *fromTime)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid -from-time: %v\n", err)
+			return 2
+		}
+		from = t
+	}
+	if *reset && !*replay {
+		fmt.Fprintln(os.Stderr, "-reset can only be used with -replay")
+		return 2
+	}
+
+	config, err := conf.Load(flags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	logger, sink := logging.New(config.Logging())
+	defer flushLogs(sink)
+	logger.Info("Configuration loaded", "env", config.Env, "config", config)
+
+	db, err := connect.ConnectDB(config.DB, logger)
+	if err != nil {
+		return 1
+	}
+	if config.DB.AutoMigrate {
+		if err := migrations.Migrate(context.Background(), db, logger); err != nil {
+			logger.Error("Database migration failed", logging.Err(err))
+			return 1
+		}
+	}
+
+	rdb, err := connect.ConnectRedis(config.Redis, logger)
+	if err != nil {
+		return 1
+	}
+	defer rdb.Close()
+
+	group := config.Events.ProjectorGroup
+	projector := projection_usecase.NewProjectionUseCase(
+		group,
+		repositories.NewProjectionRepository(db),
+		projections.NewRedisLeaderboards(rdb),
+		logger,
+	)
+	consumer := events.NewConsumer(
+		config.Events.KafkaBroker,
+		group,
+		[]string{domain.EventTopic("candidate"), domain.EventTopic("petition")},
+		logger,
+	)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *replay {
+		if *reset {
+			if err := projector.Reset(ctx); err != nil {
+				logger.Error("Failed to reset projections", logging.Err(err))
+				return 1
+			}
+		}
+		n, err := consumer.Replay(ctx, projector.Handle, *fromOffset, from)
+		if err != nil {
+			logger.Error("Replay stopped", "messages", n, logging.Err(err))
+			return 1
+		}
+		logger.Info("Replay finished", "messages", n)
+		return 0
+	}
+
+	if err := consumer.Run(ctx, projector.Handle); err != nil {
+		logger.Error("Projector stopped", logging.Err(err))
+		return 1
+	}
+	logger.Info("Projector shut down")
+	return 0
+}
+
+// flushLogs gives buffered records a few seconds to reach Kafka.
+func flushLogs(sink *logging.KafkaSink) {
+	if sink == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sink.Close(ctx)
+}
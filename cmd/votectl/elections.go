@@ -0,0 +1,149 @@
+package main
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+)
+
+func elections(ctx context.Context, e *env, args []string) error {
+	switch {
+	case len(args) == 1 && args[0] == "list":
+		return listElections(ctx, e)
+	case len(args) == 2 && args[0] == "close":
+		return closeElection(ctx, e, domain.CandidateType(args[1]))
+	default:
+		return errUsage
+	}
+}
+
+func listElections(ctx context.Context, e *env) error {
+	uc, err := e.candidateUseCase(nil)
+	if err != nil {
+		return err
+	}
+	list, err := uc.Elections(ctx)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TYPE\tSTATUS\tCANDIDATES\tVOTES\tOPENS AT\tCLOSES AT")
+	for _, el := range list {
+		status := "closed"
+		if el.Open {
+			status = "open"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n", el.Type, status, el.Candidates, el.Votes, formatTime(el.OpensAt), formatTime(el.ClosesAt))
+	}
+	return w.Flush()
+}
+
+func closeElection(ctx context.Context, e *env, candidateType domain.CandidateType) error {
+	if !domain.IsValidCandidateType(string(candidateType)) {
+		return fmt.Errorf("unknown election %q", candidateType)
+	}
+	uc, err := e.candidateUseCase(nil)
+	if err != nil {
+		return err
+	}
+	closed, err := uc.CloseElection(ctx, candidateType)
+	if err != nil {
+		return err
+	}
+	if closed == 0 {
+		fmt.Printf("Election %s was already closed\n", candidateType)
+		return nil
+	}
+	fmt.Printf("Closed election %s (%d candidates)\n", candidateType, closed)
+	return nil
+}
+
+func exportResults(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("export-results", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	format := fs.String("format", "csv", "csv or json")
+	output := fs.String("o", "", "file to write; stdout when empty")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 1 || (*format != "csv" && *format != "json") {
+		return errUsage
+	}
+	candidateType := domain.CandidateType(fs.Arg(0))
+	if !domain.IsValidCandidateType(string(candidateType)) {
+		return fmt.Errorf("unknown election %q", candidateType)
+	}
+
+	uc, err := e.candidateUseCase(nil)
+	if err != nil {
+		return err
+	}
+	if e.config.Voting.WriteBehind {
+		// Votes still counted in Redis are not in the candidates table yet
+		if err := e.writeBehind(uc); err != nil {
+			return err
+		}
+		flushed, err := flushVotes(ctx, uc)
+		if err != nil {
+			return fmt.Errorf("flush write-behind votes: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Flushed %d write-behind votes\n", flushed)
+	}
+	results, err := uc.Results(ctx, candidateType)
+	if err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return writeResultsCSV(w, results)
+}
+
+func writeResultsCSV(w io.Writer, results []domain.CandidateResult) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"candidate_id", "name", "party", "region", "votes", "share"})
+	for _, r := range results {
+		cw.Write([]string{
+			strconv.FormatUint(uint64(r.CandidateID), 10),
+			r.Name,
+			deref(r.Party),
+			deref(r.Region),
+			strconv.Itoa(r.Votes),
+			strconv.FormatFloat(r.Share, 'f', 4, 64),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
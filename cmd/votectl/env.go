@@ -0,0 +1,205 @@
+package main
+
+import (
+	"VoteGolang/conf"
+	"VoteGolang/internals/app/connect"
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/cache"
+	"VoteGolang/internals/infrastructure/dependency"
+	"VoteGolang/internals/infrastructure/email"
+	"VoteGolang/internals/infrastructure/realtime"
+	"VoteGolang/internals/infrastructure/repositories"
+	"VoteGolang/internals/infrastructure/search"
+	"VoteGolang/internals/service"
+	"VoteGolang/internals/usecases/auth_usecase"
+	"VoteGolang/internals/usecases/candidate_usecase"
+	"VoteGolang/internals/usecases/comment_usecase"
+	"VoteGolang/internals/usecases/petition_usecase"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// searchMappings are the Elasticsearch indices and their mappings.
+var searchMappings = map[string]string{
+	"candidates": search.CandidateMapping,
+	"petitions":  search.PetitionMapping,
+	"comments":   search.CommentMapping,
+}
+
+// env opens the connections a command needs on first use and builds the use
+// cases on top of them.
+type env struct {
+	config *conf.Config
+	logger *slog.Logger
+
+	db       *gorm.DB
+	rdb      *redis.Client
+	redisErr error
+	es       *elasticsearch.Client
+	deps     *dependency.Registry
+	chain    *service.QueuedBlockchain
+}
+
+func (e *env) DB() (*gorm.DB, error) {
+	if e.db == nil {
+		db, err := connect.ConnectDB(e.config.DB, e.logger)
+		if err != nil {
+			return nil, err
+		}
+		e.db = db
+	}
+	return e.db, nil
+}
+
+// Redis returns the client together with the ping error: commands that only
+// keep caches fresh go on without Redis.
+func (e *env) Redis() (*redis.Client, error) {
+	if e.rdb == nil {
+		e.rdb, e.redisErr = connect.ConnectRedis(e.config.Redis, e.logger)
+	}
+	return e.rdb, e.redisErr
+}
+
+func (e *env) Elasticsearch() (*elasticsearch.Client, error) {
+	if e.es == nil {
+		es, err := connect.ConnectElasticsearch(e.config.Elasticsearch.Address, connect.ElasticsearchTransport(e.breakers().Breaker("elasticsearch")))
+		if err != nil {
+			return nil, fmt.Errorf("connect to Elasticsearch: %w", err)
+		}
+		e.es = es
+	}
+	return e.es, nil
+}
+
+// Search returns the repository of an index, which creates the index on
+// its first write when it does not exist yet.
+func (e *env) Search(index string) (*repositories.SearchRepository, error) {
+	if _, err := e.Elasticsearch(); err != nil {
+		return nil, err
+	}
+	return repositories.NewSearchRepository(e.es, index, searchMappings[index]), nil
+}
+
+func (e *env) breakers() *dependency.Registry {
+	if e.deps == nil {
+		e.deps = dependency.NewRegistry(
+			int(e.config.Deps.FailureThreshold),
+			time.Duration(e.config.Deps.CooldownSeconds)*time.Second,
+			e.logger,
+		)
+	}
+	return e.deps
+}
+
+func (e *env) close() {
+	if e.rdb != nil {
+		e.rdb.Close()
+	}
+	if e.db != nil {
+		if sqlDB, err := e.db.DB(); err == nil {
+			sqlDB.Close()
+		}
+	}
+}
+
+func (e *env) authUseCase() (*auth_usecase.AuthUseCase, error) {
+	db, err := e.DB()
+	if err != nil {
+		return nil, err
+	}
+	rdb, _ := e.Redis()
+	return auth_usecase.NewAuthUseCase(
+		repositories.NewUserRepository(db),
+		repositories.NewRoleRepository(db),
+		domain.NewJwtToken(e.config.Auth.JWTSecret),
+		email.NewRedisEmailVerifier(rdb, e.config.SMTP, e.config.BaseURL, e.config.Auth.VerificationTTL()),
+		e.config.Auth.AccessTokenTTL(),
+		e.config.Auth.RefreshTokenTTL(),
+		e.logger,
+	), nil
+}
+
+// Cache returns the Redis-backed cache; like Redis, it is usable even when the
+// ping failed, with every call then returning the connection error.
+func (e *env) Cache() domain.Cache {
+	rdb, _ := e.Redis()
+	return cache.NewRedis(rdb)
+}
+
+// candidateUseCase builds the candidate use case; indexer may be nil for
+// commands that do not index.
+func (e *env) candidateUseCase(indexer domain.SearchIndexer) (*candidate_usecase.CandidateUseCase, error) {
+	db, err := e.DB()
+	if err != nil {
+		return nil, err
+	}
+	rdb, err := e.Redis()
+	if err != nil {
+		e.logger.Warn("Redis is unavailable, caches and live results are not updated", logging.Err(err))
+	}
+	return candidate_usecase.NewCandidateUseCase(
+		repositories.NewCandidateRepository(db),
+		repositories.NewVoteRepository(db),
+		e.blockchain(db),
+		cache.NewRedis(rdb),
+		indexer,
+		repositories.NewAssetRepository(db),
+		repositories.NewPartyRepository(db),
+		realtime.NewRedisTallyBus(rdb),
+		e.logger,
+	), nil
+}
+
+func (e *env) petitionUseCase(indexer domain.SearchIndexer) (petition_usecase.PetitionUseCase, error) {
+	db, err := e.DB()
+	if err != nil {
+		return nil, err
+	}
+	rdb, _ := e.Redis()
+	return petition_usecase.NewPetitionUseCase(
+		repositories.NewPetitionRepository(db),
+		repositories.NewPetitionVoteRepository(db),
+		e.blockchain(db),
+		cache.NewRedis(rdb),
+		e.logger,
+		indexer,
+		repositories.NewPetitionModerationRepository(db),
+		repositories.NewAssetRepository(db),
+		realtime.NewRedisTallyBus(rdb),
+	), nil
+}
+
+func (e *env) commentUseCase(indexer domain.SearchIndexer) (comment_usecase.CommentUseCase, error) {
+	db, err := e.DB()
+	if err != nil {
+		return nil, err
+	}
+	return comment_usecase.NewCommentUseCase(
+		repositories.NewPetitionCommentRepository(db),
+		repositories.NewPetitionRepository(db),
+		e.Cache(),
+		indexer,
+		e.logger,
+	), nil
+}
+
+// blockchain wraps the node client in the persistent queue, as the API does.
+// Without a node the queue is still there but flushing it fails.
+func (e *env) blockchain(db *gorm.DB) *service.QueuedBlockchain {
+	if e.chain == nil {
+		e.chain = service.NewQueuedBlockchain(
+			func() (service.BlockchainService, error) { return service.NewBnbService(e.config.BNB) },
+			repositories.NewChainLogQueue(db),
+			e.breakers().Breaker("blockchain"),
+			time.Duration(e.config.Deps.ChainRetrySeconds)*time.Second,
+			e.logger,
+		)
+	}
+	return e.chain
+}
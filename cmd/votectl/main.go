@@ -0,0 +1,138 @@
+// Command votectl runs administrative tasks against the service's database,
+// Redis, Elasticsearch and blockchain, through the same repositories and use
+// cases as the API. It reads the same configuration (see conf.Load).
+//
+//	votectl [flags] <command> [arguments]
+//
+// Run votectl without a command to list them. Logs go to stderr, so command
+// output on stdout can be piped.
+package main
+
+import (
+	"VoteGolang/conf"
+	"VoteGolang/internals/app/logging"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+)
+
+type command struct {
+	name string
+	args string
+	help string
+	run  func(ctx context.Context, e *env, args []string) error
+}
+
+var commands = []command{
+	{"create-admin", "-email <email> [-username admin] [-password <password>]", "create an administrator with a verified email", createAdmin},
+	{"assign-role", "<username> <role>", "give a user another role", assignRole},
+	{"elections", "list | close <type>", "list elections, or end voting in one now", elections},
+	{"resend-verification", "<email>", "send a new verification link to an unverified user", resendVerification},
+	{"reindex", "[-recreate] [candidates|petitions|comments|all]", "index documents for search again (all by default), with -recreate in a new index with the current mapping", reindex},
+	{"flush-cache", "<candidates|petitions|ratelimit|all>...", "delete cached entries of the given namespaces", flushCache},
+	{"replay-chain", "", "send the queued blockchain logs now", replayChain},
+	{"votes", "flush | recount", "add write-behind vote counts to the candidates now, or rebuild them from the votes table", votes},
+	{"export-results", "[-format csv|json] [-o <file>] <type>", "write the results of an election, flushing write-behind votes first", exportResults},
+}
+
+// errUsage is returned by a command called with wrong arguments.
+var errUsage = errors.New("wrong arguments")
+
+func main() {
+	os.Exit(run())
+}
+
+func run() int {
+	flags := conf.RegisterFlags(flag.CommandLine)
+	flag.Usage = printUsage
+	flag.Parse()
+
+	cmd := findCommand(flag.Arg(0))
+	if cmd == nil {
+		printUsage()
+		return 2
+	}
+
+	config, err := conf.Load(flags)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+	logConfig := config.Logging()
+	logConfig.Output = os.Stderr
+	logger, sink := logging.New(logConfig)
+	defer flushLogs(sink)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	e := &env{config: config, logger: logger}
+	defer e.close()
+
+	logger.InfoContext(ctx, "Running admin command", "command", cmd.name, "args", redactArgs(flag.Args()[1:]))
+	err = cmd.run(ctx, e, flag.Args()[1:])
+	switch {
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "usage: votectl [flags] %s %s\n", cmd.name, cmd.args)
+		return 2
+	case err != nil:
+		logger.ErrorContext(ctx, "Command failed", "command", cmd.name, logging.Err(err))
+		fmt.Fprintf(os.Stderr, "%s: %v\n", cmd.name, err)
+		return 1
+	}
+	return 0
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func printUsage() {
+	fmt.Fprint(os.Stderr, "usage: votectl [flags] <command> [arguments]\n\ncommands:\n")
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %s %s\t%s\n", c.name, c.args, c.help)
+	}
+	w.Flush()
+	fmt.Fprint(os.Stderr, "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+// redactArgs hides the value of a -password argument before it is logged.
+func redactArgs(args []string) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		switch {
+		case i > 0 && out[i-1] == "-password", i > 0 && out[i-1] == "--password":
+			out[i] = "[REDACTED]"
+		case strings.HasPrefix(a, "-") && name == "password" && hasValue:
+			out[i] = a[:strings.IndexByte(a, '=')+1] + "[REDACTED]"
+		default:
+			out[i] = a
+		}
+	}
+	return out
+}
+
+// flushLogs gives buffered records a few seconds to reach Kafka.
+func flushLogs(sink *logging.KafkaSink) {
+	if sink == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	sink.Close(ctx)
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"VoteGolang/internals/infrastructure/repositories"
+	"VoteGolang/internals/infrastructure/search"
+	"VoteGolang/internals/infrastructure/votecount"
+	"VoteGolang/internals/usecases/candidate_usecase"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// reindex indexes documents again. An existing index keeps its mapping
+// unless -recreate deletes it first; search finds only the documents indexed
+// so far until the command finishes.
+func reindex(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("reindex", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	recreate := fs.Bool("recreate", false, "delete each index and create it with the current mapping first")
+	if err := fs.Parse(args); err != nil || fs.NArg() > 1 {
+		return errUsage
+	}
+	target := "all"
+	if fs.NArg() == 1 {
+		target = fs.Arg(0)
+	}
+	if !slices.Contains([]string{"candidates", "petitions", "comments", "all"}, target) {
+		return errUsage
+	}
+
+	reindexers := []struct {
+		index string
+		run   func() (int, error)
+	}{
+		{"candidates", func() (int, error) {
+			searchRepo, err := e.Search("candidates")
+			if err != nil {
+				return 0, err
+			}
+			uc, err := e.candidateUseCase(searchRepo)
+			if err != nil {
+				return 0, err
+			}
+			return uc.Reindex(ctx)
+		}},
+		{"petitions", func() (int, error) {
+			searchRepo, err := e.Search("petitions")
+			if err != nil {
+				return 0, err
+			}
+			uc, err := e.petitionUseCase(searchRepo)
+			if err != nil {
+				return 0, err
+			}
+			return uc.Reindex(ctx)
+		}},
+		{"comments", func() (int, error) {
+			searchRepo, err := e.Search("comments")
+			if err != nil {
+				return 0, err
+			}
+			uc, err := e.commentUseCase(searchRepo)
+			if err != nil {
+				return 0, err
+			}
+			return uc.Reindex(ctx)
+		}},
+	}
+	for _, r := range reindexers {
+		if target != "all" && target != r.index {
+			continue
+		}
+		if *recreate {
+			es, err := e.Elasticsearch()
+			if err != nil {
+				return err
+			}
+			if err := search.RecreateIndex(es, r.index, searchMappings[r.index]); err != nil {
+				return fmt.Errorf("recreate index %s: %w", r.index, err)
+			}
+			fmt.Printf("Recreated index %s\n", r.index)
+		}
+		n, err := r.run()
+		if err != nil {
+			return fmt.Errorf("%s: %w (%d indexed)", r.index, err, n)
+		}
+		fmt.Printf("Indexed %d %s\n", n, r.index)
+	}
+	return nil
+}
+
+// cacheNamespaces are the key prefixes of each cache.
+var cacheNamespaces = map[string][]string{
+	"candidates": {"candidates:", "candidate:"},
+	"petitions":  {"petitions", "petition:"},
+	"ratelimit":  {"ratelimit:"},
+}
+
+func flushCache(ctx context.Context, e *env, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	var namespaces []string
+	for _, ns := range args {
+		switch _, ok := cacheNamespaces[ns]; {
+		case ns == "all":
+			namespaces = append(namespaces, "candidates", "petitions", "ratelimit")
+		case ok:
+			namespaces = append(namespaces, ns)
+		default:
+			return fmt.Errorf("unknown cache namespace %q", ns)
+		}
+	}
+
+	if _, err := e.Redis(); err != nil {
+		return fmt.Errorf("connect to Redis: %w", err)
+	}
+	store := e.Cache()
+	for _, ns := range namespaces {
+		deleted := 0
+		for _, prefix := range cacheNamespaces[ns] {
+			n, err := store.DeletePrefix(ctx, prefix)
+			deleted += n
+			if err != nil {
+				return fmt.Errorf("delete %s*: %w", prefix, err)
+			}
+		}
+		e.logger.InfoContext(ctx, "Cache flushed", "namespace", ns, "keys", deleted)
+		fmt.Printf("Flushed %d %s keys\n", deleted, ns)
+	}
+	return nil
+}
+
+func replayChain(ctx context.Context, e *env, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	db, err := e.DB()
+	if err != nil {
+		return err
+	}
+	sent, err := e.blockchain(db).Flush(ctx)
+	fmt.Printf("Sent %d queued blockchain logs\n", sent)
+	return err
+}
+
+// votes drains or rebuilds the write-behind vote counts.
+func votes(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 || (args[0] != "flush" && args[0] != "recount") {
+		return errUsage
+	}
+	uc, err := e.candidateUseCase(nil)
+	if err != nil {
+		return err
+	}
+	if err := e.writeBehind(uc); err != nil {
+		return err
+	}
+
+	if args[0] == "recount" {
+		corrected, err := uc.RecountVotes(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Corrected the count of %d candidates\n", corrected)
+		return nil
+	}
+
+	flushed, err := flushVotes(ctx, uc)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Flushed %d votes\n", flushed)
+	return nil
+}
+
+// writeBehind makes uc count votes the way the API does in write-behind
+// mode, in Redis.
+func (e *env) writeBehind(uc *candidate_usecase.CandidateUseCase) error {
+	rdb, err := e.Redis()
+	if err != nil {
+		return fmt.Errorf("connect to Redis: %w", err)
+	}
+	uc.Counter = votecount.NewRedis(rdb)
+	uc.VoteCounts = repositories.NewVoteCountRepository(e.db)
+	return nil
+}
+
+// flushVotes adds the write-behind vote counts to the candidates. A batch
+// left by a failed flush is applied before new votes are claimed, so it
+// flushes twice.
+func flushVotes(ctx context.Context, uc *candidate_usecase.CandidateUseCase) (int64, error) {
+	var flushed int64
+	for i := 0; i < 2; i++ {
+		n, err := uc.FlushVotes(ctx)
+		flushed += n
+		if err != nil {
+			return flushed, fmt.Errorf("%w (%d votes flushed)", err, flushed)
+		}
+	}
+	return flushed, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"VoteGolang/internals/domain"
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// adminRole is the role seeded with every access.
+const adminRole = "admin"
+
+func createAdmin(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	username := fs.String("username", "admin", "login of the administrator")
+	mail := fs.String("email", "", "email of the administrator")
+	password := fs.String("password", "", "password; read from the first line of stdin when empty")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 || *mail == "" {
+		return errUsage
+	}
+
+	// Reading the password from stdin keeps it out of the shell history
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	auth, err := e.authUseCase()
+	if err != nil {
+		return err
+	}
+	user := &domain.User{Username: *username, Email: *mail, Password: *password}
+	if err := auth.CreateUser(ctx, user, adminRole); err != nil {
+		return err
+	}
+	fmt.Printf("Created administrator %s (id %d)\n", user.Username, user.ID)
+	return nil
+}
+
+func assignRole(ctx context.Context, e *env, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	auth, err := e.authUseCase()
+	if err != nil {
+		return err
+	}
+	if err := auth.AssignRole(ctx, args[0], args[1]); err != nil {
+		return err
+	}
+	fmt.Printf("%s now has the role %s\n", args[0], args[1])
+	return nil
+}
+
+func resendVerification(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	// The verification token is kept in Redis
+	if _, err := e.Redis(); err != nil {
+		return fmt.Errorf("connect to Redis: %w", err)
+	}
+	auth, err := e.authUseCase()
+	if err != nil {
+		return err
+	}
+	link, err := auth.ResendVerification(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if e.config.SMTP.Host == "" {
+		fmt.Printf("SMTP is not configured, send this link by hand: %s\n", link)
+		return nil
+	}
+	fmt.Printf("Verification email sent to %s\n", args[0])
+	return nil
+}
@@ -1,90 +1,240 @@
+// Package conf holds the service configuration. Every setting lives in one
+// typed struct and is read, in increasing order of precedence, from its
+// default, a YAML file, the environment (and a .env file) and command-line
+// flags. See Load.
 package conf
 
 import (
 	"VoteGolang/internals/app/logging"
-	"fmt"
-	"log"
-	"os"
-	"strconv"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Environments. Production refuses the development defaults of secrets.
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
 
-	"github.com/joho/godotenv"
+// Each setting is tagged with its YAML key, its environment variable and
+// its default. Flags are named after the YAML path (-db.host). Settings
+// tagged secret are redacted when the configuration is logged; a dev value
+// is only used outside production, when nothing else sets the setting.
+
+// Database drivers.
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
 )
 
+// DBConfig selects the database. Port defaults to the standard port of the
+// driver. SQLite only uses Name, the path of the database file (":memory:"
+// for a throwaway one).
+type DBConfig struct {
+	Driver string `yaml:"driver" env:"DB_DRIVER" default:"mysql"`
+	Host   string `yaml:"host" env:"DB_HOST" default:"localhost"`
+	Port   string `yaml:"port" env:"DB_PORT"`
+	User   string `yaml:"user" env:"DB_USER" default:"root"`
+	Pass   string `yaml:"pass" env:"DB_PASS" dev:"$F00tba11!" secret:"true"`
+	Name   string `yaml:"name" env:"DB_NAME" default:"vote_database"`
+	// SSLMode is the PostgreSQL sslmode.
+	SSLMode string `yaml:"sslmode" env:"DB_SSLMODE" default:"disable"`
+	// AutoMigrate applies pending migrations at startup; without it they
+	// are applied with the migrate command.
+	AutoMigrate bool `yaml:"auto_migrate" env:"DB_AUTO_MIGRATE" default:"true"`
+}
+
+type RedisConfig struct {
+	Host     string `yaml:"host" env:"REDIS_HOST" default:"redis"`
+	Port     string `yaml:"port" env:"REDIS_PORT" default:"6379"`
+	Password string `yaml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB       int64  `yaml:"db" env:"REDIS_DB" default:"0"`
+}
+
+// ElasticsearchConfig holds the cluster URL; the default matches the
+// docker-compose service name.
+type ElasticsearchConfig struct {
+	Address string `yaml:"address" env:"ELASTICSEARCH_URL" default:"http://elasticsearch:9200"`
+}
+
+// SMTPConfig is the mail server of verification emails. Without Host and
+// Port no mail is sent and the verification link is only logged.
+type SMTPConfig struct {
+	Host               string `yaml:"host" env:"SMTP_HOST"`
+	Port               string `yaml:"port" env:"SMTP_PORT"`
+	Mail               string `yaml:"mail" env:"SMTP_MAIL"`
+	Password           string `yaml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify" env:"SMTP_INSECURE_SKIP_VERIFY" default:"false"`
+}
+
+// AuthConfig holds the JWT signing secret and the lifetimes of the access,
+// refresh and email verification tokens.
+type AuthConfig struct {
+	JWTSecret              string `yaml:"jwt_secret" env:"JWT_SECRET" dev:"defaultsecret" secret:"true"`
+	AccessTokenTTLMinutes  int64  `yaml:"access_token_ttl_minutes" env:"ACCESS_TOKEN_TTL_MINUTES" default:"15"`
+	RefreshTokenTTLHours   int64  `yaml:"refresh_token_ttl_hours" env:"REFRESH_TOKEN_TTL_HOURS" default:"24"`
+	VerificationTTLMinutes int64  `yaml:"verification_ttl_minutes" env:"EMAIL_VERIFICATION_TTL_MINUTES" default:"5"`
+}
+
+func (c *AuthConfig) AccessTokenTTL() time.Duration {
+	return time.Duration(c.AccessTokenTTLMinutes) * time.Minute
+}
+
+func (c *AuthConfig) RefreshTokenTTL() time.Duration {
+	return time.Duration(c.RefreshTokenTTLHours) * time.Hour
+}
+
+func (c *AuthConfig) VerificationTTL() time.Duration {
+	return time.Duration(c.VerificationTTLMinutes) * time.Minute
+}
+
+// LogConfig selects log levels and format. Records at KafkaLevel and above
+// (Level when empty) also go to Topic on the events broker.
+type LogConfig struct {
+	Level      string `yaml:"level" env:"LOG_LEVEL" default:"info"`
+	KafkaLevel string `yaml:"kafka_level" env:"LOG_KAFKA_LEVEL"`
+	Format     string `yaml:"format" env:"LOG_FORMAT" default:"json"`
+	Topic      string `yaml:"topic" env:"LOG_TOPIC" default:"app-logs"`
+}
+
 type BnbConfig struct {
-	NodeURL         string
-	PrivateKey      string
-	ContractAddress string
-	ChainID         int64
+	NodeURL         string `yaml:"node_url" env:"BNB_NODE_URL"`
+	PrivateKey      string `yaml:"private_key" env:"BNB_PRIVATE_KEY" secret:"true"`
+	ContractAddress string `yaml:"contract_address" env:"BNB_CONTRACT_ADDRESS"`
+	ChainID         int64  `yaml:"chain_id" env:"BNB_CHAIN" default:"97"`
+}
+
+// MediaConfig selects where uploaded images are stored. Backend is "local"
+// (files under Dir, served by the API itself) or "s3" (any S3-compatible store).
+type MediaConfig struct {
+	Backend string `yaml:"backend" env:"MEDIA_BACKEND" default:"local"`
+	Dir     string `yaml:"dir" env:"MEDIA_DIR" default:"./uploads"`
+	BaseURL string `yaml:"base_url" env:"MEDIA_BASE_URL" default:"http://localhost:8080"`
+	// SigningKey signs local media URLs. Development falls back to the JWT
+	// secret; production requires its own key.
+	SigningKey     string `yaml:"signing_key" env:"MEDIA_SIGNING_KEY" secret:"true"`
+	MaxUploadBytes int64  `yaml:"max_upload_bytes" env:"MEDIA_MAX_UPLOAD_BYTES" default:"5242880"`
+	URLTTLSeconds  int64  `yaml:"url_ttl_seconds" env:"MEDIA_URL_TTL_SECONDS" default:"3600"`
+	S3Endpoint     string `yaml:"s3_endpoint" env:"MEDIA_S3_ENDPOINT"`
+	S3Region       string `yaml:"s3_region" env:"MEDIA_S3_REGION"`
+	S3Bucket       string `yaml:"s3_bucket" env:"MEDIA_S3_BUCKET"`
+	S3AccessKey    string `yaml:"s3_access_key" env:"MEDIA_S3_ACCESS_KEY" secret:"true"`
+	S3SecretKey    string `yaml:"s3_secret_key" env:"MEDIA_S3_SECRET_KEY" secret:"true"`
+}
+
+// RealtimeConfig controls live result streaming. HiddenResultTypes lists
+// election types whose results stay secret until voting closes.
+type RealtimeConfig struct {
+	HiddenResultTypes []string `yaml:"hidden_result_types" env:"RESULTS_HIDDEN_TYPES"`
+	ThrottleMillis    int64    `yaml:"throttle_ms" env:"STREAM_THROTTLE_MS" default:"1000"`
+}
+
+// VotingConfig controls how candidate votes are counted. With WriteBehind,
+// votes are counted in Redis and added to the candidates every
+// FlushIntervalMillis instead of updating the candidate row with each vote;
+// vote records are still stored right away.
+type VotingConfig struct {
+	WriteBehind         bool  `yaml:"write_behind" env:"VOTES_WRITE_BEHIND" default:"false"`
+	FlushIntervalMillis int64 `yaml:"flush_interval_ms" env:"VOTES_FLUSH_INTERVAL_MS" default:"1000"`
+}
+
+// EventsConfig controls the relay that publishes business events from the
+// outbox table to Kafka. The relay gives up on a message after Kafka rejected
+// it MaxAttempts times.
+type EventsConfig struct {
+	KafkaBroker         string `yaml:"kafka_broker" env:"KAFKA_BROKER" default:"kafka:9092"`
+	RelayIntervalMillis int64  `yaml:"relay_interval_ms" env:"EVENTS_RELAY_INTERVAL_MS" default:"1000"`
+	BatchSize           int64  `yaml:"batch_size" env:"EVENTS_RELAY_BATCH_SIZE" default:"100"`
+	MaxAttempts         int64  `yaml:"max_attempts" env:"EVENTS_RELAY_MAX_ATTEMPTS" default:"10"`
+	RetentionHours      int64  `yaml:"retention_hours" env:"EVENTS_RETENTION_HOURS" default:"168"`
+	// ProjectorGroup is the Kafka consumer group of cmd/projector.
+	ProjectorGroup string `yaml:"projector_group" env:"PROJECTOR_GROUP_ID" default:"vote-projector"`
+}
+
+// TracingConfig controls OpenTelemetry tracing. Spans are exported over
+// OTLP/HTTP to Endpoint, the URL of a collector (http://otel-collector:4318);
+// tracing is off when it is empty. SamplePercent of new traces are recorded;
+// requests that arrive with a sampled trace are always recorded.
+type TracingConfig struct {
+	Endpoint      string `yaml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	SamplePercent int64  `yaml:"sample_percent" env:"TRACING_SAMPLE_PERCENT" default:"100"`
+}
+
+// ServerConfig holds the HTTP server address and timeouts. On shutdown,
+// in-flight requests get ShutdownTimeoutSeconds to finish. CORSOrigins are
+// the browser origins allowed to call the API and open result streams.
+type ServerConfig struct {
+	Addr                   string   `yaml:"addr" env:"HTTP_ADDR" default:"0.0.0.0:8080"`
+	ReadTimeoutSeconds     int64    `yaml:"read_timeout_seconds" env:"HTTP_READ_TIMEOUT_SECONDS" default:"30"`
+	WriteTimeoutSeconds    int64    `yaml:"write_timeout_seconds" env:"HTTP_WRITE_TIMEOUT_SECONDS" default:"60"`
+	IdleTimeoutSeconds     int64    `yaml:"idle_timeout_seconds" env:"HTTP_IDLE_TIMEOUT_SECONDS" default:"120"`
+	ShutdownTimeoutSeconds int64    `yaml:"shutdown_timeout_seconds" env:"SHUTDOWN_TIMEOUT_SECONDS" default:"20"`
+	CORSOrigins            []string `yaml:"cors_origins" env:"CORS_ALLOWED_ORIGINS" default:"http://localhost:3000,https://dayus.vercel.app"`
+}
+
+// DependencyConfig tunes the circuit breakers of the optional dependencies
+// (Redis, Elasticsearch, Kafka, blockchain). A breaker opens after
+// FailureThreshold consecutive failures and tries again after
+// CooldownSeconds. Queued blockchain logs are replayed every
+// ChainRetrySeconds.
+type DependencyConfig struct {
+	FailureThreshold  int64 `yaml:"failure_threshold" env:"DEPENDENCY_FAILURE_THRESHOLD" default:"5"`
+	CooldownSeconds   int64 `yaml:"cooldown_seconds" env:"DEPENDENCY_COOLDOWN_SECONDS" default:"30"`
+	ChainRetrySeconds int64 `yaml:"chain_retry_seconds" env:"CHAIN_RETRY_SECONDS" default:"60"`
 }
 
 type Config struct {
-	JWTSecret string
-	DBHost    string
-	DBPort    string
-	DBUser    string
-	DBPass    string
-	DBName    string
-	BNB       *BnbConfig // Added
-}
-
-func LoadConfig(kafkaLogger *logging.KafkaLogger) *Config {
-	// Загружаем .env файл, если он есть
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using system environment variables")
-		kafkaLogger.Log("WARN", "No .env file found, using system environment variables")
-	} else {
-		log.Println(".env file FOUND!")
-		kafkaLogger.Log("INFO", ".env file successfully loaded")
-	}
+	// Env is "development" or "production". It has no default: development
+	// fills in secrets, so it must not be what a forgotten APP_ENV gets.
+	Env string `yaml:"env" env:"APP_ENV"`
+	// BaseURL is the public URL of the API, used in verification links.
+	BaseURL string `yaml:"base_url" env:"APP_BASE_URL" default:"http://localhost:8080"`
 
-	cfg := &Config{
-		JWTSecret: getEnv("JWT_SECRET", "defaultsecret", kafkaLogger),
-		DBHost:    getEnv("DB_HOST", "localhost", kafkaLogger),
-		DBPort:    getEnv("DB_PORT", "3306", kafkaLogger),
-		DBUser:    getEnv("DB_USER", "root", kafkaLogger),
-		DBPass:    getEnv("DB_PASS", "$F00tba11!", kafkaLogger),
-		DBName:    getEnv("DB_NAME", "vote_database", kafkaLogger),
-		BNB: &BnbConfig{
-			NodeURL:         os.Getenv("BNB_NODE_URL"),
-			PrivateKey:      os.Getenv("BNB_PRIVATE_KEY"),
-			ContractAddress: os.Getenv("BNB_CONTRACT_ADDRESS"),
-			ChainID:         getEnvAsInt64("BNB_CHAIN", 97, kafkaLogger),
-		},
-	}
+	DB            *DBConfig            `yaml:"db"`
+	Redis         *RedisConfig         `yaml:"redis"`
+	Elasticsearch *ElasticsearchConfig `yaml:"elasticsearch"`
+	SMTP          *SMTPConfig          `yaml:"smtp"`
+	Auth          *AuthConfig          `yaml:"auth"`
+	Log           *LogConfig           `yaml:"log"`
+	BNB           *BnbConfig           `yaml:"bnb"`
+	Media         *MediaConfig         `yaml:"media"`
+	Realtime      *RealtimeConfig      `yaml:"realtime"`
+	Voting        *VotingConfig        `yaml:"voting"`
+	Events        *EventsConfig        `yaml:"events"`
+	Tracing       *TracingConfig       `yaml:"tracing"`
+	Server        *ServerConfig        `yaml:"server"`
+	Deps          *DependencyConfig    `yaml:"dependencies"`
 
-	kafkaLogger.Log("INFO", fmt.Sprintf("Configuration loaded successfully for DB %s:%s", cfg.DBHost, cfg.DBPort))
-	return cfg
+	// devDefaults lists the settings that fell back to a dev value.
+	devDefaults []string
 }
 
-func getEnv(key, fallback string, kafkaLogger *logging.KafkaLogger) string {
-	value, exists := os.LookupEnv(key)
-	if !exists {
-		msg := fmt.Sprintf("Environment variable %s not set, using default value", key)
-		log.Println(msg)
-		kafkaLogger.Log("WARN", msg)
-		return fallback
-	}
-	kafkaLogger.Log("DEBUG", fmt.Sprintf("Environment variable %s loaded", key))
-	return value
+// Production reports whether the service runs in production mode.
+func (c *Config) Production() bool {
+	return c.Env == EnvProduction
 }
 
-func getEnvAsInt64(key string, fallback int64, kafkaLogger *logging.KafkaLogger) int64 {
-	valueStr, exists := os.LookupEnv(key)
-	if !exists {
-		msg := fmt.Sprintf("Environment variable %s not set, using default value %d", key, fallback)
-		log.Println(msg)
-		kafkaLogger.Log("WARN", msg)
-		return fallback
-	}
+// DevDefaults lists the settings that use a development-only value.
+func (c *Config) DevDefaults() []string {
+	return c.devDefaults
+}
 
-	value, err := strconv.ParseInt(valueStr, 10, 64)
-	if err != nil {
-		msg := fmt.Sprintf("Warning: Invalid format for %s (expected integer, got '%s'), using default value %d. Error: %v", key, valueStr, fallback, err)
-		log.Println(msg)
-		kafkaLogger.Log("WARN", msg)
-		return fallback
+// Logging returns the logger settings. The Kafka sink uses the events broker.
+func (c *Config) Logging() logging.Config {
+	var level slog.Level
+	_ = level.UnmarshalText([]byte(c.Log.Level))
+	kafkaLevel := level
+	if c.Log.KafkaLevel != "" {
+		_ = kafkaLevel.UnmarshalText([]byte(c.Log.KafkaLevel))
+	}
+	return logging.Config{
+		Level:      level,
+		KafkaLevel: kafkaLevel,
+		Format:     strings.ToLower(c.Log.Format),
+		Broker:     c.Events.KafkaBroker,
+		Topic:      c.Log.Topic,
+		Buffer:     1000,
 	}
-	kafkaLogger.Log("DEBUG", fmt.Sprintf("Environment variable %s loaded", key))
-
-	return value
 }
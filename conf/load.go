@@ -0,0 +1,220 @@
+package conf
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFileEnv names the YAML file to read when -config is not given.
+const ConfigFileEnv = "CONFIG_FILE"
+
+// setting is one leaf of Config with its tags.
+type setting struct {
+	path   string // YAML path, also the flag name: "db.host"
+	env    string
+	def    string
+	dev    string
+	secret bool
+	value  reflect.Value
+}
+
+// settings walks cfg, allocating its sections.
+func settings(cfg *Config) []setting {
+	var out []setting
+	walk(reflect.ValueOf(cfg).Elem(), "", &out)
+	return out
+}
+
+func walk(v reflect.Value, prefix string, out *[]setting) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key, ok := f.Tag.Lookup("yaml")
+		if !ok || !f.IsExported() {
+			continue
+		}
+		fv := v.Field(i)
+		if f.Type.Kind() == reflect.Pointer && f.Type.Elem().Kind() == reflect.Struct {
+			if fv.IsNil() {
+				fv.Set(reflect.New(f.Type.Elem()))
+			}
+			walk(fv.Elem(), prefix+key+".", out)
+			continue
+		}
+		*out = append(*out, setting{
+			path:   prefix + key,
+			env:    f.Tag.Get("env"),
+			def:    f.Tag.Get("default"),
+			dev:    f.Tag.Get("dev"),
+			secret: f.Tag.Get("secret") == "true",
+			value:  fv,
+		})
+	}
+}
+
+// set parses raw into the setting. Lists are comma-separated.
+func (s setting) set(raw string) error {
+	switch s.value.Kind() {
+	case reflect.String:
+		s.value.SetString(raw)
+	case reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		s.value.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		s.value.SetBool(b)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		s.value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", s.value.Type())
+	}
+	return nil
+}
+
+// name identifies the setting in errors: its YAML path and variable.
+func (s setting) name() string {
+	if s.env == "" {
+		return s.path
+	}
+	return s.path + " (" + s.env + ")"
+}
+
+// Flags are the command-line flags of the configuration: -config and one
+// flag per setting.
+type Flags struct {
+	fs   *flag.FlagSet
+	file *string
+}
+
+// RegisterFlags defines the configuration flags on fs. Pass the result to
+// Load after fs has been parsed.
+func RegisterFlags(fs *flag.FlagSet) *Flags {
+	f := &Flags{
+		fs:   fs,
+		file: fs.String("config", "", "YAML configuration file (or $"+ConfigFileEnv+")"),
+	}
+	for _, s := range settings(&Config{}) {
+		usage := "overrides $" + s.env
+		if s.env == "" {
+			usage = "overrides the config file"
+		}
+		fs.String(s.path, "", usage)
+	}
+	return f
+}
+
+// Load reads the configuration from the defaults, the YAML file given by
+// -config or $CONFIG_FILE, the environment and .env, and the flags, each
+// source overriding the previous ones, then validates it. flags may be nil.
+func Load(flags *Flags) (*Config, error) {
+	// Variables already set take precedence over .env
+	_ = godotenv.Load()
+
+	cfg := &Config{}
+	all := settings(cfg)
+	var errs []error
+	for _, s := range all {
+		if s.def == "" {
+			continue
+		}
+		if err := s.set(s.def); err != nil {
+			return nil, fmt.Errorf("default of %s: %w", s.path, err)
+		}
+	}
+
+	file := os.Getenv(ConfigFileEnv)
+	if flags != nil && *flags.file != "" {
+		file = *flags.file
+	}
+	if file != "" {
+		if err := loadFile(cfg, file); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range all {
+		if s.env == "" {
+			continue
+		}
+		if raw, ok := os.LookupEnv(s.env); ok {
+			if err := s.set(raw); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", s.name(), err))
+			}
+		}
+	}
+
+	if flags != nil {
+		byPath := make(map[string]setting, len(all))
+		for _, s := range all {
+			byPath[s.path] = s
+		}
+		flags.fs.Visit(func(f *flag.Flag) {
+			s, ok := byPath[f.Name]
+			if !ok {
+				return
+			}
+			if err := s.set(f.Value.String()); err != nil {
+				errs = append(errs, fmt.Errorf("-%s: %w", s.path, err))
+			}
+		})
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+
+	// Development keeps working without secrets; production must set them
+	if cfg.Env == EnvDevelopment {
+		for _, s := range all {
+			if s.dev != "" && s.value.IsZero() {
+				_ = s.set(s.dev)
+				cfg.devDefaults = append(cfg.devDefaults, s.path)
+			}
+		}
+		if cfg.Media.SigningKey == "" {
+			cfg.Media.SigningKey = cfg.Auth.JWTSecret
+			cfg.devDefaults = append(cfg.devDefaults, "media.signing_key")
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// loadFile overlays the YAML file on cfg. Unknown keys are errors, so a
+// misspelled setting is not silently ignored.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
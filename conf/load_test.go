@@ -0,0 +1,161 @@
+package conf
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const productionSecret = "0123456789abcdef0123456789abcdef"
+
+// clearEnv unsets the configuration variables for the test, so the
+// environment of the machine running it does not leak in.
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, s := range settings(&Config{}) {
+		if s.env == "" {
+			continue
+		}
+		if old, ok := os.LookupEnv(s.env); ok {
+			os.Unsetenv(s.env)
+			t.Cleanup(func() { os.Setenv(s.env, old) })
+		}
+	}
+	if old, ok := os.LookupEnv(ConfigFileEnv); ok {
+		os.Unsetenv(ConfigFileEnv)
+		t.Cleanup(func() { os.Setenv(ConfigFileEnv, old) })
+	}
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func parseFlags(t *testing.T, args ...string) *Flags {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags := RegisterFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return flags
+}
+
+func TestLoadOverridesDefaultsWithFileEnvironmentAndFlags(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("APP_ENV", EnvDevelopment)
+	file := writeFile(t, "db:\n  host: file-host\n  port: \"3307\"\n  name: file-db\nlog:\n  level: warn\n")
+	t.Setenv("DB_PORT", "3308")
+	t.Setenv("DB_NAME", "env-db")
+
+	cfg, err := Load(parseFlags(t, "-config", file, "-db.name", "flag-db"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	for _, c := range []struct{ setting, got, want string }{
+		{"server.addr", cfg.Server.Addr, "0.0.0.0:8080"},
+		{"db.host", cfg.DB.Host, "file-host"},
+		{"log.level", cfg.Log.Level, "warn"},
+		{"db.port", cfg.DB.Port, "3308"},
+		{"db.name", cfg.DB.Name, "flag-db"},
+	} {
+		if c.got != c.want {
+			t.Errorf("%s = %q, want %q", c.setting, c.got, c.want)
+		}
+	}
+}
+
+func TestLoadRequiresAnEnvironment(t *testing.T) {
+	clearEnv(t)
+
+	_, err := Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "env (APP_ENV): is required") {
+		t.Fatalf("load without APP_ENV = %v, want it required", err)
+	}
+}
+
+func TestLoadFillsDevelopmentSecrets(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("APP_ENV", EnvDevelopment)
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if cfg.Auth.JWTSecret == "" || cfg.Media.SigningKey != cfg.Auth.JWTSecret {
+		t.Fatalf("jwt secret %q, signing key %q; want the development secret for both", cfg.Auth.JWTSecret, cfg.Media.SigningKey)
+	}
+	dev := strings.Join(cfg.DevDefaults(), ",")
+	for _, path := range []string{"auth.jwt_secret", "media.signing_key"} {
+		if !strings.Contains(dev, path) {
+			t.Errorf("dev defaults = %s, want %s among them", dev, path)
+		}
+	}
+}
+
+func TestLoadRejectsProductionWithoutSecrets(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("APP_ENV", EnvProduction)
+	t.Setenv("DB_PASS", "s3cret")
+	t.Setenv("JWT_SECRET", productionSecret)
+
+	_, err := Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "media.signing_key (MEDIA_SIGNING_KEY): is required") {
+		t.Fatalf("load without a signing key = %v, want it required", err)
+	}
+
+	t.Setenv("MEDIA_SIGNING_KEY", productionSecret)
+	_, err = Load(nil)
+	if err == nil || !strings.Contains(err.Error(), "must differ from auth.jwt_secret") {
+		t.Fatalf("load with the JWT secret as signing key = %v, want it refused", err)
+	}
+
+	t.Setenv("MEDIA_SIGNING_KEY", strings.ToUpper(productionSecret))
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if len(cfg.DevDefaults()) != 0 {
+		t.Fatalf("production used development defaults %v", cfg.DevDefaults())
+	}
+}
+
+func TestLoadReportsEveryProblemAtOnce(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("APP_ENV", EnvProduction)
+	t.Setenv("EVENTS_RELAY_BATCH_SIZE", "x")
+	t.Setenv("DB_DRIVER", "oracle")
+
+	_, err := Load(nil)
+	if err == nil || !strings.Contains(err.Error(), `events.batch_size (EVENTS_RELAY_BATCH_SIZE): "x" is not an integer`) {
+		t.Fatalf("load = %v, want the bad integer reported", err)
+	}
+
+	t.Setenv("EVENTS_RELAY_BATCH_SIZE", "10")
+	_, err = Load(nil)
+	if err == nil {
+		t.Fatal("load accepted production without secrets")
+	}
+	for _, want := range []string{"auth.jwt_secret", "db.driver", "media.signing_key"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("load error does not mention %s:\n%v", want, err)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	clearEnv(t)
+	t.Setenv("APP_ENV", EnvDevelopment)
+	file := writeFile(t, "db:\n  hots: typo\n")
+
+	if _, err := Load(parseFlags(t, "-config", file)); err == nil || !strings.Contains(err.Error(), "hots") {
+		t.Fatalf("load = %v, want the unknown key reported", err)
+	}
+}
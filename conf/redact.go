@@ -0,0 +1,40 @@
+package conf
+
+import (
+	"log/slog"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// LogValue logs the configuration as nested groups with the secrets
+// redacted, so the whole struct can be passed to a logger.
+func (c *Config) LogValue() slog.Value {
+	groups := make(map[string][]slog.Attr)
+	var order []string
+	for _, s := range settings(c) {
+		section, key := "", s.path
+		if i := strings.LastIndexByte(s.path, '.'); i >= 0 {
+			section, key = s.path[:i], s.path[i+1:]
+		}
+		if _, ok := groups[section]; !ok {
+			order = append(order, section)
+		}
+
+		value := slog.AnyValue(s.value.Interface())
+		if s.secret && !s.value.IsZero() {
+			value = slog.StringValue(redacted)
+		}
+		groups[section] = append(groups[section], slog.Attr{Key: key, Value: value})
+	}
+
+	var attrs []slog.Attr
+	for _, section := range order {
+		if section == "" {
+			attrs = append(attrs, groups[section]...)
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: section, Value: slog.GroupValue(groups[section]...)})
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,186 @@
+package conf
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// minSecretLength is the shortest JWT secret and media signing key
+// production accepts.
+const minSecretLength = 32
+
+// problems collects validation errors, naming each setting by its YAML path
+// and environment variable.
+type problems struct {
+	names map[string]string
+	errs  []error
+}
+
+func (p *problems) add(path, format string, args ...any) {
+	name, ok := p.names[path]
+	if !ok {
+		name = path
+	}
+	p.errs = append(p.errs, fmt.Errorf("%s: %s", name, fmt.Sprintf(format, args...)))
+}
+
+func (p *problems) positive(path string, n int64) {
+	if n <= 0 {
+		p.add(path, "must be positive, got %d", n)
+	}
+}
+
+func (p *problems) url(path, raw string) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		p.add(path, "%q is not an absolute URL", raw)
+	}
+}
+
+// Validate checks the configuration and reports every problem at once.
+func (c *Config) Validate() error {
+	p := &problems{names: make(map[string]string)}
+	for _, s := range settings(c) {
+		p.names[s.path] = s.name()
+	}
+
+	switch c.Env {
+	case EnvDevelopment, EnvProduction:
+	case "":
+		p.add("env", "is required, set %q or %q", EnvDevelopment, EnvProduction)
+	default:
+		p.add("env", "must be %q or %q, got %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+	if c.Production() {
+		switch {
+		case c.Auth.JWTSecret == "":
+			p.add("auth.jwt_secret", "is required in production")
+		case len(c.Auth.JWTSecret) < minSecretLength:
+			p.add("auth.jwt_secret", "must be at least %d characters in production", minSecretLength)
+		}
+		if c.DB.Pass == "" && c.DB.Driver != DriverSQLite {
+			p.add("db.pass", "is required in production")
+		}
+		if c.Media.Backend == "local" {
+			switch {
+			case c.Media.SigningKey == "":
+				p.add("media.signing_key", "is required by the local backend in production")
+			case len(c.Media.SigningKey) < minSecretLength:
+				p.add("media.signing_key", "must be at least %d characters in production", minSecretLength)
+			case c.Media.SigningKey == c.Auth.JWTSecret:
+				p.add("media.signing_key", "must differ from auth.jwt_secret in production")
+			}
+		}
+		for _, s := range settings(c) {
+			if s.dev != "" && s.value.String() == s.dev {
+				p.add(s.path, "uses the development default in production")
+			}
+		}
+	}
+	p.url("base_url", c.BaseURL)
+
+	switch c.DB.Driver {
+	case DriverMySQL, DriverPostgres:
+		if c.DB.Host == "" {
+			p.add("db.host", "is required")
+		}
+	case DriverSQLite:
+	default:
+		p.add("db.driver", "must be %s, %s or %s, got %q", DriverMySQL, DriverPostgres, DriverSQLite, c.DB.Driver)
+	}
+	if c.DB.Name == "" {
+		p.add("db.name", "is required")
+	}
+	if c.Redis.DB < 0 {
+		p.add("redis.db", "must not be negative, got %d", c.Redis.DB)
+	}
+	p.url("elasticsearch.address", c.Elasticsearch.Address)
+	if (c.SMTP.Host == "") != (c.SMTP.Port == "") {
+		p.add("smtp.host", "smtp.host and smtp.port must be set together")
+	}
+
+	p.positive("auth.access_token_ttl_minutes", c.Auth.AccessTokenTTLMinutes)
+	p.positive("auth.refresh_token_ttl_hours", c.Auth.RefreshTokenTTLHours)
+	p.positive("auth.verification_ttl_minutes", c.Auth.VerificationTTLMinutes)
+	if c.Auth.RefreshTokenTTL() <= c.Auth.AccessTokenTTL() {
+		p.add("auth.refresh_token_ttl_hours", "must outlive the access token")
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		p.add("log.level", "%q is not a level (debug, info, warn, error)", c.Log.Level)
+	}
+	if c.Log.KafkaLevel != "" {
+		if err := level.UnmarshalText([]byte(c.Log.KafkaLevel)); err != nil {
+			p.add("log.kafka_level", "%q is not a level (debug, info, warn, error)", c.Log.KafkaLevel)
+		}
+	}
+	if format := strings.ToLower(c.Log.Format); format != "json" && format != "text" {
+		p.add("log.format", "must be json or text, got %q", c.Log.Format)
+	}
+
+	// The blockchain is optional, but half a configuration is a mistake
+	bnb := []string{c.BNB.NodeURL, c.BNB.PrivateKey, c.BNB.ContractAddress}
+	if slices.Contains(bnb, "") && slices.ContainsFunc(bnb, func(s string) bool { return s != "" }) {
+		p.add("bnb.node_url", "bnb.node_url, bnb.private_key and bnb.contract_address must be set together")
+	}
+
+	switch c.Media.Backend {
+	case "local":
+		if c.Media.Dir == "" {
+			p.add("media.dir", "is required by the local backend")
+		}
+	case "s3":
+		if c.Media.S3Bucket == "" {
+			p.add("media.s3_bucket", "is required by the s3 backend")
+		}
+	default:
+		p.add("media.backend", "must be local or s3, got %q", c.Media.Backend)
+	}
+	p.positive("media.max_upload_bytes", c.Media.MaxUploadBytes)
+	p.positive("media.url_ttl_seconds", c.Media.URLTTLSeconds)
+
+	if c.Realtime.ThrottleMillis < 0 {
+		p.add("realtime.throttle_ms", "must not be negative, got %d", c.Realtime.ThrottleMillis)
+	}
+	p.positive("voting.flush_interval_ms", c.Voting.FlushIntervalMillis)
+
+	if c.Events.KafkaBroker == "" {
+		p.add("events.kafka_broker", "is required")
+	}
+	p.positive("events.relay_interval_ms", c.Events.RelayIntervalMillis)
+	p.positive("events.batch_size", c.Events.BatchSize)
+	p.positive("events.max_attempts", c.Events.MaxAttempts)
+	p.positive("events.retention_hours", c.Events.RetentionHours)
+
+	if c.Tracing.Endpoint != "" {
+		p.url("tracing.endpoint", c.Tracing.Endpoint)
+	}
+	if c.Tracing.SamplePercent < 0 || c.Tracing.SamplePercent > 100 {
+		p.add("tracing.sample_percent", "must be between 0 and 100, got %d", c.Tracing.SamplePercent)
+	}
+
+	if c.Server.Addr == "" {
+		p.add("server.addr", "is required")
+	}
+	p.positive("server.read_timeout_seconds", c.Server.ReadTimeoutSeconds)
+	p.positive("server.write_timeout_seconds", c.Server.WriteTimeoutSeconds)
+	p.positive("server.idle_timeout_seconds", c.Server.IdleTimeoutSeconds)
+	p.positive("server.shutdown_timeout_seconds", c.Server.ShutdownTimeoutSeconds)
+	for _, origin := range c.Server.CORSOrigins {
+		p.url("server.cors_origins", origin)
+	}
+
+	p.positive("dependencies.failure_threshold", c.Deps.FailureThreshold)
+	p.positive("dependencies.cooldown_seconds", c.Deps.CooldownSeconds)
+	p.positive("dependencies.chain_retry_seconds", c.Deps.ChainRetrySeconds)
+
+	if len(p.errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(p.errs...))
+	}
+	return nil
+}
@@ -8,23 +8,45 @@ import (
 	"VoteGolang/internals/app/migrations"
 	"VoteGolang/internals/controller/blockchain_routes"
 	"VoteGolang/internals/controller/candidate_routes"
+	"VoteGolang/internals/controller/comment_routes"
+	"VoteGolang/internals/controller/health_routes"
 	middleware "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/controller/login_routes"
+	"VoteGolang/internals/controller/media_routes"
+	"VoteGolang/internals/controller/nomination_routes"
+	"VoteGolang/internals/controller/party_routes"
 	"VoteGolang/internals/controller/petition_routes"
 	"VoteGolang/internals/controller/search_routes"
+	"VoteGolang/internals/controller/stream_routes"
 	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/cache"
+	"VoteGolang/internals/infrastructure/dependency"
 	"VoteGolang/internals/infrastructure/email"
+	"VoteGolang/internals/infrastructure/events"
+	"VoteGolang/internals/infrastructure/health"
+	"VoteGolang/internals/infrastructure/metrics"
+	"VoteGolang/internals/infrastructure/moderation"
+	"VoteGolang/internals/infrastructure/realtime"
 	"VoteGolang/internals/infrastructure/repositories"
 	"VoteGolang/internals/infrastructure/search"
+	"VoteGolang/internals/infrastructure/storage"
+	"VoteGolang/internals/infrastructure/tracing"
+	"VoteGolang/internals/infrastructure/votecount"
 	"VoteGolang/internals/service" // <-- NEW IMPORT
 	"VoteGolang/internals/usecases/auth_usecase"
 	"VoteGolang/internals/usecases/candidate_usecase"
+	"VoteGolang/internals/usecases/comment_usecase"
+	"VoteGolang/internals/usecases/media_usecase"
+	"VoteGolang/internals/usecases/nomination_usecase"
+	"VoteGolang/internals/usecases/party_usecase"
 	"VoteGolang/internals/usecases/petition_usecase"
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"os"
+	"sync"
+	"time"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/redis/go-redis/v9"
@@ -36,174 +58,391 @@ type App struct {
 	Config     *conf.Config
 	DB         *gorm.DB
 	Blockchain service.BlockchainService // <-- CHANGED
-}
+	// Deps holds the circuit breakers of the optional dependencies.
+	Deps *dependency.Registry
+	// ShutdownTracing flushes spans that have not been exported yet.
+	ShutdownTracing func(context.Context) error
 
-func NewApp(kafkaLogger *logging.KafkaLogger) (*App, *auth_usecase.AuthUseCase, domain.TokenManager, *redis.Client, *elasticsearch.Client, error) {
-	kafkaLogger.Log("INFO", "Initializing application components...")
+	chainLog *service.QueuedBlockchain
+}
 
-	config := conf.LoadConfig(kafkaLogger)
-	kafkaLogger.Log("INFO", "Configuration loaded successfully")
+func NewApp(config *conf.Config, logger *slog.Logger) (*App, *auth_usecase.AuthUseCase, domain.TokenManager, *redis.Client, *elasticsearch.Client, error) {
+	logger.Info("Initializing application components...")
 
-	db, err := connect.ConnectDB(config, kafkaLogger)
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing)
 	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Database connection failed: %v", err))
-		return nil, nil, nil, nil, nil, err
+		return nil, nil, nil, nil, nil, fmt.Errorf("tracing setup: %w", err)
+	}
+	if config.Tracing.Endpoint != "" {
+		logger.Info("Tracing enabled", "endpoint", config.Tracing.Endpoint, "sample_percent", config.Tracing.SamplePercent)
 	}
-	kafkaLogger.Log("INFO", "Connected to MySQL database successfully")
 
-	err = migrations.MigrateAllTables(db)
+	db, err := connect.ConnectDB(config.DB, logger)
 	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Database migration failed: %v", err))
+		logger.Error("Database connection failed", logging.Err(err))
 		return nil, nil, nil, nil, nil, err
 	}
-	kafkaLogger.Log("INFO", "Database migrations applied successfully")
+	logger.Info("Connected to the database successfully", "driver", config.DB.Driver)
+	if sqlDB, err := db.DB(); err == nil {
+		metrics.RegisterDBStats(sqlDB)
+	}
 
-	esClient, err := connect.ConnectElasticsearch()
-	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Failed to connect to Elasticsearch: %v", err))
-	} else {
-		kafkaLogger.Log("INFO", "Connected to Elasticsearch successfully")
-		// Ensure indices exist with the correct mappings
-		if err := search.CreateIndexWithMapping(esClient, "candidates", search.CandidateMapping); err != nil {
-			kafkaLogger.Log("ERROR", fmt.Sprintf("Failed to create 'candidates' index: %v", err))
-		}
-		if err := search.CreateIndexWithMapping(esClient, "petitions", search.PetitionMapping); err != nil {
-			kafkaLogger.Log("ERROR", fmt.Sprintf("Failed to create 'petitions' index: %v", err))
+	if config.DB.AutoMigrate {
+		if err := migrations.Migrate(context.Background(), db, logger); err != nil {
+			logger.Error("Database migration failed", logging.Err(err))
+			return nil, nil, nil, nil, nil, err
 		}
+		logger.Info("Database migrations applied successfully")
 	}
-	bc, err := service.NewBnbService(config.BNB) // <-- CHANGED
+
+	deps := dependency.NewRegistry(
+		int(config.Deps.FailureThreshold),
+		time.Duration(config.Deps.CooldownSeconds)*time.Second,
+		logger,
+	)
+
+	// Elasticsearch, Redis, Kafka and the blockchain are optional: the API
+	// starts without them and bypasses them while their breaker is open.
+	esClient, err := connect.ConnectElasticsearch(config.Elasticsearch.Address, connect.ElasticsearchTransport(deps.Breaker("elasticsearch")))
 	if err != nil {
-		kafkaLogger.Log("INFO", "Blockchain initialized")
+		logger.Error("Failed to connect to Elasticsearch, search falls back to the database", logging.Err(err))
+	} else {
+		// The search repositories create their indices on first write
+		logger.Info("Connected to Elasticsearch successfully")
 	}
+	// The node is connected on first use and again until it succeeds; calls
+	// are queued meanwhile
+	chainLog := service.NewQueuedBlockchain(
+		func() (service.BlockchainService, error) { return service.NewBnbService(config.BNB) },
+		repositories.NewChainLogQueue(db),
+		deps.Breaker("blockchain"),
+		time.Duration(config.Deps.ChainRetrySeconds)*time.Second,
+		logger,
+	)
 	app := &App{
 		Config:     config,
 		DB:         db,
-		Blockchain: bc, // <-- CHANGED
-	}
-
-	userRepo := repositories.NewUserRepository(db)
-	tokenManager := domain.NewJwtToken(config.JWTSecret)
-
-	redisHost := os.Getenv("REDIS_HOST")
-	if redisHost == "" {
-		redisHost = "redis"
-	}
+		Blockchain: chainLog, // <-- CHANGED
+		Deps:       deps,
 
-	redisPort := os.Getenv("REDIS_PORT")
-	if redisPort == "" {
-		redisPort = "6379"
+		ShutdownTracing: shutdownTracing,
+		chainLog:        chainLog,
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr: fmt.Sprintf("%s:%s", redisHost, redisPort),
-		DB:   0,
-	})
+	userRepo := repositories.NewUserRepository(db)
+	tokenManager := domain.NewJwtToken(config.Auth.JWTSecret)
 
-	ctx := context.Background()
-	status, err := rdb.Ping(ctx).Result()
+	rdb, err := connect.ConnectRedis(config.Redis, logger)
 	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Redis connection failed: %v", err))
-		log.Fatalln("Redis connection was refused:", err)
-	} else {
-		kafkaLogger.Log("INFO", fmt.Sprintf("Redis connected successfully: %s", status))
+		logger.Warn("Starting without Redis, caching is bypassed until it is back", logging.Err(err))
 	}
+	rdb.AddHook(dependency.NewRedisHook(deps.Breaker("redis")))
+	rdb.AddHook(metrics.NewCacheHook())
 
 	roleRepo := repositories.NewRoleRepository(db)
 
 	// создаем EmailVerifier
-	emailVerifier := email.NewRedisEmailVerifier(rdb)
-	authUseCase := auth_usecase.NewAuthUseCase(userRepo, roleRepo, tokenManager, emailVerifier)
-
-	// start clean up of unverified users
-	email.StartUnverifiedCleanupJob(userRepo)
-	kafkaLogger.Log("INFO", "Started background cleanup job for unverified users")
+	emailVerifier := email.NewRedisEmailVerifier(rdb, config.SMTP, config.BaseURL, config.Auth.VerificationTTL())
+	authUseCase := auth_usecase.NewAuthUseCase(
+		userRepo,
+		roleRepo,
+		tokenManager,
+		emailVerifier,
+		config.Auth.AccessTokenTTL(),
+		config.Auth.RefreshTokenTTL(),
+		logger,
+	)
 
-	kafkaLogger.Log("INFO", "All core services initialized successfully")
+	logger.Info("All core services initialized successfully")
 	return app, authUseCase, tokenManager, rdb, esClient, nil
 }
 
-func (a *App) Run(authUseCase *auth_usecase.AuthUseCase, tokenManager domain.TokenManager, kafkaLogger *logging.KafkaLogger, rdb *redis.Client, esClient *elasticsearch.Client) {
-	kafkaLogger.Log("INFO", "Starting HTTP server on port 8080...")
+// Close closes the database connections.
+func (a *App) Close() error {
+	sqlDB, err := a.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Run serves HTTP until ctx is cancelled, then stops the background jobs and
+// waits for in-flight requests to finish.
+func (a *App) Run(ctx context.Context, authUseCase *auth_usecase.AuthUseCase, tokenManager domain.TokenManager, logger *slog.Logger, rdb *redis.Client, esClient *elasticsearch.Client) error {
+	logger.Info("Starting HTTP server", "addr", a.Config.Server.Addr)
 
-	mux := http.NewServeMux()
+	// Closed after the relay that uses it has stopped
+	eventPublisher := events.NewKafkaPublisher(a.Config.Events.KafkaBroker)
+	defer eventPublisher.Close()
 
-	// Middleware to log every request
-	logMiddleware := func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			kafkaLogger.Log("INFO", fmt.Sprintf("Accessed %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr))
-			next.ServeHTTP(w, r)
-		})
+	// Background jobs stop as soon as shutdown begins; the hub closing ends
+	// the live result streams, which would otherwise hold the drain open.
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	var jobs sync.WaitGroup
+	defer jobs.Wait()
+	defer stopJobs()
+	startJob := func(run func(context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			run(jobsCtx)
+		}()
 	}
 
+	mux := http.NewServeMux()
+
+	// Probes
+	checker := health.NewChecker(2*time.Second, a.Deps,
+		health.Database(a.DB),
+		health.Redis(rdb),
+		health.Elasticsearch(esClient),
+		health.Kafka(a.Config.Events.KafkaBroker),
+		health.Blockchain(a.Blockchain),
+	)
+	health_routes.RegisterHealthRoutes(mux, health_routes.NewHealthHandler(checker))
+
 	// Swagger UI route
-	mux.Handle("/swagger/", httpSwagger.WrapHandler)
+	mux.HandleFunc("/swagger/", func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetRoute(r, "/swagger/")
+		httpSwagger.WrapHandler(w, r)
+	})
+
+	// Prometheus metrics
+	metricsHandler := metrics.Handler()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		middleware.SetRoute(r, "/metrics")
+		metricsHandler.ServeHTTP(w, r)
+	})
 
 	//auth
-	authHandler := login_routes.NewAuthHandler(authUseCase, tokenManager, kafkaLogger)
-	login_routes.AuthorizationRoutes(mux, authHandler, tokenManager, kafkaLogger)
-	kafkaLogger.Log("INFO", "Authentication routes registered")
+	authHandler := login_routes.NewAuthHandler(authUseCase, tokenManager, logger)
+	login_routes.AuthorizationRoutes(mux, authHandler, tokenManager, logger)
+	logger.Info("Authentication routes registered")
 
 	// create rbac repo once
 	rbacRepo := repositories.NewRBACRepository(a.DB)
+	assetRepo := repositories.NewAssetRepository(a.DB)
+	partyRepo := repositories.NewPartyRepository(a.DB)
+	candidateSearchRepo := repositories.NewSearchRepository(esClient, "candidates", search.CandidateMapping)
+	cacheStore := cache.NewRedis(rdb)
+	tallyBus := realtime.NewRedisTallyBus(rdb)
+	outboxRepo := repositories.NewOutboxRepository(a.DB)
+
+	// start clean up of unverified users
+	startJob(func(ctx context.Context) {
+		email.RunUnverifiedCleanupJob(ctx, repositories.NewUserRepository(a.DB))
+	})
+	logger.Info("Started background cleanup job for unverified users")
+
+	// Business events
+	eventRelay := events.NewRelay(
+		outboxRepo,
+		eventPublisher,
+		a.Deps.Breaker("kafka"),
+		time.Duration(a.Config.Events.RelayIntervalMillis)*time.Millisecond,
+		int(a.Config.Events.BatchSize),
+		int(a.Config.Events.MaxAttempts),
+		time.Duration(a.Config.Events.RetentionHours)*time.Hour,
+		logger,
+	)
+	startJob(eventRelay.Run)
+	logger.Info("Event outbox relay started")
+
+	// Media
+	mediaStorage, err := storage.NewFromConfig(a.Config.Media)
+	if err != nil {
+		logger.Error("Media storage unavailable, uploads disabled", logging.Err(err))
+	} else {
+		localStorage, _ := mediaStorage.(*storage.LocalStorage)
+		mediaHandler := media_routes.NewMediaHandler(
+			media_usecase.NewMediaUseCase(
+				assetRepo,
+				mediaStorage,
+				time.Duration(a.Config.Media.URLTTLSeconds)*time.Second,
+				logger,
+			),
+			localStorage,
+			a.Config.Media.MaxUploadBytes,
+			tokenManager.(*domain.JwtToken),
+			logger,
+		)
+		media_routes.RegisterMediaRoutes(mux, mediaHandler, tokenManager, rbacRepo)
+		logger.Info("Media routes registered", "storage", a.Config.Media.Backend)
+	}
 
 	// Candidate
+	candidateUseCase := candidate_usecase.NewCandidateUseCase(
+		repositories.NewCandidateRepository(a.DB),
+		repositories.NewVoteRepository(a.DB),
+		a.Blockchain,
+		cacheStore,
+		candidateSearchRepo,
+		assetRepo,
+		partyRepo,
+		tallyBus,
+		logger,
+	)
+	if a.Config.Voting.WriteBehind {
+		candidateUseCase.Counter = votecount.NewRedis(rdb)
+		candidateUseCase.VoteCounts = repositories.NewVoteCountRepository(a.DB)
+		if err := candidateUseCase.InitVoteCounter(ctx); errors.Is(err, candidate_usecase.ErrVoteCounterLost) {
+			return fmt.Errorf("write-behind voting: %w", err)
+		} else if err != nil {
+			// Redis is down: the flusher reports the counter once it is back
+			logger.Warn("Vote counter check failed", logging.Err(err))
+		}
+		startJob(func(ctx context.Context) {
+			candidateUseCase.RunVoteFlusher(ctx, time.Duration(a.Config.Voting.FlushIntervalMillis)*time.Millisecond)
+		})
+		logger.Info("Write-behind vote counting enabled", "flush_interval_ms", a.Config.Voting.FlushIntervalMillis)
+	}
 	candidateHandler := candidate_routes.NewCandidateHandler(
-		candidate_usecase.NewCandidateUseCase(
-			repositories.NewCandidateRepository(a.DB),
-			repositories.NewVoteRepository(a.DB),
-			a.Blockchain,
-			rdb,
-			repositories.NewSearchRepository(esClient, "candidates"),
-			kafkaLogger,
-		),
+		candidateUseCase,
 		tokenManager.(*domain.JwtToken),
-		kafkaLogger,
+		logger,
 	)
 	candidate_routes.RegisterCandidateRoutes(mux, candidateHandler, tokenManager, rbacRepo)
-	kafkaLogger.Log("INFO", "Candidate routes registered")
+	logger.Info("Candidate routes registered")
+
+	// Parties
+	partyHandler := party_routes.NewPartyHandler(
+		party_usecase.NewPartyUseCase(partyRepo, assetRepo, candidateSearchRepo, cacheStore, logger),
+		logger,
+	)
+	party_routes.RegisterPartyRoutes(mux, partyHandler, tokenManager, rbacRepo)
+	logger.Info("Party routes registered")
+
+	// Nominations
+	nominationHandler := nomination_routes.NewNominationHandler(
+		nomination_usecase.NewNominationUseCase(
+			repositories.NewNominationRepository(a.DB),
+			repositories.NewUserRepository(a.DB),
+			assetRepo,
+			partyRepo,
+			candidateUseCase,
+			logger,
+		),
+		tokenManager.(*domain.JwtToken),
+		logger,
+	)
+	nomination_routes.RegisterNominationRoutes(mux, nominationHandler, tokenManager, rbacRepo)
+	logger.Info("Nomination routes registered")
 
 	//Petitions
+	petitionSearchRepo := repositories.NewSearchRepository(esClient, "petitions", search.PetitionMapping)
+	screeners := []domain.PetitionScreener{moderation.NewProfanityScreener(moderation.DefaultProfanityList)}
+	if esClient != nil {
+		screeners = append(screeners, moderation.NewDuplicateScreener(petitionSearchRepo))
+	}
 	petitionsHandler := petition_routes.NewPetitionHandler(
 		petition_usecase.NewPetitionUseCase(
 			repositories.NewPetitionRepository(a.DB),
 			repositories.NewPetitionVoteRepository(a.DB),
 			a.Blockchain,
-			rdb,
-			kafkaLogger,
-			repositories.NewSearchRepository(esClient, "petitions"),
+			cacheStore,
+			logger,
+			petitionSearchRepo,
+			repositories.NewPetitionModerationRepository(a.DB),
+			assetRepo,
+			tallyBus,
+			screeners...,
 		),
 		tokenManager.(*domain.JwtToken),
-		kafkaLogger,
+		logger,
 	)
 	petition_routes.RegisterPetitionRoutes(mux, petitionsHandler, tokenManager, rbacRepo)
-	kafkaLogger.Log("INFO", "Petition routes registered")
+	logger.Info("Petition routes registered")
+
+	// Petition comments
+	commentHandler := comment_routes.NewCommentHandler(
+		comment_usecase.NewCommentUseCase(
+			repositories.NewPetitionCommentRepository(a.DB),
+			repositories.NewPetitionRepository(a.DB),
+			cacheStore,
+			repositories.NewSearchRepository(esClient, "comments", search.CommentMapping),
+			logger,
+		),
+		tokenManager.(*domain.JwtToken),
+		logger,
+	)
+	comment_routes.RegisterCommentRoutes(mux, commentHandler, tokenManager, rbacRepo)
+	logger.Info("Comment routes registered")
+
+	// Live results
+	hub := realtime.NewHub(
+		rdb,
+		realtime.NewSnapshotSource(
+			repositories.NewCandidateRepository(a.DB),
+			repositories.NewPetitionRepository(a.DB),
+			a.Config.Realtime.HiddenResultTypes,
+		),
+		time.Duration(a.Config.Realtime.ThrottleMillis)*time.Millisecond,
+		logger,
+	)
+	startJob(hub.Run)
+	stream_routes.RegisterStreamRoutes(mux, stream_routes.NewStreamHandler(hub, a.Config.Server.CORSOrigins, logger), tokenManager, rbacRepo)
+	logger.Info("Stream routes registered")
+
+	// Queued chain logs are replayed once the node is back
+	startJob(a.chainLog.Run)
 
 	// Blockchain (Handler now shows service info)
 	blockchainHandler := blockchain_routes.NewBlockchainHandler(a.Blockchain) // <-- PASSING THE INTERFACE
 	blockchain_routes.RegisterBlockchainRoutes(mux, blockchainHandler)
-	kafkaLogger.Log("INFO", "Blockchain routes registered")
+	logger.Info("Blockchain routes registered")
 
 	// Search
-	searcher := search.NewElasticsearch("http://elasticsearch:9200")
+	searcher := search.NewFallback(
+		search.NewElasticsearch(a.Config.Elasticsearch.Address, connect.ElasticsearchTransport(a.Deps.Breaker("elasticsearch"))),
+		repositories.NewSQLSearchRepository(a.DB),
+		logger,
+	)
 	search_routes.SetupRoutes(mux, searcher)
-	kafkaLogger.Log("INFO", "Search routes registered")
+	logger.Info("Search routes registered")
 
 	// fallback for unknown routes
 	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		kafkaLogger.Log("WARN", fmt.Sprintf("Unknown route accessed: %s %s from %s", r.Method, r.URL.Path, r.RemoteAddr))
+		logger.WarnContext(r.Context(), "Unknown route accessed", "method", r.Method, "path", r.URL.Path, "remote_addr", r.RemoteAddr)
 		w.WriteHeader(http.StatusNotFound)
 		w.Header().Set("Content-Type", "application/json")
 		w.Write([]byte(`{"error": "route not found"}`))
 	},
 	)
 
-	// Wrap mux with logging middleware
-	handler := middleware.CORSMiddleware(logMiddleware(mux))
-	// Listen on all network interfaces
-	err := http.ListenAndServe("0.0.0.0:8080", handler)
-	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Server failed to start: %v", err))
-		log.Fatalf("Error starting server: %v", err)
+	// Wrap mux with the request span, the request metrics and the access log,
+	// which also sets up the request log context
+	handler := middleware.CORSMiddleware(a.Config.Server.CORSOrigins)(middleware.TracingMiddleware(middleware.MetricsMiddleware(middleware.RequestLogger(logger)(mux))))
+	server := &http.Server{
+		Addr:              a.Config.Server.Addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       time.Duration(a.Config.Server.ReadTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(a.Config.Server.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(a.Config.Server.IdleTimeoutSeconds) * time.Second,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
 	}
 
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("server failed to start: %w", err)
+	case <-ctx.Done():
+	}
+
+	timeout := time.Duration(a.Config.Server.ShutdownTimeoutSeconds) * time.Second
+	logger.Info("Shutting down, draining in-flight requests", "timeout", timeout.String())
+	checker.SetShuttingDown()
+	stopJobs()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown: %w", err)
+	}
+	logger.Info("HTTP server stopped")
+	return nil
 }
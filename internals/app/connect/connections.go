@@ -5,25 +5,72 @@ import (
 	"VoteGolang/internals/app/logging"
 	"VoteGolang/internals/app/migrations"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
+	"net/url"
 
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
+	otelgorm "gorm.io/plugin/opentelemetry/tracing"
 )
 
-func ConnectDB(config *conf.Config, kafkaLogger *logging.KafkaLogger) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		config.DBUser, config.DBPass, config.DBHost, config.DBPort, config.DBName)
-	kafkaLogger.Log("INFO", fmt.Sprintf("Attempting to connect to database %s:%s/%s", config.DBHost, config.DBPort, config.DBName))
+func ConnectDB(config *conf.DBConfig, logger *slog.Logger) (*gorm.DB, error) {
+	d, err := dialector(config)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("Attempting to connect to database", "driver", config.Driver, "host", config.Host, "port", config.Port, "database", config.Name)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: migrations.SetupDatabaseLogger()})
+	db, err := gorm.Open(d, &gorm.Config{Logger: migrations.SetupDatabaseLogger()})
 	if err != nil {
-		kafkaLogger.Log("ERROR", fmt.Sprintf("Failed to connect to database: %v", err))
-		log.Fatalf("Failed to connect to database: %v", err)
+		logger.Error("Failed to connect to database", logging.Err(err))
 		return nil, err
 	}
+	if config.Driver == conf.DriverSQLite {
+		// SQLite has a single writer; one connection avoids "database is
+		// locked" errors and keeps a :memory: database alive
+		if sqlDB, err := db.DB(); err == nil {
+			sqlDB.SetMaxOpenConns(1)
+		}
+	}
+	if err := db.Use(otelgorm.NewPlugin(otelgorm.WithoutMetrics())); err != nil {
+		logger.Warn("Database tracing disabled", logging.Err(err))
+	}
 
-	kafkaLogger.Log("INFO", fmt.Sprintf("Successfully connected to database: %s", config.DBName))
-	log.Println("Connected to database")
+	logger.Info("Successfully connected to database", "database", config.Name)
 	return db, nil
 }
+
+// dialector returns the GORM dialector of the configured driver.
+func dialector(config *conf.DBConfig) (gorm.Dialector, error) {
+	switch config.Driver {
+	case conf.DriverMySQL:
+		dsn := fmt.Sprintf("%s:%s@tcp(%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			config.User, config.Pass, hostPort(config, "3306"), config.Name)
+		return mysql.Open(dsn), nil
+	case conf.DriverPostgres:
+		dsn := url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(config.User, config.Pass),
+			Host:     hostPort(config, "5432"),
+			Path:     "/" + config.Name,
+			RawQuery: url.Values{"sslmode": {config.SSLMode}}.Encode(),
+		}
+		return postgres.Open(dsn.String()), nil
+	case conf.DriverSQLite:
+		// Foreign keys are off by default in SQLite
+		return sqlite.Open(config.Name + "?_foreign_keys=on&_busy_timeout=5000"), nil
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", config.Driver)
+	}
+}
+
+func hostPort(config *conf.DBConfig, defaultPort string) string {
+	port := config.Port
+	if port == "" {
+		port = defaultPort
+	}
+	return net.JoinHostPort(config.Host, port)
+}
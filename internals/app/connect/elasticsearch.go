@@ -1,17 +1,27 @@
 package connect
 
 import (
+	"VoteGolang/internals/infrastructure/dependency"
 	"fmt"
-	"log"
+	"net/http"
 
 	"github.com/elastic/go-elasticsearch/v7"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-func ConnectElasticsearch() (*elasticsearch.Client, error) {
+// ElasticsearchTransport is the HTTP transport of every Elasticsearch call:
+// traced, and failing fast while the breaker is open.
+func ElasticsearchTransport(breaker *dependency.Breaker) http.RoundTripper {
+	return dependency.Transport(breaker, otelhttp.NewTransport(http.DefaultTransport))
+}
+
+// ConnectElasticsearch returns a client of the cluster at address even when
+// it does not answer yet, together with the error, so it can be used once
+// the cluster is back.
+func ConnectElasticsearch(address string, transport http.RoundTripper) (*elasticsearch.Client, error) {
 	cfg := elasticsearch.Config{
-		Addresses: []string{
-			"http://elasticsearch:9200", // matches docker-compose service name
-		},
+		Addresses: []string{address},
+		Transport: transport,
 	}
 	es, err := elasticsearch.NewClient(cfg)
 	if err != nil {
@@ -21,13 +31,12 @@ func ConnectElasticsearch() (*elasticsearch.Client, error) {
 	// Test connection
 	res, err := es.Info()
 	if err != nil {
-		return nil, err
+		return es, err
 	}
 	defer res.Body.Close()
 	if res.IsError() {
-		return nil, fmt.Errorf("elasticsearch connection failed: %s", res.String())
+		return es, fmt.Errorf("elasticsearch connection failed: %s", res.String())
 	}
 
-	log.Println("Connected to Elasticsearch")
 	return es, nil
 }
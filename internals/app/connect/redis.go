@@ -0,0 +1,33 @@
+package connect
+
+import (
+	"VoteGolang/conf"
+	"VoteGolang/internals/app/logging"
+	"context"
+	"log/slog"
+	"net"
+
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	"github.com/redis/go-redis/v9"
+)
+
+// ConnectRedis connects to the configured Redis server. The client is returned even when the ping fails: it reconnects on its
+// own, so callers that can run without Redis may keep it.
+func ConnectRedis(config *conf.RedisConfig, logger *slog.Logger) (*redis.Client, error) {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     net.JoinHostPort(config.Host, config.Port),
+		Password: config.Password,
+		DB:       int(config.DB),
+	})
+	if err := redisotel.InstrumentTracing(rdb); err != nil {
+		logger.Warn("Redis tracing disabled", logging.Err(err))
+	}
+
+	status, err := rdb.Ping(context.Background()).Result()
+	if err != nil {
+		logger.Error("Redis connection failed", logging.Err(err))
+		return rdb, err
+	}
+	logger.Info("Redis connected successfully", "status", status)
+	return rdb, nil
+}
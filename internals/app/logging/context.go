@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type attrsKey struct{}
+
+// attrSet is shared by a request's contexts, so fields added deep in the
+// handler chain (route, user ID) also appear on the access log written by
+// the outermost middleware.
+type attrSet struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// NewContext starts a fresh set of log fields, seeded with attrs.
+func NewContext(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, attrsKey{}, &attrSet{attrs: attrs})
+}
+
+// AddAttrs adds fields to every record logged with ctx, or with a context
+// derived from it. Without a field set on ctx it starts one.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	set, ok := ctx.Value(attrsKey{}).(*attrSet)
+	if !ok {
+		return NewContext(ctx, attrs...)
+	}
+	set.mu.Lock()
+	set.attrs = append(set.attrs, attrs...)
+	set.mu.Unlock()
+	return ctx
+}
+
+func attrsFrom(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	set, ok := ctx.Value(attrsKey{}).(*attrSet)
+	if !ok {
+		return nil
+	}
+	set.mu.Lock()
+	defer set.mu.Unlock()
+	return append([]slog.Attr(nil), set.attrs...)
+}
+
+// contextHandler adds the fields carried by the context to each record,
+// and the IDs of the current trace span so logs and traces can be joined.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if attrs := attrsFrom(ctx); len(attrs) > 0 {
+		r.AddAttrs(attrs...)
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			r.AddAttrs(
+				slog.String("trace_id", sc.TraceID().String()),
+				slog.String("span_id", sc.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
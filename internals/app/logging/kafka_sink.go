@@ -0,0 +1,133 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const kafkaBatchSize = 100
+
+// KafkaSink is an io.Writer that ships each written record to Kafka in the
+// background. Writes never block: when the buffer is full the record is
+// dropped and counted.
+type KafkaSink struct {
+	writer  *kafka.Writer
+	ch      chan []byte
+	done    chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+	failed  atomic.Uint64
+}
+
+func NewKafkaSink(broker, topic string, buffer int) *KafkaSink {
+	s := &KafkaSink{
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(broker),
+			Topic:        topic,
+			Balancer:     &kafka.LeastBytes{},
+			RequiredAcks: kafka.RequireNone,
+			BatchTimeout: 50 * time.Millisecond,
+		},
+		ch:      make(chan []byte, buffer),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write queues one record. slog handlers reuse p, so it is copied.
+func (s *KafkaSink) Write(p []byte) (int, error) {
+	msg := make([]byte, len(p))
+	copy(msg, p)
+
+	select {
+	case <-s.done:
+		s.dropped.Add(1)
+		return len(p), nil
+	default:
+	}
+	select {
+	case s.ch <- msg:
+	default:
+		// buffer full: drop the record rather than block the caller
+		s.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped counts records discarded because the buffer was full.
+func (s *KafkaSink) Dropped() uint64 { return s.dropped.Load() }
+
+// Failed counts records Kafka did not accept.
+func (s *KafkaSink) Failed() uint64 { return s.failed.Load() }
+
+func (s *KafkaSink) run() {
+	defer close(s.stopped)
+	batch := make([]kafka.Message, 0, kafkaBatchSize)
+	for {
+		select {
+		case msg := <-s.ch:
+			batch = append(batch[:0], kafka.Message{Value: msg})
+		case <-s.done:
+			s.drain()
+			return
+		}
+		// Take whatever else is already queued
+	collect:
+		for len(batch) < kafkaBatchSize {
+			select {
+			case msg := <-s.ch:
+				batch = append(batch, kafka.Message{Value: msg})
+			default:
+				break collect
+			}
+		}
+		s.send(batch)
+	}
+}
+
+// drain sends what is left in the buffer after Close.
+func (s *KafkaSink) drain() {
+	batch := make([]kafka.Message, 0, kafkaBatchSize)
+	for {
+		select {
+		case msg := <-s.ch:
+			batch = append(batch, kafka.Message{Value: msg})
+			if len(batch) == kafkaBatchSize {
+				s.send(batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				s.send(batch)
+			}
+			return
+		}
+	}
+}
+
+func (s *KafkaSink) send(batch []kafka.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.writer.WriteMessages(ctx, batch...); err != nil {
+		s.failed.Add(uint64(len(batch)))
+	}
+}
+
+// Close flushes buffered records, waiting at most until ctx is done, and
+// closes the Kafka writer.
+func (s *KafkaSink) Close(ctx context.Context) error {
+	s.once.Do(func() { close(s.done) })
+
+	select {
+	case <-s.stopped:
+	case <-ctx.Done():
+	}
+	return s.writer.Close()
+}
@@ -0,0 +1,151 @@
+// Package logging builds the service's structured logger: log/slog records
+// go to stdout and to the Kafka topic Logstash ships to Elasticsearch.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// ServiceName is added to every record.
+const ServiceName = "vote-service"
+
+// Config selects levels and sinks.
+type Config struct {
+	Level      slog.Level
+	KafkaLevel slog.Level
+	// Format of stdout records: "json" or "text".
+	Format string
+	// Output receives the records instead of stdout when set.
+	Output io.Writer
+	// Broker and Topic of the Kafka sink; it is disabled when Broker is empty.
+	Broker string
+	Topic  string
+	// Buffer is how many records wait for Kafka before new ones are dropped.
+	Buffer int
+}
+
+// New builds the logger and makes it the slog and log default, so stray
+// log.Printf calls end up in the same sinks. Close the returned sink on
+// shutdown to flush buffered records; it is nil when Kafka is disabled.
+func New(cfg Config) (*slog.Logger, *KafkaSink) {
+	out := cfg.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	stdout := newHandler(out, cfg.Format, cfg.Level)
+
+	var sink *KafkaSink
+	handlers := []slog.Handler{stdout}
+	if cfg.Broker != "" {
+		sink = NewKafkaSink(cfg.Broker, cfg.Topic, cfg.Buffer)
+		handlers = append(handlers, newHandler(sink, "json", cfg.KafkaLevel))
+		go reportDrops(slog.New(stdout), sink)
+	}
+
+	logger := slog.New(contextHandler{fanout(handlers)}).With(slog.String("service", ServiceName))
+	slog.SetDefault(logger)
+	return logger, sink
+}
+
+// newHandler writes records with the field names the Logstash pipeline expects.
+func newHandler(w io.Writer, format string, level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) > 0 {
+				return a
+			}
+			switch a.Key {
+			case slog.TimeKey:
+				a.Key = "timestamp"
+				a.Value = slog.StringValue(a.Value.Time().Format(time.RFC3339Nano))
+			case slog.MessageKey:
+				a.Key = "message"
+			}
+			return a
+		},
+	}
+	if format == "text" {
+		return slog.NewTextHandler(w, opts)
+	}
+	return slog.NewJSONHandler(w, opts)
+}
+
+// Err is the attribute every error is logged under.
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String("error", "")
+	}
+	return slog.String("error", err.Error())
+}
+
+// reportDrops warns on stdout when the Kafka sink lost records, since those
+// warnings could not reach Kafka either.
+func reportDrops(logger *slog.Logger, sink *KafkaSink) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	var lastDropped, lastFailed uint64
+	for {
+		select {
+		case <-sink.done:
+			return
+		case <-ticker.C:
+		}
+		dropped, failed := sink.Dropped(), sink.Failed()
+		if dropped != lastDropped || failed != lastFailed {
+			logger.Warn("Kafka log sink lost records",
+				slog.Uint64("dropped_total", dropped),
+				slog.Uint64("failed_total", failed),
+				slog.Uint64("dropped", dropped-lastDropped),
+				slog.Uint64("failed", failed-lastFailed),
+			)
+			lastDropped, lastFailed = dropped, failed
+		}
+	}
+}
+
+// fanout sends every record to each handler that accepts its level.
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range f {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make(fanout, len(f))
+	for i, h := range f {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	next := make(fanout, len(f))
+	for i, h := range f {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
@@ -0,0 +1,185 @@
+package migrations
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Databases created before versioned migrations have tables but no recorded
+// version. GORM AutoMigrate made their schema at startup, in the shape of
+// the release that last ran, and releases before parties kept the party of
+// candidates and nominations as free text. The baseline's CREATE TABLE IF
+// NOT EXISTS leaves such tables as they are, so adopt brings them up to the
+// baseline before recording it.
+
+var (
+	createTable = regexp.MustCompile("^CREATE TABLE IF NOT EXISTS [`\"](\\w+)[`\"] \\($")
+	columnDef   = regexp.MustCompile("^[`\"](\\w+)[`\"] ")
+	// MySQL declares indexes inside CREATE TABLE
+	inlineIndex = regexp.MustCompile("^(UNIQUE )?INDEX `(\\w+)` (\\(.+\\))$")
+)
+
+type baselineColumn struct {
+	name string
+	def  string
+}
+
+type baselineIndex struct {
+	name   string
+	create string
+}
+
+type baselineTable struct {
+	name    string
+	columns []baselineColumn
+	indexes []baselineIndex
+}
+
+// parseBaseline reads the tables, columns and inline indexes of the
+// baseline script, which declares one column or constraint per line.
+func parseBaseline(script string) []baselineTable {
+	var tables []baselineTable
+	var cur *baselineTable
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if m := createTable.FindStringSubmatch(trimmed); m != nil {
+			tables = append(tables, baselineTable{name: m[1]})
+			cur = &tables[len(tables)-1]
+			continue
+		}
+		if cur == nil {
+			continue
+		}
+		if strings.HasPrefix(trimmed, ")") {
+			cur = nil
+		} else if m := columnDef.FindStringSubmatch(trimmed); m != nil {
+			cur.columns = append(cur.columns, baselineColumn{name: m[1], def: trimmed})
+		} else if m := inlineIndex.FindStringSubmatch(trimmed); m != nil {
+			cur.indexes = append(cur.indexes, baselineIndex{
+				name:   m[2],
+				create: fmt.Sprintf("CREATE %sINDEX `%s` ON `%s` %s", m[1], m[2], cur.name, m[3]),
+			})
+		}
+	}
+	return tables
+}
+
+// legacy reports whether the database has tables created before versioned
+// migrations; it is called only when no version is recorded.
+func (m *Migrator) legacy(ctx context.Context) bool {
+	return m.db.WithContext(ctx).Migrator().HasTable("users")
+}
+
+// adopt applies the baseline to a database created before versioned
+// migrations: it adds the columns the baseline has and the existing tables
+// lack, creates the missing tables and indexes, moves free-text parties into
+// the parties table and records the baseline only if every baseline column
+// then exists. Foreign keys the baseline declares are not added to tables
+// that already existed.
+func (m *Migrator) adopt(ctx context.Context, mig Migration) error {
+	m.logger.Info("Adopting a database created before versioned migrations", "version", mig.Version, "name", mig.Name)
+	db := m.db.WithContext(ctx)
+	tables := parseBaseline(mig.Up)
+
+	// Columns first, since the baseline's indexes may cover them
+	for _, t := range tables {
+		if !db.Migrator().HasTable(t.name) {
+			continue
+		}
+		for _, c := range t.columns {
+			if db.Migrator().HasColumn(t.name, c.name) {
+				continue
+			}
+			if err := db.Exec("ALTER TABLE " + m.quote(t.name) + " ADD COLUMN " + c.def).Error; err != nil {
+				return fmt.Errorf("adopt: add column %s.%s: %w", t.name, c.name, err)
+			}
+			m.logger.Info("Added missing column", "table", t.name, "column", c.name)
+		}
+	}
+
+	if err := m.exec(ctx, mig.Up); err != nil {
+		return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+	}
+	for _, t := range tables {
+		for _, idx := range t.indexes {
+			if db.Migrator().HasIndex(t.name, idx.name) {
+				continue
+			}
+			if err := db.Exec(idx.create).Error; err != nil {
+				return fmt.Errorf("adopt: create index %s: %w", idx.name, err)
+			}
+		}
+	}
+	if err := m.normalizeParties(db); err != nil {
+		return fmt.Errorf("adopt: move party names into parties: %w", err)
+	}
+
+	var missing []string
+	for _, t := range tables {
+		for _, c := range t.columns {
+			if !db.Migrator().HasColumn(t.name, c.name) {
+				missing = append(missing, t.name+"."+c.name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("database created before versioned migrations lacks %s; the baseline was not recorded",
+			strings.Join(missing, ", "))
+	}
+	return m.record(ctx, mig)
+}
+
+func (m *Migrator) quote(name string) string {
+	if m.dialect == "mysql" {
+		return "`" + name + "`"
+	}
+	return `"` + name + `"`
+}
+
+// normalizeParties moves the free-text party column of candidates and
+// nominations into the parties table. Names differing only in case or
+// whitespace become one party. The old column is dropped once every row has
+// its party_id.
+func (m *Migrator) normalizeParties(db *gorm.DB) error {
+	for _, table := range []string{"candidates", "nominations"} {
+		if !db.Migrator().HasColumn(table, "party") {
+			continue
+		}
+
+		var names []string
+		if err := db.Table(table).
+			Where("party IS NOT NULL AND party_id IS NULL").
+			Distinct().
+			Pluck("party", &names).Error; err != nil {
+			return err
+		}
+
+		for _, raw := range names {
+			name := domain.NormalizePartyName(raw)
+			if name == "" {
+				continue
+			}
+
+			party := domain.Party{Name: name, NameKey: domain.PartyNameKey(name)}
+			if err := db.Where(domain.Party{NameKey: party.NameKey}).FirstOrCreate(&party).Error; err != nil {
+				return err
+			}
+			if err := db.Table(table).
+				Where("party = ? AND party_id IS NULL", raw).
+				Update("party_id", party.ID).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := db.Exec("ALTER TABLE " + m.quote(table) + " DROP COLUMN " + m.quote("party")).Error; err != nil {
+			return err
+		}
+		m.logger.Info("Moved party names into the parties table", "table", table, "names", len(names))
+	}
+	return nil
+}
@@ -0,0 +1,401 @@
+package migrations
+
+import (
+	"VoteGolang/internals/app/logging"
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Versioned migrations live in sql/<dialect>/ as <version>_<name>.up.sql
+// and <version>_<name>.down.sql, one directory per database driver with the
+// same versions in each. Applied versions are recorded in schema_migrations.
+//
+//go:embed sql/*/*.sql
+var files embed.FS
+
+const (
+	versionTable = "schema_migrations"
+	// The migration lock is held while migrating, so replicas starting
+	// together apply each migration once: a named lock on MySQL, an
+	// advisory lock on PostgreSQL. SQLite has a single writer anyway.
+	lockName    = "vote_schema_migrations"
+	pgLockKey   = 0x766f7465 // "vote"
+	lockTimeout = 2 * time.Minute
+)
+
+// versionTableColumns are the columns of schema_migrations in each dialect.
+var versionTableColumns = map[string]string{
+	"mysql":    "`version` bigint unsigned NOT NULL PRIMARY KEY, `name` varchar(255) NOT NULL, `applied_at` datetime(3) NOT NULL",
+	"postgres": `"version" bigint NOT NULL PRIMARY KEY, "name" varchar(255) NOT NULL, "applied_at" timestamptz NOT NULL`,
+	"sqlite":   `"version" integer NOT NULL PRIMARY KEY, "name" varchar(255) NOT NULL, "applied_at" datetime NOT NULL`,
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ErrLocked is returned when another process held the migration lock for
+// longer than the lock timeout.
+var ErrLocked = errors.New("migration lock is held by another process")
+
+// Migration is one versioned schema change.
+type Migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration and when it was applied; AppliedAt is nil for
+// pending ones.
+type Status struct {
+	Version   uint
+	Name      string
+	AppliedAt *time.Time
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	dialect    string
+	migrations []Migration
+	logger     *slog.Logger
+}
+
+// NewMigrator loads the embedded migrations of the database's dialect.
+func NewMigrator(db *gorm.DB, logger *slog.Logger) (*Migrator, error) {
+	dialect := db.Dialector.Name()
+	if _, ok := versionTableColumns[dialect]; !ok {
+		return nil, fmt.Errorf("no migrations for the %s dialect", dialect)
+	}
+	migrations, err := load(files, path.Join("sql", dialect))
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, dialect: dialect, migrations: migrations, logger: logger}, nil
+}
+
+// load reads the migrations in dir of fsys, checking that every version has
+// both scripts.
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("migration %s: name must be <version>_<name>.(up|down).sql", e.Name())
+		}
+		v, err := strconv.ParseUint(m[1], 10, 32)
+		if err != nil || v == 0 {
+			return nil, fmt.Errorf("migration %s: invalid version", e.Name())
+		}
+		body, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		mig, ok := byVersion[uint(v)]
+		if !ok {
+			mig = &Migration{Version: uint(v), Name: m[2]}
+			byVersion[uint(v)] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d: names %q and %q differ", v, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s: needs both an up and a down script", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Migrate applies every pending migration; the service runs it at startup.
+func Migrate(ctx context.Context, db *gorm.DB, logger *slog.Logger) error {
+	m, err := NewMigrator(db, logger)
+	if err != nil {
+		return err
+	}
+	return m.Up(ctx)
+}
+
+// Latest is the version of the newest migration.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every pending migration.
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.To(ctx, m.Latest())
+}
+
+// Down reverts the last steps applied migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.locked(ctx, func(applied map[uint]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; !ok {
+				continue
+			}
+			if err := m.revert(ctx, mig); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// To migrates up or down until version is the last applied migration;
+// version 0 reverts everything.
+func (m *Migrator) To(ctx context.Context, version uint) error {
+	if version != 0 && !m.known(version) {
+		return fmt.Errorf("unknown migration version %d", version)
+	}
+	return m.locked(ctx, func(applied map[uint]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if _, ok := applied[mig.Version]; ok && mig.Version > version {
+				if err := m.revert(ctx, mig); err != nil {
+					return err
+				}
+			}
+		}
+		for i, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; !ok && mig.Version <= version {
+				apply := m.apply
+				if i == 0 && len(applied) == 0 && m.legacy(ctx) {
+					apply = m.adopt
+				}
+				if err := apply(ctx, mig); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// Status lists every migration, oldest first.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if err := m.createVersionTable(ctx); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := Status{Version: mig.Version, Name: mig.Name}
+		if at, ok := applied[mig.Version]; ok {
+			s.AppliedAt = &at
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, nil
+}
+
+func (m *Migrator) known(version uint) bool {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return true
+		}
+	}
+	return false
+}
+
+// locked runs fn while holding the migration lock, with the versions
+// applied when the lock was taken.
+func (m *Migrator) locked(ctx context.Context, fn func(applied map[uint]time.Time) error) error {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := m.createVersionTable(ctx); err != nil {
+		return err
+	}
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+// lock takes the migration lock and returns its release.
+func (m *Migrator) lock(ctx context.Context) (unlock func(), err error) {
+	if m.dialect == "sqlite" {
+		return func() {}, nil
+	}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// Named and advisory locks belong to a session, so take and release them
+	// on the same connection
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	release := "SELECT RELEASE_LOCK(?)"
+	releaseArg := any(lockName)
+	if m.dialect == "postgres" {
+		err = m.pgAdvisoryLock(ctx, conn)
+		release, releaseArg = "SELECT pg_advisory_unlock($1)", pgLockKey
+	} else {
+		var got sql.NullInt64
+		err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(lockTimeout.Seconds())).Scan(&got)
+		if err == nil && (!got.Valid || got.Int64 != 1) {
+			err = ErrLocked
+		}
+	}
+	if err != nil {
+		conn.Close()
+		if !errors.Is(err, ErrLocked) {
+			err = fmt.Errorf("take migration lock: %w", err)
+		}
+		return nil, err
+	}
+
+	return func() {
+		// The lock must be released even when ctx is done
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), release, releaseArg); err != nil {
+			m.logger.Warn("Failed to release migration lock", logging.Err(err))
+		}
+		conn.Close()
+	}, nil
+}
+
+// pgAdvisoryLock polls for the advisory lock, since pg_advisory_lock itself
+// would wait without a timeout.
+func (m *Migrator) pgAdvisoryLock(ctx context.Context, conn *sql.Conn) error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		var got bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", pgLockKey).Scan(&got); err != nil {
+			return err
+		}
+		if got {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return ErrLocked
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (m *Migrator) createVersionTable(ctx context.Context) error {
+	return m.db.WithContext(ctx).Exec("CREATE TABLE IF NOT EXISTS " + versionTable + " (" + versionTableColumns[m.dialect] + ")").Error
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[uint]time.Time, error) {
+	var rows []struct {
+		Version   uint
+		AppliedAt time.Time
+	}
+	if err := m.db.WithContext(ctx).Table(versionTable).Select("version, applied_at").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[uint]time.Time, len(rows))
+	for _, r := range rows {
+		applied[r.Version] = r.AppliedAt
+	}
+	return applied, nil
+}
+
+// Scripts do not run in a transaction, since MySQL commits DDL implicitly: a
+// migration that fails halfway is not rolled back, so fix the schema by hand,
+// then run it again.
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	m.logger.Info("Applying migration", "version", mig.Version, "name", mig.Name)
+	if err := m.exec(ctx, mig.Up); err != nil {
+		return fmt.Errorf("migration %d_%s up: %w", mig.Version, mig.Name, err)
+	}
+	return m.record(ctx, mig)
+}
+
+// record marks mig as applied.
+func (m *Migrator) record(ctx context.Context, mig Migration) error {
+	return m.db.WithContext(ctx).Exec(
+		"INSERT INTO "+versionTable+" (version, name, applied_at) VALUES (?, ?, ?)",
+		mig.Version, mig.Name, time.Now(),
+	).Error
+}
+
+func (m *Migrator) revert(ctx context.Context, mig Migration) error {
+	m.logger.Info("Reverting migration", "version", mig.Version, "name", mig.Name)
+	if err := m.exec(ctx, mig.Down); err != nil {
+		return fmt.Errorf("migration %d_%s down: %w", mig.Version, mig.Name, err)
+	}
+	return m.db.WithContext(ctx).Exec("DELETE FROM "+versionTable+" WHERE version = ?", mig.Version).Error
+}
+
+// exec runs the statements of a script one by one.
+func (m *Migrator) exec(ctx context.Context, script string) error {
+	for _, stmt := range splitStatements(script) {
+		if err := m.db.WithContext(ctx).Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitStatements splits a script at semicolons ending a line and drops
+// "--" comment lines. Statements must not contain such a semicolon inside
+// a string.
+func splitStatements(script string) []string {
+	var stmts []string
+	var cur strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		cur.WriteString(line)
+		cur.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			stmts = append(stmts, strings.TrimSuffix(strings.TrimSpace(cur.String()), ";"))
+			cur.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(cur.String()); rest != "" {
+		stmts = append(stmts, rest)
+	}
+	return stmts
+}
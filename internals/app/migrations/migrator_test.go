@@ -0,0 +1,192 @@
+package migrations
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestDialectsHaveTheSameVersions(t *testing.T) {
+	want, err := load(files, path.Join("sql", "mysql"))
+	if err != nil {
+		t.Fatalf("load mysql: %v", err)
+	}
+	for dialect := range versionTableColumns {
+		got, err := load(files, path.Join("sql", dialect))
+		if err != nil {
+			t.Fatalf("load %s: %v", dialect, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("%s has %d migrations, mysql has %d", dialect, len(got), len(want))
+		}
+		for i := range got {
+			if got[i].Version != want[i].Version || got[i].Name != want[i].Name {
+				t.Fatalf("%s migration %d_%s, mysql has %d_%s", dialect, got[i].Version, got[i].Name, want[i].Version, want[i].Name)
+			}
+		}
+	}
+}
+
+func newTestMigrator(t *testing.T) (*gorm.DB, *Migrator) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "vote.db")+"?_foreign_keys=on"),
+		&gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewMigrator(db, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, m
+}
+
+// legacySchema is the schema AutoMigrate made before versioned migrations,
+// when candidates kept their party as free text.
+const legacySchema = `
+CREATE TABLE "roles" ("id" integer, "name" varchar(50) NOT NULL, PRIMARY KEY ("id"));
+CREATE TABLE "users" (
+    "id" integer, "username" varchar(100) NOT NULL, "email" varchar(100) NOT NULL,
+    "password" varchar(255) NOT NULL, "role_id" integer NOT NULL,
+    "deleted_at" datetime, "created_at" datetime, "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE TABLE "candidates" (
+    "id" integer, "name" varchar(255) NOT NULL, "photo" varchar(255), "education" varchar(255),
+    "age" integer NOT NULL, "party" varchar(255), "region" varchar(255), "votes" integer DEFAULT 0,
+    "type" varchar(255) NOT NULL, "voting_start" datetime, "voting_deadline" datetime,
+    "deleted_at" datetime, "created_at" datetime, "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE TABLE "petitions" (
+    "id" integer, "user_id" integer NOT NULL, "title" varchar(255) NOT NULL, "photo" varchar(255),
+    "description" text, "votes_in_favor" integer DEFAULT 0, "votes_against" integer DEFAULT 0,
+    "goal" integer NOT NULL, "voting_deadline" datetime,
+    "deleted_at" datetime, "created_at" datetime, "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+INSERT INTO "candidates" ("name", "age", "party", "type") VALUES
+    ('A', 40, 'Jastar', 'presidential'), ('B', 41, 'jastar ', 'presidential'), ('C', 42, NULL, 'presidential');
+INSERT INTO "petitions" ("user_id", "title", "goal") VALUES (1, 'Parks', 100);
+`
+
+func appliedCount(t *testing.T, m *Migrator) int {
+	t.Helper()
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	applied := 0
+	for _, s := range statuses {
+		if s.AppliedAt != nil {
+			applied++
+		}
+	}
+	return applied
+}
+
+func TestUpAdoptsDatabasesCreatedBeforeVersionedMigrations(t *testing.T) {
+	db, m := newTestMigrator(t)
+	if err := m.exec(context.Background(), legacySchema); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(context.Background()); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	if n := appliedCount(t, m); n != len(m.migrations) {
+		t.Fatalf("%d migrations applied, want %d", n, len(m.migrations))
+	}
+	for _, column := range []string{"party_id", "photo_asset_id", "biography", "manifesto"} {
+		if !db.Migrator().HasColumn("candidates", column) {
+			t.Fatalf("candidates.%s was not added", column)
+		}
+	}
+	if db.Migrator().HasColumn("candidates", "party") {
+		t.Fatal("candidates.party was not dropped")
+	}
+
+	var parties []string
+	if err := db.Table("parties").Pluck("name", &parties).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(parties) != 1 || parties[0] != "Jastar" {
+		t.Fatalf("parties = %q, want one Jastar", parties)
+	}
+	var withParty, withoutParty int64
+	db.Table("candidates").Where("party_id IS NOT NULL").Count(&withParty)
+	db.Table("candidates").Where("party_id IS NULL").Count(&withoutParty)
+	if withParty != 2 || withoutParty != 1 {
+		t.Fatalf("candidates with a party = %d, without = %d; want 2 and 1", withParty, withoutParty)
+	}
+
+	var status string
+	if err := db.Table("petitions").Select("status").Row().Scan(&status); err != nil {
+		t.Fatal(err)
+	}
+	if status != "approved" {
+		t.Fatalf("legacy petition status = %q, want approved", status)
+	}
+}
+
+func TestUpRefusesToAdoptAMismatchedDatabase(t *testing.T) {
+	db, m := newTestMigrator(t)
+	// A NOT NULL column without a default cannot be added to existing rows
+	legacy := legacySchema + `
+CREATE TABLE "nominations" ("id" integer, "user_id" integer NOT NULL, "name" varchar(255) NOT NULL, PRIMARY KEY ("id"));
+INSERT INTO "nominations" ("user_id", "name") VALUES (1, 'A');
+`
+	if err := m.exec(context.Background(), legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := m.Up(context.Background()); err == nil {
+		t.Fatal("up adopted a database it could not bring to the baseline")
+	}
+	if n := appliedCount(t, m); n != 0 {
+		t.Fatalf("%d migrations recorded, want none", n)
+	}
+	if !db.Migrator().HasColumn("candidates", "party") {
+		t.Fatal("party names were moved although the baseline was refused")
+	}
+}
+
+// TestUpDownUp applies every SQLite migration, reverts them all and applies
+// them again, so each down script undoes its up script completely.
+func TestUpDownUp(t *testing.T) {
+	db, m := newTestMigrator(t)
+	ctx := context.Background()
+
+	for _, step := range []struct {
+		name    string
+		run     func() error
+		applied int
+	}{
+		{"up", func() error { return m.Up(ctx) }, len(m.migrations)},
+		{"to 0", func() error { return m.To(ctx, 0) }, 0},
+		{"up again", func() error { return m.Up(ctx) }, len(m.migrations)},
+		{"down 3", func() error { return m.Down(ctx, 3) }, len(m.migrations) - 3},
+	} {
+		if err := step.run(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if applied := appliedCount(t, m); applied != step.applied {
+			t.Fatalf("after %s: %d migrations applied, want %d", step.name, applied, step.applied)
+		}
+	}
+
+	var admins int64
+	if err := db.Table("users").Where("username = ?", "admin").Count(&admins).Error; err != nil {
+		t.Fatal(err)
+	}
+	if admins != 0 {
+		t.Fatal("down 3 left the seeded admin behind")
+	}
+}
@@ -1,10 +0,0 @@
-package result
-
-// Migration summary for access
-// Table: accesses
-// -----------------------------------
-// CREATE TABLE accesses (
-//   id uint PRIMARY KEY AUTO_INCREMENT,
-//   name varchar(50) NOT NULL UNIQUE,
-// );
-// -----------------------------------
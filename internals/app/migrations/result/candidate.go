@@ -1,22 +0,0 @@
-package result
-
-// Migration summary for candidate
-// Table: candidates
-// -----------------------------------
-// CREATE TABLE candidates (
-//   id uint PRIMARY KEY AUTO_INCREMENT,
-//   name varchar(255) NOT NULL,
-//   photo varchar(255),
-//   education varchar(255),
-//   age int NOT NULL,
-//   party varchar(255),
-//   region varchar(255),
-//   votes int DEFAULT '0',
-//   type varchar(255) NOT NULL,
-//   voting_start datetime,
-//   voting_deadline datetime,
-//   deleted_at time,
-//   created_at time,
-//   updated_at time,
-// );
-// -----------------------------------
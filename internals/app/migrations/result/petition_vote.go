@@ -1,15 +0,0 @@
-package result
-
-// Migration summary for petition_vote
-// Table: petition_votes
-// -----------------------------------
-// CREATE TABLE petition_votes (
-//   id uint PRIMARY KEY AUTO_INCREMENT,
-//   user_id uint NOT NULL,
-//   petition_id uint,
-//   vote_type varchar(255) NOT NULL,
-//   deleted_at time,
-//   created_at time,
-//   updated_at time,
-// );
-// -----------------------------------
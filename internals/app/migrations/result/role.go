@@ -1,11 +0,0 @@
-package result
-
-// Migration summary for role
-// Table: roles
-// -----------------------------------
-// CREATE TABLE roles (
-//   id uint PRIMARY KEY AUTO_INCREMENT,
-//   name varchar(50) NOT NULL UNIQUE,
-//    ,
-// );
-// -----------------------------------
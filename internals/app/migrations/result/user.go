@@ -1,21 +0,0 @@
-package result
-
-// Migration summary for user
-// Table: users
-// -----------------------------------
-// CREATE TABLE users (
-//   id uint PRIMARY KEY AUTO_INCREMENT,
-//   username varchar(100) NOT NULL UNIQUE,
-//   email varchar(100) NOT NULL UNIQUE,
-//   email_verified bool DEFAULT 'false',
-//   user_full_name varchar(110),
-//   password varchar(255) NOT NULL,
-//   birth_date time,
-//   address text,
-//   deleted_at time,
-//   created_at time,
-//   updated_at time,
-//   role_id uint NOT NULL,
-//    ,
-// );
-// -----------------------------------
@@ -1,15 +0,0 @@
-package result
-
-// Migration summary for petition
-// Table: votes
-// -----------------------------------
-// CREATE TABLE votes (
-//   id uint PRIMARY KEY AUTO_INCREMENT,
-//   user_id uint NOT NULL,
-//   candidate_id uint NOT NULL,
-//   candidate_type varchar(50),
-//   deleted_at time,
-//   created_at time,
-//   updated_at time,
-// );
-// -----------------------------------
@@ -0,0 +1,315 @@
+-- Baseline schema: the tables GORM AutoMigrate created before versioned
+-- migrations. IF NOT EXISTS lets databases created by AutoMigrate adopt it.
+
+CREATE TABLE IF NOT EXISTS `roles` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(50) NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_roles_name` UNIQUE (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `users` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `username` varchar(100) NOT NULL,
+    `email` varchar(100) NOT NULL,
+    `email_verified` boolean DEFAULT false,
+    `user_full_name` varchar(110),
+    `password` varchar(255) NOT NULL,
+    `birth_date` datetime(3) NULL,
+    `address` text,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    `role_id` bigint unsigned NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `fk_users_role` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`),
+    CONSTRAINT `uni_users_username` UNIQUE (`username`),
+    CONSTRAINT `uni_users_email` UNIQUE (`email`)
+);
+
+CREATE TABLE IF NOT EXISTS `assets` (
+    `id` varchar(36),
+    `user_id` bigint unsigned NOT NULL,
+    `storage_key` varchar(255) NOT NULL,
+    `thumbnail_key` varchar(255) NOT NULL,
+    `content_type` varchar(50) NOT NULL,
+    `size` bigint NOT NULL,
+    `width` bigint NOT NULL,
+    `height` bigint NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_assets_user_id` (`user_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `parties` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `name_key` varchar(255) NOT NULL,
+    `logo_asset_id` varchar(36),
+    `leader` varchar(255),
+    `description` text,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_parties_name_key` (`name_key`)
+);
+
+CREATE TABLE IF NOT EXISTS `candidates` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(255) NOT NULL,
+    `photo` varchar(255),
+    `photo_asset_id` varchar(36),
+    `education` varchar(255),
+    `age` bigint NOT NULL,
+    `party_id` bigint unsigned,
+    `region` varchar(255),
+    `biography` text,
+    `manifesto` text,
+    `votes` bigint DEFAULT 0,
+    `type` varchar(255) NOT NULL,
+    `voting_start` datetime,
+    `voting_deadline` datetime,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_candidates_party_id` (`party_id`),
+    CONSTRAINT `fk_candidates_party` FOREIGN KEY (`party_id`) REFERENCES `parties`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `social_links` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `candidate_id` bigint unsigned NOT NULL,
+    `platform` varchar(50) NOT NULL,
+    `url` varchar(255) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_social_links_candidate_id` (`candidate_id`),
+    CONSTRAINT `fk_candidates_social_links` FOREIGN KEY (`candidate_id`) REFERENCES `candidates`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `candidate_photos` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `candidate_id` bigint unsigned NOT NULL,
+    `url` varchar(255) NOT NULL,
+    `position` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_candidate_photos_candidate_id` (`candidate_id`),
+    CONSTRAINT `fk_candidates_photos` FOREIGN KEY (`candidate_id`) REFERENCES `candidates`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `manifesto_points` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `candidate_id` bigint unsigned NOT NULL,
+    `topic` varchar(50) NOT NULL,
+    `text` text NOT NULL,
+    `position` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    INDEX `idx_manifesto_points_candidate_id` (`candidate_id`),
+    INDEX `idx_manifesto_points_topic` (`topic`),
+    CONSTRAINT `fk_candidates_manifesto_points` FOREIGN KEY (`candidate_id`) REFERENCES `candidates`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `candidate_changes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `candidate_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `field` varchar(50) NOT NULL,
+    `old_value` text,
+    `new_value` text,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_candidate_changes_candidate_id` (`candidate_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `nominations` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `name` varchar(255) NOT NULL,
+    `photo_asset_id` varchar(36),
+    `education` varchar(255),
+    `age` bigint NOT NULL,
+    `party_id` bigint unsigned,
+    `region` varchar(255),
+    `biography` text,
+    `manifesto` text,
+    `type` varchar(255) NOT NULL,
+    `voting_start` datetime,
+    `voting_deadline` datetime,
+    `status` varchar(20) NOT NULL DEFAULT 'collecting',
+    `endorsements` bigint DEFAULT 0,
+    `required_endorsements` bigint NOT NULL,
+    `reject_reason` text,
+    `reviewer_id` bigint unsigned,
+    `reviewed_at` datetime(3) NULL,
+    `candidate_id` bigint unsigned,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_nominations_user_id` (`user_id`),
+    INDEX `idx_nominations_party_id` (`party_id`),
+    INDEX `idx_nominations_type` (`type`),
+    INDEX `idx_nominations_status` (`status`),
+    INDEX `idx_nominations_candidate_id` (`candidate_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `nomination_documents` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `nomination_id` bigint unsigned NOT NULL,
+    `title` varchar(255) NOT NULL,
+    `asset_id` varchar(36) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_nomination_documents_nomination_id` (`nomination_id`),
+    CONSTRAINT `fk_nominations_documents` FOREIGN KEY (`nomination_id`) REFERENCES `nominations`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `nomination_endorsements` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `nomination_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_nomination_user` (`nomination_id`,`user_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `tags` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(50) NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_tags_name` UNIQUE (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `petitions` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `title` varchar(255) NOT NULL,
+    `photo` varchar(255),
+    `photo_asset_id` varchar(36),
+    `description` text,
+    `votes_in_favor` bigint DEFAULT 0,
+    `votes_against` bigint DEFAULT 0,
+    `goal` bigint NOT NULL,
+    `category` varchar(50) NOT NULL DEFAULT 'other',
+    `status` varchar(20) NOT NULL DEFAULT 'approved',
+    `reject_reason` text,
+    `voting_deadline` datetime,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_petitions_category` (`category`),
+    INDEX `idx_petitions_status` (`status`)
+);
+
+CREATE TABLE IF NOT EXISTS `petition_tags` (
+    `petition_id` bigint unsigned,
+    `tag_id` bigint unsigned,
+    PRIMARY KEY (`petition_id`,`tag_id`),
+    CONSTRAINT `fk_petition_tags_petition` FOREIGN KEY (`petition_id`) REFERENCES `petitions`(`id`),
+    CONSTRAINT `fk_petition_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `petition_votes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `petition_id` bigint unsigned NOT NULL,
+    `vote_type` varchar(255) NOT NULL,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_user_petition` (`user_id`,`petition_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `petition_moderations` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `petition_id` bigint unsigned NOT NULL,
+    `moderator_id` bigint unsigned,
+    `action` varchar(20) NOT NULL,
+    `reason` text,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_petition_moderations_petition_id` (`petition_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `petition_comments` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `petition_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `parent_id` bigint unsigned,
+    `body` text NOT NULL,
+    `hidden` boolean DEFAULT false,
+    `edited_at` datetime(3) NULL,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_petition_comments_petition_id` (`petition_id`),
+    INDEX `idx_petition_comments_user_id` (`user_id`),
+    INDEX `idx_petition_comments_parent_id` (`parent_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `comment_reports` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `comment_id` bigint unsigned NOT NULL,
+    `user_id` bigint unsigned NOT NULL,
+    `reason` varchar(255) NOT NULL,
+    `created_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_comment_reporter` (`comment_id`,`user_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `votes` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `user_id` bigint unsigned NOT NULL,
+    `candidate_id` bigint unsigned NOT NULL,
+    `candidate_type` varchar(50) NOT NULL,
+    `deleted_at` datetime(3) NULL,
+    `created_at` datetime(3) NULL,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_user_candidate_type` (`user_id`)
+);
+
+CREATE TABLE IF NOT EXISTS `accesses` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `name` varchar(50) NOT NULL,
+    PRIMARY KEY (`id`),
+    CONSTRAINT `uni_accesses_name` UNIQUE (`name`)
+);
+
+CREATE TABLE IF NOT EXISTS `role_access` (
+    `role_id` bigint unsigned,
+    `access_id` bigint unsigned,
+    PRIMARY KEY (`role_id`,`access_id`),
+    CONSTRAINT `fk_role_access_role` FOREIGN KEY (`role_id`) REFERENCES `roles`(`id`),
+    CONSTRAINT `fk_role_access_access` FOREIGN KEY (`access_id`) REFERENCES `accesses`(`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `outbox_messages` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `event_id` varchar(36) NOT NULL,
+    `topic` varchar(100) NOT NULL,
+    `message_key` varchar(100) NOT NULL,
+    `event_type` varchar(50) NOT NULL,
+    `payload` longblob NOT NULL,
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `last_error` text,
+    `created_at` datetime(3) NULL,
+    `published_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_outbox_messages_event_id` (`event_id`),
+    INDEX `idx_outbox_messages_published_at` (`published_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `chain_log_entries` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `action` varchar(50) NOT NULL,
+    `payload` longblob NOT NULL,
+    `attempts` bigint NOT NULL DEFAULT 0,
+    `last_error` text,
+    `created_at` datetime(3) NULL,
+    `gave_up_at` datetime(3) NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_chain_log_entries_gave_up_at` (`gave_up_at`)
+);
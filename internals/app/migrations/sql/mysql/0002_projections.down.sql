@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS `processed_events`;
+DROP TABLE IF EXISTS `election_turnouts`;
+DROP TABLE IF EXISTS `vote_tally_hourlies`;
@@ -0,0 +1,26 @@
+-- Projections maintained by cmd/projector.
+
+CREATE TABLE IF NOT EXISTS `vote_tally_hourlies` (
+    `id` bigint unsigned AUTO_INCREMENT,
+    `candidate_type` varchar(50) NOT NULL,
+    `candidate_id` bigint unsigned NOT NULL,
+    `region` varchar(255) NOT NULL DEFAULT '',
+    `hour` datetime NOT NULL,
+    `votes` bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (`id`),
+    UNIQUE INDEX `idx_vote_tally_bucket` (`candidate_type`,`candidate_id`,`region`,`hour`)
+);
+
+CREATE TABLE IF NOT EXISTS `election_turnouts` (
+    `candidate_type` varchar(50),
+    `voters` bigint NOT NULL DEFAULT 0,
+    `updated_at` datetime(3) NULL,
+    PRIMARY KEY (`candidate_type`)
+);
+
+CREATE TABLE IF NOT EXISTS `processed_events` (
+    `consumer` varchar(50),
+    `event_id` varchar(36),
+    `processed_at` datetime(3) NULL,
+    PRIMARY KEY (`consumer`,`event_id`)
+);
@@ -0,0 +1,15 @@
+-- Fails while users still have one of the seeded roles.
+
+DELETE ra FROM `role_access` ra JOIN `roles` r ON r.`id` = ra.`role_id`
+WHERE r.`name` IN ('admin', 'member', 'moderator', 'election_official', 'guest');
+
+DELETE FROM `roles` WHERE `name` IN ('admin', 'member', 'moderator', 'election_official', 'guest');
+
+DELETE FROM `accesses` WHERE `name` IN (
+    'create_candidate', 'read_candidate', 'update_candidate',
+    'delete_candidate', 'create_user', 'read_user', 'update_user',
+    'delete_user', 'create_petition', 'read_petition', 'update_petition',
+    'delete_petition', 'vote', 'moderate_petition', 'read_moderation_log',
+    'merge_petition', 'comment', 'moderate_comment', 'upload_media',
+    'nominate', 'endorse_nomination', 'review_nomination', 'manage_party'
+);
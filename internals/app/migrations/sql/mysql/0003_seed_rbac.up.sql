@@ -0,0 +1,67 @@
+-- Roles and accesses. Reruns are no-ops; later permission changes belong in
+-- new migrations.
+
+INSERT IGNORE INTO `accesses` (`name`) VALUES
+    ('create_candidate'),
+    ('read_candidate'),
+    ('update_candidate'),
+    ('delete_candidate'),
+    ('create_user'),
+    ('read_user'),
+    ('update_user'),
+    ('delete_user'),
+    ('create_petition'),
+    ('read_petition'),
+    ('update_petition'),
+    ('delete_petition'),
+    ('vote'),
+    ('moderate_petition'),
+    ('read_moderation_log'),
+    ('merge_petition'),
+    ('comment'),
+    ('moderate_comment'),
+    ('upload_media'),
+    ('nominate'),
+    ('endorse_nomination'),
+    ('review_nomination'),
+    ('manage_party');
+
+INSERT IGNORE INTO `roles` (`name`) VALUES
+    ('admin'),
+    ('member'),
+    ('moderator'),
+    ('election_official'),
+    ('guest');
+
+INSERT IGNORE INTO `role_access` (`role_id`, `access_id`)
+SELECT r.`id`, a.`id` FROM `roles` r JOIN `accesses` a
+WHERE r.`name` = 'admin' AND a.`name` IN (
+    'create_candidate', 'read_candidate', 'update_candidate',
+    'delete_candidate', 'create_petition', 'read_petition', 'update_petition',
+    'delete_petition', 'moderate_petition', 'read_moderation_log',
+    'merge_petition', 'comment', 'moderate_comment', 'upload_media',
+    'nominate', 'endorse_nomination', 'review_nomination', 'manage_party'
+);
+
+INSERT IGNORE INTO `role_access` (`role_id`, `access_id`)
+SELECT r.`id`, a.`id` FROM `roles` r JOIN `accesses` a
+WHERE r.`name` = 'member' AND a.`name` IN (
+    'read_candidate', 'vote', 'create_petition', 'read_petition',
+    'update_petition', 'delete_petition', 'comment', 'upload_media',
+    'nominate', 'endorse_nomination'
+);
+
+INSERT IGNORE INTO `role_access` (`role_id`, `access_id`)
+SELECT r.`id`, a.`id` FROM `roles` r JOIN `accesses` a
+WHERE r.`name` = 'moderator' AND a.`name` IN (
+    'read_candidate', 'vote', 'create_petition', 'read_petition',
+    'moderate_petition', 'read_moderation_log', 'comment', 'moderate_comment',
+    'upload_media', 'nominate', 'endorse_nomination'
+);
+
+INSERT IGNORE INTO `role_access` (`role_id`, `access_id`)
+SELECT r.`id`, a.`id` FROM `roles` r JOIN `accesses` a
+WHERE r.`name` = 'election_official' AND a.`name` IN (
+    'read_candidate', 'update_candidate', 'vote', 'read_petition',
+    'endorse_nomination', 'review_nomination', 'manage_party'
+);
@@ -0,0 +1,5 @@
+-- Default administrator, password admin123. Change it after the first login.
+
+INSERT IGNORE INTO `users` (`username`, `email`, `password`, `role_id`, `email_verified`, `created_at`, `updated_at`)
+SELECT 'admin', 'admin@example.com', '$2a$14$hBisEKSkRlWKSbVy.uJnPes4.H65oI5qYzV989rY.0.w.3eKA020.', `id`, true, NOW(3), NOW(3)
+FROM `roles` WHERE `name` = 'admin';
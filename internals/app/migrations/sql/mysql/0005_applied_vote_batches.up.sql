@@ -0,0 +1,8 @@
+-- Write-behind vote count batches already added to the candidates.
+
+CREATE TABLE IF NOT EXISTS `applied_vote_batches` (
+    `id` varchar(36),
+    `applied_at` datetime(3) NOT NULL,
+    PRIMARY KEY (`id`),
+    INDEX `idx_applied_vote_batches_applied_at` (`applied_at`)
+);
@@ -0,0 +1,24 @@
+DROP TABLE IF EXISTS "chain_log_entries";
+DROP TABLE IF EXISTS "outbox_messages";
+DROP TABLE IF EXISTS "role_access";
+DROP TABLE IF EXISTS "accesses";
+DROP TABLE IF EXISTS "votes";
+DROP TABLE IF EXISTS "comment_reports";
+DROP TABLE IF EXISTS "petition_comments";
+DROP TABLE IF EXISTS "petition_moderations";
+DROP TABLE IF EXISTS "petition_votes";
+DROP TABLE IF EXISTS "petition_tags";
+DROP TABLE IF EXISTS "petitions";
+DROP TABLE IF EXISTS "tags";
+DROP TABLE IF EXISTS "nomination_endorsements";
+DROP TABLE IF EXISTS "nomination_documents";
+DROP TABLE IF EXISTS "nominations";
+DROP TABLE IF EXISTS "candidate_changes";
+DROP TABLE IF EXISTS "manifesto_points";
+DROP TABLE IF EXISTS "candidate_photos";
+DROP TABLE IF EXISTS "social_links";
+DROP TABLE IF EXISTS "candidates";
+DROP TABLE IF EXISTS "parties";
+DROP TABLE IF EXISTS "assets";
+DROP TABLE IF EXISTS "users";
+DROP TABLE IF EXISTS "roles";
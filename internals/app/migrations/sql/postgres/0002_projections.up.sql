@@ -0,0 +1,26 @@
+-- Projections maintained by cmd/projector.
+
+CREATE TABLE IF NOT EXISTS "vote_tally_hourlies" (
+    "id" bigserial,
+    "candidate_type" varchar(50) NOT NULL,
+    "candidate_id" bigint NOT NULL,
+    "region" varchar(255) NOT NULL DEFAULT '',
+    "hour" timestamptz NOT NULL,
+    "votes" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_vote_tally_bucket" ON "vote_tally_hourlies" ("candidate_type","candidate_id","region","hour");
+
+CREATE TABLE IF NOT EXISTS "election_turnouts" (
+    "candidate_type" varchar(50),
+    "voters" bigint NOT NULL DEFAULT 0,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("candidate_type")
+);
+
+CREATE TABLE IF NOT EXISTS "processed_events" (
+    "consumer" varchar(50),
+    "event_id" varchar(36),
+    "processed_at" timestamptz,
+    PRIMARY KEY ("consumer","event_id")
+);
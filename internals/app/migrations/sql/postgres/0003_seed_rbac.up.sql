@@ -0,0 +1,73 @@
+-- Roles and accesses, as in mysql/0003_seed_rbac. Later permission changes
+-- belong in new migrations.
+
+INSERT INTO accesses (name) VALUES
+    ('create_candidate'),
+    ('read_candidate'),
+    ('update_candidate'),
+    ('delete_candidate'),
+    ('create_user'),
+    ('read_user'),
+    ('update_user'),
+    ('delete_user'),
+    ('create_petition'),
+    ('read_petition'),
+    ('update_petition'),
+    ('delete_petition'),
+    ('vote'),
+    ('moderate_petition'),
+    ('read_moderation_log'),
+    ('merge_petition'),
+    ('comment'),
+    ('moderate_comment'),
+    ('upload_media'),
+    ('nominate'),
+    ('endorse_nomination'),
+    ('review_nomination'),
+    ('manage_party')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO roles (name) VALUES
+    ('admin'),
+    ('member'),
+    ('moderator'),
+    ('election_official'),
+    ('guest')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_access (role_id, access_id)
+SELECT r.id, a.id FROM roles r CROSS JOIN accesses a
+WHERE r.name = 'admin' AND a.name IN (
+    'create_candidate', 'read_candidate', 'update_candidate',
+    'delete_candidate', 'create_petition', 'read_petition', 'update_petition',
+    'delete_petition', 'moderate_petition', 'read_moderation_log',
+    'merge_petition', 'comment', 'moderate_comment', 'upload_media',
+    'nominate', 'endorse_nomination', 'review_nomination', 'manage_party'
+)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_access (role_id, access_id)
+SELECT r.id, a.id FROM roles r CROSS JOIN accesses a
+WHERE r.name = 'member' AND a.name IN (
+    'read_candidate', 'vote', 'create_petition', 'read_petition',
+    'update_petition', 'delete_petition', 'comment', 'upload_media',
+    'nominate', 'endorse_nomination'
+)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_access (role_id, access_id)
+SELECT r.id, a.id FROM roles r CROSS JOIN accesses a
+WHERE r.name = 'moderator' AND a.name IN (
+    'read_candidate', 'vote', 'create_petition', 'read_petition',
+    'moderate_petition', 'read_moderation_log', 'comment', 'moderate_comment',
+    'upload_media', 'nominate', 'endorse_nomination'
+)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO role_access (role_id, access_id)
+SELECT r.id, a.id FROM roles r CROSS JOIN accesses a
+WHERE r.name = 'election_official' AND a.name IN (
+    'read_candidate', 'update_candidate', 'vote', 'read_petition',
+    'endorse_nomination', 'review_nomination', 'manage_party'
+)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,6 @@
+-- Outbox messages Kafka itself rejected: the relay gives up on a message
+-- after too many rejections and no longer retries it.
+
+ALTER TABLE "outbox_messages" ADD COLUMN IF NOT EXISTS "rejections" bigint NOT NULL DEFAULT 0;
+ALTER TABLE "outbox_messages" ADD COLUMN IF NOT EXISTS "gave_up_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_outbox_messages_gave_up_at" ON "outbox_messages" ("gave_up_at");
@@ -0,0 +1,314 @@
+-- Baseline schema, the SQLite version of mysql/0001_baseline.
+
+CREATE TABLE IF NOT EXISTS "roles" (
+    "id" integer,
+    "name" varchar(50) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_roles_name" UNIQUE ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" integer,
+    "username" varchar(100) NOT NULL,
+    "email" varchar(100) NOT NULL,
+    "email_verified" numeric DEFAULT false,
+    "user_full_name" varchar(110),
+    "password" varchar(255) NOT NULL,
+    "birth_date" datetime,
+    "address" text,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "role_id" integer NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"),
+    CONSTRAINT "uni_users_email" UNIQUE ("email"),
+    CONSTRAINT "uni_users_username" UNIQUE ("username")
+);
+
+CREATE TABLE IF NOT EXISTS "assets" (
+    "id" varchar(36),
+    "user_id" integer NOT NULL,
+    "storage_key" varchar(255) NOT NULL,
+    "thumbnail_key" varchar(255) NOT NULL,
+    "content_type" varchar(50) NOT NULL,
+    "size" integer NOT NULL,
+    "width" integer NOT NULL,
+    "height" integer NOT NULL,
+    "created_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_assets_user_id" ON "assets" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "parties" (
+    "id" integer,
+    "name" varchar(255) NOT NULL,
+    "name_key" varchar(255) NOT NULL,
+    "logo_asset_id" varchar(36),
+    "leader" varchar(255),
+    "description" text,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_parties_name_key" ON "parties" ("name_key");
+
+CREATE TABLE IF NOT EXISTS "candidates" (
+    "id" integer,
+    "name" varchar(255) NOT NULL,
+    "photo" varchar(255),
+    "photo_asset_id" varchar(36),
+    "education" varchar(255),
+    "age" integer NOT NULL,
+    "party_id" integer,
+    "region" varchar(255),
+    "biography" text,
+    "manifesto" text,
+    "votes" integer DEFAULT 0,
+    "type" varchar(255) NOT NULL,
+    "voting_start" datetime,
+    "voting_deadline" datetime,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_candidates_party" FOREIGN KEY ("party_id") REFERENCES "parties"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_candidates_party_id" ON "candidates" ("party_id");
+
+CREATE TABLE IF NOT EXISTS "social_links" (
+    "id" integer,
+    "candidate_id" integer NOT NULL,
+    "platform" varchar(50) NOT NULL,
+    "url" varchar(255) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_candidates_social_links" FOREIGN KEY ("candidate_id") REFERENCES "candidates"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_social_links_candidate_id" ON "social_links" ("candidate_id");
+
+CREATE TABLE IF NOT EXISTS "candidate_photos" (
+    "id" integer,
+    "candidate_id" integer NOT NULL,
+    "url" varchar(255) NOT NULL,
+    "position" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_candidates_photos" FOREIGN KEY ("candidate_id") REFERENCES "candidates"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_candidate_photos_candidate_id" ON "candidate_photos" ("candidate_id");
+
+CREATE TABLE IF NOT EXISTS "manifesto_points" (
+    "id" integer,
+    "candidate_id" integer NOT NULL,
+    "topic" varchar(50) NOT NULL,
+    "text" text NOT NULL,
+    "position" integer NOT NULL DEFAULT 0,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_candidates_manifesto_points" FOREIGN KEY ("candidate_id") REFERENCES "candidates"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_manifesto_points_topic" ON "manifesto_points" ("topic");
+CREATE INDEX IF NOT EXISTS "idx_manifesto_points_candidate_id" ON "manifesto_points" ("candidate_id");
+
+CREATE TABLE IF NOT EXISTS "candidate_changes" (
+    "id" integer,
+    "candidate_id" integer NOT NULL,
+    "user_id" integer NOT NULL,
+    "field" varchar(50) NOT NULL,
+    "old_value" text,
+    "new_value" text,
+    "created_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_candidate_changes_candidate_id" ON "candidate_changes" ("candidate_id");
+
+CREATE TABLE IF NOT EXISTS "nominations" (
+    "id" integer,
+    "user_id" integer NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "photo_asset_id" varchar(36),
+    "education" varchar(255),
+    "age" integer NOT NULL,
+    "party_id" integer,
+    "region" varchar(255),
+    "biography" text,
+    "manifesto" text,
+    "type" varchar(255) NOT NULL,
+    "voting_start" datetime,
+    "voting_deadline" datetime,
+    "status" varchar(20) NOT NULL DEFAULT "collecting",
+    "endorsements" integer DEFAULT 0,
+    "required_endorsements" integer NOT NULL,
+    "reject_reason" text,
+    "reviewer_id" integer,
+    "reviewed_at" datetime,
+    "candidate_id" integer,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_nominations_candidate_id" ON "nominations" ("candidate_id");
+CREATE INDEX IF NOT EXISTS "idx_nominations_status" ON "nominations" ("status");
+CREATE INDEX IF NOT EXISTS "idx_nominations_type" ON "nominations" ("type");
+CREATE INDEX IF NOT EXISTS "idx_nominations_party_id" ON "nominations" ("party_id");
+CREATE INDEX IF NOT EXISTS "idx_nominations_user_id" ON "nominations" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "nomination_documents" (
+    "id" integer,
+    "nomination_id" integer NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "asset_id" varchar(36) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_nominations_documents" FOREIGN KEY ("nomination_id") REFERENCES "nominations"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_nomination_documents_nomination_id" ON "nomination_documents" ("nomination_id");
+
+CREATE TABLE IF NOT EXISTS "nomination_endorsements" (
+    "id" integer,
+    "nomination_id" integer NOT NULL,
+    "user_id" integer NOT NULL,
+    "created_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_nomination_user" ON "nomination_endorsements" ("nomination_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "tags" (
+    "id" integer,
+    "name" varchar(50) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_tags_name" UNIQUE ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "petitions" (
+    "id" integer,
+    "user_id" integer NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "photo" varchar(255),
+    "photo_asset_id" varchar(36),
+    "description" text,
+    "votes_in_favor" integer DEFAULT 0,
+    "votes_against" integer DEFAULT 0,
+    "goal" integer NOT NULL,
+    "category" varchar(50) NOT NULL DEFAULT "other",
+    "status" varchar(20) NOT NULL DEFAULT "approved",
+    "reject_reason" text,
+    "voting_deadline" datetime,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_petitions_status" ON "petitions" ("status");
+CREATE INDEX IF NOT EXISTS "idx_petitions_category" ON "petitions" ("category");
+
+CREATE TABLE IF NOT EXISTS "petition_tags" (
+    "petition_id" integer,
+    "tag_id" integer,
+    PRIMARY KEY ("petition_id","tag_id"),
+    CONSTRAINT "fk_petition_tags_petition" FOREIGN KEY ("petition_id") REFERENCES "petitions"("id"),
+    CONSTRAINT "fk_petition_tags_tag" FOREIGN KEY ("tag_id") REFERENCES "tags"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "petition_votes" (
+    "id" integer,
+    "user_id" integer NOT NULL,
+    "petition_id" integer NOT NULL,
+    "vote_type" varchar(255) NOT NULL,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_petition" ON "petition_votes" ("user_id","petition_id");
+
+CREATE TABLE IF NOT EXISTS "petition_moderations" (
+    "id" integer,
+    "petition_id" integer NOT NULL,
+    "moderator_id" integer,
+    "action" varchar(20) NOT NULL,
+    "reason" text,
+    "created_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_petition_moderations_petition_id" ON "petition_moderations" ("petition_id");
+
+CREATE TABLE IF NOT EXISTS "petition_comments" (
+    "id" integer,
+    "petition_id" integer NOT NULL,
+    "user_id" integer NOT NULL,
+    "parent_id" integer,
+    "body" text NOT NULL,
+    "hidden" numeric DEFAULT false,
+    "edited_at" datetime,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_petition_comments_parent_id" ON "petition_comments" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_petition_comments_user_id" ON "petition_comments" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_petition_comments_petition_id" ON "petition_comments" ("petition_id");
+
+CREATE TABLE IF NOT EXISTS "comment_reports" (
+    "id" integer,
+    "comment_id" integer NOT NULL,
+    "user_id" integer NOT NULL,
+    "reason" varchar(255) NOT NULL,
+    "created_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_comment_reporter" ON "comment_reports" ("comment_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "votes" (
+    "id" integer,
+    "user_id" integer NOT NULL,
+    "candidate_id" integer NOT NULL,
+    "candidate_type" varchar(50) NOT NULL,
+    "deleted_at" datetime,
+    "created_at" datetime,
+    "updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_user_candidate_type" ON "votes" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "accesses" (
+    "id" integer,
+    "name" varchar(50) NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "uni_accesses_name" UNIQUE ("name")
+);
+
+CREATE TABLE IF NOT EXISTS "role_access" (
+    "role_id" integer,
+    "access_id" integer,
+    PRIMARY KEY ("role_id","access_id"),
+    CONSTRAINT "fk_role_access_role" FOREIGN KEY ("role_id") REFERENCES "roles"("id"),
+    CONSTRAINT "fk_role_access_access" FOREIGN KEY ("access_id") REFERENCES "accesses"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "outbox_messages" (
+    "id" integer,
+    "event_id" varchar(36) NOT NULL,
+    "topic" varchar(100) NOT NULL,
+    "message_key" varchar(100) NOT NULL,
+    "event_type" varchar(50) NOT NULL,
+    "payload" blob NOT NULL,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text,
+    "created_at" datetime,
+    "published_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_messages_published_at" ON "outbox_messages" ("published_at");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_outbox_messages_event_id" ON "outbox_messages" ("event_id");
+
+CREATE TABLE IF NOT EXISTS "chain_log_entries" (
+    "id" integer,
+    "action" varchar(50) NOT NULL,
+    "payload" blob NOT NULL,
+    "attempts" integer NOT NULL DEFAULT 0,
+    "last_error" text,
+    "created_at" datetime,
+    "gave_up_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_chain_log_entries_gave_up_at" ON "chain_log_entries" ("gave_up_at");
@@ -0,0 +1,6 @@
+-- Default administrator, password admin123. Change it after the first login.
+
+INSERT INTO users (username, email, password, role_id, email_verified, created_at, updated_at)
+SELECT 'admin', 'admin@example.com', '$2a$14$hBisEKSkRlWKSbVy.uJnPes4.H65oI5qYzV989rY.0.w.3eKA020.', id, true, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP
+FROM roles WHERE name = 'admin'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "idx_outbox_messages_gave_up_at";
+ALTER TABLE "outbox_messages" DROP COLUMN "gave_up_at";
+ALTER TABLE "outbox_messages" DROP COLUMN "rejections";
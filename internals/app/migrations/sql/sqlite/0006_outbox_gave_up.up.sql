@@ -0,0 +1,6 @@
+-- Outbox messages Kafka itself rejected: the relay gives up on a message
+-- after too many rejections and no longer retries it.
+
+ALTER TABLE "outbox_messages" ADD COLUMN "rejections" integer NOT NULL DEFAULT 0;
+ALTER TABLE "outbox_messages" ADD COLUMN "gave_up_at" datetime;
+CREATE INDEX IF NOT EXISTS "idx_outbox_messages_gave_up_at" ON "outbox_messages" ("gave_up_at");
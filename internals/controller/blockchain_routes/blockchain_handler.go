@@ -15,7 +15,7 @@ func NewBlockchainHandler(bc service.BlockchainService) *BlockchainHandler {
 }
 
 func (h *BlockchainHandler) GetBlockchainInfo(w http.ResponseWriter, r *http.Request) {
-	info, err := h.Blockchain.GetServiceInfo()
+	info, err := h.Blockchain.GetServiceInfo(r.Context())
 	if err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to get blockchain service info", err.Error())
 		return
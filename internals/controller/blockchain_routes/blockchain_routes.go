@@ -1,9 +1,13 @@
 package blockchain_routes
 
 import (
+	http2 "VoteGolang/internals/controller/http"
 	"net/http"
 )
 
 func RegisterBlockchainRoutes(mux *http.ServeMux, handler *BlockchainHandler) {
-	mux.HandleFunc("/blockchain", handler.GetBlockchainInfo)
+	mux.HandleFunc("/blockchain", func(w http.ResponseWriter, r *http.Request) {
+		http2.SetRoute(r, "/blockchain")
+		handler.GetBlockchainInfo(w, r)
+	})
 }
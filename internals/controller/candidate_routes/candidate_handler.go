@@ -1,23 +1,26 @@
 package candidate_routes
 
 import (
-	"VoteGolang/internals/app/logging"
 	http2 "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/controller/http/response"
 	candidate_data2 "VoteGolang/internals/domain"
 	"VoteGolang/internals/usecases/candidate_usecase"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
+	"gorm.io/gorm"
 )
 
 type CandidateHandler struct {
 	UseCase      *candidate_usecase.CandidateUseCase
 	TokenManager *candidate_data2.JwtToken
-	KafkaLogger  *logging.KafkaLogger
+	Logger       *slog.Logger
 }
 
 type IDRequest struct {
@@ -36,11 +39,11 @@ type candidatesByPageRequest struct {
 	Limit int    `json:"limit" example:"10"`
 }
 
-func NewCandidateHandler(useCase *candidate_usecase.CandidateUseCase, tokenManager *candidate_data2.JwtToken, kafkaLogger *logging.KafkaLogger) *CandidateHandler {
+func NewCandidateHandler(useCase *candidate_usecase.CandidateUseCase, tokenManager *candidate_data2.JwtToken, logger *slog.Logger) *CandidateHandler {
 	return &CandidateHandler{
 		UseCase:      useCase,
 		TokenManager: tokenManager,
-		KafkaLogger:  kafkaLogger,
+		Logger:       logger,
 	}
 }
 
@@ -75,7 +78,7 @@ func (h *CandidateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Вызываем use case
-	candidates, err := h.UseCase.GetAllByType(req.Type)
+	candidates, err := h.UseCase.GetAllByType(r.Context(), req.Type)
 	if err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to get candidates", err.Error())
 		return
@@ -84,52 +87,6 @@ func (h *CandidateHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, true, "Candidates retrieved successfully", candidates)
 }
 
-// @Summary Create a candidate
-// @Tags Candidates
-// @Accept json
-// @Produce json
-// @Param candidate body candidate_data2.Candidate true "Candidate data"
-// @Security BearerAuth
-// @Success 201 {object} candidate_data2.Candidate "Candidate created successfully"
-// @Failure 400 {object} response.JSONResponse "Bad Request"
-// @Failure 401 {object} response.JSONResponse "Unauthorized"
-// @Failure 500 {object} response.JSONResponse "Internal Server Error"
-// @Router /candidate/create [post]
-func (h *CandidateHandler) CreateCandidate(w http.ResponseWriter, r *http.Request) {
-	token, err := http2.ExtractTokenFromRequest(r)
-	if err != nil {
-		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, missing tokens: "+err.Error(), nil)
-		return
-	}
-	payload := &candidate_data2.JwtClaims{}
-	_, err = jwt.ParseWithClaims(token, payload, func(t *jwt.Token) (interface{}, error) {
-		return h.TokenManager.Secret, nil
-	})
-
-	if err != nil {
-		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, invalid tokens: "+err.Error(), nil)
-		return
-	}
-	var candidate candidate_data2.Candidate
-	if err := json.NewDecoder(r.Body).Decode(&candidate); err != nil {
-		response.JSON(w, http.StatusBadRequest, false, "Invalid request: "+err.Error(), nil)
-		return
-	}
-
-	if candidate.Type == "" || candidate.Name == "" {
-		response.JSON(w, http.StatusBadRequest, false, "Missing candidate fields", nil)
-		return
-	}
-
-	err = h.UseCase.CreateCandidate(&candidate)
-	if err != nil {
-		response.JSON(w, http.StatusInternalServerError, false, "Failed to create candidate: "+err.Error(), nil)
-		return
-	}
-
-	response.JSON(w, http.StatusCreated, true, "Candidate created successfully", candidate)
-}
-
 // @Summary Get candidates by type by page
 // @Tags Candidates
 // @Produce json
@@ -172,7 +129,7 @@ func (h *CandidateHandler) GetCandidatesByPage(w http.ResponseWriter, r *http.Re
 	offset := (req.Page - 1) * req.Limit
 
 	// Fetch data
-	candidates, err := h.UseCase.GetAllByTypePaginated(req.Type, req.Limit, offset)
+	candidates, err := h.UseCase.GetAllByTypePaginated(r.Context(), req.Type, req.Limit, offset)
 	if err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to get candidates", err.Error())
 		return
@@ -205,7 +162,7 @@ func (h *CandidateHandler) GetCandidateByID(w http.ResponseWriter, r *http.Reque
 		return
 	}
 	// Fetch candidate
-	candidate, err := h.UseCase.GetCandidateByID(req.ID)
+	candidate, err := h.UseCase.GetCandidateByID(r.Context(), req.ID)
 	if err != nil {
 		response.JSON(w, http.StatusNotFound, false, "Candidate not found: "+err.Error(), nil)
 		return
@@ -224,7 +181,7 @@ func (h *CandidateHandler) GetCandidateByID(w http.ResponseWriter, r *http.Reque
 // @Failure 401 {object} response.JSONResponse "Unauthorized"
 // @Router /vote [post]
 func (h *CandidateHandler) Vote(w http.ResponseWriter, r *http.Request) {
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Candidate vote attempt from %s", r.RemoteAddr))
+	h.Logger.InfoContext(r.Context(), "Candidate vote attempt", "remote_addr", r.RemoteAddr)
 
 	token, err := http2.ExtractTokenFromRequest(r)
 	if err != nil {
@@ -247,18 +204,16 @@ func (h *CandidateHandler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	log.Printf("User ID from tokens: %d", userID)
-
 	var req candidate_data2.VoteRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.JSON(w, http.StatusBadRequest, false, "Invalid request format: "+err.Error(), nil)
 		return
 	}
 
-	err = h.UseCase.Vote(req.CandidateID, userID, candidate_data2.CandidateType(req.CandidateType))
+	err = h.UseCase.Vote(r.Context(), req.CandidateID, userID, candidate_data2.CandidateType(req.CandidateType))
 	if err != nil {
 		if err.Error() == "already voted for this category" {
-			h.KafkaLogger.Log("INFO", fmt.Sprintf("Duplicate vote attempt: user %d for candidate %d", userID, req.CandidateID))
+			h.Logger.InfoContext(r.Context(), "Duplicate vote attempt", "user_id", userID, "candidate_id", req.CandidateID)
 			response.JSON(w, http.StatusOK, true, "Vote already recorded", nil)
 			return
 		}
@@ -266,7 +221,7 @@ func (h *CandidateHandler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Candidate vote success: user %d voted for candidate %d", userID, req.CandidateID))
+	h.Logger.InfoContext(r.Context(), "Candidate vote success", "user_id", userID, "candidate_id", req.CandidateID)
 
 	response.JSON(w, http.StatusOK, true, "Vote successfully", nil)
 }
@@ -318,11 +273,130 @@ func (h *CandidateHandler) DeleteCandidate(w http.ResponseWriter, r *http.Reques
 	}
 
 	// Perform delete
-	if err := h.UseCase.DeleteCandidate(req.ID); err != nil {
+	if err := h.UseCase.DeleteCandidate(r.Context(), req.ID); err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to delete candidate: "+err.Error(), nil)
 		return
 	}
 
 	response.JSON(w, http.StatusOK, true, "Candidate deleted successfully", nil)
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Candidate deleted: %d", req.ID))
+	h.Logger.InfoContext(r.Context(), "Candidate deleted", "candidate_id", req.ID)
+}
+
+// @Summary Update a candidate profile
+// @Description Partial update: omitted fields are left unchanged. social_links and photos replace the existing lists. Field errors are returned in data.
+// @Tags Candidates
+// @Accept json
+// @Produce json
+// @Param id path int true "Candidate ID"
+// @Param candidate body candidate_data2.CandidateUpdate true "Fields to update"
+// @Security BearerAuth
+// @Success 200 {object} candidate_data2.Candidate "Candidate updated successfully"
+// @Failure 400 {object} response.JSONResponse "Validation failed"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Failure 404 {object} response.JSONResponse "Candidate not found"
+// @Router /candidate/{id} [put]
+func (h *CandidateHandler) UpdateCandidate(w http.ResponseWriter, r *http.Request) {
+	token, err := http2.ExtractTokenFromRequest(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, missing tokens: "+err.Error(), nil)
+		return
+	}
+	payload := &candidate_data2.JwtClaims{}
+	_, err = jwt.ParseWithClaims(token, payload, func(t *jwt.Token) (interface{}, error) {
+		return h.TokenManager.Secret, nil
+	})
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, invalid tokens: "+err.Error(), nil)
+		return
+	}
+
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid candidate ID", nil)
+		return
+	}
+
+	var update candidate_data2.CandidateUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request: "+err.Error(), nil)
+		return
+	}
+
+	candidate, err := h.UseCase.UpdateCandidate(r.Context(), uint(id), payload.UserID, update)
+	if err != nil {
+		var validationErrs candidate_data2.ValidationErrors
+		if errors.As(err, &validationErrs) {
+			response.JSON(w, http.StatusBadRequest, false, "Validation failed", validationErrs)
+			return
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			response.JSON(w, http.StatusNotFound, false, "Candidate not found", nil)
+			return
+		}
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to update candidate: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Candidate updated successfully", candidate)
+	h.Logger.InfoContext(r.Context(), "Candidate updated", "candidate_id", id, "user_id", payload.UserID)
+}
+
+// @Summary Get the change history of a candidate profile
+// @Tags Candidates
+// @Produce json
+// @Param id path int true "Candidate ID"
+// @Security BearerAuth
+// @Success 200 {array} candidate_data2.CandidateChange "Profile changes, newest first"
+// @Failure 400 {object} response.JSONResponse "Invalid ID"
+// @Failure 500 {object} response.JSONResponse "Internal Server Error"
+// @Router /candidate/{id}/history [get]
+func (h *CandidateHandler) GetCandidateHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 64)
+	if err != nil || id == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid candidate ID", nil)
+		return
+	}
+
+	history, err := h.UseCase.GetCandidateHistory(r.Context(), uint(id))
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get candidate history: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", history)
+}
+
+// @Summary Compare candidates side by side
+// @Description Returns the profiles of 2 to 5 candidates and their manifesto points grouped by topic, aligned in the order of ids.
+// @Tags Candidates
+// @Produce json
+// @Param ids query string true "Comma-separated candidate IDs" example(1,2,3)
+// @Security BearerAuth
+// @Success 200 {object} candidate_data2.CandidateComparison "Aligned candidate profiles"
+// @Failure 400 {object} response.JSONResponse "Invalid IDs"
+// @Router /candidates/compare [get]
+func (h *CandidateHandler) CompareCandidates(w http.ResponseWriter, r *http.Request) {
+	raw := r.URL.Query().Get("ids")
+	if raw == "" {
+		response.JSON(w, http.StatusBadRequest, false, "Missing ids", nil)
+		return
+	}
+
+	var ids []uint
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+		if err != nil || id == 0 {
+			response.JSON(w, http.StatusBadRequest, false, fmt.Sprintf("Invalid candidate ID %q", part), nil)
+			return
+		}
+		ids = append(ids, uint(id))
+	}
+
+	comparison, err := h.UseCase.CompareCandidates(r.Context(), ids)
+	if err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to compare candidates: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", comparison)
 }
@@ -4,20 +4,14 @@ import (
 	http2 "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/domain"
 	"VoteGolang/internals/infrastructure/repositories"
-	"log"
 	"net/http"
+	"strings"
 )
 
 func RegisterCandidateRoutes(mux *http.ServeMux, handler *CandidateHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
 	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			token, err := http2.ExtractTokenFromRequest(r)
-			if err != nil {
-				log.Printf("Failed to extract tokens: %v", err)
-			} else {
-				log.Printf("Accessing %s route | Method: %s | URL: %s | Token: %s", route, r.Method, r.URL.Path, token)
-			}
-
+			http2.SetRoute(r, route)
 			handlerFunc(w, r)
 		}
 	}
@@ -29,34 +23,59 @@ func RegisterCandidateRoutes(mux *http.ServeMux, handler *CandidateHandler, toke
 			),
 		),
 	)
-	mux.Handle("/candidate/page",
+	mux.Handle("/candidates/compare",
 		http2.JWTMiddleware(tokenManager)(
 			http2.RBACMiddleware(rbacRepo, "read_candidate")(
-				logRequest("/candidate/page", handler.GetCandidatesByPage),
+				logRequest("/candidates/compare", handler.CompareCandidates),
 			),
 		),
 	)
-
-	mux.Handle("/candidate/",
+	mux.Handle("/candidate/page",
 		http2.JWTMiddleware(tokenManager)(
 			http2.RBACMiddleware(rbacRepo, "read_candidate")(
-				logRequest("/candidate/", handler.GetCandidateByID),
+				logRequest("/candidate/page", handler.GetCandidatesByPage),
 			),
 		),
 	)
 
-	mux.Handle("/vote",
-		http2.JWTMiddleware(tokenManager)(
-			http2.RBACMiddleware(rbacRepo, "vote")(
-				logRequest("/vote", handler.Vote),
-			),
+	getByID := http2.JWTMiddleware(tokenManager)(
+		http2.RBACMiddleware(rbacRepo, "read_candidate")(
+			logRequest("/candidate/", handler.GetCandidateByID),
 		),
 	)
+	update := http2.JWTMiddleware(tokenManager)(
+		http2.RBACMiddleware(rbacRepo, "update_candidate")(
+			logRequest("/candidate/{id}", handler.UpdateCandidate),
+		),
+	)
+	history := http2.JWTMiddleware(tokenManager)(
+		http2.RBACMiddleware(rbacRepo, "update_candidate")(
+			logRequest("/candidate/{id}/history", handler.GetCandidateHistory),
+		),
+	)
+
+	// "/candidate/" also serves PUT /candidate/{id} and GET /candidate/{id}/history.
+	// Method patterns cannot be registered next to the method-less /candidate/* routes.
+	mux.HandleFunc("/candidate/", func(w http.ResponseWriter, r *http.Request) {
+		rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/candidate/"), "/")
+		parts := strings.Split(rest, "/")
 
-	mux.Handle("/candidate/create",
+		switch {
+		case r.Method == http.MethodPut && rest != "" && len(parts) == 1:
+			r.SetPathValue("id", parts[0])
+			update.ServeHTTP(w, r)
+		case r.Method == http.MethodGet && len(parts) == 2 && parts[1] == "history":
+			r.SetPathValue("id", parts[0])
+			history.ServeHTTP(w, r)
+		default:
+			getByID.ServeHTTP(w, r)
+		}
+	})
+
+	mux.Handle("/vote",
 		http2.JWTMiddleware(tokenManager)(
-			http2.RBACMiddleware(rbacRepo, "create_candidate")(
-				logRequest("/candidate/create", handler.CreateCandidate),
+			http2.RBACMiddleware(rbacRepo, "vote")(
+				logRequest("/vote", handler.Vote),
 			),
 		),
 	)
@@ -0,0 +1,378 @@
+package comment_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/controller/http/response"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/usecases/comment_usecase"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"gorm.io/gorm"
+)
+
+type CommentHandler struct {
+	usecase      comment_usecase.CommentUseCase
+	TokenManager *domain.JwtToken
+	Logger       *slog.Logger
+}
+
+type CreateCommentRequest struct {
+	PetitionID uint   `json:"petition_id" example:"1"`
+	ParentID   *uint  `json:"parent_id,omitempty" example:"5"`
+	Body       string `json:"body" example:"I support this"`
+}
+
+type ListCommentsRequest struct {
+	PetitionID uint   `json:"petition_id" example:"1"`
+	ParentID   *uint  `json:"parent_id,omitempty" example:"5"`
+	Cursor     string `json:"cursor,omitempty"`
+	Limit      int    `json:"limit" example:"20"`
+}
+
+type EditCommentRequest struct {
+	ID   uint   `json:"id" example:"5"`
+	Body string `json:"body" example:"I support this, edited"`
+}
+
+type ReportCommentRequest struct {
+	ID     uint   `json:"id" example:"5"`
+	Reason string `json:"reason" example:"spam"`
+}
+
+type IDRequest struct {
+	ID uint `json:"id" example:"5"`
+}
+
+type PaginationRequest struct {
+	Page  int `json:"page" example:"1"`
+	Limit int `json:"limit" example:"20"`
+}
+
+func NewCommentHandler(usecase comment_usecase.CommentUseCase, tokenManager *domain.JwtToken, logger *slog.Logger) *CommentHandler {
+	return &CommentHandler{
+		usecase:      usecase,
+		TokenManager: tokenManager,
+		Logger:       logger,
+	}
+}
+
+// requestUserID extracts the acting user's ID from the request's bearer token.
+func (h *CommentHandler) requestUserID(r *http.Request) (uint, error) {
+	token, err := http2.ExtractTokenFromRequest(r)
+	if err != nil {
+		return 0, fmt.Errorf("missing tokens: %w", err)
+	}
+
+	payload := &domain.JwtClaims{}
+	_, err = jwt.ParseWithClaims(token, payload, func(t *jwt.Token) (interface{}, error) {
+		return h.TokenManager.Secret, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("invalid tokens: %w", err)
+	}
+	if payload.UserID == 0 {
+		return 0, fmt.Errorf("invalid userID")
+	}
+	return payload.UserID, nil
+}
+
+// errorStatus maps use case errors to HTTP status codes.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, comment_usecase.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, comment_usecase.ErrNotCommentAuthor), errors.Is(err, comment_usecase.ErrEditWindowClosed):
+		return http.StatusForbidden
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// @Summary Comment on a petition
+// @Description Creates a top-level comment, or a reply when parent_id is set. Limited to 5 comments per minute per user.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param comment body CreateCommentRequest true "Comment"
+// @Success 201 {object} domain.PetitionComment "Comment created"
+// @Failure 400 {object} response.JSONResponse "Invalid request"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Failure 429 {object} response.JSONResponse "Too many comments"
+// @Router /petition/comments/create [post]
+func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request body: "+err.Error(), nil)
+		return
+	}
+	if req.PetitionID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid petition ID", nil)
+		return
+	}
+
+	comment := domain.PetitionComment{
+		PetitionID: req.PetitionID,
+		ParentID:   req.ParentID,
+		UserID:     userID,
+		Body:       req.Body,
+	}
+	if err := h.usecase.CreateComment(r.Context(), &comment); err != nil {
+		response.JSON(w, errorStatus(err), false, "Failed to create comment: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, true, "Comment created successfully", comment)
+}
+
+// @Summary List comments of a petition
+// @Description Returns top-level comments, or replies to parent_id, newest first. Pass next_cursor back as cursor to get the next page.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ListCommentsRequest true "Petition, thread and cursor"
+// @Success 200 {object} domain.CommentPage "Page of comments"
+// @Failure 400 {object} response.JSONResponse "Invalid request"
+// @Failure 404 {object} response.JSONResponse "Petition not found or not approved"
+// @Router /petition/comments [post]
+func (h *CommentHandler) ListComments(w http.ResponseWriter, r *http.Request) {
+	var req ListCommentsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON", nil)
+		return
+	}
+	if req.PetitionID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid petition ID", nil)
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20 // default
+	}
+
+	page, err := h.usecase.ListComments(r.Context(), req.PetitionID, req.ParentID, req.Cursor, req.Limit)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		response.JSON(w, http.StatusNotFound, false, "Petition not found", nil)
+		return
+	}
+	if err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to get comments: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", page)
+}
+
+// @Summary Edit a comment
+// @Description Authors can edit their comment within 15 minutes of posting.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param comment body EditCommentRequest true "Comment ID and new body"
+// @Success 200 {object} domain.PetitionComment "Comment updated"
+// @Failure 400 {object} response.JSONResponse "Invalid request"
+// @Failure 403 {object} response.JSONResponse "Not the author or edit window closed"
+// @Router /petition/comments/edit [post]
+func (h *CommentHandler) EditComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req EditCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid comment ID", nil)
+		return
+	}
+
+	comment, err := h.usecase.EditComment(r.Context(), req.ID, userID, req.Body)
+	if err != nil {
+		response.JSON(w, errorStatus(err), false, "Failed to edit comment: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Comment updated successfully", comment)
+}
+
+// @Summary Delete a comment
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Comment ID"
+// @Success 200 {object} response.JSONResponse "Comment deleted"
+// @Failure 400 {object} response.JSONResponse "Invalid ID"
+// @Failure 403 {object} response.JSONResponse "Not the author"
+// @Router /petition/comments/delete [delete]
+func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodDelete && r.Method != http.MethodPost {
+		response.JSON(w, http.StatusMethodNotAllowed, false, "Only DELETE or POST allowed", nil)
+		return
+	}
+
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid comment ID", nil)
+		return
+	}
+
+	if err := h.usecase.DeleteComment(r.Context(), req.ID, userID); err != nil {
+		response.JSON(w, errorStatus(err), false, "Failed to delete comment: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Comment deleted successfully", nil)
+}
+
+// @Summary Report a comment for abuse
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param report body ReportCommentRequest true "Comment ID and reason"
+// @Success 200 {object} response.JSONResponse "Comment reported"
+// @Failure 400 {object} response.JSONResponse "Invalid request or already reported"
+// @Router /petition/comments/report [post]
+func (h *CommentHandler) ReportComment(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req ReportCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid comment ID", nil)
+		return
+	}
+
+	if err := h.usecase.ReportComment(r.Context(), req.ID, userID, req.Reason); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to report comment: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Comment reported", nil)
+}
+
+// @Summary Hide a comment
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Comment ID"
+// @Success 200 {object} response.JSONResponse "Comment hidden"
+// @Failure 400 {object} response.JSONResponse "Invalid ID"
+// @Router /petition/comments/hide [post]
+func (h *CommentHandler) HideComment(w http.ResponseWriter, r *http.Request) {
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid comment ID", nil)
+		return
+	}
+
+	if err := h.usecase.HideComment(r.Context(), req.ID); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to hide comment: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Comment hidden", nil)
+	h.Logger.InfoContext(r.Context(), "Comment hidden", "comment_id", req.ID)
+}
+
+// @Summary Restore a hidden comment
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Comment ID"
+// @Success 200 {object} response.JSONResponse "Comment restored"
+// @Failure 400 {object} response.JSONResponse "Invalid ID"
+// @Router /petition/comments/restore [post]
+func (h *CommentHandler) RestoreComment(w http.ResponseWriter, r *http.Request) {
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid comment ID", nil)
+		return
+	}
+
+	if err := h.usecase.RestoreComment(r.Context(), req.ID); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to restore comment: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Comment restored", nil)
+	h.Logger.InfoContext(r.Context(), "Comment restored", "comment_id", req.ID)
+}
+
+// @Summary Get reported comments
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param pagination body PaginationRequest true "Pagination info"
+// @Success 200 {array} domain.ReportedComment "Reported comments, most reported first"
+// @Failure 400 {object} response.JSONResponse "Invalid page"
+// @Failure 500 {object} response.JSONResponse "Failed to get reported comments"
+// @Router /petition/comments/reported [post]
+func (h *CommentHandler) GetReportedComments(w http.ResponseWriter, r *http.Request) {
+	var req PaginationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON", nil)
+		return
+	}
+	if req.Page <= 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid page number", nil)
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20 // default
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	comments, err := h.usecase.GetReportedComments(r.Context(), req.Limit, offset)
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get reported comments: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", comments)
+}
@@ -0,0 +1,81 @@
+package comment_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/repositories"
+	"net/http"
+)
+
+func RegisterCommentRoutes(mux *http.ServeMux, handler *CommentHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
+	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			http2.SetRoute(r, route)
+			handlerFunc(w, r)
+		}
+	}
+
+	mux.Handle("/petition/comments",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_petition")(
+				logRequest("/petition/comments", handler.ListComments),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/create",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "comment")(
+				logRequest("/petition/comments/create", handler.CreateComment),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/edit",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "comment")(
+				logRequest("/petition/comments/edit", handler.EditComment),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/delete",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "comment")(
+				logRequest("/petition/comments/delete", handler.DeleteComment),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/report",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "comment")(
+				logRequest("/petition/comments/report", handler.ReportComment),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/hide",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "moderate_comment")(
+				logRequest("/petition/comments/hide", handler.HideComment),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/restore",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "moderate_comment")(
+				logRequest("/petition/comments/restore", handler.RestoreComment),
+			),
+		),
+	)
+
+	mux.Handle("/petition/comments/reported",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "moderate_comment")(
+				logRequest("/petition/comments/reported", handler.GetReportedComments),
+			),
+		),
+	)
+}
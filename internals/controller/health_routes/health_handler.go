@@ -0,0 +1,43 @@
+package health_routes
+
+import (
+	"VoteGolang/internals/controller/http/response"
+	"VoteGolang/internals/infrastructure/health"
+	"net/http"
+)
+
+type HealthHandler struct {
+	Checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{Checker: checker}
+}
+
+// Liveness godoc
+// @Summary Liveness probe
+// @Description Answers while the process is able to serve HTTP. Dependencies are not checked.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response.JSONResponse
+// @Router /healthz [get]
+func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, true, health.StatusUp, nil)
+}
+
+// Readiness godoc
+// @Summary Readiness probe
+// @Description Checks the database, Redis, Elasticsearch, Kafka and the blockchain node and lists the dependencies whose circuit breaker is open. Returns 503 when the database is down or the server is shutting down; other failures report "degraded" with 200.
+// @Tags Health
+// @Produce json
+// @Success 200 {object} response.JSONResponse{data=health.Report}
+// @Failure 503 {object} response.JSONResponse{data=health.Report}
+// @Router /readyz [get]
+func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
+	report := h.Checker.Run(r.Context())
+	if report.Status == health.StatusDown {
+		response.JSON(w, http.StatusServiceUnavailable, false, report.Status, report)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, report.Status, report)
+}
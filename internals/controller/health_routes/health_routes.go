@@ -0,0 +1,17 @@
+package health_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"net/http"
+)
+
+func RegisterHealthRoutes(mux *http.ServeMux, handler *HealthHandler) {
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		http2.SetRoute(r, "/healthz")
+		handler.Liveness(w, r)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		http2.SetRoute(r, "/readyz")
+		handler.Readiness(w, r)
+	})
+}
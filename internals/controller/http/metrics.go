@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"VoteGolang/internals/infrastructure/metrics"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels requests no route claimed, so unknown paths do not
+// create new series.
+const unmatchedRoute = "unmatched"
+
+type routeKey struct{}
+
+// routeHolder receives the route from SetRoute further down the chain.
+type routeHolder struct {
+	route string
+}
+
+// MetricsMiddleware records the latency and status of each request under
+// the route passed to SetRoute.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		holder := &routeHolder{route: unmatchedRoute}
+		ctx := context.WithValue(r.Context(), routeKey{}, holder)
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		metrics.HTTPRequestDuration.
+			WithLabelValues(holder.route, r.Method, strconv.Itoa(rec.status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+func setMetricsRoute(ctx context.Context, route string) {
+	if holder, ok := ctx.Value(routeKey{}).(*routeHolder); ok {
+		holder.route = route
+	}
+}
@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"VoteGolang/internals/app/logging"
 	"VoteGolang/internals/domain"
 	"VoteGolang/internals/infrastructure/repositories"
 	"context"
+	"log/slog"
 	"net/http"
+	"slices"
 	"strings"
 )
 
@@ -12,30 +15,32 @@ type contextKey string
 
 const userIDKey contextKey = "userID"
 
-func CORSMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Add your Vercel domain here!
-		allowedOrigins := []string{"http://localhost:3000", "https://dayus.vercel.app"}
-		origin := r.Header.Get("Origin")
+// OriginAllowed reports whether a browser origin may call the API.
+func OriginAllowed(allowed []string, origin string) bool {
+	return origin != "" && slices.Contains(allowed, origin)
+}
 
-		for _, allowedOrigin := range allowedOrigins {
-			if origin == allowedOrigin {
+// CORSMiddleware lets the allowed browser origins call the API.
+func CORSMiddleware(allowed []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if OriginAllowed(allowed, origin) {
 				w.Header().Set("Access-Control-Allow-Origin", origin)
-				break
 			}
-		}
 
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+			w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
+			w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 
-		// Handle pre-flight requests
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-			return
-		}
+			// Handle pre-flight requests
+			if r.Method == "OPTIONS" {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}
 }
 
 func JWTMiddleware(tokenManager domain.TokenManager) func(http.Handler) http.Handler {
@@ -55,6 +60,7 @@ func JWTMiddleware(tokenManager domain.TokenManager) func(http.Handler) http.Han
 			}
 
 			// Attaching userID to context
+			logging.AddAttrs(r.Context(), slog.Uint64("user_id", uint64(userID)))
 			ctx := context.WithValue(r.Context(), userIDKey, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
@@ -75,7 +81,7 @@ func RBACMiddleware(rbacRepo *repositories.RBACRepository, permission string) fu
 				return
 			}
 
-			if !rbacRepo.HasAccess(userID, permission) {
+			if !rbacRepo.HasAccess(r.Context(), userID, permission) {
 				http.Error(w, "Permission denied: no access", http.StatusForbidden)
 				return
 			}
@@ -86,6 +92,20 @@ func RBACMiddleware(rbacRepo *repositories.RBACRepository, permission string) fu
 	}
 }
 
+// QueryTokenMiddleware accepts the access token in the access_token query
+// parameter. Browsers cannot set headers on EventSource and WebSocket
+// connections, so stream routes use it in front of JWTMiddleware.
+func QueryTokenMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			if token := r.URL.Query().Get("access_token"); token != "" {
+				r.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 func ExtractTokenFromRequest(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"VoteGolang/internals/app/logging"
+	"log/slog"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware opens a server span for each request, continuing the
+// trace of an incoming traceparent header. The span starts out named after
+// the method and is renamed once SetRoute knows the route.
+func TracingMiddleware(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.request",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method
+		}),
+	)
+}
+
+// SetRoute names the matched route on the request's log context, span and
+// metrics.
+func SetRoute(r *http.Request, route string) {
+	logging.AddAttrs(r.Context(), slog.String("route", route))
+	setMetricsRoute(r.Context(), route)
+
+	span := trace.SpanFromContext(r.Context())
+	span.SetName(r.Method + " " + route)
+	span.SetAttributes(attribute.String("http.route", route))
+}
@@ -6,21 +6,21 @@ import (
 	"VoteGolang/internals/domain"
 	"VoteGolang/internals/usecases/auth_usecase"
 	"encoding/json"
-	"fmt"
+	"log/slog"
 	"net/http"
 )
 
 type AuthHandler struct {
 	authUseCase  *auth_usecase.AuthUseCase
 	tokenManager domain.TokenManager
-	kafkaLogger  *logging.KafkaLogger
+	logger       *slog.Logger
 }
 
-func NewAuthHandler(authUseCase *auth_usecase.AuthUseCase, tokenManager domain.TokenManager, kafkaLogger *logging.KafkaLogger) *AuthHandler {
+func NewAuthHandler(authUseCase *auth_usecase.AuthUseCase, tokenManager domain.TokenManager, logger *slog.Logger) *AuthHandler {
 	return &AuthHandler{
 		authUseCase:  authUseCase,
 		tokenManager: tokenManager,
-		kafkaLogger:  kafkaLogger,
+		logger:       logger,
 	}
 }
 
@@ -36,7 +36,7 @@ func NewAuthHandler(authUseCase *auth_usecase.AuthUseCase, tokenManager domain.T
 // @Failure      401  {object}  response.JSONResponse  "Unauthorized"
 // @Router       /login [post]
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Login attempt from %s", r.RemoteAddr))
+	h.logger.InfoContext(r.Context(), "Login attempt", "remote_addr", r.RemoteAddr)
 	var req domain.AuthRequest
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -44,14 +44,14 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	accessToken, refreshToken, isAdmin, err := h.authUseCase.Login(req.Username, req.Password)
+	accessToken, refreshToken, isAdmin, err := h.authUseCase.Login(r.Context(), req.Username, req.Password)
 	if err != nil {
-		h.kafkaLogger.Log("WARN", fmt.Sprintf("Login failed for %s: %v", req.Username, err))
+		h.logger.WarnContext(r.Context(), "Login failed", "username", req.Username, logging.Err(err))
 		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized: "+err.Error(), nil)
 		return
 	}
 
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Login success for %s", req.Username))
+	h.logger.InfoContext(r.Context(), "Login success", "username", req.Username)
 
 	tokenResp := domain.TokenResponse{
 		AccessToken:  accessToken,
@@ -76,7 +76,7 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 // @Failure      400  {object}  response.JSONResponse  "Invalid request"
 // @Router       /register [post]
 func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Register attempt from %s", r.RemoteAddr))
+	h.logger.InfoContext(r.Context(), "Register attempt", "remote_addr", r.RemoteAddr)
 	var req domain.User
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -86,12 +86,12 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 	link, token, err := h.authUseCase.Register(r.Context(), &req)
 	if err != nil {
-		h.kafkaLogger.Log("WARN", fmt.Sprintf("Register failed for %s: %v", req.Username, err))
+		h.logger.WarnContext(r.Context(), "Register failed", "username", req.Username, logging.Err(err))
 		response.JSON(w, http.StatusBadRequest, false, "Failed to register user: "+err.Error(), nil)
 		return
 	}
 
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Register success for %s", req.Username))
+	h.logger.InfoContext(r.Context(), "Register success", "username", req.Username)
 	response.JSON(w, http.StatusCreated, true, "User registered successfully", map[string]string{
 		"verify_link":  link,
 		"verify_token": token,
@@ -110,7 +110,7 @@ func (h *AuthHandler) Register(w http.ResponseWriter, r *http.Request) {
 // @Failure      401  {object}  response.JSONResponse  "Unauthorized"
 // @Router       /refresh [post]
 func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Refresh token attempt from %s", r.RemoteAddr))
+	h.logger.InfoContext(r.Context(), "Refresh token attempt", "remote_addr", r.RemoteAddr)
 	var req domain.RefreshRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		response.JSON(w, http.StatusBadRequest, false, "Invalid request", nil)
@@ -119,12 +119,12 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 
 	accessToken, refreshToken, err := h.authUseCase.Refresh(r.Context(), req.RefreshToken)
 	if err != nil {
-		h.kafkaLogger.Log("WARN", fmt.Sprintf("Refresh token failed %v", err))
+		h.logger.WarnContext(r.Context(), "Refresh token failed", logging.Err(err))
 		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized: "+err.Error(), nil)
 		return
 	}
 
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Refresh token success"))
+	h.logger.InfoContext(r.Context(), "Refresh token success")
 	response.JSON(w, http.StatusOK, true, "OK", domain.TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -141,7 +141,7 @@ func (h *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
 // @Failure      400  {object}  response.JSONResponse  "Invalid or missing token"
 // @Router       /verify-email [get]
 func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
-	h.kafkaLogger.Log("INFO", fmt.Sprintf("Email verification attempt from %s", r.RemoteAddr))
+	h.logger.InfoContext(r.Context(), "Email verification attempt", "remote_addr", r.RemoteAddr)
 	token := r.URL.Query().Get("token")
 	if token == "" {
 		response.JSON(w, http.StatusBadRequest, false, "Missing token", nil)
@@ -151,12 +151,12 @@ func (h *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	err := h.authUseCase.VerifyEmail(ctx, token)
 	if err != nil {
-		h.kafkaLogger.Log("WARN", fmt.Sprintf("Email verification failed: %v", err))
+		h.logger.WarnContext(r.Context(), "Email verification failed", logging.Err(err))
 		response.JSON(w, http.StatusBadRequest, false, "Failed to verify email: "+err.Error(), nil)
 		return
 	}
 
-	h.kafkaLogger.Log("INFO", "Email verification success")
+	h.logger.InfoContext(r.Context(), "Email verification success")
 	response.JSON(w, http.StatusOK, true, "Email verified successfully!", nil)
 
 }
@@ -1,32 +1,32 @@
 package login_routes
 
 import (
-	"VoteGolang/internals/app/logging"
+	http2 "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/domain"
-	"fmt"
+	"log/slog"
 	"net/http"
 )
 
-func AuthorizationRoutes(mux *http.ServeMux, authHandler *AuthHandler, tokenManager domain.TokenManager, kafkaLogger *logging.KafkaLogger) {
+func AuthorizationRoutes(mux *http.ServeMux, authHandler *AuthHandler, tokenManager domain.TokenManager, logger *slog.Logger) {
 
 	//login_routes
 	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
-		kafkaLogger.Log("INFO", fmt.Sprintf("Accessed /login route from %s", r.RemoteAddr))
+		http2.SetRoute(r, "/login")
 		authHandler.Login(w, r)
 	})
 
 	mux.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
-		kafkaLogger.Log("INFO", fmt.Sprintf("Accessed /register route from %s", r.RemoteAddr))
+		http2.SetRoute(r, "/register")
 		authHandler.Register(w, r)
 	})
 
 	mux.HandleFunc("/refresh", func(w http.ResponseWriter, r *http.Request) {
-		kafkaLogger.Log("INFO", fmt.Sprintf("Accessed /refresh route from %s", r.RemoteAddr))
+		http2.SetRoute(r, "/refresh")
 		authHandler.Refresh(w, r)
 	})
 
 	mux.HandleFunc("/verify-email", func(w http.ResponseWriter, r *http.Request) {
-		kafkaLogger.Log("INFO", fmt.Sprintf("Accessed /verify-email route from %s", r.RemoteAddr))
+		http2.SetRoute(r, "/verify-email")
 		authHandler.VerifyEmail(w, r)
 	})
 
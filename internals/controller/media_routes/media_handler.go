@@ -0,0 +1,182 @@
+package media_routes
+
+import (
+	"VoteGolang/internals/app/logging"
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/controller/http/response"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/storage"
+	"VoteGolang/internals/usecases/media_usecase"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type MediaHandler struct {
+	usecase        media_usecase.MediaUseCase
+	localStorage   *storage.LocalStorage
+	maxUploadBytes int64
+	TokenManager   *domain.JwtToken
+	Logger         *slog.Logger
+}
+
+// NewMediaHandler creates the media handler. localStorage is only set when
+// files are kept on disk; the API then serves them itself at /media/file/.
+func NewMediaHandler(
+	usecase media_usecase.MediaUseCase,
+	localStorage *storage.LocalStorage,
+	maxUploadBytes int64,
+	tokenManager *domain.JwtToken,
+	logger *slog.Logger,
+) *MediaHandler {
+	return &MediaHandler{
+		usecase:        usecase,
+		localStorage:   localStorage,
+		maxUploadBytes: maxUploadBytes,
+		TokenManager:   tokenManager,
+		Logger:         logger,
+	}
+}
+
+// requestUserID extracts the acting user's ID from the request's bearer token.
+func (h *MediaHandler) requestUserID(r *http.Request) (uint, error) {
+	token, err := http2.ExtractTokenFromRequest(r)
+	if err != nil {
+		return 0, fmt.Errorf("missing tokens: %w", err)
+	}
+
+	payload := &domain.JwtClaims{}
+	_, err = jwt.ParseWithClaims(token, payload, func(t *jwt.Token) (interface{}, error) {
+		return h.TokenManager.Secret, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("invalid tokens: %w", err)
+	}
+	if payload.UserID == 0 {
+		return 0, fmt.Errorf("invalid userID")
+	}
+	return payload.UserID, nil
+}
+
+// @Summary Upload an image
+// @Description Uploads a JPEG, PNG or GIF (multipart field "file"). Metadata such as EXIF is stripped and a thumbnail is generated. Use the returned id as photo_asset_id on candidates and petitions.
+// @Tags Media
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "Image file"
+// @Success 201 {object} domain.AssetURLs "Uploaded asset with signed URLs"
+// @Failure 400 {object} response.JSONResponse "Invalid or unsupported file"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Failure 413 {object} response.JSONResponse "File too large"
+// @Router /media/upload [post]
+func (h *MediaHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		response.JSON(w, http.StatusMethodNotAllowed, false, "Method not allowed", nil)
+		return
+	}
+
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file itself
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxUploadBytes+1<<20)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			response.JSON(w, http.StatusRequestEntityTooLarge, false, h.tooLargeMessage(), nil)
+			return
+		}
+		response.JSON(w, http.StatusBadRequest, false, "Missing file: "+err.Error(), nil)
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, h.maxUploadBytes+1))
+	if err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to read file: "+err.Error(), nil)
+		return
+	}
+	if int64(len(data)) > h.maxUploadBytes {
+		response.JSON(w, http.StatusRequestEntityTooLarge, false, h.tooLargeMessage(), nil)
+		return
+	}
+
+	asset, err := h.usecase.Upload(r.Context(), userID, data)
+	if err != nil {
+		h.Logger.WarnContext(r.Context(), "Upload rejected", "user_id", userID, logging.Err(err))
+		response.JSON(w, http.StatusBadRequest, false, "Failed to upload file: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, true, "File uploaded successfully", asset)
+}
+
+// @Summary Get an uploaded image
+// @Description Returns fresh signed URLs for an asset and its thumbnail.
+// @Tags Media
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Asset ID"
+// @Success 200 {object} domain.AssetURLs "Asset with signed URLs"
+// @Failure 404 {object} response.JSONResponse "Asset not found"
+// @Router /media/{id} [get]
+func (h *MediaHandler) GetAsset(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/media/")
+	asset, err := h.usecase.GetAsset(r.Context(), id)
+	if errors.Is(err, media_usecase.ErrAssetNotFound) {
+		response.JSON(w, http.StatusNotFound, false, "Asset not found", nil)
+		return
+	}
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get asset: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", asset)
+}
+
+// ServeFile streams a locally stored file if the request carries a valid
+// signature from a URL issued by the API.
+func (h *MediaHandler) ServeFile(w http.ResponseWriter, r *http.Request) {
+	if h.localStorage == nil {
+		response.JSON(w, http.StatusNotFound, false, "Not found", nil)
+		return
+	}
+
+	key := strings.TrimPrefix(r.URL.Path, "/media/file/")
+	query := r.URL.Query()
+	if err := h.localStorage.VerifySignature(key, query.Get("expires"), query.Get("signature")); err != nil {
+		response.JSON(w, http.StatusForbidden, false, err.Error(), nil)
+		return
+	}
+
+	file, err := h.localStorage.Get(r.Context(), key)
+	if err != nil {
+		response.JSON(w, http.StatusNotFound, false, "Not found", nil)
+		return
+	}
+	defer file.Close()
+
+	// Keys are only ever written by the upload pipeline, so sniffing is safe
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	w.Header().Set("Content-Type", http.DetectContentType(head[:n]))
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Write(head[:n])
+	io.Copy(w, file)
+}
+
+func (h *MediaHandler) tooLargeMessage() string {
+	return fmt.Sprintf("File too large, maximum is %d bytes", h.maxUploadBytes)
+}
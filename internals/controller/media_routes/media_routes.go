@@ -0,0 +1,35 @@
+package media_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/repositories"
+	"net/http"
+)
+
+func RegisterMediaRoutes(mux *http.ServeMux, handler *MediaHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
+	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			http2.SetRoute(r, route)
+			handlerFunc(w, r)
+		}
+	}
+
+	mux.Handle("/media/upload",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "upload_media")(
+				logRequest("/media/upload", handler.Upload),
+			),
+		),
+	)
+
+	mux.Handle("/media/",
+		http2.JWTMiddleware(tokenManager)(
+			logRequest("/media/{id}", handler.GetAsset),
+		),
+	)
+
+	// Signed links are opened directly by browsers, so no JWT here; the
+	// signature in the query string is the credential.
+	mux.HandleFunc("/media/file/", logRequest("/media/file/", handler.ServeFile))
+}
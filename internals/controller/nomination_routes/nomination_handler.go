@@ -0,0 +1,278 @@
+package nomination_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/controller/http/response"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/usecases/nomination_usecase"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type NominationHandler struct {
+	usecase      nomination_usecase.NominationUseCase
+	TokenManager *domain.JwtToken
+	Logger       *slog.Logger
+}
+
+type IDRequest struct {
+	ID uint `json:"id" example:"1"`
+}
+
+type RejectRequest struct {
+	ID     uint   `json:"id" example:"1"`
+	Reason string `json:"reason" example:"supporting documents are illegible"`
+}
+
+type ListNominationsRequest struct {
+	Page   int    `json:"page" example:"1"`
+	Limit  int    `json:"limit" example:"20"`
+	Status string `json:"status,omitempty" example:"submitted"`
+}
+
+func NewNominationHandler(usecase nomination_usecase.NominationUseCase, tokenManager *domain.JwtToken, logger *slog.Logger) *NominationHandler {
+	return &NominationHandler{
+		usecase:      usecase,
+		TokenManager: tokenManager,
+		Logger:       logger,
+	}
+}
+
+// requestUserID extracts the acting user's ID from the request's bearer token.
+func (h *NominationHandler) requestUserID(r *http.Request) (uint, error) {
+	token, err := http2.ExtractTokenFromRequest(r)
+	if err != nil {
+		return 0, fmt.Errorf("missing tokens: %w", err)
+	}
+
+	payload := &domain.JwtClaims{}
+	_, err = jwt.ParseWithClaims(token, payload, func(t *jwt.Token) (interface{}, error) {
+		return h.TokenManager.Secret, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("invalid tokens: %w", err)
+	}
+	if payload.UserID == 0 {
+		return 0, fmt.Errorf("invalid userID")
+	}
+	return payload.UserID, nil
+}
+
+// writeError maps use case errors to HTTP responses.
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	var validationErrs domain.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		response.JSON(w, http.StatusBadRequest, false, "Invalid nomination", validationErrs)
+	case errors.Is(err, nomination_usecase.ErrNominationNotFound):
+		response.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+	case errors.Is(err, nomination_usecase.ErrNotEligible):
+		response.JSON(w, http.StatusForbidden, false, prefix+err.Error(), nil)
+	case errors.Is(err, nomination_usecase.ErrAlreadyEndorsed), errors.Is(err, nomination_usecase.ErrNotCollecting):
+		response.JSON(w, http.StatusConflict, false, prefix+err.Error(), nil)
+	default:
+		response.JSON(w, http.StatusBadRequest, false, prefix+err.Error(), nil)
+	}
+}
+
+// @Summary Submit a candidate nomination
+// @Description Nominates a candidate for an election (type and voting window) with at least one supporting document. Documents and the photo are asset IDs from /media/upload. The nomination then collects endorsements.
+// @Tags Nominations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param nomination body domain.Nomination true "Nomination"
+// @Success 201 {object} domain.Nomination "Nomination submitted"
+// @Failure 400 {object} response.JSONResponse "Invalid nomination"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Router /nomination/create [post]
+func (h *NominationHandler) Submit(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var n domain.Nomination
+	if err := json.NewDecoder(r.Body).Decode(&n); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request body: "+err.Error(), nil)
+		return
+	}
+	n.ID = 0
+	n.UserID = userID
+
+	if err := h.usecase.Submit(r.Context(), &n); err != nil {
+		writeError(w, "Failed to submit nomination: ", err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, true, "Nomination submitted successfully", n)
+}
+
+// @Summary List nominations
+// @Description Lists nominations newest first, optionally filtered by status (collecting, submitted, approved, rejected).
+// @Tags Nominations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ListNominationsRequest true "Pagination and status filter"
+// @Success 200 {array} domain.Nomination "List of nominations"
+// @Failure 400 {object} response.JSONResponse "Invalid request"
+// @Router /nominations [post]
+func (h *NominationHandler) List(w http.ResponseWriter, r *http.Request) {
+	var req ListNominationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON", nil)
+		return
+	}
+	if req.Page <= 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid page number", nil)
+		return
+	}
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 20 // default
+	}
+	if req.Status != "" && !domain.IsValidNominationStatus(req.Status) {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid status", nil)
+		return
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	nominations, err := h.usecase.List(r.Context(), domain.NominationStatus(req.Status), req.Limit, offset)
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get nominations: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", nominations)
+}
+
+// @Summary Get a nomination by ID
+// @Tags Nominations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Nomination ID"
+// @Success 200 {object} domain.Nomination "Nomination"
+// @Failure 404 {object} response.JSONResponse "Nomination not found"
+// @Router /nomination/ [post]
+func (h *NominationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid ID", nil)
+		return
+	}
+
+	n, err := h.usecase.GetByID(r.Context(), req.ID)
+	if err != nil {
+		writeError(w, "Failed to get nomination: ", err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", n)
+}
+
+// @Summary Endorse a nomination
+// @Description Adds the caller's signature. Only users with a verified email who are of age may endorse, once per nomination, and not their own.
+// @Tags Nominations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Nomination ID"
+// @Success 200 {object} domain.Nomination "Nomination with updated endorsement count"
+// @Failure 403 {object} response.JSONResponse "Not eligible"
+// @Failure 409 {object} response.JSONResponse "Already endorsed or no longer collecting"
+// @Router /nomination/endorse [post]
+func (h *NominationHandler) Endorse(w http.ResponseWriter, r *http.Request) {
+	userID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid nomination ID", nil)
+		return
+	}
+
+	n, err := h.usecase.Endorse(r.Context(), req.ID, userID)
+	if err != nil {
+		writeError(w, "Failed to endorse nomination: ", err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Nomination endorsed successfully", n)
+}
+
+// @Summary Approve a nomination
+// @Description Election officials approve a nomination that has collected its endorsements. The nominee becomes a candidate.
+// @Tags Nominations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Nomination ID"
+// @Success 201 {object} domain.Candidate "Candidate created from the nomination"
+// @Failure 400 {object} response.JSONResponse "Nomination cannot be approved"
+// @Router /nomination/approve [post]
+func (h *NominationHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	reviewerID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid nomination ID", nil)
+		return
+	}
+
+	candidate, err := h.usecase.Approve(r.Context(), req.ID, reviewerID)
+	if err != nil {
+		writeError(w, "Failed to approve nomination: ", err)
+		return
+	}
+
+	response.JSON(w, http.StatusCreated, true, "Nomination approved, candidate created", candidate)
+}
+
+// @Summary Reject a nomination
+// @Tags Nominations
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RejectRequest true "Nomination ID and reason"
+// @Success 200 {object} domain.Nomination "Nomination rejected"
+// @Failure 400 {object} response.JSONResponse "Nomination cannot be rejected"
+// @Router /nomination/reject [post]
+func (h *NominationHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	reviewerID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req RejectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid nomination ID", nil)
+		return
+	}
+
+	n, err := h.usecase.Reject(r.Context(), req.ID, reviewerID, req.Reason)
+	if err != nil {
+		writeError(w, "Failed to reject nomination: ", err)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Nomination rejected", n)
+}
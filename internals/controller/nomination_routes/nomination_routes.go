@@ -0,0 +1,65 @@
+package nomination_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/repositories"
+	"net/http"
+)
+
+func RegisterNominationRoutes(mux *http.ServeMux, handler *NominationHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
+	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			http2.SetRoute(r, route)
+			handlerFunc(w, r)
+		}
+	}
+
+	mux.Handle("/nomination/create",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "nominate")(
+				logRequest("/nomination/create", handler.Submit),
+			),
+		),
+	)
+
+	mux.Handle("/nominations",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_candidate")(
+				logRequest("/nominations", handler.List),
+			),
+		),
+	)
+
+	mux.Handle("/nomination/",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_candidate")(
+				logRequest("/nomination/", handler.GetByID),
+			),
+		),
+	)
+
+	mux.Handle("/nomination/endorse",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "endorse_nomination")(
+				logRequest("/nomination/endorse", handler.Endorse),
+			),
+		),
+	)
+
+	mux.Handle("/nomination/approve",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "review_nomination")(
+				logRequest("/nomination/approve", handler.Approve),
+			),
+		),
+	)
+
+	mux.Handle("/nomination/reject",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "review_nomination")(
+				logRequest("/nomination/reject", handler.Reject),
+			),
+		),
+	)
+}
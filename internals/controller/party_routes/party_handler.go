@@ -0,0 +1,210 @@
+package party_routes
+
+import (
+	"VoteGolang/internals/controller/http/response"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/usecases/party_usecase"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type PartyHandler struct {
+	usecase party_usecase.PartyUseCase
+	Logger  *slog.Logger
+}
+
+type IDRequest struct {
+	ID uint `json:"id" example:"1"`
+}
+
+type ResultsRequest struct {
+	Type string `json:"type" example:"presidential"`
+}
+
+func NewPartyHandler(usecase party_usecase.PartyUseCase, logger *slog.Logger) *PartyHandler {
+	return &PartyHandler{
+		usecase: usecase,
+		Logger:  logger,
+	}
+}
+
+// writeError maps use case errors to HTTP responses.
+func writeError(w http.ResponseWriter, prefix string, err error) {
+	var validationErrs domain.ValidationErrors
+	switch {
+	case errors.As(err, &validationErrs):
+		response.JSON(w, http.StatusBadRequest, false, "Invalid party", validationErrs)
+	case errors.Is(err, party_usecase.ErrPartyNotFound):
+		response.JSON(w, http.StatusNotFound, false, err.Error(), nil)
+	case errors.Is(err, party_usecase.ErrPartyExists), errors.Is(err, party_usecase.ErrPartyInUse):
+		response.JSON(w, http.StatusConflict, false, prefix+err.Error(), nil)
+	default:
+		response.JSON(w, http.StatusInternalServerError, false, prefix+err.Error(), nil)
+	}
+}
+
+// @Summary List parties
+// @Tags Parties
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.Party "List of parties"
+// @Router /parties [get]
+func (h *PartyHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	parties, err := h.usecase.GetAllParties(r.Context())
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get parties: "+err.Error(), nil)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "OK", parties)
+}
+
+// @Summary Get a party by ID
+// @Tags Parties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Party ID"
+// @Success 200 {object} domain.Party "Party"
+// @Failure 404 {object} response.JSONResponse "Party not found"
+// @Router /party/ [post]
+func (h *PartyHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid ID", nil)
+		return
+	}
+
+	party, err := h.usecase.GetParty(r.Context(), req.ID)
+	if err != nil {
+		writeError(w, "Failed to get party: ", err)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "OK", party)
+}
+
+// @Summary Create a party
+// @Description Party names are unique regardless of case and surrounding whitespace.
+// @Tags Parties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param party body domain.Party true "Party"
+// @Success 201 {object} domain.Party "Party created"
+// @Failure 400 {object} response.JSONResponse "Invalid party"
+// @Failure 409 {object} response.JSONResponse "Party already exists"
+// @Router /party/create [post]
+func (h *PartyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var party domain.Party
+	if err := json.NewDecoder(r.Body).Decode(&party); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request body: "+err.Error(), nil)
+		return
+	}
+
+	if err := h.usecase.CreateParty(r.Context(), &party); err != nil {
+		writeError(w, "Failed to create party: ", err)
+		return
+	}
+	response.JSON(w, http.StatusCreated, true, "Party created successfully", party)
+}
+
+// @Summary Update a party
+// @Description Replaces the party's name, logo, leader and description.
+// @Tags Parties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param party body domain.Party true "Party with id"
+// @Success 200 {object} domain.Party "Party updated"
+// @Failure 400 {object} response.JSONResponse "Invalid party"
+// @Failure 404 {object} response.JSONResponse "Party not found"
+// @Failure 409 {object} response.JSONResponse "Party name taken"
+// @Router /party/update [post]
+func (h *PartyHandler) Update(w http.ResponseWriter, r *http.Request) {
+	var party domain.Party
+	if err := json.NewDecoder(r.Body).Decode(&party); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid request body: "+err.Error(), nil)
+		return
+	}
+	if party.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid ID", nil)
+		return
+	}
+
+	updated, err := h.usecase.UpdateParty(r.Context(), &party)
+	if err != nil {
+		writeError(w, "Failed to update party: ", err)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "Party updated successfully", updated)
+}
+
+// @Summary Delete a party
+// @Description Only parties without candidates can be deleted.
+// @Tags Parties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Party ID"
+// @Success 200 {object} response.JSONResponse "Party deleted"
+// @Failure 404 {object} response.JSONResponse "Party not found"
+// @Failure 409 {object} response.JSONResponse "Party still has candidates"
+// @Router /party/delete [post]
+func (h *PartyHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid ID", nil)
+		return
+	}
+
+	if err := h.usecase.DeleteParty(r.Context(), req.ID); err != nil {
+		writeError(w, "Failed to delete party: ", err)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "Party deleted successfully", nil)
+}
+
+// @Summary Get party-level results
+// @Description Sums the votes of each party's candidates in one election. Candidates without a party are grouped as Independent.
+// @Tags Parties
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body ResultsRequest true "Election type"
+// @Success 200 {array} domain.PartyResult "Votes per party"
+// @Failure 400 {object} response.JSONResponse "Invalid type"
+// @Router /party/results [post]
+func (h *PartyHandler) GetResults(w http.ResponseWriter, r *http.Request) {
+	var req ResultsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON", nil)
+		return
+	}
+	if !domain.IsValidCandidateType(req.Type) {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid candidate type", nil)
+		return
+	}
+
+	results, err := h.usecase.GetResults(r.Context(), domain.CandidateType(req.Type))
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get results: "+err.Error(), nil)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "OK", results)
+}
+
+// @Summary Get candidate counts per party
+// @Tags Parties
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int64 "Candidates per party name"
+// @Router /party/facets [get]
+func (h *PartyHandler) GetFacets(w http.ResponseWriter, r *http.Request) {
+	facets, err := h.usecase.GetFacets(r.Context())
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get party facets: "+err.Error(), nil)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "OK", facets)
+}
@@ -0,0 +1,73 @@
+package party_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/repositories"
+	"net/http"
+)
+
+func RegisterPartyRoutes(mux *http.ServeMux, handler *PartyHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
+	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			http2.SetRoute(r, route)
+			handlerFunc(w, r)
+		}
+	}
+
+	mux.Handle("/parties",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_candidate")(
+				logRequest("/parties", handler.GetAll),
+			),
+		),
+	)
+
+	mux.Handle("/party/",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_candidate")(
+				logRequest("/party/", handler.GetByID),
+			),
+		),
+	)
+
+	mux.Handle("/party/results",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_candidate")(
+				logRequest("/party/results", handler.GetResults),
+			),
+		),
+	)
+
+	mux.Handle("/party/facets",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_candidate")(
+				logRequest("/party/facets", handler.GetFacets),
+			),
+		),
+	)
+
+	mux.Handle("/party/create",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "manage_party")(
+				logRequest("/party/create", handler.Create),
+			),
+		),
+	)
+
+	mux.Handle("/party/update",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "manage_party")(
+				logRequest("/party/update", handler.Update),
+			),
+		),
+	)
+
+	mux.Handle("/party/delete",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "manage_party")(
+				logRequest("/party/delete", handler.Delete),
+			),
+		),
+	)
+}
@@ -0,0 +1,86 @@
+package petition_routes
+
+import (
+	"VoteGolang/internals/controller/http/response"
+	"encoding/json"
+	"net/http"
+)
+
+type SimilarRequest struct {
+	Title       string `json:"title" example:"petition title"`
+	Description string `json:"description" example:"petition description"`
+}
+
+type MergeRequest struct {
+	SourceID uint `json:"source_id" example:"4"`
+	TargetID uint `json:"target_id" example:"3"`
+}
+
+// @Summary Find petitions similar to a draft
+// @Description Pre-check before creating a petition: returns existing petitions the user may want to sign instead.
+// @Tags Petition
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param draft body SimilarRequest true "Draft title and description"
+// @Success 200 {array} petition_data2.SimilarPetition "Likely duplicates"
+// @Failure 400 {object} response.JSONResponse "Invalid request"
+// @Failure 503 {object} response.JSONResponse "Search unavailable"
+// @Router /petition/similar [post]
+func (h *PetitionHandler) FindSimilarPetitions(w http.ResponseWriter, r *http.Request) {
+	var req SimilarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.Title == "" && req.Description == "" {
+		response.JSON(w, http.StatusBadRequest, false, "Title or description is required", nil)
+		return
+	}
+
+	similar, err := h.usecase.FindSimilarPetitions(r.Context(), req.Title, req.Description)
+	if err != nil {
+		response.JSON(w, http.StatusServiceUnavailable, false, "Failed to search similar petitions: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", similar)
+}
+
+// @Summary Merge a duplicate petition into another
+// @Description Moves the source petition's signatures to the target, skipping users who signed both, and deletes the source.
+// @Tags Petition
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param merge body MergeRequest true "Source and target petition IDs"
+// @Success 200 {object} petition_data2.Petition "Merged target petition"
+// @Failure 400 {object} response.JSONResponse "Invalid request"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Router /petition/merge [post]
+func (h *PetitionHandler) MergePetitions(w http.ResponseWriter, r *http.Request) {
+	adminID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.SourceID == 0 || req.TargetID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid petition IDs", nil)
+		return
+	}
+
+	target, err := h.usecase.MergePetitions(r.Context(), req.SourceID, req.TargetID, adminID)
+	if err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to merge petitions: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Petitions merged successfully", target)
+	h.Logger.InfoContext(r.Context(), "Petition merged", "source_id", req.SourceID, "target_id", req.TargetID, "admin_id", adminID)
+}
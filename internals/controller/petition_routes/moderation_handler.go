@@ -0,0 +1,174 @@
+package petition_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/controller/http/response"
+	petition_data2 "VoteGolang/internals/domain"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+type RejectRequest struct {
+	ID     uint   `json:"id" example:"1"`
+	Reason string `json:"reason" example:"duplicate of petition 3"`
+}
+
+// requestUserID extracts the acting user's ID from the request's bearer token.
+func (h *PetitionHandler) requestUserID(r *http.Request) (uint, error) {
+	token, err := http2.ExtractTokenFromRequest(r)
+	if err != nil {
+		return 0, fmt.Errorf("missing tokens: %w", err)
+	}
+
+	payload := &petition_data2.JwtClaims{}
+	_, err = jwt.ParseWithClaims(token, payload, func(t *jwt.Token) (interface{}, error) {
+		return h.TokenManager.Secret, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("invalid tokens: %w", err)
+	}
+	if payload.UserID == 0 {
+		return 0, fmt.Errorf("invalid userID")
+	}
+	return payload.UserID, nil
+}
+
+// @Summary Get the petition moderation queue
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param pagination body PaginationRequest true "Pagination info"
+// @Success 200 {array} petition_data2.ModerationQueueItem "Pending petitions with screening flags"
+// @Failure 400 {object} response.JSONResponse "Invalid page or limit"
+// @Failure 500 {object} response.JSONResponse "Failed to get moderation queue"
+// @Router /petition/moderation/queue [post]
+func (h *PetitionHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	var req PaginationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON", nil)
+		return
+	}
+
+	if req.Page <= 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid page number", nil)
+		return
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20 // default
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	items, err := h.usecase.GetModerationQueue(r.Context(), req.Limit, offset)
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get moderation queue: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", items)
+}
+
+// @Summary Approve a pending petition
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Petition ID"
+// @Success 200 {object} response.JSONResponse "Petition approved"
+// @Failure 400 {object} response.JSONResponse "Invalid ID or petition not pending"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Router /petition/moderation/approve [post]
+func (h *PetitionHandler) ApprovePetition(w http.ResponseWriter, r *http.Request) {
+	moderatorID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid petition ID", nil)
+		return
+	}
+
+	if err := h.usecase.ApprovePetition(r.Context(), req.ID, moderatorID); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to approve petition: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Petition approved", nil)
+	h.Logger.InfoContext(r.Context(), "Petition approved", "petition_id", req.ID, "moderator_id", moderatorID)
+}
+
+// @Summary Reject a pending petition
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param reject body RejectRequest true "Petition ID and rejection reason"
+// @Success 200 {object} response.JSONResponse "Petition rejected"
+// @Failure 400 {object} response.JSONResponse "Invalid request or petition not pending"
+// @Failure 401 {object} response.JSONResponse "Unauthorized"
+// @Router /petition/moderation/reject [post]
+func (h *PetitionHandler) RejectPetition(w http.ResponseWriter, r *http.Request) {
+	moderatorID, err := h.requestUserID(r)
+	if err != nil {
+		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, "+err.Error(), nil)
+		return
+	}
+
+	var req RejectRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid petition ID", nil)
+		return
+	}
+
+	if err := h.usecase.RejectPetition(r.Context(), req.ID, moderatorID, req.Reason); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Failed to reject petition: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "Petition rejected", nil)
+	h.Logger.InfoContext(r.Context(), "Petition rejected", "petition_id", req.ID, "moderator_id", moderatorID)
+}
+
+// @Summary Get the moderation audit trail of a petition
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id body IDRequest true "Petition ID"
+// @Success 200 {array} petition_data2.PetitionModeration "Moderation history"
+// @Failure 400 {object} response.JSONResponse "Invalid ID"
+// @Failure 500 {object} response.JSONResponse "Failed to get moderation history"
+// @Router /petition/moderation/history [post]
+func (h *PetitionHandler) GetModerationHistory(w http.ResponseWriter, r *http.Request) {
+	var req IDRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid JSON body: "+err.Error(), nil)
+		return
+	}
+	if req.ID == 0 {
+		response.JSON(w, http.StatusBadRequest, false, "Missing or invalid petition ID", nil)
+		return
+	}
+
+	history, err := h.usecase.GetModerationHistory(r.Context(), req.ID)
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get moderation history: "+err.Error(), nil)
+		return
+	}
+
+	response.JSON(w, http.StatusOK, true, "OK", history)
+}
@@ -1,14 +1,14 @@
 package petition_routes
 
 import (
-	"VoteGolang/internals/app/logging"
 	http2 "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/controller/http/response"
 	petition_data2 "VoteGolang/internals/domain"
 	"VoteGolang/internals/usecases/petition_usecase"
 	"encoding/json"
-	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 
 	"github.com/dgrijalva/jwt-go"
 )
@@ -16,23 +16,27 @@ import (
 type PetitionHandler struct {
 	usecase      petition_usecase.PetitionUseCase
 	TokenManager *petition_data2.JwtToken
-	KafkaLogger  *logging.KafkaLogger
+	Logger       *slog.Logger
 }
 
 type PaginationRequest struct {
-	Page  int `json:"page"`
-	Limit int `json:"limit"`
+	Page     int    `json:"page"`
+	Limit    int    `json:"limit"`
+	Category string `json:"category,omitempty" example:"environment"`
+	Tag      string `json:"tag,omitempty" example:"parks"`
+	// Enum values: newest, most_signed, closing_soon, trending
+	Sort string `json:"sort,omitempty" enums:"newest,most_signed,closing_soon,trending" example:"newest"`
 }
 
 type IDRequest struct {
 	ID uint `json:"id"`
 }
 
-func NewPetitionHandler(usecase petition_usecase.PetitionUseCase, tokenManager *petition_data2.JwtToken, kafkaLogger *logging.KafkaLogger) *PetitionHandler {
+func NewPetitionHandler(usecase petition_usecase.PetitionUseCase, tokenManager *petition_data2.JwtToken, logger *slog.Logger) *PetitionHandler {
 	return &PetitionHandler{
 		usecase:      usecase,
 		TokenManager: tokenManager,
-		KafkaLogger:  kafkaLogger,
+		Logger:       logger,
 	}
 }
 
@@ -48,7 +52,7 @@ func NewPetitionHandler(usecase petition_usecase.PetitionUseCase, tokenManager *
 // @Failure 500 {object} response.JSONResponse "Internal server error"
 // @Router /petition/create [post]
 func (h *PetitionHandler) CreatePetition(w http.ResponseWriter, r *http.Request) {
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Petition create attempt from %s", r.RemoteAddr))
+	h.Logger.InfoContext(r.Context(), "Petition create attempt", "remote_addr", r.RemoteAddr)
 	token, err := http2.ExtractTokenFromRequest(r)
 	if err != nil {
 		response.JSON(w, http.StatusUnauthorized, false, "Unauthorized, missing tokens: "+err.Error(), nil)
@@ -77,15 +81,20 @@ func (h *PetitionHandler) CreatePetition(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if p.Category != "" && !petition_data2.IsValidPetitionCategory(string(p.Category)) {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid petition category", nil)
+		return
+	}
+
 	p.UserID = userID
 
-	if err := h.usecase.CreatePetition(&p); err != nil {
+	if err := h.usecase.CreatePetition(r.Context(), &p); err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to create petition: "+err.Error(), nil)
 		return
 	}
 
 	response.JSON(w, http.StatusCreated, true, "Petition created successfully", p)
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Petition created by user %d: %s", userID, p.Title))
+	h.Logger.InfoContext(r.Context(), "Petition created", "user_id", userID, "title", p.Title)
 }
 
 // @Summary Get all petitions
@@ -97,7 +106,7 @@ func (h *PetitionHandler) CreatePetition(w http.ResponseWriter, r *http.Request)
 // @Router /petition/all [get]
 func (h *PetitionHandler) GetAllPetitions(w http.ResponseWriter, r *http.Request) {
 
-	petitions, err := h.usecase.GetAllPetitions()
+	petitions, err := h.usecase.GetAllPetitions(r.Context())
 	if err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to get all paginated petitions: "+err.Error(), petitions)
 		return
@@ -106,6 +115,7 @@ func (h *PetitionHandler) GetAllPetitions(w http.ResponseWriter, r *http.Request
 }
 
 // @Summary Get petitions by page
+// @Description Supports filtering by category and tag, and sorting by newest, most_signed, closing_soon or trending.
 // @Tags Petition
 // @Accept json
 // @Produce json
@@ -129,9 +139,23 @@ func (h *PetitionHandler) GetPetitionsByPage(w http.ResponseWriter, r *http.Requ
 	if req.Limit <= 0 {
 		req.Limit = 5 // default
 	}
+	if req.Category != "" && !petition_data2.IsValidPetitionCategory(req.Category) {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid petition category", nil)
+		return
+	}
+	if req.Sort != "" && !petition_data2.IsValidPetitionSort(req.Sort) {
+		response.JSON(w, http.StatusBadRequest, false, "Invalid sort: must be one of newest, most_signed, closing_soon, trending", nil)
+		return
+	}
+
+	filter := petition_data2.PetitionFilter{
+		Category: petition_data2.PetitionCategory(req.Category),
+		Tag:      strings.ToLower(strings.TrimSpace(req.Tag)),
+		Sort:     petition_data2.PetitionSort(req.Sort),
+	}
 
 	offset := (req.Page - 1) * req.Limit
-	petitions, err := h.usecase.GetAllPetitionsPaginated(req.Limit, offset)
+	petitions, err := h.usecase.GetAllPetitionsPaginated(r.Context(), filter, req.Limit, offset)
 	if err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to get petitions: "+err.Error(), nil)
 		return
@@ -140,6 +164,22 @@ func (h *PetitionHandler) GetPetitionsByPage(w http.ResponseWriter, r *http.Requ
 	response.JSON(w, http.StatusOK, true, "OK", petitions)
 }
 
+// @Summary Get petition counts per category
+// @Tags Petition
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]int64 "Number of petitions per category"
+// @Failure 500 {object} response.JSONResponse "Failed to get category counts"
+// @Router /petition/categories [get]
+func (h *PetitionHandler) GetCategoryCounts(w http.ResponseWriter, r *http.Request) {
+	counts, err := h.usecase.GetCategoryCounts(r.Context())
+	if err != nil {
+		response.JSON(w, http.StatusInternalServerError, false, "Failed to get category counts: "+err.Error(), nil)
+		return
+	}
+	response.JSON(w, http.StatusOK, true, "OK", counts)
+}
+
 // @Summary Get petition by ID
 // @Tags Petition
 // @Accept json
@@ -163,7 +203,7 @@ func (h *PetitionHandler) GetPetitionByID(w http.ResponseWriter, r *http.Request
 		return
 	}
 	// Fetch petition
-	petition, err := h.usecase.GetPetitionByID(req.ID)
+	petition, err := h.usecase.GetPetitionByID(r.Context(), req.ID)
 	if err != nil {
 		response.JSON(w, http.StatusNotFound, false, "Petition not found: "+err.Error(), nil)
 		return
@@ -184,7 +224,7 @@ func (h *PetitionHandler) GetPetitionByID(w http.ResponseWriter, r *http.Request
 // @Failure 500 {object} response.JSONResponse "Failed to vote"
 // @Router /petition/vote [post]
 func (h *PetitionHandler) Vote(w http.ResponseWriter, r *http.Request) {
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Petition vote attempt from %s", r.RemoteAddr))
+	h.Logger.InfoContext(r.Context(), "Petition vote attempt", "remote_addr", r.RemoteAddr)
 
 	token, err := http2.ExtractTokenFromRequest(r)
 	if err != nil {
@@ -213,14 +253,14 @@ func (h *PetitionHandler) Vote(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err = h.usecase.Vote(userID, voteReq.PetitionID, voteReq.VoteType)
+	err = h.usecase.Vote(r.Context(), userID, voteReq.PetitionID, voteReq.VoteType)
 	if err != nil {
 		// Check if it's an "already voted" error - return 200 for idempotency
 		if err.Error() == "user has already voted" {
-			h.KafkaLogger.Log("INFO", fmt.Sprintf("Duplicate petition vote attempt: user %d for petition %d", userID, voteReq.PetitionID))
+			h.Logger.InfoContext(r.Context(), "Duplicate petition vote attempt", "user_id", userID, "petition_id", voteReq.PetitionID)
 
 			// Fetch petition to return current state
-			petition, fetchErr := h.usecase.GetPetitionByID(voteReq.PetitionID)
+			petition, fetchErr := h.usecase.GetPetitionByID(r.Context(), voteReq.PetitionID)
 			if fetchErr != nil {
 				response.JSON(w, http.StatusOK, true, "Vote already recorded", nil)
 				return
@@ -233,13 +273,13 @@ func (h *PetitionHandler) Vote(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Fetch updated petition
-	petition, err := h.usecase.GetPetitionByID(voteReq.PetitionID)
+	petition, err := h.usecase.GetPetitionByID(r.Context(), voteReq.PetitionID)
 	if err != nil {
 		response.JSON(w, http.StatusOK, true, "Vote recorded successfully", nil)
 		return
 	}
 
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Petition vote success: user %d voted on petition %d", userID, voteReq.PetitionID))
+	h.Logger.InfoContext(r.Context(), "Petition vote success", "user_id", userID, "petition_id", voteReq.PetitionID)
 	response.JSON(w, http.StatusOK, true, "Vote recorded successfully", petition)
 }
 
@@ -291,11 +331,11 @@ func (h *PetitionHandler) DeletePetition(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Perform delete
-	if err := h.usecase.DeletePetition(req.ID); err != nil {
+	if err := h.usecase.DeletePetition(r.Context(), req.ID); err != nil {
 		response.JSON(w, http.StatusInternalServerError, false, "Failed to delete petition: "+err.Error(), nil)
 		return
 	}
 
 	response.JSON(w, http.StatusOK, true, "Petition deleted successfully", nil)
-	h.KafkaLogger.Log("INFO", fmt.Sprintf("Petition deleted: %d", req.ID))
+	h.Logger.InfoContext(r.Context(), "Petition deleted", "petition_id", req.ID)
 }
@@ -4,20 +4,13 @@ import (
 	http2 "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/domain"
 	"VoteGolang/internals/infrastructure/repositories"
-	"log"
 	"net/http"
 )
 
 func RegisterPetitionRoutes(mux *http.ServeMux, handler *PetitionHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
 	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
-			token, err := http2.ExtractTokenFromRequest(r)
-			if err != nil {
-				log.Printf("Failed to extract tokens: %v", err)
-			} else {
-				log.Printf("Accessing %s route | Method: %s | URL: %s | Token: %s", route, r.Method, r.URL.Path, token)
-			}
-
+			http2.SetRoute(r, route)
 			handlerFunc(w, r)
 		}
 	}
@@ -45,6 +38,14 @@ func RegisterPetitionRoutes(mux *http.ServeMux, handler *PetitionHandler, tokenM
 		),
 	)
 
+	mux.Handle("/petition/categories",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_petition")(
+				logRequest("/petition/petition_repository/categories", handler.GetCategoryCounts),
+			),
+		),
+	)
+
 	mux.Handle("/petition/",
 		http2.JWTMiddleware(tokenManager)(
 			http2.RBACMiddleware(rbacRepo, "read_petition")(
@@ -61,6 +62,54 @@ func RegisterPetitionRoutes(mux *http.ServeMux, handler *PetitionHandler, tokenM
 		),
 	)
 
+	mux.Handle("/petition/similar",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "create_petition")(
+				logRequest("/petition/similar", handler.FindSimilarPetitions),
+			),
+		),
+	)
+
+	mux.Handle("/petition/merge",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "merge_petition")(
+				logRequest("/petition/merge", handler.MergePetitions),
+			),
+		),
+	)
+
+	mux.Handle("/petition/moderation/queue",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "moderate_petition")(
+				logRequest("/petition/moderation/queue", handler.GetModerationQueue),
+			),
+		),
+	)
+
+	mux.Handle("/petition/moderation/approve",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "moderate_petition")(
+				logRequest("/petition/moderation/approve", handler.ApprovePetition),
+			),
+		),
+	)
+
+	mux.Handle("/petition/moderation/reject",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "moderate_petition")(
+				logRequest("/petition/moderation/reject", handler.RejectPetition),
+			),
+		),
+	)
+
+	mux.Handle("/petition/moderation/history",
+		http2.JWTMiddleware(tokenManager)(
+			http2.RBACMiddleware(rbacRepo, "read_moderation_log")(
+				logRequest("/petition/moderation/history", handler.GetModerationHistory),
+			),
+		),
+	)
+
 	mux.Handle("/petition/delete", http2.JWTMiddleware(tokenManager)(
 		http2.RBACMiddleware(rbacRepo, "delete_petition")(
 			logRequest("/petition/petition_repository/delete", handler.DeletePetition),
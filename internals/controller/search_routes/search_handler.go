@@ -30,7 +30,7 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 	searchType := pathParts[1]
 
-	results, err := h.searcher.Search(searchType, query)
+	results, err := h.searcher.Search(r.Context(), searchType, query)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3,6 +3,7 @@ package search_routes
 import (
 	"net/http"
 
+	http2 "VoteGolang/internals/controller/http"
 	"VoteGolang/internals/infrastructure/search"
 )
 
@@ -10,6 +11,10 @@ import (
 func SetupRoutes(mux *http.ServeMux, searcher search.Search) {
 	handler := NewSearchHandler(searcher)
 
-	mux.HandleFunc("/search/candidates", handler.Search)
-	mux.HandleFunc("/search/petitions", handler.Search)
+	for _, route := range []string{"/search/candidates", "/search/petitions", "/search/comments"} {
+		mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+			http2.SetRoute(r, route)
+			handler.Search(w, r)
+		})
+	}
 }
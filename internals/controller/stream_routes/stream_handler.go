@@ -0,0 +1,233 @@
+package stream_routes
+
+import (
+	"VoteGolang/internals/app/logging"
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/controller/http/response"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/realtime"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// keepAliveInterval keeps idle connections from being closed by proxies.
+	keepAliveInterval = 25 * time.Second
+	writeTimeout      = 10 * time.Second
+)
+
+type StreamHandler struct {
+	hub      *realtime.Hub
+	upgrader websocket.Upgrader
+	Logger   *slog.Logger
+}
+
+// NewStreamHandler accepts WebSocket upgrades from the same browser origins
+// CORS allows.
+func NewStreamHandler(hub *realtime.Hub, allowedOrigins []string, logger *slog.Logger) *StreamHandler {
+	return &StreamHandler{
+		hub: hub,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin(allowedOrigins),
+		},
+		Logger: logger,
+	}
+}
+
+// checkOrigin allows non-browser clients and the allowed origins.
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || http2.OriginAllowed(allowed, origin)
+	}
+}
+
+// topicFromRequest builds the stream topic from ?type= (elections) or ?id= (petitions).
+func topicFromRequest(r *http.Request, kind string) (string, error) {
+	if kind == "election" {
+		t := r.URL.Query().Get("type")
+		if !domain.IsValidCandidateType(t) {
+			return "", fmt.Errorf("invalid candidate type")
+		}
+		return domain.ElectionTallyTopic(domain.CandidateType(t)), nil
+	}
+
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 10, 64)
+	if err != nil || id == 0 {
+		return "", fmt.Errorf("missing or invalid petition ID")
+	}
+	return domain.PetitionTallyTopic(uint(id)), nil
+}
+
+// @Summary Stream election results (SSE)
+// @Description Server-sent events with the vote counts of an election, sent on connect and then at most once per second while votes come in. Elections configured to hide results while open send hidden=true and no counts until voting closes. The token may be passed as access_token.
+// @Tags Streaming
+// @Produce text/event-stream
+// @Param type query string true "Candidate type" example(presidential)
+// @Param access_token query string false "Access token, for clients that cannot set headers"
+// @Success 200 {object} domain.TallySnapshot "tally events"
+// @Router /stream/election [get]
+func (h *StreamHandler) StreamElection(w http.ResponseWriter, r *http.Request) {
+	h.serveSSE(w, r, "election")
+}
+
+// @Summary Stream petition signatures (SSE)
+// @Description Server-sent events with the signature counts of a petition, sent on connect and then at most once per second while votes come in.
+// @Tags Streaming
+// @Produce text/event-stream
+// @Param id query int true "Petition ID"
+// @Param access_token query string false "Access token, for clients that cannot set headers"
+// @Success 200 {object} domain.TallySnapshot "tally events"
+// @Router /stream/petition [get]
+func (h *StreamHandler) StreamPetition(w http.ResponseWriter, r *http.Request) {
+	h.serveSSE(w, r, "petition")
+}
+
+// @Summary Stream election results (WebSocket)
+// @Description WebSocket variant of /stream/election; every message is a TallySnapshot.
+// @Tags Streaming
+// @Param type query string true "Candidate type" example(presidential)
+// @Param access_token query string false "Access token, for clients that cannot set headers"
+// @Router /ws/election [get]
+func (h *StreamHandler) WebSocketElection(w http.ResponseWriter, r *http.Request) {
+	h.serveWebSocket(w, r, "election")
+}
+
+// @Summary Stream petition signatures (WebSocket)
+// @Description WebSocket variant of /stream/petition; every message is a TallySnapshot.
+// @Tags Streaming
+// @Param id query int true "Petition ID"
+// @Param access_token query string false "Access token, for clients that cannot set headers"
+// @Router /ws/petition [get]
+func (h *StreamHandler) WebSocketPetition(w http.ResponseWriter, r *http.Request) {
+	h.serveWebSocket(w, r, "petition")
+}
+
+func (h *StreamHandler) serveSSE(w http.ResponseWriter, r *http.Request, kind string) {
+	topic, err := topicFromRequest(r, kind)
+	if err != nil {
+		response.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		response.JSON(w, http.StatusInternalServerError, false, "Streaming unsupported", nil)
+		return
+	}
+
+	updates, cancel, err := h.hub.Subscribe(r.Context(), topic)
+	if errors.Is(err, realtime.ErrHubClosed) {
+		response.JSON(w, http.StatusServiceUnavailable, false, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.JSON(w, http.StatusNotFound, false, "Failed to subscribe: "+err.Error(), nil)
+		return
+	}
+	defer cancel()
+
+	// Streams outlive the server's write timeout
+	if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil {
+		h.Logger.WarnContext(r.Context(), "Failed to clear write deadline for stream", logging.Err(err))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data, ok := <-updates:
+			if !ok {
+				// The server is shutting down
+				return
+			}
+			if _, err := fmt.Fprintf(w, "event: tally\ndata: %s\n\n", data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func (h *StreamHandler) serveWebSocket(w http.ResponseWriter, r *http.Request, kind string) {
+	topic, err := topicFromRequest(r, kind)
+	if err != nil {
+		response.JSON(w, http.StatusBadRequest, false, err.Error(), nil)
+		return
+	}
+
+	updates, cancel, err := h.hub.Subscribe(r.Context(), topic)
+	if errors.Is(err, realtime.ErrHubClosed) {
+		response.JSON(w, http.StatusServiceUnavailable, false, err.Error(), nil)
+		return
+	}
+	if err != nil {
+		response.JSON(w, http.StatusNotFound, false, "Failed to subscribe: "+err.Error(), nil)
+		return
+	}
+	defer cancel()
+
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade has already replied to the client
+		return
+	}
+	defer conn.Close()
+
+	// Clients only listen; reading is needed to notice when they go away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case data, ok := <-updates:
+			if !ok {
+				// The server is shutting down; clients may reconnect to another instance
+				conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"),
+					time.Now().Add(writeTimeout))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package stream_routes
+
+import (
+	http2 "VoteGolang/internals/controller/http"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/repositories"
+	"net/http"
+)
+
+func RegisterStreamRoutes(mux *http.ServeMux, handler *StreamHandler, tokenManager domain.TokenManager, rbacRepo *repositories.RBACRepository) {
+	logRequest := func(route string, handlerFunc http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			http2.SetRoute(r, route)
+			handlerFunc(w, r)
+		}
+	}
+
+	routes := []struct {
+		path    string
+		access  string
+		handler http.HandlerFunc
+	}{
+		{"/stream/election", "read_candidate", handler.StreamElection},
+		{"/stream/petition", "read_petition", handler.StreamPetition},
+		{"/ws/election", "read_candidate", handler.WebSocketElection},
+		{"/ws/petition", "read_petition", handler.WebSocketPetition},
+	}
+	for _, route := range routes {
+		mux.Handle(route.path,
+			http2.QueryTokenMiddleware(
+				http2.JWTMiddleware(tokenManager)(
+					http2.RBACMiddleware(rbacRepo, route.access)(
+						logRequest(route.path, route.handler),
+					),
+				),
+			),
+		)
+	}
+}
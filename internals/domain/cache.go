@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrCacheMiss is returned by Cache.Get for keys that are not cached.
+var ErrCacheMiss = errors.New("cache miss")
+
+// Cache keeps serialized values under string keys for a limited time. It is
+// only an optimization: callers treat a failed read as a miss and ignore
+// failed writes.
+type Cache interface {
+	// Get returns the value of key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePrefix removes every key starting with prefix and returns how
+	// many were removed.
+	DeletePrefix(ctx context.Context, prefix string) (int, error)
+	// Increment adds one to the counter at key and returns the new value. A
+	// counter created by Increment expires after ttl.
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"context"
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,30 +10,183 @@ import (
 
 // Candidate represents a candidate for election.
 type Candidate struct {
-	ID             uint           `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
-	Name           string         `gorm:"type:varchar(255);not null" example:"Beksultan" json:"name"`
-	Photo          *string        `gorm:"type:varchar(255)" example:"link" json:"photo"`
-	Education      *string        `gorm:"type:varchar(255)" example:"KBTU" json:"education"`
-	Age            int            `gorm:"not null" example:"20" json:"age"`
-	Party          *string        `gorm:"type:varchar(255)" example:"Jastar" json:"party"`
-	Region         *string        `gorm:"type:varchar(255)" example:"SKO" json:"region"`
-	Votes          int            `gorm:"default:0" swaggerignore:"true" json:"votes"`
-	Type           CandidateType  `gorm:"type:varchar(255);not null" example:"manager" json:"type"`
-	VotingStart    time.Time      `json:"voting_start" gorm:"type:datetime" example:"2025-11-12T09:00:00+05:00"`
-	VotingDeadline time.Time      `json:"voting_deadline" gorm:"type:datetime" example:"2026-11-12T09:00:00+05:00"`
-	DeletedAt      gorm.DeletedAt `json:"-" swaggerignore:"true"`
-	CreatedAt      time.Time      `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
-	UpdatedAt      time.Time      `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
+	ID              uint             `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
+	Name            string           `gorm:"type:varchar(255);not null" example:"Beksultan" json:"name"`
+	Photo           *string          `gorm:"type:varchar(255)" example:"link" json:"photo"`
+	PhotoAssetID    *string          `gorm:"type:varchar(36)" example:"5f0c8a4e-2d7b-4c1e-9a43-0b6f5e2d9c11" json:"photo_asset_id,omitempty"`
+	Education       *string          `gorm:"type:varchar(255)" example:"KBTU" json:"education"`
+	Age             int              `gorm:"not null" example:"20" json:"age"`
+	PartyID         *uint            `gorm:"index" example:"1" json:"party_id"`
+	Party           *Party           `gorm:"foreignKey:PartyID" swaggerignore:"true" json:"party_info,omitempty"`
+	Region          *string          `gorm:"type:varchar(255)" example:"SKO" json:"region"`
+	Biography       *string          `gorm:"type:text" example:"Born in Shymkent, studied at KBTU" json:"biography,omitempty"`
+	Manifesto       *string          `gorm:"type:text" example:"Free public transport" json:"manifesto,omitempty"`
+	ManifestoPoints []ManifestoPoint `gorm:"foreignKey:CandidateID" json:"manifesto_points,omitempty"`
+	SocialLinks     []SocialLink     `gorm:"foreignKey:CandidateID" json:"social_links,omitempty"`
+	Photos          []CandidatePhoto `gorm:"foreignKey:CandidateID" json:"photos,omitempty"`
+	Votes           int              `gorm:"default:0" swaggerignore:"true" json:"votes"`
+	Type            CandidateType    `gorm:"type:varchar(255);not null" example:"manager" json:"type"`
+	VotingStart     time.Time        `json:"voting_start" gorm:"type:datetime" example:"2025-11-12T09:00:00+05:00"`
+	VotingDeadline  time.Time        `json:"voting_deadline" gorm:"type:datetime" example:"2026-11-12T09:00:00+05:00"`
+	DeletedAt       gorm.DeletedAt   `json:"-" swaggerignore:"true"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
+}
+
+// MarshalJSON adds "party", the name of the candidate's party, next to
+// "party_info": clients and the candidates search index have always had the
+// party as a string.
+func (c Candidate) MarshalJSON() ([]byte, error) {
+	type candidate Candidate
+	var party *string
+	if c.Party != nil {
+		party = &c.Party.Name
+	}
+	return json.Marshal(struct {
+		candidate
+		PartyName *string `json:"party"`
+	}{candidate(c), party})
 }
 
 // CandidateRepository retrieves candidate data.
 type CandidateRepository interface {
-	Create(candidate *Candidate) error
-	GetAllByType(candidateType string) ([]Candidate, error)
-	GetByID(id uint) (*Candidate, error)
-	IncrementVote(id uint) error
-	GetAllByTypePaginated(candidateType string, limit, offset int) ([]Candidate, error)
-	DeleteByID(id uint) error
+	Create(ctx context.Context, candidate *Candidate) error
+	GetAllByType(ctx context.Context, candidateType string) ([]Candidate, error)
+	GetByID(ctx context.Context, id uint) (*Candidate, error)
+	GetByIDs(ctx context.Context, ids []uint) ([]Candidate, error)
+	IncrementVote(ctx context.Context, id uint) error
+	GetAllByTypePaginated(ctx context.Context, candidateType string, limit, offset int) ([]Candidate, error)
+	DeleteByID(ctx context.Context, id uint) error
+	// Update saves the candidate's profile, replaces its social links, photos
+	// and manifesto points and records changes in its history, all in one
+	// transaction.
+	Update(ctx context.Context, candidate *Candidate, changes []CandidateChange) error
+	GetHistory(ctx context.Context, id uint) ([]CandidateChange, error)
+	// CloseVoting moves the deadline of every candidate of a type that is
+	// still open to at, and returns how many were changed.
+	CloseVoting(ctx context.Context, candidateType CandidateType, at time.Time) (int64, error)
+}
+
+// SocialLink is a candidate's profile on an external platform.
+type SocialLink struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"-"`
+	CandidateID uint   `gorm:"not null;index" swaggerignore:"true" json:"-"`
+	Platform    string `gorm:"type:varchar(50);not null" example:"instagram" json:"platform"`
+	URL         string `gorm:"type:varchar(255);not null" example:"https://instagram.com/beksultan" json:"url"`
+}
+
+// CandidatePhoto is one photo in a candidate's gallery, ordered by Position.
+type CandidatePhoto struct {
+	ID          uint   `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"-"`
+	CandidateID uint   `gorm:"not null;index" swaggerignore:"true" json:"-"`
+	URL         string `gorm:"type:varchar(255);not null" example:"https://cdn.example.com/photo.jpg" json:"url"`
+	Position    int    `gorm:"not null;default:0" example:"0" json:"position"`
+}
+
+// ManifestoPoint is one position of a candidate's manifesto, tagged by topic
+// so positions of different candidates can be compared side by side.
+type ManifestoPoint struct {
+	ID          uint           `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"-"`
+	CandidateID uint           `gorm:"not null;index" swaggerignore:"true" json:"-"`
+	Topic       ManifestoTopic `gorm:"type:varchar(50);not null;index" example:"transport" json:"topic"`
+	Text        string         `gorm:"type:text;not null" example:"Free public transport for students" json:"text"`
+	Position    int            `gorm:"not null;default:0" example:"0" json:"position"`
+}
+
+type ManifestoTopic string
+
+const (
+	TopicEconomy        ManifestoTopic = "economy"
+	TopicEducation      ManifestoTopic = "education"
+	TopicHealthcare     ManifestoTopic = "healthcare"
+	TopicEnvironment    ManifestoTopic = "environment"
+	TopicInfrastructure ManifestoTopic = "infrastructure"
+	TopicTransport      ManifestoTopic = "transport"
+	TopicSocial         ManifestoTopic = "social"
+	TopicSecurity       ManifestoTopic = "security"
+	TopicGovernance     ManifestoTopic = "governance"
+	TopicCulture        ManifestoTopic = "culture"
+	TopicOther          ManifestoTopic = "other"
+)
+
+// ManifestoTopics lists the topics in the order comparisons show them.
+var ManifestoTopics = []ManifestoTopic{
+	TopicEconomy, TopicEducation, TopicHealthcare, TopicEnvironment, TopicInfrastructure,
+	TopicTransport, TopicSocial, TopicSecurity, TopicGovernance, TopicCulture, TopicOther,
+}
+
+func IsValidManifestoTopic(t string) bool {
+	for _, topic := range ManifestoTopics {
+		if string(topic) == t {
+			return true
+		}
+	}
+	return false
+}
+
+// CandidateComparison lines up the profiles of several candidates. Every
+// topic lists positions in the same order as Candidates.
+type CandidateComparison struct {
+	Candidates []CandidateProfile `json:"candidates"`
+	Topics     []TopicComparison  `json:"topics"`
+}
+
+// CandidateProfile holds the comparable fields of a candidate, trimmed and
+// with the party resolved to its name.
+type CandidateProfile struct {
+	ID           uint          `json:"id"`
+	Name         string        `json:"name"`
+	Type         CandidateType `json:"type"`
+	PhotoAssetID *string       `json:"photo_asset_id,omitempty"`
+	Education    *string       `json:"education"`
+	Age          int           `json:"age"`
+	PartyID      *uint         `json:"party_id"`
+	Party        *string       `json:"party"`
+	Region       *string       `json:"region"`
+}
+
+type TopicComparison struct {
+	Topic     ManifestoTopic       `json:"topic"`
+	Positions []CandidatePositions `json:"positions"`
+}
+
+// CandidatePositions are one candidate's manifesto points on a topic; Points
+// is empty when the candidate has not taken a position.
+type CandidatePositions struct {
+	CandidateID uint     `json:"candidate_id"`
+	Points      []string `json:"points"`
+}
+
+// CandidateChange records one field changed by a profile update.
+type CandidateChange struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CandidateID uint      `gorm:"not null;index" json:"candidate_id"`
+	UserID      uint      `gorm:"not null" json:"user_id"`
+	Field       string    `gorm:"type:varchar(50);not null" json:"field"`
+	OldValue    *string   `gorm:"type:text" json:"old_value"`
+	NewValue    *string   `gorm:"type:text" json:"new_value"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// CandidateUpdate is a partial update of a candidate profile. Nil fields are
+// left unchanged; SocialLinks, Photos and ManifestoPoints replace the existing
+// lists when set.
+type CandidateUpdate struct {
+	Name         *string `json:"name,omitempty" example:"Beksultan"`
+	Photo        *string `json:"photo,omitempty" example:"https://cdn.example.com/photo.jpg"`
+	PhotoAssetID *string `json:"photo_asset_id,omitempty" example:"5f0c8a4e-2d7b-4c1e-9a43-0b6f5e2d9c11"`
+	Education    *string `json:"education,omitempty" example:"KBTU"`
+	Age          *int    `json:"age,omitempty" example:"21"`
+	// PartyID moves the candidate to another party; 0 makes them independent.
+	PartyID         *uint             `json:"party_id,omitempty" example:"1"`
+	Region          *string           `json:"region,omitempty" example:"SKO"`
+	Biography       *string           `json:"biography,omitempty" example:"Born in Shymkent, studied at KBTU"`
+	Manifesto       *string           `json:"manifesto,omitempty" example:"Free public transport"`
+	SocialLinks     *[]SocialLink     `json:"social_links,omitempty"`
+	Photos          *[]string         `json:"photos,omitempty"`
+	ManifestoPoints *[]ManifestoPoint `json:"manifesto_points,omitempty"`
+	VotingStart     *time.Time        `json:"voting_start,omitempty" example:"2025-11-12T09:00:00+05:00"`
+	VotingDeadline  *time.Time        `json:"voting_deadline,omitempty" example:"2026-11-12T09:00:00+05:00"`
 }
 
 type CandidateType string
@@ -42,6 +197,9 @@ const (
 	Manager      CandidateType = "manager"
 )
 
+// CandidateTypes lists the elections.
+var CandidateTypes = []CandidateType{Presidential, Deputy, Manager}
+
 func IsValidCandidateType(t string) bool {
 	switch CandidateType(t) {
 	case Presidential, Deputy, Manager:
@@ -50,3 +208,24 @@ func IsValidCandidateType(t string) bool {
 		return false
 	}
 }
+
+// Election summarizes the candidates of one type. It is open while any of
+// them can receive votes.
+type Election struct {
+	Type       CandidateType `json:"type"`
+	Candidates int           `json:"candidates"`
+	Votes      int           `json:"votes"`
+	OpensAt    time.Time     `json:"opens_at"`
+	ClosesAt   time.Time     `json:"closes_at"`
+	Open       bool          `json:"open"`
+}
+
+// CandidateResult is one candidate's line in the results of an election.
+type CandidateResult struct {
+	CandidateID uint    `json:"candidate_id"`
+	Name        string  `json:"name"`
+	Party       *string `json:"party"`
+	Region      *string `json:"region"`
+	Votes       int     `json:"votes"`
+	Share       float64 `json:"share"`
+}
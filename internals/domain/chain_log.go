@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ChainLogEntry is a blockchain log call made while the node was
+// unavailable. It is replayed once the node is back. GaveUpAt is set when
+// replaying cannot succeed, e.g. the transaction was broadcast but its
+// outcome is unknown; such entries need a manual check.
+type ChainLogEntry struct {
+	ID        uint64     `gorm:"primaryKey;autoIncrement"`
+	Action    string     `gorm:"type:varchar(50);not null"`
+	Payload   []byte     `gorm:"not null"`
+	Attempts  int        `gorm:"not null;default:0"`
+	LastError *string    `gorm:"type:text"`
+	CreatedAt time.Time  `gorm:"autoCreateTime"`
+	GaveUpAt  *time.Time `gorm:"index"`
+}
+
+type ChainLogQueue interface {
+	Enqueue(ctx context.Context, action string, payload []byte) error
+	// Pending returns the oldest entries still to be replayed.
+	Pending(ctx context.Context, limit int) ([]ChainLogEntry, error)
+	Delete(ctx context.Context, id uint64) error
+	MarkFailed(ctx context.Context, id uint64, reason string) error
+	GiveUp(ctx context.Context, id uint64, reason string) error
+	CountPending(ctx context.Context) (int64, error)
+}
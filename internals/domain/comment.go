@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PetitionComment is a comment in a petition's discussion thread. Replies
+// point at their parent comment through ParentID.
+type PetitionComment struct {
+	ID         uint           `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
+	PetitionID uint           `gorm:"not null;index" example:"1" json:"petition_id"`
+	UserID     uint           `gorm:"not null;index" swaggerignore:"true" json:"user_id"`
+	ParentID   *uint          `gorm:"index" example:"1" json:"parent_id,omitempty"`
+	Body       string         `gorm:"type:text;not null" example:"I support this" json:"body"`
+	Hidden     bool           `gorm:"default:false" swaggerignore:"true" json:"hidden"`
+	EditedAt   *time.Time     `swaggerignore:"true" json:"edited_at,omitempty"`
+	DeletedAt  gorm.DeletedAt `json:"-" swaggerignore:"true"`
+	CreatedAt  time.Time      `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
+}
+
+// CommentReport is an abuse report filed by a user against a comment.
+type CommentReport struct {
+	ID        uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	CommentID uint      `gorm:"not null;uniqueIndex:idx_comment_reporter" json:"comment_id"`
+	UserID    uint      `gorm:"not null;uniqueIndex:idx_comment_reporter" json:"user_id"`
+	Reason    string    `gorm:"type:varchar(255);not null" json:"reason"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ReportedComment is a comment together with the number of abuse reports against it.
+type ReportedComment struct {
+	PetitionComment
+	Reports int64 `json:"reports"`
+}
+
+// CommentPage is one page of a cursor-paginated comment listing. NextCursor is
+// empty when there are no more comments.
+type CommentPage struct {
+	Comments   []PetitionComment `json:"comments"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// PetitionCommentRepository manages petition comments and abuse reports.
+type PetitionCommentRepository interface {
+	Create(ctx context.Context, comment *PetitionComment) error
+	GetByID(ctx context.Context, id uint) (*PetitionComment, error)
+	// List returns visible comments of a petition under parentID (nil for
+	// top-level comments) with IDs below beforeID, newest first. A zero
+	// beforeID starts from the newest comment.
+	List(ctx context.Context, petitionID uint, parentID *uint, beforeID uint, limit int) ([]PetitionComment, error)
+	// ListVisible returns visible comments of every petition with IDs above
+	// afterID, oldest first.
+	ListVisible(ctx context.Context, afterID uint, limit int) ([]PetitionComment, error)
+	UpdateBody(ctx context.Context, id uint, body string, editedAt time.Time) error
+	// SetHidden hides or restores a comment. Restoring also clears its reports.
+	SetHidden(ctx context.Context, id uint, hidden bool) error
+	Delete(ctx context.Context, id uint) error
+	Report(ctx context.Context, report *CommentReport) error
+	GetReported(ctx context.Context, limit, offset int) ([]ReportedComment, error)
+}
@@ -0,0 +1,259 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventSource names this service in every event envelope.
+const EventSource = "vote-service"
+
+type EventType string
+
+const (
+	EventVoteCast            EventType = "VoteCast"
+	EventCandidateCreated    EventType = "CandidateCreated"
+	EventPetitionCreated     EventType = "PetitionCreated"
+	EventPetitionVoteCast    EventType = "PetitionVoteCast"
+	EventPetitionGoalReached EventType = "PetitionGoalReached"
+	EventUserRegistered      EventType = "UserRegistered"
+	EventUserEmailVerified   EventType = "UserEmailVerified"
+)
+
+// Event is the envelope every business event is published in. Data holds the
+// payload of Type at schema Version; a payload change that is not backwards
+// compatible bumps the version. Consumers deduplicate by ID, since delivery
+// is at-least-once.
+type Event struct {
+	ID            string          `json:"id" example:"0b6f5e2d-9c11-4c1e-9a43-5f0c8a4e2d7b"`
+	Type          EventType       `json:"type" example:"VoteCast"`
+	Version       int             `json:"version" example:"1"`
+	Source        string          `json:"source" example:"vote-service"`
+	AggregateType string          `json:"aggregate_type" example:"candidate"`
+	AggregateID   string          `json:"aggregate_id" example:"12"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Data          json.RawMessage `json:"data" swaggertype:"object"`
+}
+
+// Topic is the Kafka topic of the event; each aggregate type has its own.
+func (e Event) Topic() string {
+	return EventTopic(e.AggregateType)
+}
+
+// EventTopic returns the topic events of an aggregate type are published to.
+func EventTopic(aggregateType string) string {
+	return "events." + aggregateType
+}
+
+// EventPayload is implemented by every event payload.
+type EventPayload interface {
+	EventType() EventType
+	EventVersion() int
+	// Aggregate identifies the entity the event belongs to; it is the Kafka
+	// key, so events of one aggregate keep their order.
+	Aggregate() (aggregateType, aggregateID string)
+}
+
+// NewEvent wraps a payload in an envelope with a fresh ID.
+func NewEvent(payload EventPayload) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	aggregateType, aggregateID := payload.Aggregate()
+	return Event{
+		ID:            uuid.NewString(),
+		Type:          payload.EventType(),
+		Version:       payload.EventVersion(),
+		Source:        EventSource,
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		OccurredAt:    time.Now().UTC(),
+		Data:          data,
+	}, nil
+}
+
+// NewUniqueEvent is NewEvent for events that may happen only once per
+// aggregate: the ID is derived from the type and aggregate, so recording
+// it twice keeps one copy.
+func NewUniqueEvent(payload EventPayload) (Event, error) {
+	event, err := NewEvent(payload)
+	if err != nil {
+		return Event{}, err
+	}
+	name := string(event.Type) + ":" + event.AggregateType + ":" + event.AggregateID
+	event.ID = uuid.NewSHA1(uuid.NameSpaceOID, []byte(name)).String()
+	return event, nil
+}
+
+// NewEvents wraps each payload in an envelope with NewEvent.
+func NewEvents(payloads ...EventPayload) ([]Event, error) {
+	events := make([]Event, len(payloads))
+	for i, payload := range payloads {
+		event, err := NewEvent(payload)
+		if err != nil {
+			return nil, err
+		}
+		events[i] = event
+	}
+	return events, nil
+}
+
+// EventsFunc returns the events describing a change. Repositories call it
+// inside the change's transaction, after its rows are written, so the events
+// can carry the IDs the database assigned, and store them in the outbox in
+// the same transaction. It may be nil.
+type EventsFunc func() ([]Event, error)
+
+// EventRecorder stores events in the outbox; they are published to Kafka
+// asynchronously.
+type EventRecorder interface {
+	Record(ctx context.Context, events ...Event) error
+}
+
+// OutboxMessage is an event waiting in the outbox. PublishedAt is set once
+// Kafka acknowledged it. Attempts counts every failed publish, Rejections
+// only those where Kafka refused the message itself; GaveUpAt is set when it
+// was rejected too often to retry, and such messages need a manual check.
+type OutboxMessage struct {
+	ID          uint64     `gorm:"primaryKey;autoIncrement"`
+	EventID     string     `gorm:"type:varchar(36);uniqueIndex;not null"`
+	Topic       string     `gorm:"type:varchar(100);not null"`
+	MessageKey  string     `gorm:"type:varchar(100);not null"`
+	EventType   EventType  `gorm:"type:varchar(50);not null"`
+	Payload     []byte     `gorm:"not null"`
+	Attempts    int        `gorm:"not null;default:0"`
+	Rejections  int        `gorm:"not null;default:0"`
+	LastError   *string    `gorm:"type:text"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime"`
+	PublishedAt *time.Time `gorm:"index"`
+	GaveUpAt    *time.Time `gorm:"index"`
+}
+
+type OutboxRepository interface {
+	EventRecorder
+	// Pending returns the oldest unpublished messages not given up on.
+	Pending(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkPublished(ctx context.Context, ids []uint64) error
+	MarkFailed(ctx context.Context, id uint64, reason string) error
+	MarkRejected(ctx context.Context, id uint64, reason string) error
+	GiveUp(ctx context.Context, id uint64, reason string) error
+	// PurgePublished deletes messages published before the given time.
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// VoteCast is recorded when a vote for a candidate is stored.
+type VoteCast struct {
+	// VoteID is the ID of the stored vote; it is 0 in events recorded
+	// before it was added.
+	VoteID        uint          `json:"vote_id"`
+	CandidateID   uint          `json:"candidate_id"`
+	CandidateType CandidateType `json:"candidate_type"`
+	Region        *string       `json:"region"`
+	UserID        uint          `json:"user_id"`
+}
+
+func (VoteCast) EventType() EventType { return EventVoteCast }
+func (VoteCast) EventVersion() int    { return 1 }
+func (p VoteCast) Aggregate() (string, string) {
+	return "candidate", strconv.FormatUint(uint64(p.CandidateID), 10)
+}
+
+// DedupKey identifies the vote for consumers that must count it once: one
+// vote keeps its key even if it is recorded under several event IDs. Events
+// without a VoteID fall back to their eventID.
+func (p VoteCast) DedupKey(eventID string) string {
+	if p.VoteID == 0 {
+		return eventID
+	}
+	return "vote:" + strconv.FormatUint(uint64(p.VoteID), 10)
+}
+
+// CandidateCreated is recorded when an approved nomination becomes a candidate.
+type CandidateCreated struct {
+	CandidateID    uint          `json:"candidate_id"`
+	Name           string        `json:"name"`
+	Type           CandidateType `json:"type"`
+	PartyID        *uint         `json:"party_id"`
+	Region         *string       `json:"region"`
+	VotingStart    time.Time     `json:"voting_start"`
+	VotingDeadline time.Time     `json:"voting_deadline"`
+}
+
+func (CandidateCreated) EventType() EventType { return EventCandidateCreated }
+func (CandidateCreated) EventVersion() int    { return 1 }
+func (p CandidateCreated) Aggregate() (string, string) {
+	return "candidate", strconv.FormatUint(uint64(p.CandidateID), 10)
+}
+
+// PetitionCreated is recorded when a petition is submitted; it starts pending moderation.
+type PetitionCreated struct {
+	PetitionID     uint             `json:"petition_id"`
+	UserID         uint             `json:"user_id"`
+	Title          string           `json:"title"`
+	Category       PetitionCategory `json:"category"`
+	Status         PetitionStatus   `json:"status"`
+	Goal           int              `json:"goal"`
+	VotingDeadline time.Time        `json:"voting_deadline"`
+}
+
+func (PetitionCreated) EventType() EventType { return EventPetitionCreated }
+func (PetitionCreated) EventVersion() int    { return 1 }
+func (p PetitionCreated) Aggregate() (string, string) {
+	return "petition", strconv.FormatUint(uint64(p.PetitionID), 10)
+}
+
+// PetitionVoteCast is recorded when a vote on a petition is stored.
+type PetitionVoteCast struct {
+	PetitionID uint     `json:"petition_id"`
+	UserID     uint     `json:"user_id"`
+	VoteType   VoteType `json:"vote_type"`
+}
+
+func (PetitionVoteCast) EventType() EventType { return EventPetitionVoteCast }
+func (PetitionVoteCast) EventVersion() int    { return 1 }
+func (p PetitionVoteCast) Aggregate() (string, string) {
+	return "petition", strconv.FormatUint(uint64(p.PetitionID), 10)
+}
+
+// PetitionGoalReached is recorded once, by the vote that brings a petition to its goal.
+type PetitionGoalReached struct {
+	PetitionID   uint `json:"petition_id"`
+	Goal         int  `json:"goal"`
+	VotesInFavor int  `json:"votes_in_favor"`
+	VotesAgainst int  `json:"votes_against"`
+}
+
+func (PetitionGoalReached) EventType() EventType { return EventPetitionGoalReached }
+func (PetitionGoalReached) EventVersion() int    { return 1 }
+func (p PetitionGoalReached) Aggregate() (string, string) {
+	return "petition", strconv.FormatUint(uint64(p.PetitionID), 10)
+}
+
+// UserRegistered is recorded when an account is created, before its email is verified.
+type UserRegistered struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Email    string `json:"email"`
+}
+
+func (UserRegistered) EventType() EventType { return EventUserRegistered }
+func (UserRegistered) EventVersion() int    { return 1 }
+func (p UserRegistered) Aggregate() (string, string) {
+	return "user", strconv.FormatUint(uint64(p.UserID), 10)
+}
+
+// UserEmailVerified is recorded when a user confirms their email address.
+type UserEmailVerified struct {
+	UserID uint `json:"user_id"`
+}
+
+func (UserEmailVerified) EventType() EventType { return EventUserEmailVerified }
+func (UserEmailVerified) EventVersion() int    { return 1 }
+func (p UserEmailVerified) Aggregate() (string, string) {
+	return "user", strconv.FormatUint(uint64(p.UserID), 10)
+}
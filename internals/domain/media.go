@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Asset is an uploaded image. The original (with metadata stripped) and its
+// thumbnail live in the configured MediaStorage under StorageKey and ThumbnailKey.
+type Asset struct {
+	ID           string    `gorm:"type:varchar(36);primaryKey" json:"id"`
+	UserID       uint      `gorm:"not null;index" json:"user_id"`
+	StorageKey   string    `gorm:"type:varchar(255);not null" json:"-"`
+	ThumbnailKey string    `gorm:"type:varchar(255);not null" json:"-"`
+	ContentType  string    `gorm:"type:varchar(50);not null" json:"content_type"`
+	Size         int64     `gorm:"not null" json:"size"`
+	Width        int       `gorm:"not null" json:"width"`
+	Height       int       `gorm:"not null" json:"height"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// AssetURLs are time-limited links to an asset's files.
+type AssetURLs struct {
+	Asset
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type AssetRepository interface {
+	Create(ctx context.Context, asset *Asset) error
+	GetByID(ctx context.Context, id string) (*Asset, error)
+}
+
+// MediaStorage stores uploaded files. Implementations exist for the local
+// filesystem and S3-compatible object stores.
+type MediaStorage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL that grants read access to key until ttl elapses.
+	SignedURL(key string, ttl time.Duration) (string, error)
+}
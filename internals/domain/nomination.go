@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Nomination is a member's proposal of a candidate for an election. It
+// collects endorsements from eligible users and becomes a Candidate once an
+// election official approves it.
+type Nomination struct {
+	ID                   uint                 `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
+	UserID               uint                 `gorm:"not null;index" swaggerignore:"true" json:"user_id"`
+	Name                 string               `gorm:"type:varchar(255);not null" example:"Beksultan" json:"name"`
+	PhotoAssetID         *string              `gorm:"type:varchar(36)" example:"5f0c8a4e-2d7b-4c1e-9a43-0b6f5e2d9c11" json:"photo_asset_id,omitempty"`
+	Education            *string              `gorm:"type:varchar(255)" example:"KBTU" json:"education"`
+	Age                  int                  `gorm:"not null" example:"20" json:"age"`
+	PartyID              *uint                `gorm:"index" example:"1" json:"party_id"`
+	Region               *string              `gorm:"type:varchar(255)" example:"SKO" json:"region"`
+	Biography            *string              `gorm:"type:text" example:"Born in Shymkent, studied at KBTU" json:"biography,omitempty"`
+	Manifesto            *string              `gorm:"type:text" example:"Free public transport" json:"manifesto,omitempty"`
+	Type                 CandidateType        `gorm:"type:varchar(255);not null;index" example:"manager" json:"type"`
+	VotingStart          time.Time            `json:"voting_start" gorm:"type:datetime" example:"2025-11-12T09:00:00+05:00"`
+	VotingDeadline       time.Time            `json:"voting_deadline" gorm:"type:datetime" example:"2026-11-12T09:00:00+05:00"`
+	Documents            []NominationDocument `gorm:"foreignKey:NominationID" json:"documents"`
+	Status               NominationStatus     `gorm:"type:varchar(20);not null;default:collecting;index" swaggerignore:"true" json:"status"`
+	Endorsements         int                  `gorm:"default:0" swaggerignore:"true" json:"endorsements"`
+	RequiredEndorsements int                  `gorm:"not null" swaggerignore:"true" json:"required_endorsements"`
+	RejectReason         *string              `gorm:"type:text" swaggerignore:"true" json:"reject_reason,omitempty"`
+	ReviewerID           *uint                `swaggerignore:"true" json:"reviewer_id,omitempty"`
+	ReviewedAt           *time.Time           `swaggerignore:"true" json:"reviewed_at,omitempty"`
+	CandidateID          *uint                `gorm:"index" swaggerignore:"true" json:"candidate_id,omitempty"`
+	DeletedAt            gorm.DeletedAt       `json:"-" swaggerignore:"true"`
+	CreatedAt            time.Time            `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
+	UpdatedAt            time.Time            `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
+}
+
+// NominationDocument is a supporting document (an uploaded scan) attached
+// to a nomination.
+type NominationDocument struct {
+	ID           uint   `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"-"`
+	NominationID uint   `gorm:"not null;index" swaggerignore:"true" json:"-"`
+	Title        string `gorm:"type:varchar(255);not null" example:"Party endorsement letter" json:"title"`
+	AssetID      string `gorm:"type:varchar(36);not null" example:"5f0c8a4e-2d7b-4c1e-9a43-0b6f5e2d9c11" json:"asset_id"`
+}
+
+// NominationEndorsement is one user's signature under a nomination.
+type NominationEndorsement struct {
+	ID           uint      `gorm:"primaryKey;autoIncrement" json:"id"`
+	NominationID uint      `gorm:"not null;uniqueIndex:idx_nomination_user" json:"nomination_id"`
+	UserID       uint      `gorm:"not null;uniqueIndex:idx_nomination_user" json:"user_id"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+type NominationStatus string
+
+const (
+	// NominationCollecting nominations are gathering endorsements.
+	NominationCollecting NominationStatus = "collecting"
+	// NominationSubmitted nominations have enough endorsements and await review.
+	NominationSubmitted NominationStatus = "submitted"
+	NominationApproved  NominationStatus = "approved"
+	NominationRejected  NominationStatus = "rejected"
+)
+
+func IsValidNominationStatus(s string) bool {
+	switch NominationStatus(s) {
+	case NominationCollecting, NominationSubmitted, NominationApproved, NominationRejected:
+		return true
+	default:
+		return false
+	}
+}
+
+// RequiredEndorsements is how many endorsements a nomination for an
+// election of the given type needs before officials can review it.
+func RequiredEndorsements(t CandidateType) int {
+	switch t {
+	case Presidential:
+		return 100
+	case Deputy:
+		return 50
+	default:
+		return 10
+	}
+}
+
+// ErrNominationNotCollecting is returned for endorsements of a nomination
+// that no longer collects them.
+var ErrNominationNotCollecting = errors.New("nomination is not collecting endorsements")
+
+// NominationRepository persists nominations and their endorsements.
+type NominationRepository interface {
+	Create(ctx context.Context, n *Nomination) error
+	GetByID(ctx context.Context, id uint) (*Nomination, error)
+	GetAllPaginated(ctx context.Context, status NominationStatus, limit, offset int) ([]Nomination, error)
+	// Endorse records userID's endorsement, increments the counter and moves
+	// the nomination to submitted once it reaches its required endorsements.
+	// It returns false if the user had already endorsed it and
+	// ErrNominationNotCollecting if the nomination no longer collects.
+	Endorse(ctx context.Context, nominationID, userID uint) (bool, error)
+	// Approve marks a submitted nomination approved and creates its
+	// candidate and the events it returns in the same transaction.
+	Approve(ctx context.Context, n *Nomination, reviewerID uint, candidate *Candidate, events EventsFunc) error
+	Reject(ctx context.Context, n *Nomination, reviewerID uint, reason string) error
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Party is a political party candidates can belong to. NameKey is the
+// normalized name and keeps "Jastar" and "jastar " from becoming two parties.
+type Party struct {
+	ID          uint      `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
+	Name        string    `gorm:"type:varchar(255);not null" example:"Jastar" json:"name"`
+	NameKey     string    `gorm:"type:varchar(255);not null;uniqueIndex" swaggerignore:"true" json:"-"`
+	LogoAssetID *string   `gorm:"type:varchar(36)" example:"5f0c8a4e-2d7b-4c1e-9a43-0b6f5e2d9c11" json:"logo_asset_id,omitempty"`
+	Leader      *string   `gorm:"type:varchar(255)" example:"Aruzhan Sadykova" json:"leader,omitempty"`
+	Description *string   `gorm:"type:text" example:"Youth party focused on education" json:"description,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
+}
+
+// PartyResult is a party's share of the votes in one election. Candidates
+// without a party are grouped under a nil PartyID.
+type PartyResult struct {
+	PartyID    *uint   `json:"party_id"`
+	PartyName  string  `json:"party_name"`
+	Candidates int64   `json:"candidates"`
+	Votes      int64   `json:"votes"`
+	Share      float64 `json:"share"`
+}
+
+// IndependentPartyName labels candidates without a party in results.
+const IndependentPartyName = "Independent"
+
+type PartyRepository interface {
+	Create(ctx context.Context, p *Party) error
+	GetByID(ctx context.Context, id uint) (*Party, error)
+	GetByNameKey(ctx context.Context, key string) (*Party, error)
+	GetAll(ctx context.Context) ([]Party, error)
+	Update(ctx context.Context, p *Party) error
+	Delete(ctx context.Context, id uint) error
+	GetCandidates(ctx context.Context, partyID uint) ([]Candidate, error)
+	CountCandidates(ctx context.Context, partyID uint) (int64, error)
+	// CountCandidatesByParty returns the number of candidates per party name.
+	CountCandidatesByParty(ctx context.Context) (map[string]int64, error)
+	// Results sums candidate votes per party for one election type.
+	Results(ctx context.Context, candidateType CandidateType) ([]PartyResult, error)
+}
+
+// NormalizePartyName trims a party name and collapses inner whitespace.
+func NormalizePartyName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// PartyNameKey is the case-insensitive identity of a party name.
+func PartyNameKey(name string) string {
+	return strings.ToLower(NormalizePartyName(name))
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,28 +9,38 @@ import (
 
 // Petition represents a created petition.
 type Petition struct {
-	ID             uint           `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
-	UserID         uint           `gorm:"not null" swaggerignore:"true" json:"user_id"`
-	Title          string         `gorm:"type:varchar(255);not null" example:"petition title" json:"title"`
-	Photo          *string        `gorm:"type:varchar(255)" example:"link" json:"photo"`
-	Description    *string        `gorm:"type:text" example:"petition description" json:"description"`
-	VotesInFavor   int            `gorm:"default:0" swaggerignore:"true" json:"votes_in_favor"`
-	VotesAgainst   int            `gorm:"default:0" swaggerignore:"true" json:"votes_against"`
-	Goal           int            `gorm:"not null" example:"0" json:"goal"`
-	VotingDeadline time.Time      `json:"voting_deadline" gorm:"type:datetime" example:"2025-05-10T23:59:00+05:00"`
-	DeletedAt      gorm.DeletedAt `json:"-" swaggerignore:"true"`
-	CreatedAt      time.Time      `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
-	UpdatedAt      time.Time      `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
+	ID             uint             `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"id"`
+	UserID         uint             `gorm:"not null" swaggerignore:"true" json:"user_id"`
+	Title          string           `gorm:"type:varchar(255);not null" example:"petition title" json:"title"`
+	Photo          *string          `gorm:"type:varchar(255)" example:"link" json:"photo"`
+	PhotoAssetID   *string          `gorm:"type:varchar(36)" example:"5f0c8a4e-2d7b-4c1e-9a43-0b6f5e2d9c11" json:"photo_asset_id,omitempty"`
+	Description    *string          `gorm:"type:text" example:"petition description" json:"description"`
+	VotesInFavor   int              `gorm:"default:0" swaggerignore:"true" json:"votes_in_favor"`
+	VotesAgainst   int              `gorm:"default:0" swaggerignore:"true" json:"votes_against"`
+	Goal           int              `gorm:"not null" example:"0" json:"goal"`
+	Category       PetitionCategory `gorm:"type:varchar(50);not null;default:other;index" example:"environment" json:"category"`
+	Tags           []Tag            `gorm:"many2many:petition_tags;" json:"tags"`
+	Status         PetitionStatus   `gorm:"type:varchar(20);not null;default:approved;index" swaggerignore:"true" json:"status"`
+	RejectReason   *string          `gorm:"type:text" swaggerignore:"true" json:"reject_reason,omitempty"`
+	VotingDeadline time.Time        `json:"voting_deadline" gorm:"type:datetime" example:"2025-05-10T23:59:00+05:00"`
+	DeletedAt      gorm.DeletedAt   `json:"-" swaggerignore:"true"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime" swaggerignore:"true" json:"created_at"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime" swaggerignore:"true" json:"updated_at"`
 }
 
+// PetitionRepository persists petitions. GetAll and CountByCategory only
+// consider approved petitions.
 type PetitionRepository interface {
-	Create(petition *Petition) error
-	GetAll() ([]Petition, error)
-	GetAllPaginated(limit, offset int) ([]Petition, error)
-	GetByID(id uint) (*Petition, error)
-	VoteInFavor(id uint) error
-	VoteAgainst(id uint) error
-	Delete(id uint) error
+	// Create stores the petition with its tags and the events it returns in
+	// one transaction.
+	Create(ctx context.Context, petition *Petition, events EventsFunc) error
+	GetAll(ctx context.Context) ([]Petition, error)
+	GetAllPaginated(ctx context.Context, filter PetitionFilter, limit, offset int) ([]Petition, error)
+	CountByCategory(ctx context.Context) (map[PetitionCategory]int64, error)
+	GetByID(ctx context.Context, id uint) (*Petition, error)
+	VoteInFavor(ctx context.Context, id uint) error
+	VoteAgainst(ctx context.Context, id uint) error
+	Delete(ctx context.Context, id uint) error
 }
 
 type PetitionVote struct {
@@ -60,3 +71,120 @@ const (
 func IsValidVoteType(v string) bool {
 	return v == string(Favor) || v == string(Against)
 }
+
+// Tag is a free-form label attached to petitions.
+type Tag struct {
+	ID   uint   `gorm:"primaryKey;autoIncrement" swaggerignore:"true" json:"-"`
+	Name string `gorm:"type:varchar(50);unique;not null" example:"parks" json:"name"`
+}
+
+type PetitionCategory string
+
+const (
+	CategoryEducation      PetitionCategory = "education"
+	CategoryHealthcare     PetitionCategory = "healthcare"
+	CategoryEnvironment    PetitionCategory = "environment"
+	CategoryInfrastructure PetitionCategory = "infrastructure"
+	CategoryTransport      PetitionCategory = "transport"
+	CategorySocial         PetitionCategory = "social"
+	CategoryEconomy        PetitionCategory = "economy"
+	CategoryCulture        PetitionCategory = "culture"
+	CategoryOther          PetitionCategory = "other"
+)
+
+func IsValidPetitionCategory(c string) bool {
+	switch PetitionCategory(c) {
+	case CategoryEducation, CategoryHealthcare, CategoryEnvironment, CategoryInfrastructure,
+		CategoryTransport, CategorySocial, CategoryEconomy, CategoryCulture, CategoryOther:
+		return true
+	default:
+		return false
+	}
+}
+
+// PetitionSort selects the ordering of a petition listing.
+type PetitionSort string
+
+const (
+	SortNewest      PetitionSort = "newest"
+	SortMostSigned  PetitionSort = "most_signed"
+	SortClosingSoon PetitionSort = "closing_soon"
+	SortTrending    PetitionSort = "trending"
+)
+
+func IsValidPetitionSort(s string) bool {
+	switch PetitionSort(s) {
+	case SortNewest, SortMostSigned, SortClosingSoon, SortTrending:
+		return true
+	default:
+		return false
+	}
+}
+
+// PetitionFilter narrows and orders a paginated petition listing.
+// Zero values mean "no filter" and the default (newest first) ordering.
+type PetitionFilter struct {
+	Status   PetitionStatus
+	Category PetitionCategory
+	Tag      string
+	Sort     PetitionSort
+}
+
+// PetitionStatus is the moderation state of a petition. Only approved
+// petitions are listed publicly and open for voting.
+type PetitionStatus string
+
+const (
+	PetitionPending  PetitionStatus = "pending"
+	PetitionApproved PetitionStatus = "approved"
+	PetitionRejected PetitionStatus = "rejected"
+)
+
+type ModerationAction string
+
+const (
+	ModerationFlagged  ModerationAction = "flagged"
+	ModerationApproved ModerationAction = "approved"
+	ModerationRejected ModerationAction = "rejected"
+	ModerationMerged   ModerationAction = "merged"
+)
+
+// PetitionModeration is an audit trail entry for a petition. Entries written by
+// automated screening have no ModeratorID.
+type PetitionModeration struct {
+	ID          uint             `gorm:"primaryKey;autoIncrement" json:"id"`
+	PetitionID  uint             `gorm:"not null;index" json:"petition_id"`
+	ModeratorID *uint            `json:"moderator_id,omitempty"`
+	Action      ModerationAction `gorm:"type:varchar(20);not null" json:"action"`
+	Reason      *string          `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ModerationQueueItem is a pending petition together with the flags raised by
+// automated screening.
+type ModerationQueueItem struct {
+	Petition Petition `json:"petition"`
+	Flags    []string `json:"flags"`
+}
+
+// PetitionModerationRepository manages the moderation queue and its audit trail.
+type PetitionModerationRepository interface {
+	GetPending(ctx context.Context, limit, offset int) ([]Petition, error)
+	Record(ctx context.Context, entry *PetitionModeration) error
+	// Decide changes the petition status and records the decision atomically.
+	Decide(ctx context.Context, entry *PetitionModeration, status PetitionStatus) error
+	GetHistory(ctx context.Context, petitionIDs ...uint) ([]PetitionModeration, error)
+}
+
+// SimilarPetition is a likely duplicate found by the similarity search.
+type SimilarPetition struct {
+	ID    uint    `json:"id" example:"3"`
+	Title string  `json:"title" example:"petition title"`
+	Score float64 `json:"score" example:"7.5"`
+}
+
+// PetitionScreener inspects a newly submitted petition before it reaches the
+// moderation queue and returns the reasons it was flagged, if any.
+type PetitionScreener interface {
+	Screen(ctx context.Context, p *Petition) ([]string, error)
+}
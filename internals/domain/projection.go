@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUnprocessableEvent marks events a consumer can never apply, such as a
+// malformed payload or an unknown schema version. They are skipped instead
+// of retried.
+var ErrUnprocessableEvent = errors.New("unprocessable event")
+
+// VoteTallyHourly counts the votes a candidate received in one hour, split by
+// the candidate's region ("" when it has none).
+type VoteTallyHourly struct {
+	ID            uint          `gorm:"primaryKey;autoIncrement" json:"-"`
+	CandidateType CandidateType `gorm:"type:varchar(50);not null;uniqueIndex:idx_vote_tally_bucket" json:"candidate_type"`
+	CandidateID   uint          `gorm:"not null;uniqueIndex:idx_vote_tally_bucket" json:"candidate_id"`
+	Region        string        `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_vote_tally_bucket" json:"region"`
+	Hour          time.Time     `gorm:"type:datetime;not null;uniqueIndex:idx_vote_tally_bucket" json:"hour"`
+	Votes         int64         `gorm:"not null;default:0" json:"votes"`
+}
+
+// ElectionTurnout counts the users who voted in an election type. Turnout is
+// Voters divided by the number of verified users.
+type ElectionTurnout struct {
+	CandidateType CandidateType `gorm:"primaryKey;type:varchar(50)" json:"candidate_type"`
+	Voters        int64         `gorm:"not null;default:0" json:"voters"`
+	UpdatedAt     time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ProcessedEvent remembers which events a consumer has applied to its
+// database projections, so redelivered events are not counted twice. For
+// votes EventID holds VoteCast.DedupKey.
+type ProcessedEvent struct {
+	Consumer    string    `gorm:"primaryKey;type:varchar(50)"`
+	EventID     string    `gorm:"primaryKey;type:varchar(36)"`
+	ProcessedAt time.Time `gorm:"autoCreateTime"`
+}
+
+// ProjectionRepository maintains the database projections of vote events.
+type ProjectionRepository interface {
+	// ApplyVote adds the vote to its hourly tally and the turnout in one
+	// transaction, unless the consumer already applied key, the vote's
+	// VoteCast.DedupKey. It reports whether the vote was applied.
+	ApplyVote(ctx context.Context, consumer, key string, vote VoteCast, at time.Time) (bool, error)
+	// Reset deletes the projections and the consumer's processed events.
+	Reset(ctx context.Context, consumer string) error
+}
+
+// LeaderboardStore maintains the Redis projections of vote events. Every
+// increment is skipped when its key, the event ID or for candidate votes
+// VoteCast.DedupKey, was already applied.
+type LeaderboardStore interface {
+	AddCandidateVote(ctx context.Context, key string, vote VoteCast) (bool, error)
+	AddPetitionSignature(ctx context.Context, eventID string, petitionID uint) (bool, error)
+	Reset(ctx context.Context) error
+}
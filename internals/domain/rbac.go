@@ -1,5 +1,7 @@
 package domain
 
+import "context"
+
 type Role struct {
 	ID       uint     `gorm:"primaryKey;autoIncrement"`
 	Name     string   `gorm:"type:varchar(50);unique;not null"`
@@ -12,5 +14,5 @@ type Access struct {
 }
 
 type RoleRepository interface {
-	GetByName(name string) (*Role, error)
+	GetByName(ctx context.Context, name string) (*Role, error)
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "context"
+
+// SearchHit is a single document returned by a similarity query.
+type SearchHit struct {
+	ID     string                 `json:"id"`
+	Score  float64                `json:"score"`
+	Source map[string]interface{} `json:"source"`
+}
+
+// SearchIndexer maintains one search index, such as the candidates or the
+// petitions index, and answers the queries the use cases run against it.
+type SearchIndexer interface {
+	// Index adds or replaces the document stored under id.
+	Index(ctx context.Context, id string, document interface{}) error
+	// Delete removes a document. Missing documents are not an error.
+	Delete(ctx context.Context, id string) error
+	// TermsAggregation returns document counts per distinct value of field;
+	// nested fields are written with dots, like "party_info.name".
+	TermsAggregation(ctx context.Context, field string, size int) (map[string]int64, error)
+	// FindSimilar returns up to size documents whose fields look like text,
+	// best first, dropping those scoring below minScore.
+	FindSimilar(ctx context.Context, text string, fields []string, size int, minScore float64) ([]SearchHit, error)
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"strconv"
+	"time"
+)
+
+// TallyEvent announces that a vote changed the counts of an election or a
+// petition. It carries no counts; listeners load a fresh snapshot.
+type TallyEvent struct {
+	Election   CandidateType `json:"election,omitempty"`
+	PetitionID uint          `json:"petition_id,omitempty"`
+}
+
+// Topic is the stream the event belongs to.
+func (e TallyEvent) Topic() string {
+	if e.PetitionID != 0 {
+		return PetitionTallyTopic(e.PetitionID)
+	}
+	return ElectionTallyTopic(e.Election)
+}
+
+func ElectionTallyTopic(t CandidateType) string {
+	return "election:" + string(t)
+}
+
+func PetitionTallyTopic(id uint) string {
+	return "petition:" + strconv.FormatUint(uint64(id), 10)
+}
+
+// TallyPublisher is notified after every successful vote.
+type TallyPublisher interface {
+	PublishTally(ctx context.Context, event TallyEvent) error
+}
+
+// TallySnapshot is the current state of an election or petition as streamed
+// to clients. When Hidden is set the election is still open and its results
+// are kept secret, so no counts are included.
+type TallySnapshot struct {
+	Topic        string           `json:"topic"`
+	Election     CandidateType    `json:"election,omitempty"`
+	PetitionID   uint             `json:"petition_id,omitempty"`
+	Hidden       bool             `json:"hidden"`
+	Candidates   []CandidateTally `json:"candidates,omitempty"`
+	VotesInFavor *int             `json:"votes_in_favor,omitempty"`
+	VotesAgainst *int             `json:"votes_against,omitempty"`
+	Goal         int              `json:"goal,omitempty"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+type CandidateTally struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Votes *int   `json:"votes,omitempty"`
+}
@@ -26,12 +26,15 @@ type User struct {
 
 // UserRepository handles database operations related to users.
 type UserRepository interface {
-	Create(user *User) error
-	GetByID(id uint) (*User, error)
-	GetByUsername(username string) (*User, error)
-	Update(user *User) error
-	Delete(id uint) error
-	MarkEmailVerified(ctx context.Context, userID uint) error
-	GetByEmail(email string) (*User, error)
-	DeleteUnverifiedUser(cutoff time.Time) (int64, error)
+	// Create stores the user and the events it returns in one transaction.
+	Create(ctx context.Context, user *User, events EventsFunc) error
+	GetByID(ctx context.Context, id uint) (*User, error)
+	GetByUsername(ctx context.Context, username string) (*User, error)
+	Update(ctx context.Context, user *User) error
+	Delete(ctx context.Context, id uint) error
+	// MarkEmailVerified verifies the user's email and stores the events it
+	// returns in one transaction.
+	MarkEmailVerified(ctx context.Context, userID uint, events EventsFunc) error
+	GetByEmail(ctx context.Context, email string) (*User, error)
+	DeleteUnverifiedUser(ctx context.Context, cutoff time.Time) (int64, error)
 }
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// ValidationErrors maps request field names to what is wrong with them.
+type ValidationErrors map[string]string
+
+func (v ValidationErrors) Error() string {
+	fields := make([]string, 0, len(v))
+	for field := range v {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = field + ": " + v[field]
+	}
+	return "validation failed: " + strings.Join(parts, "; ")
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"gorm.io/gorm"
@@ -26,14 +27,26 @@ type VoteRequest struct {
 
 // VoteRepository manages voting data for candidates.
 type VoteRepository interface {
-	HasVoted(userID uint, voteType string) (bool, error)
-	SaveVote(candidateID uint, userID uint, voteType string) error
-	VoteWithTransaction(candidateID uint, userID uint, candidateType string, afterSave func() error) error
+	HasVoted(ctx context.Context, userID uint, voteType string) (bool, error)
+	SaveVote(ctx context.Context, candidateID uint, userID uint, voteType string) error
+	// VoteWithTransaction stores the vote, setting its ID, runs afterSave and
+	// records the events it returns in the outbox, all in one transaction.
+	// afterSave gets a context carrying the transaction: the repository calls
+	// made with it are part of the vote.
+	VoteWithTransaction(ctx context.Context, vote *Vote, afterSave func(ctx context.Context) error, events EventsFunc) error
 }
 
 // PetitionVoteRepository manages voting data for petitions.
 type PetitionVoteRepository interface {
-	CreateVote(vote *PetitionVote) error
-	HasUserVoted(userID uint, petitionID uint) (bool, error)
-	VoteWithTransaction(userID uint, petitionID uint, voteType VoteType, afterSave func() error) error
+	CreateVote(ctx context.Context, vote *PetitionVote) error
+	HasUserVoted(ctx context.Context, userID uint, petitionID uint) (bool, error)
+	// VoteWithTransaction stores the vote, runs afterSave and records the
+	// events it returns in the outbox, all in one transaction. afterSave gets a
+	// context carrying the transaction, as in VoteRepository.
+	VoteWithTransaction(ctx context.Context, userID uint, petitionID uint, voteType VoteType, afterSave func(ctx context.Context) error, events EventsFunc) error
+	// MergeInto moves the votes of sourceID to targetID, skipping users who
+	// already voted on targetID, recomputes the target's counters, deletes
+	// sourceID and records entry, all in one transaction. It returns the
+	// number of votes moved. The target must be approved.
+	MergeInto(ctx context.Context, sourceID uint, targetID uint, entry *PetitionModeration) (int64, error)
 }
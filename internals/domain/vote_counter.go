@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotCounted is wrapped by the errors of VoteCounter.Add that prove the
+// vote did not reach the counter. Other errors leave it unknown: the store
+// may have counted the vote before the error, a timeout for instance.
+var ErrNotCounted = errors.New("vote not counted")
+
+// VoteBatch is a set of buffered vote increments claimed for one flush, by
+// candidate ID.
+type VoteBatch struct {
+	ID     string
+	Counts map[uint]int64
+}
+
+// AppliedVoteBatch remembers a VoteBatch added to the candidates, so a retried
+// flush does not add it twice.
+type AppliedVoteBatch struct {
+	ID        string    `gorm:"primaryKey;type:varchar(36)"`
+	AppliedAt time.Time `gorm:"autoCreateTime;index"`
+}
+
+// VoteCounter buffers candidate vote counts outside the database in
+// write-behind mode; the votes themselves are still stored by VoteRepository.
+type VoteCounter interface {
+	// Add counts one vote for the candidate. Its error wraps ErrNotCounted
+	// when the vote surely was not counted.
+	Add(ctx context.Context, candidateID uint) error
+	// Claim returns the batch of a flush that did not finish or, when there
+	// is none, moves the buffered counts into a new batch. It returns nil
+	// when nothing is buffered.
+	Claim(ctx context.Context) (*VoteBatch, error)
+	// Done drops a batch once it is applied.
+	Done(ctx context.Context, batchID string) error
+	// Reset drops every buffered count and claimed batch, and returns the ID
+	// of the batch it dropped, or "" when none was claimed.
+	Reset(ctx context.Context) (string, error)
+	// Intact reports whether the counter still holds everything added since
+	// the last Reset; it is false when the store lost its data.
+	Intact(ctx context.Context) (bool, error)
+	// Init marks a counter holding no counts and no batch intact, as a Reset
+	// would, and reports whether the counter is intact afterwards.
+	Init(ctx context.Context) (bool, error)
+}
+
+// VoteCountRepository writes the buffered counts of a VoteCounter to the
+// candidates.
+type VoteCountRepository interface {
+	// ApplyVoteBatch adds the counts of batch to the candidates in one
+	// transaction. A batch is applied once: it reports false for a batch
+	// already applied, so a flush interrupted after the commit can be retried.
+	ApplyVoteBatch(ctx context.Context, batch VoteBatch) (bool, error)
+	// RecountVotes sets every candidate's count to its rows in the votes
+	// table and returns the number of candidates changed. A non-empty dropped
+	// batch is marked applied in the same transaction, so a flush still
+	// holding it does not add it on top of the recount.
+	RecountVotes(ctx context.Context, dropped string) (int64, error)
+	// CountDrift returns the number of candidates whose count differs from
+	// their rows in the votes table.
+	CountDrift(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,113 @@
+package fakes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// EmailVerifier records verification mails instead of sending them. Tokens
+// are single use, like the codes the Redis verifier stores.
+type EmailVerifier struct {
+	mu     sync.Mutex
+	next   int
+	tokens map[string]string
+	sent   []string
+}
+
+func NewEmailVerifier() *EmailVerifier {
+	return &EmailVerifier{tokens: make(map[string]string)}
+}
+
+func (v *EmailVerifier) SendVerificationMail(_ context.Context, email string) (string, string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.next++
+	token := fmt.Sprintf("verify-%d", v.next)
+	v.tokens[token] = email
+	v.sent = append(v.sent, email)
+	return "http://localhost/verify?token=" + token, token, nil
+}
+
+func (v *EmailVerifier) VerifyEmail(_ context.Context, token string) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	email, ok := v.tokens[token]
+	if !ok {
+		return "", errors.New("invalid token")
+	}
+	delete(v.tokens, token)
+	return email, nil
+}
+
+// Sent returns the addresses mailed so far, oldest first.
+func (v *EmailVerifier) Sent() []string {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]string(nil), v.sent...)
+}
+
+type issuedToken struct {
+	userID  uint
+	refresh bool
+	expires time.Time
+}
+
+// TokenManager issues opaque tokens and remembers who they belong to.
+type TokenManager struct {
+	mu     sync.Mutex
+	next   int
+	tokens map[string]issuedToken
+}
+
+func NewTokenManager() *TokenManager {
+	return &TokenManager{tokens: make(map[string]issuedToken)}
+}
+
+func (m *TokenManager) CreateAccessToken(userID uint, ttl time.Duration) (string, error) {
+	return m.issue(userID, false, ttl), nil
+}
+
+func (m *TokenManager) CreateRefreshToken(userID uint, ttl time.Duration) (string, error) {
+	return m.issue(userID, true, ttl), nil
+}
+
+func (m *TokenManager) VerifyAccessToken(_ context.Context, token string) (uint, error) {
+	return m.verify(token, false)
+}
+
+func (m *TokenManager) VerifyRefreshToken(_ context.Context, token string) (uint, error) {
+	return m.verify(token, true)
+}
+
+func (m *TokenManager) GetSecret() []byte {
+	return []byte("fake-secret")
+}
+
+func (m *TokenManager) issue(userID uint, refresh bool, ttl time.Duration) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.next++
+	kind := "access"
+	if refresh {
+		kind = "refresh"
+	}
+	token := fmt.Sprintf("%s-%d-%d", kind, userID, m.next)
+	m.tokens[token] = issuedToken{userID: userID, refresh: refresh, expires: time.Now().Add(ttl)}
+	return token
+}
+
+func (m *TokenManager) verify(token string, refresh bool) (uint, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tokens[token]
+	if !ok || t.refresh != refresh {
+		return 0, errors.New("invalid token")
+	}
+	if time.Now().After(t.expires) {
+		return 0, errors.New("token expired")
+	}
+	return t.userID, nil
+}
@@ -0,0 +1,68 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/service"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BlockchainService records logs instead of sending transactions. When Err
+// is set every log call fails with it and nothing is recorded; set it to
+// service.ErrQueued to act like the queued blockchain during an outage.
+type BlockchainService struct {
+	mu   sync.Mutex
+	Err  error
+	logs []service.TransactionLog
+}
+
+func NewBlockchainService() *BlockchainService {
+	return &BlockchainService{}
+}
+
+func (b *BlockchainService) LogCandidateCreation(_ context.Context, candidate *domain.Candidate) (*service.TransactionLog, error) {
+	return b.log("candidate_creation", map[string]interface{}{"candidate_id": candidate.ID})
+}
+
+func (b *BlockchainService) LogCandidateVote(_ context.Context, userID uint, candidateID uint, candidateType domain.CandidateType) (*service.TransactionLog, error) {
+	return b.log("candidate_vote", map[string]interface{}{"user_id": userID, "candidate_id": candidateID, "candidate_type": candidateType})
+}
+
+func (b *BlockchainService) LogPetitionCreation(_ context.Context, petition *domain.Petition) (*service.TransactionLog, error) {
+	return b.log("petition_creation", map[string]interface{}{"petition_id": petition.ID})
+}
+
+func (b *BlockchainService) LogPetitionVote(_ context.Context, userID uint, petitionID uint, voteType domain.VoteType) (*service.TransactionLog, error) {
+	return b.log("petition_vote", map[string]interface{}{"user_id": userID, "petition_id": petitionID, "vote_type": voteType})
+}
+
+func (b *BlockchainService) GetServiceInfo(_ context.Context) (map[string]interface{}, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return map[string]interface{}{"service": "fake", "transactions": len(b.logs)}, nil
+}
+
+// Logs returns the recorded logs, oldest first.
+func (b *BlockchainService) Logs() []service.TransactionLog {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]service.TransactionLog(nil), b.logs...)
+}
+
+func (b *BlockchainService) log(action string, details map[string]interface{}) (*service.TransactionLog, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.Err != nil {
+		return nil, b.Err
+	}
+	entry := service.TransactionLog{
+		TransactionID: fmt.Sprintf("0x%064x", len(b.logs)+1),
+		Timestamp:     time.Now(),
+		ActionType:    action,
+		Details:       details,
+	}
+	b.logs = append(b.logs, entry)
+	return &entry, nil
+}
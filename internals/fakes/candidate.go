@@ -0,0 +1,180 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CandidateRepository keeps candidates in memory. Deleted candidates are
+// removed outright rather than soft-deleted.
+type CandidateRepository struct {
+	mu         sync.Mutex
+	nextID     uint
+	candidates map[uint]domain.Candidate
+	history    map[uint][]domain.CandidateChange
+}
+
+func NewCandidateRepository(candidates ...domain.Candidate) *CandidateRepository {
+	r := &CandidateRepository{
+		candidates: make(map[uint]domain.Candidate),
+		history:    make(map[uint][]domain.CandidateChange),
+	}
+	for i := range candidates {
+		r.Create(context.Background(), &candidates[i])
+	}
+	return r
+}
+
+func (r *CandidateRepository) Create(_ context.Context, candidate *domain.Candidate) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if candidate.ID == 0 {
+		r.nextID++
+		candidate.ID = r.nextID
+	} else if candidate.ID > r.nextID {
+		r.nextID = candidate.ID
+	}
+	if candidate.CreatedAt.IsZero() {
+		candidate.CreatedAt = time.Now()
+	}
+	r.candidates[candidate.ID] = *candidate
+	return nil
+}
+
+func (r *CandidateRepository) GetAllByType(_ context.Context, candidateType string) ([]domain.Candidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.byType(candidateType), nil
+}
+
+func (r *CandidateRepository) GetAllByTypePaginated(_ context.Context, candidateType string, limit, offset int) ([]domain.Candidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return page(r.byType(candidateType), limit, offset), nil
+}
+
+func (r *CandidateRepository) GetByID(_ context.Context, id uint) (*domain.Candidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.candidates[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &c, nil
+}
+
+func (r *CandidateRepository) GetByIDs(_ context.Context, ids []uint) ([]domain.Candidate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var candidates []domain.Candidate
+	for _, id := range ids {
+		if c, ok := r.candidates[id]; ok {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, nil
+}
+
+func (r *CandidateRepository) IncrementVote(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	c, ok := r.candidates[id]
+	if !ok {
+		return ErrNotFound
+	}
+	c.Votes++
+	r.candidates[id] = c
+	return nil
+}
+
+func (r *CandidateRepository) DeleteByID(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.candidates, id)
+	return nil
+}
+
+func (r *CandidateRepository) Update(_ context.Context, candidate *domain.Candidate, changes []domain.CandidateChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.candidates[candidate.ID]; !ok {
+		return ErrNotFound
+	}
+	r.candidates[candidate.ID] = *candidate
+	r.history[candidate.ID] = append(r.history[candidate.ID], changes...)
+	return nil
+}
+
+func (r *CandidateRepository) GetHistory(_ context.Context, id uint) ([]domain.CandidateChange, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.CandidateChange(nil), r.history[id]...), nil
+}
+
+func (r *CandidateRepository) CloseVoting(_ context.Context, candidateType domain.CandidateType, at time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, c := range r.candidates {
+		if c.Type == candidateType && c.VotingDeadline.After(at) {
+			c.VotingDeadline = at
+			r.candidates[id] = c
+			n++
+		}
+	}
+	return n, nil
+}
+
+// setVotes changes the counts of the candidates that exist to fn of their
+// current count and returns how many changed.
+func (r *CandidateRepository) setVotes(fn func(c domain.Candidate) int) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var changed int64
+	for id, c := range r.candidates {
+		if votes := fn(c); votes != c.Votes {
+			c.Votes = votes
+			r.candidates[id] = c
+			changed++
+		}
+	}
+	return changed
+}
+
+// votes returns the count of every candidate by ID.
+func (r *CandidateRepository) votes() map[uint]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	votes := make(map[uint]int, len(r.candidates))
+	for id, c := range r.candidates {
+		votes[id] = c.Votes
+	}
+	return votes
+}
+
+// byType returns the candidates of a type ordered by ID; the caller holds mu.
+func (r *CandidateRepository) byType(candidateType string) []domain.Candidate {
+	var candidates []domain.Candidate
+	for _, c := range r.candidates {
+		if string(c.Type) == candidateType {
+			candidates = append(candidates, c)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	return candidates
+}
+
+// page returns the items of items[offset:offset+limit] that exist.
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
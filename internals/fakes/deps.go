@@ -0,0 +1,59 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/cache"
+	"context"
+	"io"
+	"log/slog"
+)
+
+// Deps is one set of fakes wired to each other, holding whatever a use case
+// is built from, so a test builds the use case under test straight from it.
+type Deps struct {
+	Candidates    *CandidateRepository
+	Votes         *VoteRepository
+	VoteCounts    *VoteCountRepository
+	Petitions     *PetitionRepository
+	PetitionVotes *PetitionVoteRepository
+	Roles         *RoleRepository
+	Users         *UserRepository
+	Mailer        *EmailVerifier
+	Tokens        *TokenManager
+	Blockchain    *BlockchainService
+	Cache         domain.Cache
+	// Logger discards everything.
+	Logger *slog.Logger
+}
+
+func NewDeps() *Deps {
+	d := &Deps{
+		Candidates: NewCandidateRepository(),
+		Votes:      NewVoteRepository(),
+		Petitions:  NewPetitionRepository(),
+		Roles:      NewRoleRepository(),
+		Mailer:     NewEmailVerifier(),
+		Tokens:     NewTokenManager(),
+		Blockchain: NewBlockchainService(),
+		Cache:      cache.NewMemory(),
+		Logger:     slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+	d.VoteCounts = NewVoteCountRepository(d.Candidates, d.Votes)
+	d.PetitionVotes = NewPetitionVoteRepository(d.Petitions)
+	d.Users = NewUserRepository(d.Roles)
+	return d
+}
+
+// AddCandidates stores candidates, keeping their IDs.
+func (d *Deps) AddCandidates(candidates ...domain.Candidate) {
+	for i := range candidates {
+		d.Candidates.Create(context.Background(), &candidates[i])
+	}
+}
+
+// AddPetitions stores petitions, keeping their IDs.
+func (d *Deps) AddPetitions(petitions ...domain.Petition) {
+	for i := range petitions {
+		d.Petitions.Create(context.Background(), &petitions[i], nil)
+	}
+}
@@ -0,0 +1,42 @@
+// Package fakes provides in-memory implementations of the domain
+// repositories and services, for testing use cases without a database or a
+// blockchain node; cache.NewMemory and search.NewMemoryIndex stand in for
+// Redis and Elasticsearch. Deps wires one of each together for a test. Every
+// fake is safe for concurrent use.
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/service"
+
+	"gorm.io/gorm"
+)
+
+// The fakes implement the interfaces they stand in for.
+var (
+	_ domain.CandidateRepository    = (*CandidateRepository)(nil)
+	_ domain.VoteRepository         = (*VoteRepository)(nil)
+	_ domain.VoteCountRepository    = (*VoteCountRepository)(nil)
+	_ domain.PetitionRepository     = (*PetitionRepository)(nil)
+	_ domain.PetitionVoteRepository = (*PetitionVoteRepository)(nil)
+	_ domain.UserRepository         = (*UserRepository)(nil)
+	_ domain.RoleRepository         = (*RoleRepository)(nil)
+	_ domain.EmailVerifier          = (*EmailVerifier)(nil)
+	_ domain.TokenManager           = (*TokenManager)(nil)
+	_ domain.ProjectionRepository   = (*ProjectionRepository)(nil)
+	_ domain.LeaderboardStore       = (*LeaderboardStore)(nil)
+	_ service.BlockchainService     = (*BlockchainService)(nil)
+)
+
+// ErrNotFound is returned for missing records; it is the error the GORM
+// repositories return, so callers' errors.Is checks behave the same.
+var ErrNotFound = gorm.ErrRecordNotFound
+
+// buildEvents runs build, the way the GORM repositories do inside the
+// transaction of the change.
+func buildEvents(build domain.EventsFunc) ([]domain.Event, error) {
+	if build == nil {
+		return nil, nil
+	}
+	return build()
+}
@@ -0,0 +1,180 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PetitionRepository keeps petitions in memory, with the events recorded
+// when they were created. The trending sort falls back to newest first,
+// since the fake does not see vote times.
+type PetitionRepository struct {
+	mu        sync.Mutex
+	nextID    uint
+	petitions map[uint]domain.Petition
+	events    []domain.Event
+}
+
+func NewPetitionRepository(petitions ...domain.Petition) *PetitionRepository {
+	r := &PetitionRepository{petitions: make(map[uint]domain.Petition)}
+	for i := range petitions {
+		r.Create(context.Background(), &petitions[i], nil)
+	}
+	return r
+}
+
+func (r *PetitionRepository) Create(_ context.Context, petition *domain.Petition, events domain.EventsFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	assigned := petition.ID == 0
+	if assigned {
+		petition.ID = r.nextID + 1
+	}
+	if petition.CreatedAt.IsZero() {
+		petition.CreatedAt = time.Now()
+	}
+	built, err := buildEvents(events)
+	if err != nil {
+		if assigned {
+			petition.ID = 0
+		}
+		return err
+	}
+	if petition.ID > r.nextID {
+		r.nextID = petition.ID
+	}
+	r.petitions[petition.ID] = *petition
+	r.events = append(r.events, built...)
+	return nil
+}
+
+// Events returns the events recorded with created petitions, oldest first.
+func (r *PetitionRepository) Events() []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Event(nil), r.events...)
+}
+
+func (r *PetitionRepository) GetAll(_ context.Context) ([]domain.Petition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.filter(domain.PetitionFilter{Status: domain.PetitionApproved}), nil
+}
+
+func (r *PetitionRepository) GetAllPaginated(_ context.Context, filter domain.PetitionFilter, limit, offset int) ([]domain.Petition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	petitions := r.filter(filter)
+
+	newest := func(a, b domain.Petition) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID > b.ID
+	}
+	sort.Slice(petitions, func(i, j int) bool {
+		a, b := petitions[i], petitions[j]
+		switch filter.Sort {
+		case domain.SortMostSigned:
+			if sa, sb := a.VotesInFavor+a.VotesAgainst, b.VotesInFavor+b.VotesAgainst; sa != sb {
+				return sa > sb
+			}
+		case domain.SortClosingSoon:
+			if !a.VotingDeadline.Equal(b.VotingDeadline) {
+				return a.VotingDeadline.Before(b.VotingDeadline)
+			}
+		}
+		return newest(a, b)
+	})
+	return page(petitions, limit, offset), nil
+}
+
+func (r *PetitionRepository) CountByCategory(_ context.Context) (map[domain.PetitionCategory]int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[domain.PetitionCategory]int64)
+	for _, p := range r.filter(domain.PetitionFilter{Status: domain.PetitionApproved}) {
+		counts[p.Category]++
+	}
+	return counts, nil
+}
+
+func (r *PetitionRepository) GetByID(_ context.Context, id uint) (*domain.Petition, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.petitions[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *PetitionRepository) VoteInFavor(_ context.Context, id uint) error {
+	return r.update(id, func(p *domain.Petition) { p.VotesInFavor++ })
+}
+
+func (r *PetitionRepository) VoteAgainst(_ context.Context, id uint) error {
+	return r.update(id, func(p *domain.Petition) { p.VotesAgainst++ })
+}
+
+func (r *PetitionRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.petitions, id)
+	return nil
+}
+
+func (r *PetitionRepository) setCounts(id uint, favor, against int) error {
+	return r.update(id, func(p *domain.Petition) {
+		p.VotesInFavor = favor
+		p.VotesAgainst = against
+	})
+}
+
+func (r *PetitionRepository) update(id uint, fn func(p *domain.Petition)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p, ok := r.petitions[id]
+	if !ok {
+		return ErrNotFound
+	}
+	fn(&p)
+	p.UpdatedAt = time.Now()
+	r.petitions[id] = p
+	return nil
+}
+
+// filter returns the petitions matching filter, ignoring its sort; the
+// caller holds mu.
+func (r *PetitionRepository) filter(filter domain.PetitionFilter) []domain.Petition {
+	var petitions []domain.Petition
+	for _, p := range r.petitions {
+		if filter.Status != "" && p.Status != filter.Status {
+			continue
+		}
+		if filter.Category != "" && p.Category != filter.Category {
+			continue
+		}
+		if filter.Tag != "" && !hasTag(p, filter.Tag) {
+			continue
+		}
+		if filter.Sort == domain.SortClosingSoon && !p.VotingDeadline.After(time.Now()) {
+			continue
+		}
+		petitions = append(petitions, p)
+	}
+	sort.Slice(petitions, func(i, j int) bool { return petitions[i].ID < petitions[j].ID })
+	return petitions
+}
+
+func hasTag(p domain.Petition, name string) bool {
+	for _, t := range p.Tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
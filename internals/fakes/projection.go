@@ -0,0 +1,96 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"sync"
+	"time"
+)
+
+// ProjectionRepository counts applied votes per candidate in memory,
+// skipping keys a consumer already applied.
+type ProjectionRepository struct {
+	mu      sync.Mutex
+	applied map[string]bool
+	votes   map[uint]int64
+}
+
+func NewProjectionRepository() *ProjectionRepository {
+	return &ProjectionRepository{applied: make(map[string]bool), votes: make(map[uint]int64)}
+}
+
+func (r *ProjectionRepository) ApplyVote(_ context.Context, consumer, key string, vote domain.VoteCast, _ time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.applied[consumer+"/"+key] {
+		return false, nil
+	}
+	r.applied[consumer+"/"+key] = true
+	r.votes[vote.CandidateID]++
+	return true, nil
+}
+
+func (r *ProjectionRepository) Reset(context.Context, string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.applied = make(map[string]bool)
+	r.votes = make(map[uint]int64)
+	return nil
+}
+
+// Votes returns the votes applied to candidateID.
+func (r *ProjectionRepository) Votes(candidateID uint) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.votes[candidateID]
+}
+
+// LeaderboardStore counts candidate votes and petition signatures in memory,
+// skipping keys already applied.
+type LeaderboardStore struct {
+	mu         sync.Mutex
+	applied    map[string]bool
+	candidates map[uint]int64
+	petitions  map[uint]int64
+}
+
+func NewLeaderboardStore() *LeaderboardStore {
+	s := &LeaderboardStore{}
+	s.Reset(context.Background())
+	return s
+}
+
+func (s *LeaderboardStore) AddCandidateVote(_ context.Context, key string, vote domain.VoteCast) (bool, error) {
+	return s.add(key, s.candidates, vote.CandidateID), nil
+}
+
+func (s *LeaderboardStore) AddPetitionSignature(_ context.Context, eventID string, petitionID uint) (bool, error) {
+	return s.add(eventID, s.petitions, petitionID), nil
+}
+
+func (s *LeaderboardStore) add(key string, counts map[uint]int64, id uint) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.applied[key] {
+		return false
+	}
+	s.applied[key] = true
+	counts[id]++
+	return true
+}
+
+func (s *LeaderboardStore) Reset(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.applied = make(map[string]bool)
+	s.candidates = make(map[uint]int64)
+	s.petitions = make(map[uint]int64)
+	return nil
+}
+
+// CandidateVotes returns the leaderboard score of candidateID.
+func (s *LeaderboardStore) CandidateVotes(candidateID uint) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.candidates[candidateID]
+}
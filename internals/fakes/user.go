@@ -0,0 +1,171 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// UserRepository keeps users in memory, with the events recorded with their
+// changes. Usernames and emails are unique, as in the database; the Role of
+// returned users is filled from the roles the repository was given.
+type UserRepository struct {
+	roles *RoleRepository
+
+	mu     sync.Mutex
+	nextID uint
+	users  map[uint]domain.User
+	events []domain.Event
+}
+
+func NewUserRepository(roles *RoleRepository) *UserRepository {
+	return &UserRepository{roles: roles, users: make(map[uint]domain.User)}
+}
+
+func (r *UserRepository) Create(_ context.Context, user *domain.User, events domain.EventsFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if u.Username == user.Username || u.Email == user.Email {
+			return errors.New("duplicate username or email")
+		}
+	}
+	user.ID = r.nextID + 1
+	user.CreatedAt = time.Now()
+	built, err := buildEvents(events)
+	if err != nil {
+		user.ID = 0
+		return err
+	}
+	r.nextID++
+	r.users[user.ID] = *user
+	r.events = append(r.events, built...)
+	return nil
+}
+
+func (r *UserRepository) GetByID(_ context.Context, id uint) (*domain.User, error) {
+	return r.find(func(u domain.User) bool { return u.ID == id })
+}
+
+func (r *UserRepository) GetByUsername(_ context.Context, username string) (*domain.User, error) {
+	return r.find(func(u domain.User) bool { return u.Username == username })
+}
+
+func (r *UserRepository) GetByEmail(_ context.Context, email string) (*domain.User, error) {
+	return r.find(func(u domain.User) bool { return u.Email == email })
+}
+
+func (r *UserRepository) Update(_ context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.ID]; !ok {
+		return ErrNotFound
+	}
+	user.UpdatedAt = time.Now()
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) Delete(_ context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+func (r *UserRepository) MarkEmailVerified(_ context.Context, userID uint, events domain.EventsFunc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[userID]
+	if !ok {
+		return ErrNotFound
+	}
+	built, err := buildEvents(events)
+	if err != nil {
+		return err
+	}
+	u.EmailVerified = true
+	r.users[userID] = u
+	r.events = append(r.events, built...)
+	return nil
+}
+
+// Events returns the events recorded with user changes, oldest first.
+func (r *UserRepository) Events() []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Event(nil), r.events...)
+}
+
+func (r *UserRepository) DeleteUnverifiedUser(_ context.Context, cutoff time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var n int64
+	for id, u := range r.users {
+		if !u.EmailVerified && u.CreatedAt.Before(cutoff) {
+			delete(r.users, id)
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *UserRepository) find(match func(domain.User) bool) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if match(u) {
+			if role, ok := r.roles.byID(u.RoleID); ok {
+				u.Role = role
+			}
+			return &u, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// RoleRepository holds a fixed set of roles.
+type RoleRepository struct {
+	mu    sync.Mutex
+	roles []domain.Role
+}
+
+// NewRoleRepository returns a repository with the named roles, numbered
+// from 1 in order. Without names it has the roles the migrations seed.
+func NewRoleRepository(names ...string) *RoleRepository {
+	if len(names) == 0 {
+		names = []string{"admin", "member", "moderator", "election_official", "guest"}
+	}
+	r := &RoleRepository{}
+	for i, name := range names {
+		r.roles = append(r.roles, domain.Role{ID: uint(i + 1), Name: name})
+	}
+	return r
+}
+
+func (r *RoleRepository) GetByName(_ context.Context, name string) (*domain.Role, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, role := range r.roles {
+		if role.Name == name {
+			return &role, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *RoleRepository) byID(id uint) (domain.Role, bool) {
+	if r == nil {
+		return domain.Role{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, role := range r.roles {
+		if role.ID == id {
+			return role, true
+		}
+	}
+	return domain.Role{}, false
+}
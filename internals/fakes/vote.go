@@ -0,0 +1,252 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+type voteKey struct {
+	userID        uint
+	candidateType string
+}
+
+// VoteRepository keeps candidate votes and the events recorded with them in
+// memory. VoteWithTransaction runs one vote at a time, like the row lock of
+// the GORM repository, and keeps nothing when afterSave fails; changes
+// afterSave made to other fakes are not rolled back.
+type VoteRepository struct {
+	tx     sync.Mutex
+	mu     sync.Mutex
+	votes  map[voteKey]domain.Vote
+	events []domain.Event
+}
+
+func NewVoteRepository() *VoteRepository {
+	return &VoteRepository{votes: make(map[voteKey]domain.Vote)}
+}
+
+func (r *VoteRepository) HasVoted(_ context.Context, userID uint, voteType string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.votes[voteKey{userID, voteType}]
+	return ok, nil
+}
+
+func (r *VoteRepository) SaveVote(_ context.Context, candidateID uint, userID uint, voteType string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.save(candidateID, userID, voteType)
+	return nil
+}
+
+func (r *VoteRepository) VoteWithTransaction(ctx context.Context, vote *domain.Vote, afterSave func(ctx context.Context) error, events domain.EventsFunc) error {
+	r.tx.Lock()
+	defer r.tx.Unlock()
+
+	if voted, _ := r.HasVoted(ctx, vote.UserID, string(vote.CandidateType)); voted {
+		return errors.New("already voted for this category")
+	}
+	r.mu.Lock()
+	vote.ID = uint(len(r.votes) + 1)
+	r.mu.Unlock()
+	if afterSave != nil {
+		if err := afterSave(ctx); err != nil {
+			return err
+		}
+	}
+	built, err := buildEvents(events)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.save(vote.CandidateID, vote.UserID, string(vote.CandidateType))
+	r.events = append(r.events, built...)
+	return nil
+}
+
+// Votes returns the stored votes in no particular order.
+func (r *VoteRepository) Votes() []domain.Vote {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	votes := make([]domain.Vote, 0, len(r.votes))
+	for _, v := range r.votes {
+		votes = append(votes, v)
+	}
+	return votes
+}
+
+// Events returns the events recorded with votes, oldest first.
+func (r *VoteRepository) Events() []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Event(nil), r.events...)
+}
+
+// save stores a vote unless the user already voted; the caller holds mu.
+func (r *VoteRepository) save(candidateID, userID uint, candidateType string) {
+	key := voteKey{userID, candidateType}
+	if _, ok := r.votes[key]; ok {
+		return
+	}
+	r.votes[key] = domain.Vote{
+		ID:            uint(len(r.votes) + 1),
+		UserID:        userID,
+		CandidateID:   candidateID,
+		CandidateType: domain.CandidateType(candidateType),
+		CreatedAt:     time.Now(),
+	}
+}
+
+type petitionVoteKey struct {
+	userID     uint
+	petitionID uint
+}
+
+// PetitionVoteRepository keeps petition votes in memory and updates the
+// counters of the PetitionRepository it was created with, like the GORM
+// repository does in SQL. VoteWithTransaction behaves as in VoteRepository.
+type PetitionVoteRepository struct {
+	petitions *PetitionRepository
+
+	tx         sync.Mutex
+	mu         sync.Mutex
+	votes      map[petitionVoteKey]domain.PetitionVote
+	events     []domain.Event
+	moderation []domain.PetitionModeration
+}
+
+func NewPetitionVoteRepository(petitions *PetitionRepository) *PetitionVoteRepository {
+	return &PetitionVoteRepository{petitions: petitions, votes: make(map[petitionVoteKey]domain.PetitionVote)}
+}
+
+func (r *PetitionVoteRepository) CreateVote(ctx context.Context, vote *domain.PetitionVote) error {
+	r.mu.Lock()
+	saved := r.save(vote.UserID, vote.PetitionID, vote.VoteType)
+	r.mu.Unlock()
+	if !saved {
+		return nil
+	}
+	switch vote.VoteType {
+	case domain.Favor:
+		return r.petitions.VoteInFavor(ctx, vote.PetitionID)
+	case domain.Against:
+		return r.petitions.VoteAgainst(ctx, vote.PetitionID)
+	}
+	return nil
+}
+
+func (r *PetitionVoteRepository) HasUserVoted(_ context.Context, userID uint, petitionID uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.votes[petitionVoteKey{userID, petitionID}]
+	return ok, nil
+}
+
+func (r *PetitionVoteRepository) VoteWithTransaction(ctx context.Context, userID uint, petitionID uint, voteType domain.VoteType, afterSave func(ctx context.Context) error, events domain.EventsFunc) error {
+	r.tx.Lock()
+	defer r.tx.Unlock()
+
+	if voted, _ := r.HasUserVoted(ctx, userID, petitionID); voted {
+		return errors.New("user has already voted")
+	}
+	if afterSave != nil {
+		if err := afterSave(ctx); err != nil {
+			return err
+		}
+	}
+
+	built, err := buildEvents(events)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.save(userID, petitionID, voteType)
+	r.events = append(r.events, built...)
+	return nil
+}
+
+func (r *PetitionVoteRepository) MergeInto(ctx context.Context, sourceID uint, targetID uint, entry *domain.PetitionModeration) (int64, error) {
+	r.tx.Lock()
+	defer r.tx.Unlock()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.petitions.GetByID(ctx, sourceID); err != nil {
+		return 0, errors.New("both petitions must exist to merge")
+	}
+	target, err := r.petitions.GetByID(ctx, targetID)
+	if err != nil {
+		return 0, errors.New("both petitions must exist to merge")
+	}
+	if target.Status != domain.PetitionApproved {
+		return 0, errors.New("petitions can only be merged into an approved petition")
+	}
+
+	var moved int64
+	var favor, against int
+	for key, v := range r.votes {
+		if key.petitionID == sourceID {
+			if _, ok := r.votes[petitionVoteKey{key.userID, targetID}]; !ok {
+				delete(r.votes, key)
+				v.PetitionID = targetID
+				r.votes[petitionVoteKey{key.userID, targetID}] = v
+				moved++
+			}
+		}
+	}
+	for key, v := range r.votes {
+		if key.petitionID != targetID {
+			continue
+		}
+		if v.VoteType == domain.Favor {
+			favor++
+		} else if v.VoteType == domain.Against {
+			against++
+		}
+	}
+	if err := r.petitions.setCounts(targetID, favor, against); err != nil {
+		return 0, err
+	}
+	r.petitions.Delete(ctx, sourceID)
+	entry.CreatedAt = time.Now()
+	r.moderation = append(r.moderation, *entry)
+	return moved, nil
+}
+
+// Moderation returns the audit entries recorded by MergeInto, oldest first.
+func (r *PetitionVoteRepository) Moderation() []domain.PetitionModeration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.PetitionModeration(nil), r.moderation...)
+}
+
+// Events returns the events recorded with votes, oldest first.
+func (r *PetitionVoteRepository) Events() []domain.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]domain.Event(nil), r.events...)
+}
+
+// save stores a vote unless the user already voted and reports whether it
+// did; the caller holds mu.
+func (r *PetitionVoteRepository) save(userID, petitionID uint, voteType domain.VoteType) bool {
+	key := petitionVoteKey{userID, petitionID}
+	if _, ok := r.votes[key]; ok {
+		return false
+	}
+	r.votes[key] = domain.PetitionVote{
+		ID:         uint(len(r.votes) + 1),
+		UserID:     userID,
+		PetitionID: petitionID,
+		VoteType:   voteType,
+		CreatedAt:  time.Now(),
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+package fakes
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"sync"
+)
+
+// VoteCountRepository adds write-behind counts to a CandidateRepository and
+// recounts them from a VoteRepository.
+type VoteCountRepository struct {
+	mu         sync.Mutex
+	candidates *CandidateRepository
+	votes      *VoteRepository
+	applied    map[string]bool
+}
+
+func NewVoteCountRepository(candidates *CandidateRepository, votes *VoteRepository) *VoteCountRepository {
+	return &VoteCountRepository{candidates: candidates, votes: votes, applied: make(map[string]bool)}
+}
+
+func (r *VoteCountRepository) ApplyVoteBatch(_ context.Context, batch domain.VoteBatch) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.applied[batch.ID] {
+		return false, nil
+	}
+	r.applied[batch.ID] = true
+	r.candidates.setVotes(func(c domain.Candidate) int {
+		return c.Votes + int(batch.Counts[c.ID])
+	})
+	return true, nil
+}
+
+func (r *VoteCountRepository) RecountVotes(_ context.Context, dropped string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if dropped != "" {
+		r.applied[dropped] = true
+	}
+	counts := make(map[uint]int)
+	for _, v := range r.votes.Votes() {
+		counts[v.CandidateID]++
+	}
+	return r.candidates.setVotes(func(c domain.Candidate) int {
+		return counts[c.ID]
+	}), nil
+}
+
+func (r *VoteCountRepository) CountDrift(_ context.Context) (int64, error) {
+	counts := make(map[uint]int)
+	for _, v := range r.votes.Votes() {
+		counts[v.CandidateID]++
+	}
+	var drift int64
+	for id, votes := range r.candidates.votes() {
+		if votes != counts[id] {
+			drift++
+		}
+	}
+	return drift, nil
+}
@@ -0,0 +1,165 @@
+package cache
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// NegativeTTL is how long a record that does not exist is remembered.
+	NegativeTTL = 30 * time.Second
+
+	// versionPrefix starts the keys holding the current version of a tag.
+	versionPrefix = "tag:"
+	// versionTTL bounds how long an idle tag keeps its version. An expired
+	// version is replaced by a fresh one, which only costs a round of misses.
+	versionTTL = 24 * time.Hour
+)
+
+// Aside reads through a domain.Cache. Every entry is stored under its key
+// plus the current versions of its tags, so invalidating a tag is a single
+// write: entries carrying the old version are never read again and expire on
+// their own, without scanning the keyspace. Concurrent misses of one entry
+// share a single load, and loads failing with the not-found error are cached
+// for NegativeTTL.
+type Aside struct {
+	store    domain.Cache
+	notFound error
+	logger   *slog.Logger
+	group    singleflight.Group
+}
+
+// NewAside returns an Aside on store. notFound is the error loads return for
+// missing records, such as gorm.ErrRecordNotFound.
+func NewAside(store domain.Cache, notFound error, logger *slog.Logger) *Aside {
+	return &Aside{store: store, notFound: notFound, logger: logger}
+}
+
+// Fetch returns the value cached under key for the current versions of tags.
+// On a miss it calls load, caches the result for ttl and returns it. When the
+// cache is unavailable Fetch still loads, without caching.
+//
+// Values round-trip through JSON on hits and misses alike, so every caller
+// gets its own copy.
+func Fetch[T any](ctx context.Context, a *Aside, key string, tags []string, ttl time.Duration, load func(context.Context) (T, error)) (T, error) {
+	var value T
+
+	versioned, cached := a.versionedKey(ctx, key, tags)
+	if cached {
+		if data, err := a.store.Get(ctx, versioned); err == nil {
+			if len(data) == 0 {
+				a.logger.DebugContext(ctx, "Cache hit", "cache_key", key, "negative", true)
+				return value, a.notFound
+			}
+			if json.Unmarshal(data, &value) == nil {
+				a.logger.DebugContext(ctx, "Cache hit", "cache_key", key)
+				return value, nil
+			}
+		}
+		a.logger.DebugContext(ctx, "Cache miss", "cache_key", key)
+	} else {
+		versioned = key
+	}
+
+	// The load outlives a caller that gives up, as others may be waiting on it
+	result := a.group.DoChan(versioned, func() (interface{}, error) {
+		loadCtx := context.WithoutCancel(ctx)
+		loaded, err := load(loadCtx)
+		if err != nil {
+			if cached && errors.Is(err, a.notFound) {
+				a.store.Set(loadCtx, versioned, nil, NegativeTTL)
+			}
+			return nil, err
+		}
+		data, err := json.Marshal(loaded)
+		if err != nil {
+			return nil, err
+		}
+		if cached {
+			a.store.Set(loadCtx, versioned, data, ttl)
+		}
+		return data, nil
+	})
+
+	select {
+	case res := <-result:
+		if res.Err != nil {
+			return value, res.Err
+		}
+		err := json.Unmarshal(res.Val.([]byte), &value)
+		return value, err
+	case <-ctx.Done():
+		return value, ctx.Err()
+	}
+}
+
+// Invalidate gives each tag a new version, dropping every entry that carries
+// it. Failures are logged: the entries then live until their TTL.
+func (a *Aside) Invalidate(ctx context.Context, tags ...string) {
+	for _, tag := range tags {
+		if err := a.store.Set(ctx, versionPrefix+tag, newVersion(), versionTTL); err != nil {
+			a.logger.WarnContext(ctx, "Failed to invalidate cache", "tag", tag, logging.Err(err))
+			continue
+		}
+		a.logger.DebugContext(ctx, "Cache invalidated", "tag", tag)
+	}
+}
+
+// versionedKey appends the versions of tags to key. It reports false when a
+// version cannot be read, in which case nothing should be cached.
+func (a *Aside) versionedKey(ctx context.Context, key string, tags []string) (string, bool) {
+	var b strings.Builder
+	b.WriteString(key)
+	for i, tag := range tags {
+		version, err := a.version(ctx, tag)
+		if err != nil {
+			a.logger.WarnContext(ctx, "Cache unavailable, loading without it", "cache_key", key, logging.Err(err))
+			return "", false
+		}
+		if i == 0 {
+			b.WriteByte('#')
+		} else {
+			b.WriteByte('.')
+		}
+		b.Write(version)
+	}
+	return b.String(), true
+}
+
+// version returns the current version of tag. A tag without one, new or
+// expired, gets a fresh random version rather than a counter restarting at
+// one, so entries written under a lost version cannot be read again.
+func (a *Aside) version(ctx context.Context, tag string) ([]byte, error) {
+	key := versionPrefix + tag
+	version, err := a.store.Get(ctx, key)
+	if !errors.Is(err, domain.ErrCacheMiss) {
+		return version, err
+	}
+
+	// Readers missing together agree on one version, or each would load
+	v, err, _ := a.group.Do(key, func() (interface{}, error) {
+		if version, err := a.store.Get(ctx, key); err == nil {
+			return version, nil
+		}
+		version := newVersion()
+		return version, a.store.Set(ctx, key, version, versionTTL)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+func newVersion() []byte {
+	return []byte(strconv.FormatUint(rand.Uint64(), 36))
+}
@@ -0,0 +1,144 @@
+package cache
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var errMissing = errors.New("record not found")
+
+func newTestAside(store domain.Cache) *Aside {
+	return NewAside(store, errMissing, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// countingLoad returns a load of value that counts its calls.
+func countingLoad(calls *atomic.Int32, value string, err error) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		calls.Add(1)
+		return value, err
+	}
+}
+
+func TestFetchInvalidate(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAside(NewMemory())
+	var calls atomic.Int32
+
+	for i := 0; i < 2; i++ {
+		got, err := Fetch(ctx, a, "candidate:1", []string{"candidates", "candidate:1"}, time.Minute, countingLoad(&calls, "v1", nil))
+		if err != nil || got != "v1" {
+			t.Fatalf("Fetch = %q, %v, want \"v1\"", got, err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("loaded %d times, want 1", calls.Load())
+	}
+
+	// Any one tag drops the entry
+	a.Invalidate(ctx, "candidates")
+	got, _ := Fetch(ctx, a, "candidate:1", []string{"candidates", "candidate:1"}, time.Minute, countingLoad(&calls, "v2", nil))
+	if got != "v2" || calls.Load() != 2 {
+		t.Fatalf("Fetch after invalidation = %q after %d loads, want \"v2\" after 2", got, calls.Load())
+	}
+
+	// Other tags are untouched
+	a.Invalidate(ctx, "candidate:2")
+	got, _ = Fetch(ctx, a, "candidate:1", []string{"candidates", "candidate:1"}, time.Minute, countingLoad(&calls, "v3", nil))
+	if got != "v2" {
+		t.Fatalf("Fetch after invalidating another tag = %q, want the cached \"v2\"", got)
+	}
+}
+
+func TestFetchCachesNotFound(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAside(NewMemory())
+	var calls atomic.Int32
+
+	for i := 0; i < 2; i++ {
+		_, err := Fetch(ctx, a, "petition:9", []string{"petition:9"}, time.Minute, countingLoad(&calls, "", errMissing))
+		if !errors.Is(err, errMissing) {
+			t.Fatalf("Fetch returned %v, want the not-found error", err)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("loaded %d times, want 1", calls.Load())
+	}
+
+	// Other errors are not cached
+	boom := errors.New("connection refused")
+	for i := 0; i < 2; i++ {
+		Fetch(ctx, a, "petition:10", []string{"petition:10"}, time.Minute, countingLoad(&calls, "", boom))
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("loaded %d times, want 3", calls.Load())
+	}
+}
+
+func TestFetchSharesConcurrentLoads(t *testing.T) {
+	ctx := context.Background()
+	a := newTestAside(NewMemory())
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	const readers = 20
+	var started, done sync.WaitGroup
+	started.Add(readers)
+	done.Add(readers)
+	for i := 0; i < readers; i++ {
+		go func() {
+			defer done.Done()
+			started.Done()
+			if got, err := Fetch(ctx, a, "petitions", []string{"petitions"}, time.Minute, load); err != nil || got != "value" {
+				t.Errorf("Fetch = %q, %v", got, err)
+			}
+		}()
+	}
+	started.Wait()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if calls.Load() != 1 {
+		t.Fatalf("loaded %d times, want 1", calls.Load())
+	}
+}
+
+// offline is a cache whose every call fails.
+type offline struct{}
+
+var errOffline = errors.New("offline")
+
+func (offline) Get(context.Context, string) ([]byte, error) { return nil, errOffline }
+func (offline) Set(context.Context, string, []byte, time.Duration) error {
+	return errOffline
+}
+func (offline) Delete(context.Context, ...string) error { return errOffline }
+func (offline) DeletePrefix(context.Context, string) (int, error) {
+	return 0, errOffline
+}
+func (offline) Increment(context.Context, string, time.Duration) (int64, error) {
+	return 0, errOffline
+}
+
+func TestFetchWithoutCache(t *testing.T) {
+	a := newTestAside(offline{})
+	var calls atomic.Int32
+
+	got, err := Fetch(context.Background(), a, "candidate:1", []string{"candidate:1"}, time.Minute, countingLoad(&calls, "v1", nil))
+	if err != nil || got != "v1" {
+		t.Fatalf("Fetch = %q, %v, want \"v1\" from the load", got, err)
+	}
+	a.Invalidate(context.Background(), "candidate:1")
+}
@@ -0,0 +1,109 @@
+package cache
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	value   []byte
+	expires time.Time // zero for no expiry
+}
+
+// Memory keeps the cache in process memory. It suits a single instance and
+// tests; replicas do not see each other's entries or invalidations. Expired
+// entries are dropped when they are next read.
+type Memory struct {
+	mu      sync.Mutex
+	entries map[string]entry
+	now     func() time.Time
+}
+
+func NewMemory() domain.Cache {
+	return &Memory{entries: make(map[string]entry), now: time.Now}
+}
+
+func (c *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.live(key)
+	if !ok {
+		return nil, domain.ErrCacheMiss
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+func (c *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry{value: append([]byte(nil), value...), expires: c.expiry(ttl)}
+	return nil
+}
+
+func (c *Memory) Delete(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+func (c *Memory) DeletePrefix(_ context.Context, prefix string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deleted := 0
+	for key := range c.entries {
+		if strings.HasPrefix(key, prefix) {
+			if _, ok := c.live(key); ok {
+				deleted++
+			}
+			delete(c.entries, key)
+		}
+	}
+	return deleted, nil
+}
+
+func (c *Memory) Increment(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.live(key)
+	if !ok {
+		e = entry{expires: c.expiry(ttl)}
+	}
+	var count int64
+	if len(e.value) > 0 {
+		n, err := strconv.ParseInt(string(e.value), 10, 64)
+		if err != nil {
+			return 0, errors.New("value is not an integer")
+		}
+		count = n
+	}
+	count++
+	e.value = []byte(strconv.FormatInt(count, 10))
+	c.entries[key] = e
+	return count, nil
+}
+
+// live returns the entry of key unless it has expired, in which case it is
+// dropped; the caller holds mu.
+func (c *Memory) live(key string) (entry, bool) {
+	e, ok := c.entries[key]
+	if ok && !e.expires.IsZero() && !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return entry{}, false
+	}
+	return e, ok
+}
+
+func (c *Memory) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return c.now().Add(ttl)
+}
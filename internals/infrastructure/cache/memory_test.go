@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestMemory(now *time.Time) *Memory {
+	c := NewMemory().(*Memory)
+	c.now = func() time.Time { return *now }
+	return c
+}
+
+func TestMemoryExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := newTestMemory(&now)
+
+	c.Set(ctx, "short", []byte("a"), time.Minute)
+	c.Set(ctx, "forever", []byte("b"), 0)
+
+	now = now.Add(time.Minute)
+	if _, err := c.Get(ctx, "short"); !errors.Is(err, domain.ErrCacheMiss) {
+		t.Fatalf("Get of an expired key returned %v, want ErrCacheMiss", err)
+	}
+	if got, err := c.Get(ctx, "forever"); err != nil || string(got) != "b" {
+		t.Fatalf("Get = %q, %v, want \"b\"", got, err)
+	}
+}
+
+func TestMemoryDeletePrefix(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := newTestMemory(&now)
+
+	c.Set(ctx, "petitions:page:1", []byte("1"), time.Minute)
+	c.Set(ctx, "petitions:page:2", []byte("2"), 0)
+	c.Set(ctx, "petition:7", []byte("7"), 0)
+	c.Set(ctx, "petitions:stale", []byte("x"), time.Second)
+
+	now = now.Add(time.Second)
+	deleted, err := c.DeletePrefix(ctx, "petitions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 2 {
+		t.Fatalf("deleted %d keys, want 2 (expired keys are not counted)", deleted)
+	}
+	if _, err := c.Get(ctx, "petition:7"); err != nil {
+		t.Fatalf("key outside the prefix was deleted: %v", err)
+	}
+}
+
+func TestMemoryIncrement(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	c := newTestMemory(&now)
+
+	for want := int64(1); want <= 3; want++ {
+		got, err := c.Increment(ctx, "ratelimit:comment:1", time.Minute)
+		if err != nil || got != want {
+			t.Fatalf("Increment = %d, %v, want %d", got, err, want)
+		}
+	}
+
+	// The window is fixed by the first increment
+	now = now.Add(time.Minute)
+	if got, _ := c.Increment(ctx, "ratelimit:comment:1", time.Minute); got != 1 {
+		t.Fatalf("Increment after the window = %d, want 1", got)
+	}
+
+	c.Set(ctx, "text", []byte("abc"), 0)
+	if _, err := c.Increment(ctx, "text", 0); err == nil {
+		t.Fatal("Increment of a non-integer value succeeded")
+	}
+}
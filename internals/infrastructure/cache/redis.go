@@ -0,0 +1,82 @@
+// Package cache implements domain.Cache on Redis and in process memory.
+package cache
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanBatch is how many keys DeletePrefix asks Redis for per SCAN.
+const scanBatch = 100
+
+// Redis stores the cache in Redis. The client's hooks still apply, so
+// commands fail fast while the Redis circuit breaker is open.
+type Redis struct {
+	rdb *redis.Client
+}
+
+func NewRedis(rdb *redis.Client) domain.Cache {
+	return &Redis{rdb: rdb}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.rdb.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, domain.ErrCacheMiss
+	}
+	return value, err
+}
+
+func (c *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.rdb.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *Redis) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.rdb.Del(ctx, keys...).Err()
+}
+
+// DeletePrefix walks the keyspace with SCAN rather than KEYS, so Redis keeps
+// serving other clients while a large cache is dropped.
+func (c *Redis) DeletePrefix(ctx context.Context, prefix string) (int, error) {
+	pattern := globEscaper.Replace(prefix) + "*"
+	var cursor uint64
+	deleted := 0
+	for {
+		keys, next, err := c.rdb.Scan(ctx, cursor, pattern, scanBatch).Result()
+		if err != nil {
+			return deleted, err
+		}
+		if len(keys) > 0 {
+			if err := c.rdb.Del(ctx, keys...).Err(); err != nil {
+				return deleted, err
+			}
+			deleted += len(keys)
+		}
+		if next == 0 {
+			return deleted, nil
+		}
+		cursor = next
+	}
+}
+
+func (c *Redis) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	count, err := c.rdb.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if count == 1 {
+		c.rdb.Expire(ctx, key, ttl)
+	}
+	return count, nil
+}
+
+// globEscaper escapes the characters SCAN MATCH treats as wildcards.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
@@ -0,0 +1,199 @@
+// Package dependency tracks the health of the optional dependencies (Redis,
+// Elasticsearch, Kafka, the blockchain node) with circuit breakers, so the
+// API keeps serving without them instead of waiting on timeouts.
+package dependency
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/infrastructure/metrics"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned instead of calling a dependency whose breaker is open.
+var ErrOpen = errors.New("circuit open")
+
+type State string
+
+const (
+	// Closed lets calls through.
+	Closed State = "closed"
+	// Open rejects calls until the cooldown has passed.
+	Open State = "open"
+	// HalfOpen lets one trial call through; its result closes or reopens
+	// the breaker.
+	HalfOpen State = "half_open"
+)
+
+// Status describes a breaker for the health endpoint.
+type Status struct {
+	Dependency string    `json:"dependency"`
+	State      State     `json:"state"`
+	Since      time.Time `json:"since"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// Breaker opens after threshold consecutive failures and tries the
+// dependency again after cooldown.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	state    State
+	since    time.Time
+	failures int
+	trial    bool
+	lastErr  error
+}
+
+// Allow reports whether a call may go through. It returns an error wrapping
+// ErrOpen while the breaker is open. Every allowed call must be followed by
+// Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case Open:
+		if time.Since(b.since) < b.cooldown {
+			return b.openErr()
+		}
+		b.setState(HalfOpen)
+		b.trial = true
+		return nil
+	case HalfOpen:
+		if b.trial {
+			return b.openErr()
+		}
+		b.trial = true
+		return nil
+	}
+	return nil
+}
+
+// Record reports the result of an allowed call. Pass nil for errors that do
+// not mean the dependency is down, such as a cache miss or a cancelled
+// request.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+	if err == nil {
+		b.failures = 0
+		if b.state != Closed {
+			b.setState(Closed)
+			b.logger.Info("Dependency recovered", "dependency", b.name)
+		}
+		return
+	}
+
+	b.lastErr = err
+	b.failures++
+	if b.state == HalfOpen || (b.state == Closed && b.failures >= b.threshold) {
+		b.setState(Open)
+		b.logger.Warn("Dependency unavailable, circuit opened",
+			"dependency", b.name, "cooldown", b.cooldown.String(), logging.Err(err))
+	}
+}
+
+// Do runs fn if the breaker allows it and records its result.
+func (b *Breaker) Do(fn func() error) error {
+	if err := b.Allow(); err != nil {
+		return err
+	}
+	err := fn()
+	b.Record(err)
+	return err
+}
+
+// Healthy reports whether the breaker is closed.
+func (b *Breaker) Healthy() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == Closed
+}
+
+func (b *Breaker) Status() Status {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status := Status{Dependency: b.name, State: b.state, Since: b.since}
+	if b.state != Closed && b.lastErr != nil {
+		status.LastError = b.lastErr.Error()
+	}
+	return status
+}
+
+func (b *Breaker) setState(state State) {
+	b.state = state
+	b.since = time.Now()
+	open := 0.0
+	if state != Closed {
+		open = 1
+	}
+	metrics.DependencyDegraded.WithLabelValues(b.name).Set(open)
+}
+
+func (b *Breaker) openErr() error {
+	return fmt.Errorf("%s: %w", b.name, ErrOpen)
+}
+
+// Registry holds the breakers of all optional dependencies.
+type Registry struct {
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	breakers []*Breaker
+}
+
+func NewRegistry(threshold int, cooldown time.Duration, logger *slog.Logger) *Registry {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Registry{threshold: threshold, cooldown: cooldown, logger: logger}
+}
+
+// Breaker returns the breaker of the named dependency, creating it closed.
+func (r *Registry) Breaker(name string) *Breaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, b := range r.breakers {
+		if b.name == name {
+			return b
+		}
+	}
+	b := &Breaker{
+		name:      name,
+		threshold: r.threshold,
+		cooldown:  r.cooldown,
+		logger:    r.logger,
+		state:     Closed,
+		since:     time.Now(),
+	}
+	metrics.DependencyDegraded.WithLabelValues(name).Set(0)
+	r.breakers = append(r.breakers, b)
+	return b
+}
+
+// Degraded returns the breakers that are not closed.
+func (r *Registry) Degraded() []Status {
+	r.mu.Lock()
+	breakers := append([]*Breaker(nil), r.breakers...)
+	r.mu.Unlock()
+
+	var degraded []Status
+	for _, b := range breakers {
+		if status := b.Status(); status.State != Closed {
+			degraded = append(degraded, status)
+		}
+	}
+	return degraded
+}
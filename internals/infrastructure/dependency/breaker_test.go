@@ -0,0 +1,87 @@
+package dependency
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func newTestRegistry(threshold int, cooldown time.Duration) *Registry {
+	return NewRegistry(threshold, cooldown, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestBreakerOpensAfterThresholdConsecutiveFailures(t *testing.T) {
+	b := newTestRegistry(2, time.Hour).Breaker("redis")
+	down := errors.New("connection refused")
+
+	b.Record(down)
+	b.Record(nil) // a success resets the count
+	b.Record(down)
+	if !b.Healthy() {
+		t.Fatal("breaker opened before two consecutive failures")
+	}
+
+	b.Record(down)
+	if b.Healthy() {
+		t.Fatal("breaker still closed after two consecutive failures")
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow during cooldown = %v, want %v", err, ErrOpen)
+	}
+	status := b.Status()
+	if status.State != Open || status.LastError != down.Error() {
+		t.Fatalf("status = %+v, want open with the last error", status)
+	}
+}
+
+func TestBreakerLetsOneTrialThroughAfterCooldown(t *testing.T) {
+	cooldown := 10 * time.Millisecond
+	b := newTestRegistry(1, cooldown).Breaker("kafka")
+	down := errors.New("connection refused")
+
+	b.Record(down)
+	time.Sleep(cooldown)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("Allow after cooldown = %v, want a trial", err)
+	}
+	if state := b.Status().State; state != HalfOpen {
+		t.Fatalf("state = %s, want %s", state, HalfOpen)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("second Allow during the trial = %v, want %v", err, ErrOpen)
+	}
+
+	// A failed trial reopens the breaker for another cooldown
+	b.Record(down)
+	if state := b.Status().State; state != Open {
+		t.Fatalf("state after a failed trial = %s, want %s", state, Open)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("Allow right after a failed trial = %v, want %v", err, ErrOpen)
+	}
+
+	time.Sleep(cooldown)
+	if err := b.Do(func() error { return nil }); err != nil {
+		t.Fatalf("trial = %v", err)
+	}
+	if !b.Healthy() || b.Status().LastError != "" {
+		t.Fatalf("status after a successful trial = %+v, want closed", b.Status())
+	}
+}
+
+func TestRegistryReturnsOneBreakerPerDependency(t *testing.T) {
+	r := newTestRegistry(0, time.Hour)
+	redis := r.Breaker("redis")
+	if r.Breaker("redis") != redis {
+		t.Fatal("Breaker returned a second breaker for the same dependency")
+	}
+
+	// A threshold below one is raised to one
+	r.Breaker("elasticsearch").Record(errors.New("timeout"))
+	degraded := r.Degraded()
+	if len(degraded) != 1 || degraded[0].Dependency != "elasticsearch" {
+		t.Fatalf("degraded = %+v, want only elasticsearch", degraded)
+	}
+}
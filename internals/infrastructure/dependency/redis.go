@@ -0,0 +1,67 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisHook fails commands fast while the Redis breaker is open. The use
+// cases already treat a failed read as a cache miss and ignore failed
+// writes, so caching is simply bypassed until Redis is back.
+type redisHook struct {
+	breaker *Breaker
+}
+
+// NewRedisHook returns a hook to add with redis.Client.AddHook.
+func NewRedisHook(breaker *Breaker) redis.Hook {
+	return redisHook{breaker: breaker}
+}
+
+func (h redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (h redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err := next(ctx, cmd)
+		h.breaker.Record(redisFailure(err))
+		return err
+	}
+}
+
+func (h redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if err := h.breaker.Allow(); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		err := next(ctx, cmds)
+		h.breaker.Record(redisFailure(err))
+		return err
+	}
+}
+
+// redisFailure keeps the errors that mean Redis could not be reached. A
+// reply from the server, even an error reply, shows it is up.
+func redisFailure(err error) error {
+	var reply redis.Error
+	switch {
+	case err == nil,
+		errors.Is(err, redis.Nil),
+		errors.As(err, &reply),
+		errors.Is(err, context.Canceled):
+		return nil
+	}
+	return err
+}
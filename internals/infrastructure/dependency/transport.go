@@ -0,0 +1,37 @@
+package dependency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Transport fails HTTP requests fast while the breaker is open. Network
+// errors and 5xx responses count as failures.
+func Transport(breaker *Breaker, next http.RoundTripper) http.RoundTripper {
+	return &transport{breaker: breaker, next: next}
+}
+
+type transport struct {
+	breaker *Breaker
+	next    http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	res, err := t.next.RoundTrip(req)
+	switch {
+	case err != nil && errors.Is(err, context.Canceled):
+		t.breaker.Record(nil)
+	case err != nil:
+		t.breaker.Record(err)
+	case res.StatusCode >= 500:
+		t.breaker.Record(fmt.Errorf("HTTP %d", res.StatusCode))
+	default:
+		t.breaker.Record(nil)
+	}
+	return res, err
+}
@@ -1,51 +1,51 @@
 package email
 
 import (
+	"VoteGolang/conf"
 	"context"
 	"crypto/sha256"
 	"crypto/tls"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/smtp"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
+// RedisEmailVerifier mails verification links to baseURL/verify-email and
+// keeps their tokens in Redis for ttl.
 type RedisEmailVerifier struct {
-	client *redis.Client
+	client  *redis.Client
+	smtp    *conf.SMTPConfig
+	baseURL string
+	ttl     time.Duration
 }
 
-func NewRedisEmailVerifier(client *redis.Client) *RedisEmailVerifier {
-	return &RedisEmailVerifier{client: client}
+func NewRedisEmailVerifier(client *redis.Client, smtp *conf.SMTPConfig, baseURL string, ttl time.Duration) *RedisEmailVerifier {
+	return &RedisEmailVerifier{client: client, smtp: smtp, baseURL: baseURL, ttl: ttl}
 }
 
 func (r *RedisEmailVerifier) SendVerificationMail(ctx context.Context, email string) (string, string, error) {
-	host := os.Getenv("SMTP_HOST")
-	port := os.Getenv("SMTP_PORT")
-	from := os.Getenv("SMTP_MAIL")
-	password := os.Getenv("SMTP_PASSWORD")
+	host := r.smtp.Host
+	port := r.smtp.Port
+	from := r.smtp.Mail
+	password := r.smtp.Password
 
 	verificationKey := fmt.Sprintf(
 		"%x", sha256.Sum256([]byte(email + "-" + uuid.New().String())[:]),
 	)
 
-	// Use environment variable for base URL or fallback to localhost
-	baseURL := os.Getenv("APP_BASE_URL")
-	if baseURL == "" {
-		baseURL = "http://34.6.74.23:8080"
-	}
-
-	verificationLinkBase := fmt.Sprintf("%s/verify-email?token=", baseURL)
+	verificationLinkBase := fmt.Sprintf("%s/verify-email?token=", strings.TrimSuffix(r.baseURL, "/"))
 	link := fmt.Sprintf("%s%s", verificationLinkBase, verificationKey)
 
 	// Check if SMTP is configured
 	if host == "" || port == "" {
-		log.Printf("SMTP not configured. Skipping email send. Verification Link: %s\n", link)
+		slog.Warn("SMTP not configured, skipping verification email", "verification_link", link)
 		// Save to Redis so verification still works
-		err := r.client.Set(ctx, verificationKey, email, 5*time.Minute).Err()
+		err := r.client.Set(ctx, verificationKey, email, r.ttl).Err()
 		if err != nil {
 			return "", "", err
 		}
@@ -58,13 +58,13 @@ func (r *RedisEmailVerifier) SendVerificationMail(ctx context.Context, email str
 	body := fmt.Sprintf(`
 	<html>
 		<a href="%v" target="_blank">CLICK</a>
-		<p>This link will expire in 24 hours.</p>
+		<p>This link will expire in %v.</p>
 	</html>
-	`, link)
+	`, link, r.ttl)
 
 	// smtp
 	tlsconfig := &tls.Config{
-		InsecureSkipVerify: true,
+		InsecureSkipVerify: r.smtp.InsecureSkipVerify,
 		ServerName:         host,
 	}
 	c, err := smtp.Dial(host + ":" + port)
@@ -87,7 +87,6 @@ func (r *RedisEmailVerifier) SendVerificationMail(ctx context.Context, email str
 	}
 	w, err := c.Data()
 	if err != nil {
-		log.Println(err.Error())
 		return "", "", err
 	}
 
@@ -98,7 +97,7 @@ func (r *RedisEmailVerifier) SendVerificationMail(ctx context.Context, email str
 	w.Close()
 
 	// сохраняем в Redis
-	err = r.client.Set(ctx, verificationKey, email, 5*time.Minute).Err()
+	err = r.client.Set(ctx, verificationKey, email, r.ttl).Err()
 	if err != nil {
 		return "", "", err
 	}
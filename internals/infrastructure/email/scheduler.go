@@ -1,24 +1,39 @@
 package email
 
 import (
+	"VoteGolang/internals/app/logging"
 	"VoteGolang/internals/domain"
-	"log"
+	"VoteGolang/internals/infrastructure/metrics"
+	"context"
+	"log/slog"
 	"time"
 )
 
-func StartUnverifiedCleanupJob(repo domain.UserRepository) {
+// RunUnverifiedCleanupJob deletes users who did not verify their email
+// within a day, once an hour, until ctx is cancelled.
+func RunUnverifiedCleanupJob(ctx context.Context, repo domain.UserRepository) {
 	//ticker := time.NewTicker(10 * time.Second)
 	ticker := time.NewTicker(1 * time.Hour)
-	go func() {
-		for range ticker.C {
-			cutoff := time.Now().Add(-24 * time.Hour)
-			//cutoff := time.Now().Add(-10 * time.Second)
-			deleted, err := repo.DeleteUnverifiedUser(cutoff)
-			if err != nil {
-				log.Println("Cleanup error:", err)
-			} else if deleted > 0 {
-				log.Printf("Deleted %d unverified users\n", deleted)
-			}
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
 		}
-	}()
+		cutoff := time.Now().Add(-24 * time.Hour)
+		//cutoff := time.Now().Add(-10 * time.Second)
+		deleted, err := repo.DeleteUnverifiedUser(ctx, cutoff)
+		if err != nil {
+			metrics.CleanupRuns.WithLabelValues("error").Inc()
+			slog.Error("Failed to delete unverified users", logging.Err(err))
+			continue
+		}
+		metrics.CleanupRuns.WithLabelValues("success").Inc()
+		metrics.UnverifiedUsersDeleted.Add(float64(deleted))
+		metrics.CleanupLastSuccess.SetToCurrentTime()
+		if deleted > 0 {
+			slog.Info("Deleted unverified users", "count", deleted)
+		}
+	}
 }
@@ -0,0 +1,198 @@
+package events
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	retryBackoffMin = 500 * time.Millisecond
+	retryBackoffMax = 30 * time.Second
+)
+
+// Handler applies one event.
+type Handler func(ctx context.Context, event domain.Event) error
+
+// Consumer reads domain events from Kafka and hands them to a Handler.
+type Consumer struct {
+	broker string
+	group  string
+	topics []string
+	logger *slog.Logger
+}
+
+func NewConsumer(broker, group string, topics []string, logger *slog.Logger) *Consumer {
+	return &Consumer{broker: broker, group: group, topics: topics, logger: logger}
+}
+
+// Run consumes as part of the consumer group until ctx is cancelled. An
+// offset is committed only after its event was handled; failing events are
+// retried with backoff, unprocessable ones are logged and skipped.
+func (c *Consumer) Run(ctx context.Context, handle Handler) error {
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     []string{c.broker},
+		GroupID:     c.group,
+		GroupTopics: c.topics,
+		StartOffset: kafka.FirstOffset,
+		MaxWait:     time.Second,
+	})
+	defer reader.Close()
+
+	c.logger.Info("Consumer group started", "group", c.group, "topics", c.topics)
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("fetch message: %w", err)
+		}
+		if err := c.process(ctx, handle, msg); err != nil {
+			// Only cancellation stops process
+			return nil
+		}
+		if err := reader.CommitMessages(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("commit offset %d on %s/%d: %w", msg.Offset, msg.Topic, msg.Partition, err)
+		}
+	}
+}
+
+// Replay reads every partition of the topics from a starting point up to the
+// end offset at the time of the call, then returns. It does not use the
+// consumer group, so committed offsets are left alone. When from is non-zero
+// replay starts at the first message at or after it, otherwise at offset
+// (kafka.FirstOffset for the beginning).
+func (c *Consumer) Replay(ctx context.Context, handle Handler, offset int64, from time.Time) (int, error) {
+	total := 0
+	for _, topic := range c.topics {
+		partitions, err := c.partitions(topic)
+		if err != nil {
+			return total, err
+		}
+		for _, partition := range partitions {
+			n, err := c.replayPartition(ctx, handle, topic, partition, offset, from)
+			total += n
+			if err != nil {
+				return total, err
+			}
+		}
+	}
+	return total, nil
+}
+
+func (c *Consumer) partitions(topic string) ([]int, error) {
+	conn, err := kafka.Dial("tcp", c.broker)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", c.broker, err)
+	}
+	defer conn.Close()
+
+	parts, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return nil, fmt.Errorf("read partitions of %s: %w", topic, err)
+	}
+	ids := make([]int, len(parts))
+	for i, p := range parts {
+		ids[i] = p.ID
+	}
+	return ids, nil
+}
+
+func (c *Consumer) replayPartition(ctx context.Context, handle Handler, topic string, partition int, offset int64, from time.Time) (int, error) {
+	leader, err := kafka.DialLeader(ctx, "tcp", c.broker, topic, partition)
+	if err != nil {
+		return 0, fmt.Errorf("dial leader of %s/%d: %w", topic, partition, err)
+	}
+	first, end, err := leader.ReadOffsets()
+	leader.Close()
+	if err != nil {
+		return 0, fmt.Errorf("read offsets of %s/%d: %w", topic, partition, err)
+	}
+
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:   []string{c.broker},
+		Topic:     topic,
+		Partition: partition,
+		MaxWait:   time.Second,
+	})
+	defer reader.Close()
+
+	start := offset
+	switch {
+	case !from.IsZero():
+		if err := reader.SetOffsetAt(ctx, from); err != nil {
+			return 0, fmt.Errorf("seek %s/%d to %s: %w", topic, partition, from.Format(time.RFC3339), err)
+		}
+		start = reader.Offset()
+	case offset == kafka.FirstOffset || offset < first:
+		start = first
+	case offset == kafka.LastOffset:
+		start = end
+	}
+	if start >= end {
+		return 0, nil
+	}
+	if err := reader.SetOffset(start); err != nil {
+		return 0, fmt.Errorf("seek %s/%d to %d: %w", topic, partition, start, err)
+	}
+
+	count := 0
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			return count, fmt.Errorf("read %s/%d: %w", topic, partition, err)
+		}
+		if err := c.process(ctx, handle, msg); err != nil {
+			return count, err
+		}
+		count++
+		if msg.Offset >= end-1 {
+			break
+		}
+	}
+	c.logger.Info("Partition replayed", "messages", count, "topic", topic, "partition", partition, "first_offset", start, "last_offset", end-1)
+	return count, nil
+}
+
+// process decodes and handles a message, retrying until it succeeds, is
+// unprocessable or ctx is cancelled; only cancellation returns an error.
+func (c *Consumer) process(ctx context.Context, handle Handler, msg kafka.Message) error {
+	var event domain.Event
+	if err := json.Unmarshal(msg.Value, &event); err != nil {
+		c.logger.Error("Skipping malformed message", "topic", msg.Topic, "partition", msg.Partition, "offset", msg.Offset, logging.Err(err))
+		return nil
+	}
+
+	backoff := retryBackoffMin
+	for {
+		err := handle(ctx, event)
+		if err == nil {
+			return nil
+		}
+		if errors.Is(err, domain.ErrUnprocessableEvent) {
+			c.logger.Error("Skipping unprocessable event", "event_id", event.ID, "topic", msg.Topic, "partition", msg.Partition, "offset", msg.Offset, logging.Err(err))
+			return nil
+		}
+
+		c.logger.Warn("Failed to handle event, retrying", "event_id", event.ID, "backoff", backoff, logging.Err(err))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > retryBackoffMax {
+			backoff = retryBackoffMax
+		}
+	}
+}
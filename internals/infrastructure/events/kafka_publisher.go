@@ -0,0 +1,57 @@
+package events
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// KafkaPublisher writes outbox messages to their topics, keyed by aggregate
+// ID so events of one aggregate land on one partition in order.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+func NewKafkaPublisher(broker string) *KafkaPublisher {
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:                   kafka.TCP(broker),
+			Balancer:               &kafka.Hash{},
+			RequiredAcks:           kafka.RequireAll,
+			AllowAutoTopicCreation: true,
+			WriteTimeout:           10 * time.Second,
+		},
+	}
+}
+
+// Publish writes messages in one batch. The returned slice holds the error
+// of every message (nil when it was acknowledged); err is set when the whole
+// batch failed.
+func (p *KafkaPublisher) Publish(ctx context.Context, messages []domain.OutboxMessage) ([]error, error) {
+	batch := make([]kafka.Message, len(messages))
+	for i, m := range messages {
+		batch[i] = kafka.Message{
+			Topic: m.Topic,
+			Key:   []byte(m.MessageKey),
+			Value: m.Payload,
+			Headers: []kafka.Header{
+				{Key: "event_id", Value: []byte(m.EventID)},
+				{Key: "event_type", Value: []byte(m.EventType)},
+			},
+		}
+	}
+
+	errs := make([]error, len(messages))
+	err := p.writer.WriteMessages(ctx, batch...)
+	if writeErrs, ok := err.(kafka.WriteErrors); ok {
+		copy(errs, writeErrs)
+		return errs, nil
+	}
+	return errs, err
+}
+
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,145 @@
+package events
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/dependency"
+	"VoteGolang/internals/infrastructure/metrics"
+	"context"
+	"log/slog"
+	"time"
+)
+
+const purgeInterval = time.Hour
+
+// Publisher writes outbox messages to the broker; KafkaPublisher is the
+// production one. Publish returns the error of every message, and err when
+// the whole batch failed.
+type Publisher interface {
+	Publish(ctx context.Context, messages []domain.OutboxMessage) (errs []error, err error)
+}
+
+// Relay moves events from the outbox to Kafka. A message is marked published
+// only after Kafka acknowledged it, so a crash or a second API instance can
+// publish it again: delivery is at-least-once. While the Kafka breaker is
+// open events simply wait in the outbox. A message Kafka itself rejected
+// maxAttempts times is given up on, so it no longer holds up the ones after
+// it; failures of the whole batch, e.g. the broker being down, never count
+// towards giving up.
+type Relay struct {
+	outbox      domain.OutboxRepository
+	publisher   Publisher
+	breaker     *dependency.Breaker
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+	retention   time.Duration
+	logger      *slog.Logger
+}
+
+func NewRelay(outbox domain.OutboxRepository, publisher Publisher, breaker *dependency.Breaker, interval time.Duration, batchSize, maxAttempts int, retention time.Duration, logger *slog.Logger) *Relay {
+	return &Relay{
+		outbox:      outbox,
+		publisher:   publisher,
+		breaker:     breaker,
+		interval:    interval,
+		batchSize:   batchSize,
+		maxAttempts: maxAttempts,
+		retention:   retention,
+		logger:      logger,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	lastPurge := time.Now()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Drain full batches without waiting for the next tick
+		for r.relayBatch(ctx) == r.batchSize && ctx.Err() == nil {
+		}
+
+		if time.Since(lastPurge) >= purgeInterval {
+			lastPurge = time.Now()
+			if n, err := r.outbox.PurgePublished(ctx, time.Now().Add(-r.retention)); err != nil {
+				r.logger.Warn("Failed to purge published outbox messages", logging.Err(err))
+			} else if n > 0 {
+				r.logger.Debug("Purged published outbox messages", "count", n)
+			}
+		}
+	}
+}
+
+// relayBatch publishes one batch and returns how many messages it read.
+func (r *Relay) relayBatch(ctx context.Context) int {
+	messages, err := r.outbox.Pending(ctx, r.batchSize)
+	if err != nil {
+		r.logger.Error("Failed to read outbox", logging.Err(err))
+		return 0
+	}
+	if len(messages) == 0 {
+		return 0
+	}
+
+	if r.breaker.Allow() != nil {
+		return 0
+	}
+	errs, err := r.publisher.Publish(ctx, messages)
+	r.breaker.Record(err)
+	if err != nil {
+		r.logger.Warn("Failed to publish outbox messages", "count", len(messages), logging.Err(err))
+		for _, m := range messages {
+			r.markFailed(ctx, m, err)
+		}
+		return 0
+	}
+
+	published := make([]uint64, 0, len(messages))
+	for i, m := range messages {
+		if errs[i] != nil {
+			r.rejected(ctx, m, errs[i])
+			continue
+		}
+		published = append(published, m.ID)
+	}
+	if err := r.outbox.MarkPublished(ctx, published); err != nil {
+		r.logger.Error("Failed to mark outbox messages published", "count", len(published), logging.Err(err))
+		return 0
+	}
+	if len(published) < len(messages) {
+		// Retry the failures on the next tick instead of spinning on them
+		return 0
+	}
+	return len(messages)
+}
+
+// rejected records that Kafka rejected m, giving up on it at the last allowed
+// rejection.
+func (r *Relay) rejected(ctx context.Context, m domain.OutboxMessage, cause error) {
+	if m.Rejections+1 < r.maxAttempts {
+		if err := r.outbox.MarkRejected(ctx, m.ID, cause.Error()); err != nil {
+			r.logger.Error("Failed to record outbox failure", "event_id", m.EventID, logging.Err(err))
+		}
+		return
+	}
+	r.logger.ErrorContext(ctx, "CRITICAL: Kafka keeps rejecting outbox message, giving up, manual check needed",
+		"event_id", m.EventID, "event_type", m.EventType, "rejections", m.Rejections+1, logging.Err(cause))
+	metrics.OutboxGivenUp.WithLabelValues(string(m.EventType)).Inc()
+	if err := r.outbox.GiveUp(ctx, m.ID, cause.Error()); err != nil {
+		r.logger.Error("Failed to record outbox failure", "event_id", m.EventID, logging.Err(err))
+	}
+}
+
+func (r *Relay) markFailed(ctx context.Context, m domain.OutboxMessage, cause error) {
+	if err := r.outbox.MarkFailed(ctx, m.ID, cause.Error()); err != nil {
+		r.logger.Error("Failed to record outbox failure", "event_id", m.EventID, logging.Err(err))
+	}
+}
@@ -0,0 +1,261 @@
+package events
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/dependency"
+	"VoteGolang/internals/infrastructure/metrics"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// memoryOutbox keeps outbox messages in memory.
+type memoryOutbox struct {
+	mu       sync.Mutex
+	messages []domain.OutboxMessage
+}
+
+func newMemoryOutbox(n int) *memoryOutbox {
+	o := &memoryOutbox{}
+	for i := 1; i <= n; i++ {
+		o.messages = append(o.messages, domain.OutboxMessage{ID: uint64(i), EventID: string(rune('a' + i - 1))})
+	}
+	return o
+}
+
+func (o *memoryOutbox) Record(context.Context, ...domain.Event) error { return nil }
+
+func (o *memoryOutbox) Pending(_ context.Context, limit int) ([]domain.OutboxMessage, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var pending []domain.OutboxMessage
+	for _, m := range o.messages {
+		if m.PublishedAt == nil && m.GaveUpAt == nil && len(pending) < limit {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+func (o *memoryOutbox) MarkPublished(_ context.Context, ids []uint64) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	for _, id := range ids {
+		o.messages[id-1].PublishedAt = &now
+	}
+	return nil
+}
+
+func (o *memoryOutbox) MarkFailed(_ context.Context, id uint64, reason string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages[id-1].Attempts++
+	o.messages[id-1].LastError = &reason
+	return nil
+}
+
+func (o *memoryOutbox) MarkRejected(ctx context.Context, id uint64, reason string) error {
+	o.mu.Lock()
+	o.messages[id-1].Rejections++
+	o.mu.Unlock()
+	return o.MarkFailed(ctx, id, reason)
+}
+
+func (o *memoryOutbox) GiveUp(ctx context.Context, id uint64, reason string) error {
+	if err := o.MarkRejected(ctx, id, reason); err != nil {
+		return err
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	now := time.Now()
+	o.messages[id-1].GaveUpAt = &now
+	return nil
+}
+
+func (o *memoryOutbox) PurgePublished(context.Context, time.Time) (int64, error) { return 0, nil }
+
+func (o *memoryOutbox) published() []uint64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var ids []uint64
+	for _, m := range o.messages {
+		if m.PublishedAt != nil {
+			ids = append(ids, m.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (o *memoryOutbox) message(id uint64) domain.OutboxMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.messages[id-1]
+}
+
+// stubPublisher fails the messages in failing, or the whole batch with err.
+type stubPublisher struct {
+	err     error
+	failing map[string]error
+	calls   int
+}
+
+func (p *stubPublisher) Publish(_ context.Context, messages []domain.OutboxMessage) ([]error, error) {
+	p.calls++
+	errs := make([]error, len(messages))
+	if p.err != nil {
+		return errs, p.err
+	}
+	for i, m := range messages {
+		errs[i] = p.failing[m.EventID]
+	}
+	return errs, nil
+}
+
+func newTestRelay(outbox domain.OutboxRepository, publisher Publisher, breaker *dependency.Breaker) *Relay {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewRelay(outbox, publisher, breaker, time.Hour, 10, 3, time.Hour, logger)
+}
+
+func newTestBreaker(threshold int) *dependency.Breaker {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return dependency.NewRegistry(threshold, time.Hour, logger).Breaker("kafka")
+}
+
+func TestRelayBatchMarksAcknowledgedMessagesPublished(t *testing.T) {
+	outbox := newMemoryOutbox(3)
+	publisher := &stubPublisher{}
+	relay := newTestRelay(outbox, publisher, newTestBreaker(1))
+
+	if n := relay.relayBatch(context.Background()); n != 3 {
+		t.Fatalf("relayBatch = %d, want 3", n)
+	}
+	if got := outbox.published(); len(got) != 3 {
+		t.Fatalf("published = %v, want all three", got)
+	}
+	if n := relay.relayBatch(context.Background()); n != 0 || publisher.calls != 1 {
+		t.Fatalf("second batch read %d messages with %d publishes, want nothing to publish", n, publisher.calls)
+	}
+}
+
+func TestRelayBatchRetriesOnlyRejectedMessages(t *testing.T) {
+	outbox := newMemoryOutbox(3)
+	rejected := errors.New("message too large")
+	publisher := &stubPublisher{failing: map[string]error{"b": rejected}}
+	breaker := newTestBreaker(1)
+	relay := newTestRelay(outbox, publisher, breaker)
+
+	// A partly failed batch does not drain further batches right away
+	if n := relay.relayBatch(context.Background()); n != 0 {
+		t.Fatalf("relayBatch = %d, want 0 after a rejected message", n)
+	}
+	if got := outbox.published(); len(got) != 2 || got[0] != 1 || got[1] != 3 {
+		t.Fatalf("published = %v, want [1 3]", got)
+	}
+	failed := outbox.message(2)
+	if failed.Attempts != 1 || failed.LastError == nil || *failed.LastError != rejected.Error() {
+		t.Fatalf("rejected message = %d attempts, error %v; want one attempt with the reason", failed.Attempts, failed.LastError)
+	}
+	if !breaker.Healthy() {
+		t.Fatal("a rejected message opened the breaker")
+	}
+
+	publisher.failing = nil
+	relay.relayBatch(context.Background())
+	if got := outbox.published(); len(got) != 3 {
+		t.Fatalf("published after retry = %v, want all three", got)
+	}
+}
+
+func TestRelayBatchWaitsWhileKafkaIsDown(t *testing.T) {
+	outbox := newMemoryOutbox(2)
+	publisher := &stubPublisher{err: errors.New("connection refused")}
+	breaker := newTestBreaker(2)
+	relay := newTestRelay(outbox, publisher, breaker)
+
+	for range 2 {
+		if n := relay.relayBatch(context.Background()); n != 0 {
+			t.Fatalf("relayBatch = %d, want 0 while Kafka fails", n)
+		}
+	}
+	if breaker.Healthy() {
+		t.Fatal("breaker still closed after two failed batches")
+	}
+	for id := uint64(1); id <= 2; id++ {
+		if m := outbox.message(id); m.Attempts != 2 || m.PublishedAt != nil {
+			t.Fatalf("message %d = %d attempts, published %v; want two failed attempts", id, m.Attempts, m.PublishedAt)
+		}
+	}
+
+	// The open breaker keeps the messages in the outbox without calling Kafka
+	relay.relayBatch(context.Background())
+	if publisher.calls != 2 {
+		t.Fatalf("publisher called %d times, want 2: no call while the breaker is open", publisher.calls)
+	}
+	if got := outbox.published(); len(got) != 0 {
+		t.Fatalf("published = %v while Kafka was down", got)
+	}
+}
+
+func TestRelayBatchGivesUpOnMessagesKafkaKeepsRejecting(t *testing.T) {
+	outbox := newMemoryOutbox(2)
+	outbox.messages[0].EventType = domain.EventUserEmailVerified
+	rejected := errors.New("message too large")
+	publisher := &stubPublisher{failing: map[string]error{"a": rejected}}
+	relay := newTestRelay(outbox, publisher, newTestBreaker(1))
+	givenUp := metrics.OutboxGivenUp.WithLabelValues(string(domain.EventUserEmailVerified))
+	before := testutil.ToFloat64(givenUp)
+
+	for range 2 {
+		relay.relayBatch(context.Background())
+	}
+	if m := outbox.message(1); m.GaveUpAt != nil || m.Rejections != 2 {
+		t.Fatalf("message after two rejections = %d rejections, gave up %v; want it still retried", m.Rejections, m.GaveUpAt)
+	}
+
+	relay.relayBatch(context.Background())
+	if m := outbox.message(1); m.GaveUpAt == nil || m.Rejections != 3 || m.PublishedAt != nil {
+		t.Fatalf("message after three rejections = %d rejections, gave up %v; want it given up on", m.Rejections, m.GaveUpAt)
+	}
+	if got := testutil.ToFloat64(givenUp) - before; got != 1 {
+		t.Fatalf("given up counter rose by %v, want 1", got)
+	}
+
+	// The message given up on no longer holds up the relay
+	calls := publisher.calls
+	if n := relay.relayBatch(context.Background()); n != 0 || publisher.calls != calls {
+		t.Fatalf("relayBatch = %d with %d more publishes, want nothing left to publish", n, publisher.calls-calls)
+	}
+	if got := outbox.published(); len(got) != 1 || got[0] != 2 {
+		t.Fatalf("published = %v, want [2]", got)
+	}
+}
+
+func TestRelayBatchDoesNotCountKafkaOutagesAsRejections(t *testing.T) {
+	outbox := newMemoryOutbox(1)
+	publisher := &stubPublisher{err: errors.New("connection refused")}
+	relay := newTestRelay(outbox, publisher, newTestBreaker(100))
+
+	for range 5 {
+		relay.relayBatch(context.Background())
+	}
+	if m := outbox.message(1); m.Attempts != 5 || m.Rejections != 0 || m.GaveUpAt != nil {
+		t.Fatalf("message = %d attempts, %d rejections, gave up %v; want five failed attempts and no rejection", m.Attempts, m.Rejections, m.GaveUpAt)
+	}
+
+	// After the outage the message gets all its rejections
+	publisher.err = nil
+	publisher.failing = map[string]error{"a": errors.New("message too large")}
+	relay.relayBatch(context.Background())
+	if m := outbox.message(1); m.Rejections != 1 || m.GaveUpAt != nil {
+		t.Fatalf("message after its first rejection = %d rejections, gave up %v; want it retried", m.Rejections, m.GaveUpAt)
+	}
+}
@@ -0,0 +1,78 @@
+package health
+
+import (
+	"VoteGolang/internals/service"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/elastic/go-elasticsearch/v7"
+	"github.com/redis/go-redis/v9"
+	"github.com/segmentio/kafka-go"
+	"gorm.io/gorm"
+)
+
+// Database pings the database through the connection pool.
+func Database(db *gorm.DB) Check {
+	return Check{Name: "database", Critical: true, Probe: func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	}}
+}
+
+// Redis is optional: without it caching is bypassed.
+func Redis(rdb *redis.Client) Check {
+	return Check{Name: "redis", Probe: func(ctx context.Context) error {
+		return rdb.Ping(ctx).Err()
+	}}
+}
+
+// Elasticsearch checks the cluster answers. client is nil when the API
+// started without it.
+func Elasticsearch(client *elasticsearch.Client) Check {
+	return Check{Name: "elasticsearch", Probe: func(ctx context.Context) error {
+		if client == nil {
+			return errors.New("not connected")
+		}
+		res, err := client.Ping(client.Ping.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		defer res.Body.Close()
+		if res.IsError() {
+			return fmt.Errorf("ping failed: %s", res.Status())
+		}
+		return nil
+	}}
+}
+
+// Kafka checks the broker accepts connections and returns its metadata.
+func Kafka(broker string) Check {
+	return Check{Name: "kafka", Probe: func(ctx context.Context) error {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		if deadline, ok := ctx.Deadline(); ok {
+			conn.SetDeadline(deadline)
+		}
+		_, err = conn.Brokers()
+		return err
+	}}
+}
+
+// Blockchain checks the node returns its latest block. bc is nil when the
+// service failed to initialise.
+func Blockchain(bc service.BlockchainService) Check {
+	return Check{Name: "blockchain", Probe: func(ctx context.Context) error {
+		if bc == nil {
+			return errors.New("not initialized")
+		}
+		_, err := bc.GetServiceInfo(ctx)
+		return err
+	}}
+}
@@ -0,0 +1,120 @@
+// Package health runs the dependency checks behind the readiness probe.
+package health
+
+import (
+	"VoteGolang/internals/infrastructure/dependency"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Check probes one dependency. The API cannot serve without a Critical
+// dependency; without the others it runs with some features failing.
+type Check struct {
+	Name     string
+	Critical bool
+	Probe    func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of one check.
+type CheckResult struct {
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report is the outcome of all checks. Status is down when a critical check
+// failed or the server is shutting down, degraded when another check failed
+// or a dependency's circuit breaker is open. Degradations lists those
+// breakers: the features that are currently bypassed.
+type Report struct {
+	Status       string                 `json:"status"`
+	Checks       map[string]CheckResult `json:"checks"`
+	Degradations []dependency.Status    `json:"degradations,omitempty"`
+}
+
+// Checker runs the checks concurrently, each bounded by timeout.
+type Checker struct {
+	checks       []Check
+	timeout      time.Duration
+	registry     *dependency.Registry
+	shuttingDown atomic.Bool
+}
+
+func NewChecker(timeout time.Duration, registry *dependency.Registry, checks ...Check) *Checker {
+	return &Checker{checks: checks, timeout: timeout, registry: registry}
+}
+
+// SetShuttingDown makes the readiness probe fail, so load balancers stop
+// sending new requests while in-flight ones drain.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+func (c *Checker) ShuttingDown() bool {
+	return c.shuttingDown.Load()
+}
+
+// Run executes every check and returns the combined report.
+func (c *Checker) Run(ctx context.Context) Report {
+	results := make([]CheckResult, len(c.checks))
+	var wg sync.WaitGroup
+	for i, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult, len(c.checks))}
+	for i, check := range c.checks {
+		result := results[i]
+		report.Checks[check.Name] = result
+		if result.Status == StatusUp {
+			continue
+		}
+		if check.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	if c.registry != nil {
+		report.Degradations = c.registry.Degraded()
+		if len(report.Degradations) > 0 && report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	if c.ShuttingDown() {
+		report.Status = StatusDown
+	}
+	return report
+}
+
+func (c *Checker) run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Probe(ctx)
+	result := CheckResult{
+		Status:    StatusUp,
+		Critical:  check.Critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
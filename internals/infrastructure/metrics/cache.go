@@ -0,0 +1,75 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// cachePrefixes maps the cached key families to their cache label.
+var cachePrefixes = []struct{ prefix, cache string }{
+	{"candidates:", "candidates"},
+	{"petitions", "petitions"},
+}
+
+// CacheHook counts GET hits and misses on the candidate and petition list
+// caches. Other keys are not counted.
+type CacheHook struct{}
+
+// NewCacheHook returns a hook to add with redis.Client.AddHook.
+func NewCacheHook() redis.Hook {
+	return CacheHook{}
+}
+
+func (CacheHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+func (CacheHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		observeCache(cmd, err)
+		return err
+	}
+}
+
+func (CacheHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			observeCache(cmd, cmd.Err())
+		}
+		return err
+	}
+}
+
+func observeCache(cmd redis.Cmder, err error) {
+	if cmd.Name() != "get" {
+		return
+	}
+	args := cmd.Args()
+	if len(args) < 2 {
+		return
+	}
+	key, ok := args[1].(string)
+	if !ok {
+		return
+	}
+	for _, p := range cachePrefixes {
+		if !strings.HasPrefix(key, p.prefix) {
+			continue
+		}
+		switch {
+		case err == nil:
+			CacheRequests.WithLabelValues(p.cache, "hit").Inc()
+		case errors.Is(err, redis.Nil):
+			CacheRequests.WithLabelValues(p.cache, "miss").Inc()
+		}
+		return
+	}
+}
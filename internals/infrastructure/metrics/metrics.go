@@ -0,0 +1,134 @@
+// Package metrics defines the Prometheus metrics of the service and serves
+// them on /metrics. Collectors live on the default registry, so any package
+// can record to them without wiring.
+package metrics
+
+import (
+	"VoteGolang/internals/app/logging"
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "votegolang"
+
+var (
+	// HTTPRequestDuration is labelled with the route pattern, not the raw
+	// path, so IDs in URLs do not create new series.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	CandidateVotes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "candidate_votes_total",
+		Help:      "Candidate votes committed, by election type.",
+	}, []string{"election"})
+
+	PetitionVotes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "petition_votes_total",
+		Help:      "Petition votes committed, by vote type.",
+	}, []string{"vote_type"})
+
+	// BlockchainTxDuration covers the whole call, from packing to the mined
+	// receipt, so the buckets reach into minutes.
+	BlockchainTxDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "blockchain",
+		Name:      "tx_duration_seconds",
+		Help:      "Time to send a contract transaction and wait for it to be mined.",
+		Buckets:   []float64{0.5, 1, 2, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"function", "result"})
+
+	BlockchainTxFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "blockchain",
+		Name:      "tx_failures_total",
+		Help:      "Contract transactions that failed or reverted.",
+	}, []string{"function"})
+
+	BlockchainTxFees = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "blockchain",
+		Name:      "tx_fees_wei_total",
+		Help:      "Fees paid for mined contract transactions, in wei.",
+	}, []string{"function"})
+
+	OutboxGivenUp = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "outbox",
+		Name:      "given_up_total",
+		Help:      "Outbox messages Kafka rejected too often to retry, by event type.",
+	}, []string{"event_type"})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Redis cache lookups by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	DependencyDegraded = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "dependency",
+		Name:      "degraded",
+		Help:      "1 while the circuit breaker of an optional dependency is open or half-open.",
+	}, []string{"dependency"})
+
+	CleanupRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cleanup",
+		Name:      "runs_total",
+		Help:      "Runs of the unverified-user cleanup job by result.",
+	}, []string{"result"})
+
+	UnverifiedUsersDeleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cleanup",
+		Name:      "unverified_users_deleted_total",
+		Help:      "Unverified users deleted by the cleanup job.",
+	})
+
+	CleanupLastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "cleanup",
+		Name:      "last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful cleanup run.",
+	})
+)
+
+// Handler serves the default registry.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterDBStats exports the connection pool statistics of db.
+func RegisterDBStats(db *sql.DB) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// RegisterLogSink exports the records the Kafka log sink dropped or failed
+// to deliver.
+func RegisterLogSink(sink *logging.KafkaSink) {
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logger",
+		Name:      "kafka_dropped_total",
+		Help:      "Log records dropped because the Kafka buffer was full.",
+	}, func() float64 { return float64(sink.Dropped()) })
+	promauto.NewCounterFunc(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "logger",
+		Name:      "kafka_failed_total",
+		Help:      "Log records Kafka did not accept.",
+	}, func() float64 { return float64(sink.Failed()) })
+}
@@ -0,0 +1,42 @@
+package moderation
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+)
+
+// DuplicateMinScore is the relevance score above which a search hit is treated
+// as a likely duplicate.
+const DuplicateMinScore = 5.0
+
+// DuplicateScreener flags petitions that closely match one already present in
+// the petitions search index.
+type DuplicateScreener struct {
+	indexer domain.SearchIndexer
+}
+
+func NewDuplicateScreener(indexer domain.SearchIndexer) *DuplicateScreener {
+	return &DuplicateScreener{indexer: indexer}
+}
+
+func (s *DuplicateScreener) Screen(ctx context.Context, p *domain.Petition) ([]string, error) {
+	text := p.Title
+	if p.Description != nil {
+		text += " " + *p.Description
+	}
+
+	hits, err := s.indexer.FindSimilar(ctx, text, []string{"title", "description"}, 3, DuplicateMinScore)
+	if err != nil {
+		return nil, err
+	}
+
+	var flags []string
+	for _, hit := range hits {
+		if hit.ID == fmt.Sprintf("%d", p.ID) {
+			continue
+		}
+		flags = append(flags, fmt.Sprintf("possible duplicate of petition %s: %v", hit.ID, hit.Source["title"]))
+	}
+	return flags, nil
+}
@@ -0,0 +1,53 @@
+package moderation
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultProfanityList is a small built-in word list. Deployments are expected
+// to pass their own list to NewProfanityScreener.
+var DefaultProfanityList = []string{
+	"fuck", "shit", "bitch", "bastard", "asshole", "dick", "cunt",
+	"блять", "сука", "хуй", "пизда", "ебать",
+}
+
+// ProfanityScreener flags petitions whose title or description contains a
+// word from its list. Matching is case-insensitive and on whole words.
+type ProfanityScreener struct {
+	words map[string]bool
+}
+
+func NewProfanityScreener(words []string) *ProfanityScreener {
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[strings.ToLower(strings.TrimSpace(w))] = true
+	}
+	return &ProfanityScreener{words: set}
+}
+
+func (s *ProfanityScreener) Screen(_ context.Context, p *domain.Petition) ([]string, error) {
+	text := p.Title
+	if p.Description != nil {
+		text += " " + *p.Description
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if s.words[word] && !seen[word] {
+			seen[word] = true
+			found = append(found, word)
+		}
+	}
+
+	if len(found) == 0 {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("contains profanity: %s", strings.Join(found, ", "))}, nil
+}
@@ -0,0 +1,91 @@
+package projections
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// PetitionLeaderboardKey ranks petitions by signatures (votes in favor).
+	PetitionLeaderboardKey = "leaderboard:petitions"
+	// TurnoutKey is a hash of voters per election type.
+	TurnoutKey = "turnout:voters"
+
+	appliedKeyPrefix = "projection:applied:"
+	// appliedTTL bounds how long applied event IDs are remembered; replays
+	// reaching further back must reset the projections first.
+	appliedTTL = 30 * 24 * time.Hour
+)
+
+// CandidateLeaderboardKey ranks the candidates of an election type by votes.
+func CandidateLeaderboardKey(candidateType domain.CandidateType) string {
+	return "leaderboard:" + string(candidateType)
+}
+
+// applyOnce marks the event applied and runs the increments atomically, so a
+// redelivered event changes nothing.
+var applyOnce = redis.NewScript(`
+if not redis.call('SET', KEYS[1], 1, 'NX', 'PX', ARGV[1]) then
+	return 0
+end
+redis.call('ZINCRBY', KEYS[2], 1, ARGV[2])
+if KEYS[3] then
+	redis.call('HINCRBY', KEYS[3], ARGV[3], 1)
+end
+return 1
+`)
+
+type RedisLeaderboards struct {
+	rdb *redis.Client
+}
+
+func NewRedisLeaderboards(rdb *redis.Client) *RedisLeaderboards {
+	return &RedisLeaderboards{rdb: rdb}
+}
+
+func (l *RedisLeaderboards) AddCandidateVote(ctx context.Context, key string, vote domain.VoteCast) (bool, error) {
+	keys := []string{appliedKeyPrefix + key, CandidateLeaderboardKey(vote.CandidateType), TurnoutKey}
+	return l.apply(ctx, keys, strconv.FormatUint(uint64(vote.CandidateID), 10), string(vote.CandidateType))
+}
+
+func (l *RedisLeaderboards) AddPetitionSignature(ctx context.Context, eventID string, petitionID uint) (bool, error) {
+	keys := []string{appliedKeyPrefix + eventID, PetitionLeaderboardKey}
+	return l.apply(ctx, keys, strconv.FormatUint(uint64(petitionID), 10))
+}
+
+func (l *RedisLeaderboards) apply(ctx context.Context, keys []string, args ...interface{}) (bool, error) {
+	args = append([]interface{}{appliedTTL.Milliseconds()}, args...)
+	n, err := applyOnce.Run(ctx, l.rdb, keys, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("apply projection: %w", err)
+	}
+	return n == 1, nil
+}
+
+// Reset deletes the leaderboards, the turnout and the applied event IDs.
+func (l *RedisLeaderboards) Reset(ctx context.Context) error {
+	for _, pattern := range []string{"leaderboard:*", TurnoutKey, appliedKeyPrefix + "*"} {
+		var cursor uint64
+		for {
+			keys, next, err := l.rdb.Scan(ctx, cursor, pattern, 500).Result()
+			if err != nil {
+				return err
+			}
+			if len(keys) > 0 {
+				if err := l.rdb.Del(ctx, keys...).Err(); err != nil {
+					return err
+				}
+			}
+			if next == 0 {
+				break
+			}
+			cursor = next
+		}
+	}
+	return nil
+}
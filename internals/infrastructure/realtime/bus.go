@@ -0,0 +1,30 @@
+package realtime
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tallyChannel is the Redis pub/sub channel vote events travel on.
+const tallyChannel = "tally:events"
+
+// RedisTallyBus publishes vote events on a Redis channel so that the hubs of
+// all API instances see every vote, whichever instance handled it.
+type RedisTallyBus struct {
+	rdb *redis.Client
+}
+
+func NewRedisTallyBus(rdb *redis.Client) *RedisTallyBus {
+	return &RedisTallyBus{rdb: rdb}
+}
+
+func (b *RedisTallyBus) PublishTally(ctx context.Context, event domain.TallyEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return b.rdb.Publish(ctx, tallyChannel, data).Err()
+}
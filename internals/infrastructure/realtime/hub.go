@@ -0,0 +1,201 @@
+package realtime
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Hub fans tally updates out to the stream clients of this instance. Vote
+// events arrive over Redis pub/sub; topics touched by events are marked dirty
+// and refreshed at most once per throttle interval, so a burst of votes
+// becomes a single update with the latest counts.
+type Hub struct {
+	rdb      *redis.Client
+	source   *SnapshotSource
+	throttle time.Duration
+	logger   *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan []byte]struct{}
+	dirty       map[string]bool
+	// revealAt holds when hidden elections with subscribers close, so their
+	// results are pushed even though no more votes will arrive.
+	revealAt map[string]time.Time
+	closed   bool
+}
+
+// ErrHubClosed is returned by Subscribe once the hub has stopped.
+var ErrHubClosed = errors.New("live results are shutting down")
+
+func NewHub(rdb *redis.Client, source *SnapshotSource, throttle time.Duration, logger *slog.Logger) *Hub {
+	if throttle <= 0 {
+		throttle = time.Second
+	}
+	return &Hub{
+		rdb:         rdb,
+		source:      source,
+		throttle:    throttle,
+		logger:      logger,
+		subscribers: make(map[string]map[chan []byte]struct{}),
+		dirty:       make(map[string]bool),
+		revealAt:    make(map[string]time.Time),
+	}
+}
+
+// Run listens for vote events until ctx is cancelled. It then closes the
+// channels of all subscribers, which ends their streams.
+func (h *Hub) Run(ctx context.Context) {
+	defer h.close()
+	pubsub := h.rdb.Subscribe(ctx, tallyChannel)
+	defer pubsub.Close()
+	messages := pubsub.Channel()
+
+	ticker := time.NewTicker(h.throttle)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var event domain.TallyEvent
+			if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+				h.logger.Warn("Ignoring malformed tally event", logging.Err(err))
+				continue
+			}
+			h.markDirty(event.Topic())
+		case <-ticker.C:
+			h.flush(ctx)
+		}
+	}
+}
+
+// Subscribe registers a client for topic and immediately queues the current
+// snapshot. The returned cancel func must be called when the client leaves.
+// The channel only ever holds the latest update: slow clients skip
+// intermediate ones rather than block the hub.
+func (h *Hub) Subscribe(ctx context.Context, topic string) (<-chan []byte, func(), error) {
+	snap, revealAt, err := h.source.Snapshot(ctx, topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ch := make(chan []byte, 1)
+	ch <- data
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return nil, nil, ErrHubClosed
+	}
+	if h.subscribers[topic] == nil {
+		h.subscribers[topic] = make(map[chan []byte]struct{})
+	}
+	h.subscribers[topic][ch] = struct{}{}
+	if !revealAt.IsZero() {
+		h.revealAt[topic] = revealAt
+	}
+	h.mu.Unlock()
+
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[topic], ch)
+		if len(h.subscribers[topic]) == 0 {
+			delete(h.subscribers, topic)
+			delete(h.dirty, topic)
+			delete(h.revealAt, topic)
+		}
+	}
+	return ch, cancel, nil
+}
+
+func (h *Hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, chans := range h.subscribers {
+		for ch := range chans {
+			close(ch)
+		}
+	}
+	h.subscribers = make(map[string]map[chan []byte]struct{})
+	h.dirty = make(map[string]bool)
+	h.revealAt = make(map[string]time.Time)
+}
+
+func (h *Hub) markDirty(topic string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// Nobody on this instance listens, so there is nothing to refresh
+	if len(h.subscribers[topic]) > 0 {
+		h.dirty[topic] = true
+	}
+}
+
+func (h *Hub) flush(ctx context.Context) {
+	now := time.Now()
+
+	h.mu.Lock()
+	topics := make([]string, 0, len(h.dirty))
+	for topic := range h.dirty {
+		topics = append(topics, topic)
+	}
+	h.dirty = make(map[string]bool)
+	for topic, at := range h.revealAt {
+		if now.After(at) {
+			topics = append(topics, topic)
+			delete(h.revealAt, topic)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, topic := range topics {
+		snap, _, err := h.source.Snapshot(ctx, topic)
+		if err != nil {
+			h.logger.Warn("Failed to load tally snapshot", "topic", topic, logging.Err(err))
+			continue
+		}
+		// Clients already know a hidden election is hidden; an update would
+		// only leak that votes are coming in.
+		if snap.Hidden {
+			continue
+		}
+		data, err := json.Marshal(snap)
+		if err != nil {
+			continue
+		}
+		h.broadcast(topic, data)
+	}
+}
+
+func (h *Hub) broadcast(topic string, data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers[topic] {
+		// Replace an unread update with the newer one
+		select {
+		case <-ch:
+		default:
+		}
+		select {
+		case ch <- data:
+		default:
+		}
+	}
+}
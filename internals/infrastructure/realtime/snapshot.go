@@ -0,0 +1,114 @@
+package realtime
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SnapshotSource loads the current tallies of a topic from the database.
+type SnapshotSource struct {
+	candidates domain.CandidateRepository
+	petitions  domain.PetitionRepository
+	// hidden lists election types whose results stay secret while voting is open.
+	hidden map[domain.CandidateType]bool
+}
+
+func NewSnapshotSource(cr domain.CandidateRepository, pr domain.PetitionRepository, hiddenTypes []string) *SnapshotSource {
+	hidden := make(map[domain.CandidateType]bool, len(hiddenTypes))
+	for _, t := range hiddenTypes {
+		hidden[domain.CandidateType(strings.TrimSpace(t))] = true
+	}
+	return &SnapshotSource{candidates: cr, petitions: pr, hidden: hidden}
+}
+
+// ParseTopic validates a topic and splits it into its kind and key.
+func ParseTopic(topic string) (kind, key string, err error) {
+	kind, key, ok := strings.Cut(topic, ":")
+	if !ok || key == "" {
+		return "", "", fmt.Errorf("invalid topic %q", topic)
+	}
+	switch kind {
+	case "election":
+		if !domain.IsValidCandidateType(key) {
+			return "", "", fmt.Errorf("invalid election type %q", key)
+		}
+	case "petition":
+		if id, err := strconv.ParseUint(key, 10, 64); err != nil || id == 0 {
+			return "", "", fmt.Errorf("invalid petition id %q", key)
+		}
+	default:
+		return "", "", fmt.Errorf("invalid topic %q", topic)
+	}
+	return kind, key, nil
+}
+
+// Snapshot returns the current state of topic. For hidden elections it also
+// returns when the results become public; otherwise revealAt is zero.
+func (s *SnapshotSource) Snapshot(ctx context.Context, topic string) (snap *domain.TallySnapshot, revealAt time.Time, err error) {
+	kind, key, err := ParseTopic(topic)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	if kind == "petition" {
+		id, _ := strconv.ParseUint(key, 10, 64)
+		petition, err := s.petitions.GetByID(ctx, uint(id))
+		if err != nil {
+			return nil, time.Time{}, err
+		}
+		// Petitions awaiting moderation or rejected are not public
+		if petition.Status != domain.PetitionApproved {
+			return nil, time.Time{}, gorm.ErrRecordNotFound
+		}
+		inFavor, against := petition.VotesInFavor, petition.VotesAgainst
+		return &domain.TallySnapshot{
+			Topic:        topic,
+			PetitionID:   petition.ID,
+			VotesInFavor: &inFavor,
+			VotesAgainst: &against,
+			Goal:         petition.Goal,
+			UpdatedAt:    time.Now(),
+		}, time.Time{}, nil
+	}
+
+	election := domain.CandidateType(key)
+	candidates, err := s.candidates.GetAllByType(ctx, key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	// The election is open as long as any of its candidates can receive votes
+	now := time.Now()
+	var closesAt time.Time
+	for _, c := range candidates {
+		if c.VotingDeadline.After(closesAt) {
+			closesAt = c.VotingDeadline
+		}
+	}
+	hidden := s.hidden[election] && now.Before(closesAt)
+
+	snap = &domain.TallySnapshot{
+		Topic:      topic,
+		Election:   election,
+		Hidden:     hidden,
+		Candidates: make([]domain.CandidateTally, len(candidates)),
+		UpdatedAt:  now,
+	}
+	for i, c := range candidates {
+		snap.Candidates[i] = domain.CandidateTally{ID: c.ID, Name: c.Name}
+		if !hidden {
+			votes := c.Votes
+			snap.Candidates[i].Votes = &votes
+		}
+	}
+	if hidden {
+		revealAt = closesAt
+	}
+	return snap, revealAt, nil
+}
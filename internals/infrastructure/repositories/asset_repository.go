@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type assetGormRepository struct {
+	db *gorm.DB
+}
+
+func NewAssetRepository(db *gorm.DB) domain.AssetRepository {
+	return &assetGormRepository{db: db}
+}
+
+func (r *assetGormRepository) Create(ctx context.Context, asset *domain.Asset) error {
+	return r.db.WithContext(ctx).Create(asset).Error
+}
+
+func (r *assetGormRepository) GetByID(ctx context.Context, id string) (*domain.Asset, error) {
+	var asset domain.Asset
+	err := r.db.WithContext(ctx).First(&asset, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &asset, nil
+}
@@ -2,6 +2,8 @@ package repositories
 
 import (
 	"VoteGolang/internals/domain"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -14,13 +16,14 @@ func NewCandidateRepository(db *gorm.DB) domain.CandidateRepository {
 	return &candidateGormRepository{db: db}
 }
 
-func (r *candidateGormRepository) Create(candidate *domain.Candidate) error {
-	return r.db.Create(candidate).Error
+func (r *candidateGormRepository) Create(ctx context.Context, candidate *domain.Candidate) error {
+	return r.db.WithContext(ctx).Create(candidate).Error
 }
 
-func (r *candidateGormRepository) GetAllByTypePaginated(candidateType string, limit, offset int) ([]domain.Candidate, error) {
+func (r *candidateGormRepository) GetAllByTypePaginated(ctx context.Context, candidateType string, limit, offset int) ([]domain.Candidate, error) {
 	var candidates []domain.Candidate
-	err := r.db.
+	err := r.db.WithContext(ctx).
+		Preload("Party").
 		Where("type = ?", candidateType).
 		Limit(limit).
 		Offset(offset).
@@ -28,27 +31,115 @@ func (r *candidateGormRepository) GetAllByTypePaginated(candidateType string, li
 	return candidates, err
 }
 
-func (r *candidateGormRepository) GetAllByType(candidateType string) ([]domain.Candidate, error) {
+func (r *candidateGormRepository) GetAllByType(ctx context.Context, candidateType string) ([]domain.Candidate, error) {
 	var candidates []domain.Candidate
-	err := r.db.Where("type = ?", candidateType).Find(&candidates).Error
+	err := r.db.WithContext(ctx).Preload("Party").Where("type = ?", candidateType).Find(&candidates).Error
 	return candidates, err
 }
 
-func (r *candidateGormRepository) GetByID(id uint) (*domain.Candidate, error) {
+func (r *candidateGormRepository) GetByID(ctx context.Context, id uint) (*domain.Candidate, error) {
 	var candidate domain.Candidate
-	err := r.db.First(&candidate, id).Error
+	err := r.db.WithContext(ctx).
+		Preload("Party").
+		Preload("SocialLinks").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Preload("ManifestoPoints", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&candidate, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &candidate, nil
 }
 
-func (r *candidateGormRepository) IncrementVote(id uint) error {
-	return r.db.Model(&domain.Candidate{}).
+func (r *candidateGormRepository) GetByIDs(ctx context.Context, ids []uint) ([]domain.Candidate, error) {
+	var candidates []domain.Candidate
+	err := r.db.WithContext(ctx).
+		Preload("Party").
+		Preload("ManifestoPoints", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Where("id IN ?", ids).
+		Find(&candidates).Error
+	return candidates, err
+}
+
+func (r *candidateGormRepository) IncrementVote(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&domain.Candidate{}).
 		Where("id = ?", id).
 		UpdateColumn("votes", gorm.Expr("votes + ?", 1)).Error
 }
 
-func (r *candidateGormRepository) DeleteByID(id uint) error {
-	return r.db.Delete(&domain.Candidate{}, id).Error
+func (r *candidateGormRepository) DeleteByID(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Candidate{}, id).Error
+}
+
+func (r *candidateGormRepository) Update(ctx context.Context, candidate *domain.Candidate, changes []domain.CandidateChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Votes are only ever changed by IncrementVote, so never write them here
+		if err := tx.Model(candidate).
+			Select("name", "photo", "photo_asset_id", "education", "age", "party_id", "region", "biography", "manifesto", "voting_start", "voting_deadline", "updated_at").
+			Updates(candidate).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("candidate_id = ?", candidate.ID).Delete(&domain.SocialLink{}).Error; err != nil {
+			return err
+		}
+		for i := range candidate.SocialLinks {
+			candidate.SocialLinks[i].ID = 0
+			candidate.SocialLinks[i].CandidateID = candidate.ID
+		}
+		if len(candidate.SocialLinks) > 0 {
+			if err := tx.Create(&candidate.SocialLinks).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("candidate_id = ?", candidate.ID).Delete(&domain.CandidatePhoto{}).Error; err != nil {
+			return err
+		}
+		for i := range candidate.Photos {
+			candidate.Photos[i].ID = 0
+			candidate.Photos[i].CandidateID = candidate.ID
+		}
+		if len(candidate.Photos) > 0 {
+			if err := tx.Create(&candidate.Photos).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("candidate_id = ?", candidate.ID).Delete(&domain.ManifestoPoint{}).Error; err != nil {
+			return err
+		}
+		for i := range candidate.ManifestoPoints {
+			candidate.ManifestoPoints[i].ID = 0
+			candidate.ManifestoPoints[i].CandidateID = candidate.ID
+		}
+		if len(candidate.ManifestoPoints) > 0 {
+			if err := tx.Create(&candidate.ManifestoPoints).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(changes) > 0 {
+			return tx.Create(&changes).Error
+		}
+		return nil
+	})
+}
+
+func (r *candidateGormRepository) GetHistory(ctx context.Context, id uint) ([]domain.CandidateChange, error) {
+	var changes []domain.CandidateChange
+	err := r.db.WithContext(ctx).
+		Where("candidate_id = ?", id).
+		Order("created_at DESC").
+		Order("id DESC").
+		Find(&changes).Error
+	return changes, err
+}
+
+func (r *candidateGormRepository) CloseVoting(ctx context.Context, candidateType domain.CandidateType, at time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&domain.Candidate{}).
+		Where("type = ? AND voting_deadline > ?", candidateType, at).
+		Update("voting_deadline", at)
+	return res.RowsAffected, res.Error
 }
@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCloseVotingOnlyMovesOpenDeadlines(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewCandidateRepository(db)
+	open := createCandidate(t, db, "Open", domain.Deputy)
+	closed := createCandidate(t, db, "Closed", domain.Deputy)
+	other := createCandidate(t, db, "Other", domain.Manager)
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := db.Model(closed).Update("voting_deadline", past).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Now().Truncate(time.Second)
+	n, err := repo.CloseVoting(ctx, domain.Deputy, at)
+	if err != nil {
+		t.Fatalf("close voting: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("closed %d candidates, want 1", n)
+	}
+
+	for _, tt := range []struct {
+		id   uint
+		want time.Time
+	}{
+		{open.ID, at},
+		{closed.ID, past},
+		{other.ID, other.VotingDeadline},
+	} {
+		got, err := repo.GetByID(ctx, tt.id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.VotingDeadline.Equal(tt.want) {
+			t.Fatalf("candidate %d deadline = %v, want %v", tt.id, got.VotingDeadline, tt.want)
+		}
+	}
+}
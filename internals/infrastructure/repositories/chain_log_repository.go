@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type chainLogGormRepository struct {
+	db *gorm.DB
+}
+
+func NewChainLogQueue(db *gorm.DB) domain.ChainLogQueue {
+	return &chainLogGormRepository{db: db}
+}
+
+func (r *chainLogGormRepository) Enqueue(ctx context.Context, action string, payload []byte) error {
+	return r.db.WithContext(ctx).Create(&domain.ChainLogEntry{Action: action, Payload: payload}).Error
+}
+
+func (r *chainLogGormRepository) Pending(ctx context.Context, limit int) ([]domain.ChainLogEntry, error) {
+	var entries []domain.ChainLogEntry
+	err := r.db.WithContext(ctx).
+		Where("gave_up_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&entries).Error
+	return entries, err
+}
+
+func (r *chainLogGormRepository) Delete(ctx context.Context, id uint64) error {
+	return r.db.WithContext(ctx).Delete(&domain.ChainLogEntry{}, id).Error
+}
+
+func (r *chainLogGormRepository) MarkFailed(ctx context.Context, id uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&domain.ChainLogEntry{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + ?", 1),
+			"last_error": reason,
+		}).Error
+}
+
+func (r *chainLogGormRepository) GiveUp(ctx context.Context, id uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&domain.ChainLogEntry{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + ?", 1),
+			"last_error": reason,
+			"gave_up_at": time.Now(),
+		}).Error
+}
+
+func (r *chainLogGormRepository) CountPending(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.ChainLogEntry{}).
+		Where("gave_up_at IS NULL").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// The repositories run on MySQL, PostgreSQL and SQLite. Queries stick to SQL
+// the three share; the differences are kept here.
+
+// forUpdate locks the rows the query selects until the transaction ends.
+// SQLite has no row locks: it lets one writer in at a time instead.
+func forUpdate(tx *gorm.DB) *gorm.DB {
+	if tx.Dialector.Name() == "sqlite" {
+		return tx
+	}
+	return tx.Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate})
+}
+
+// likeEscape is the escape character of LIKE patterns. Backslash is not
+// portable: it is the default on MySQL and PostgreSQL but not on SQLite, and
+// MySQL string literals would need it doubled.
+const likeEscape = "!"
+
+// containsCondition matches column against a case-insensitive substring;
+// MySQL collations ignore case but PostgreSQL and SQLite do not.
+func containsCondition(column string) string {
+	return "LOWER(" + column + ") LIKE ? ESCAPE '" + likeEscape + "'"
+}
+
+// containsPattern is the LIKE pattern of containsCondition, with % and _ in
+// s matching literally.
+func containsPattern(s string) string {
+	escaped := strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_").Replace(s)
+	return "%" + strings.ToLower(escaped) + "%"
+}
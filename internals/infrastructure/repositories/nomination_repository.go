@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrNominationNotSubmitted = errors.New("nomination is not awaiting review")
+
+type nominationGormRepository struct {
+	db *gorm.DB
+}
+
+func NewNominationRepository(db *gorm.DB) domain.NominationRepository {
+	return &nominationGormRepository{db: db}
+}
+
+func (r *nominationGormRepository) Create(ctx context.Context, n *domain.Nomination) error {
+	return r.db.WithContext(ctx).Create(n).Error
+}
+
+func (r *nominationGormRepository) GetByID(ctx context.Context, id uint) (*domain.Nomination, error) {
+	var n domain.Nomination
+	err := r.db.WithContext(ctx).Preload("Documents").First(&n, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func (r *nominationGormRepository) GetAllPaginated(ctx context.Context, status domain.NominationStatus, limit, offset int) ([]domain.Nomination, error) {
+	var nominations []domain.Nomination
+	query := r.db.WithContext(ctx).Preload("Documents").Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Limit(limit).Offset(offset).Find(&nominations).Error
+	return nominations, err
+}
+
+// Endorse signs a nomination the way a petition vote signs a petition. The
+// nomination is locked so its status cannot change between the check and
+// the signature.
+func (r *nominationGormRepository) Endorse(ctx context.Context, nominationID, userID uint) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var n domain.Nomination
+		if err := forUpdate(tx).First(&n, nominationID).Error; err != nil {
+			return err
+		}
+		if n.Status != domain.NominationCollecting {
+			return domain.ErrNominationNotCollecting
+		}
+
+		endorsement := &domain.NominationEndorsement{NominationID: nominationID, UserID: userID}
+		var err error
+		created, err = sign(tx, endorsement, []string{"nomination_id", "user_id"}, &domain.Nomination{}, nominationID, "endorsements")
+		if err != nil || !created {
+			return err
+		}
+		if n.Endorsements+1 < n.RequiredEndorsements {
+			return nil
+		}
+		return tx.Model(&domain.Nomination{}).
+			Where("id = ?", nominationID).
+			UpdateColumn("status", domain.NominationSubmitted).Error
+	})
+	return created, err
+}
+
+func (r *nominationGormRepository) Approve(ctx context.Context, n *domain.Nomination, reviewerID uint, candidate *domain.Candidate, events domain.EventsFunc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(candidate).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		result := tx.Model(&domain.Nomination{}).
+			Where("id = ? AND status = ?", n.ID, domain.NominationSubmitted).
+			Updates(map[string]interface{}{
+				"status":       domain.NominationApproved,
+				"reviewer_id":  reviewerID,
+				"reviewed_at":  now,
+				"candidate_id": candidate.ID,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNominationNotSubmitted
+		}
+		if err := insertEvents(tx, events); err != nil {
+			return err
+		}
+
+		n.Status = domain.NominationApproved
+		n.ReviewerID = &reviewerID
+		n.ReviewedAt = &now
+		n.CandidateID = &candidate.ID
+		return nil
+	})
+}
+
+func (r *nominationGormRepository) Reject(ctx context.Context, n *domain.Nomination, reviewerID uint, reason string) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).Model(&domain.Nomination{}).
+		Where("id = ? AND status IN ?", n.ID, []domain.NominationStatus{domain.NominationCollecting, domain.NominationSubmitted}).
+		Updates(map[string]interface{}{
+			"status":        domain.NominationRejected,
+			"reviewer_id":   reviewerID,
+			"reviewed_at":   now,
+			"reject_reason": reason,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNominationNotSubmitted
+	}
+
+	n.Status = domain.NominationRejected
+	n.ReviewerID = &reviewerID
+	n.ReviewedAt = &now
+	n.RejectReason = &reason
+	return nil
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestEndorseSubmitsOnTheLastRequiredEndorsement(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewNominationRepository(db)
+	nominator := createUser(t, db, "nominator")
+	n := &domain.Nomination{UserID: nominator.ID, Name: "Nominee", Age: 30, Type: domain.Presidential,
+		Status: domain.NominationCollecting, RequiredEndorsements: 2}
+	if err := db.Create(n).Error; err != nil {
+		t.Fatal(err)
+	}
+	first := createUser(t, db, "first")
+	second := createUser(t, db, "second")
+	late := createUser(t, db, "late")
+
+	for _, step := range []struct {
+		user    uint
+		created bool
+		status  domain.NominationStatus
+	}{
+		{first.ID, true, domain.NominationCollecting},
+		{first.ID, false, domain.NominationCollecting},
+		{second.ID, true, domain.NominationSubmitted},
+	} {
+		created, err := repo.Endorse(ctx, n.ID, step.user)
+		if err != nil {
+			t.Fatalf("endorse by %d: %v", step.user, err)
+		}
+		got, err := repo.GetByID(ctx, n.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if created != step.created || got.Status != step.status {
+			t.Fatalf("endorse by %d = %v, status %s; want %v, %s", step.user, created, got.Status, step.created, step.status)
+		}
+	}
+
+	// A submitted nomination takes no more endorsements
+	if _, err := repo.Endorse(ctx, n.ID, late.ID); !errors.Is(err, domain.ErrNominationNotCollecting) {
+		t.Fatalf("endorse after submission = %v, want %v", err, domain.ErrNominationNotCollecting)
+	}
+	var endorsements int64
+	db.Model(&domain.NominationEndorsement{}).Where("nomination_id = ?", n.ID).Count(&endorsements)
+	got, err := repo.GetByID(ctx, n.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if endorsements != 2 || got.Endorsements != 2 {
+		t.Fatalf("endorsements = %d rows, counter %d; want 2 and 2", endorsements, got.Endorsements)
+	}
+}
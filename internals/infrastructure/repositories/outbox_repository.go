@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxGormRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &outboxGormRepository{db: db}
+}
+
+func (r *outboxGormRepository) Record(ctx context.Context, events ...domain.Event) error {
+	return insertOutbox(r.db.WithContext(ctx), events)
+}
+
+// insertEvents stores the events build returns with tx, the transaction of
+// the change they describe.
+func insertEvents(tx *gorm.DB, build domain.EventsFunc) error {
+	if build == nil {
+		return nil
+	}
+	events, err := build()
+	if err != nil {
+		return err
+	}
+	return insertOutbox(tx, events)
+}
+
+// insertOutbox stores events with db, which may be a transaction so the
+// events commit together with the change they describe. Events already in
+// the outbox are skipped.
+func insertOutbox(db *gorm.DB, events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	messages := make([]domain.OutboxMessage, len(events))
+	for i, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		messages[i] = domain.OutboxMessage{
+			EventID:    e.ID,
+			Topic:      e.Topic(),
+			MessageKey: e.AggregateID,
+			EventType:  e.Type,
+			Payload:    payload,
+		}
+	}
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}},
+		DoNothing: true,
+	}).Create(&messages).Error
+}
+
+func (r *outboxGormRepository) Pending(ctx context.Context, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+	err := r.db.WithContext(ctx).
+		Where("published_at IS NULL AND gave_up_at IS NULL").
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+	return messages, err
+}
+
+func (r *outboxGormRepository) MarkPublished(ctx context.Context, ids []uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&domain.OutboxMessage{}).
+		Where("id IN ?", ids).
+		UpdateColumn("published_at", time.Now()).Error
+}
+
+func (r *outboxGormRepository) MarkFailed(ctx context.Context, id uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&domain.OutboxMessage{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + ?", 1),
+			"last_error": reason,
+		}).Error
+}
+
+func (r *outboxGormRepository) MarkRejected(ctx context.Context, id uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&domain.OutboxMessage{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + ?", 1),
+			"rejections": gorm.Expr("rejections + ?", 1),
+			"last_error": reason,
+		}).Error
+}
+
+func (r *outboxGormRepository) GiveUp(ctx context.Context, id uint64, reason string) error {
+	return r.db.WithContext(ctx).Model(&domain.OutboxMessage{}).
+		Where("id = ?", id).
+		UpdateColumns(map[string]interface{}{
+			"attempts":   gorm.Expr("attempts + ?", 1),
+			"rejections": gorm.Expr("rejections + ?", 1),
+			"last_error": reason,
+			"gave_up_at": time.Now(),
+		}).Error
+}
+
+func (r *outboxGormRepository) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&domain.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
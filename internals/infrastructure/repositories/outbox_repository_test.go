@@ -0,0 +1,77 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"testing"
+)
+
+func TestOutboxRecordIgnoresDuplicateEvents(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewOutboxRepository(db)
+
+	event, err := domain.NewEvent(domain.VoteCast{CandidateID: 1, CandidateType: domain.Presidential, UserID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Record(ctx, event); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	if err := repo.Record(ctx, event); err != nil {
+		t.Fatalf("record again: %v", err)
+	}
+
+	pending, err := repo.Pending(ctx, 10)
+	if err != nil {
+		t.Fatalf("pending: %v", err)
+	}
+	if len(pending) != 1 || pending[0].EventID != event.ID {
+		t.Fatalf("pending = %+v, want one message for %s", pending, event.ID)
+	}
+
+	if err := repo.MarkPublished(ctx, []uint64{pending[0].ID}); err != nil {
+		t.Fatalf("mark published: %v", err)
+	}
+	if pending, err = repo.Pending(ctx, 10); err != nil || len(pending) != 0 {
+		t.Fatalf("pending after publish = %d, %v; want none", len(pending), err)
+	}
+}
+
+func TestOutboxPendingSkipsMessagesGivenUpOn(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewOutboxRepository(db)
+
+	var events []domain.Event
+	for userID := uint(1); userID <= 2; userID++ {
+		event, err := domain.NewEvent(domain.UserEmailVerified{UserID: userID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		events = append(events, event)
+	}
+	if err := repo.Record(ctx, events...); err != nil {
+		t.Fatalf("record: %v", err)
+	}
+	pending, err := repo.Pending(ctx, 10)
+	if err != nil || len(pending) != 2 {
+		t.Fatalf("pending = %d, %v; want two messages", len(pending), err)
+	}
+
+	if err := repo.GiveUp(ctx, pending[0].ID, "message too large"); err != nil {
+		t.Fatalf("give up: %v", err)
+	}
+	left, err := repo.Pending(ctx, 10)
+	if err != nil || len(left) != 1 || left[0].ID != pending[1].ID {
+		t.Fatalf("pending after giving up = %+v, %v; want only message %d", left, err, pending[1].ID)
+	}
+
+	var given domain.OutboxMessage
+	if err := db.First(&given, pending[0].ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if given.GaveUpAt == nil || given.Attempts != 1 || given.Rejections != 1 || given.LastError == nil || *given.LastError != "message too large" {
+		t.Fatalf("message given up on = %+v, want gave_up_at, one rejection and the reason", given)
+	}
+}
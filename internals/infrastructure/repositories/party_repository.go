@@ -0,0 +1,98 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type partyGormRepository struct {
+	db *gorm.DB
+}
+
+func NewPartyRepository(db *gorm.DB) domain.PartyRepository {
+	return &partyGormRepository{db: db}
+}
+
+func (r *partyGormRepository) Create(ctx context.Context, p *domain.Party) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r *partyGormRepository) GetByID(ctx context.Context, id uint) (*domain.Party, error) {
+	var party domain.Party
+	if err := r.db.WithContext(ctx).First(&party, id).Error; err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (r *partyGormRepository) GetByNameKey(ctx context.Context, key string) (*domain.Party, error) {
+	var party domain.Party
+	if err := r.db.WithContext(ctx).Where("name_key = ?", key).First(&party).Error; err != nil {
+		return nil, err
+	}
+	return &party, nil
+}
+
+func (r *partyGormRepository) GetAll(ctx context.Context) ([]domain.Party, error) {
+	var parties []domain.Party
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&parties).Error
+	return parties, err
+}
+
+func (r *partyGormRepository) Update(ctx context.Context, p *domain.Party) error {
+	return r.db.WithContext(ctx).Model(p).
+		Select("name", "name_key", "logo_asset_id", "leader", "description", "updated_at").
+		Updates(p).Error
+}
+
+func (r *partyGormRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Party{}, id).Error
+}
+
+func (r *partyGormRepository) GetCandidates(ctx context.Context, partyID uint) ([]domain.Candidate, error) {
+	var candidates []domain.Candidate
+	err := r.db.WithContext(ctx).Preload("Party").Where("party_id = ?", partyID).Find(&candidates).Error
+	return candidates, err
+}
+
+func (r *partyGormRepository) CountCandidates(ctx context.Context, partyID uint) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Candidate{}).Where("party_id = ?", partyID).Count(&count).Error
+	return count, err
+}
+
+func (r *partyGormRepository) CountCandidatesByParty(ctx context.Context) (map[string]int64, error) {
+	var rows []struct {
+		Name  string
+		Count int64
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Candidate{}).
+		Select("parties.name AS name, COUNT(*) AS count").
+		Joins("JOIN parties ON parties.id = candidates.party_id").
+		Group("parties.name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Name] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *partyGormRepository) Results(ctx context.Context, candidateType domain.CandidateType) ([]domain.PartyResult, error) {
+	var results []domain.PartyResult
+	err := r.db.WithContext(ctx).Model(&domain.Candidate{}).
+		Select("candidates.party_id AS party_id, COALESCE(parties.name, ?) AS party_name, "+
+			"COUNT(*) AS candidates, COALESCE(SUM(candidates.votes), 0) AS votes", domain.IndependentPartyName).
+		Joins("LEFT JOIN parties ON parties.id = candidates.party_id").
+		Where("candidates.type = ?", candidateType).
+		Group("candidates.party_id, parties.name").
+		Order("votes DESC").
+		Scan(&results).Error
+	return results, err
+}
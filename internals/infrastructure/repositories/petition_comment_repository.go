@@ -0,0 +1,119 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type petitionCommentGormRepository struct {
+	db *gorm.DB
+}
+
+func NewPetitionCommentRepository(db *gorm.DB) domain.PetitionCommentRepository {
+	return &petitionCommentGormRepository{db: db}
+}
+
+func (r *petitionCommentGormRepository) Create(ctx context.Context, comment *domain.PetitionComment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *petitionCommentGormRepository) GetByID(ctx context.Context, id uint) (*domain.PetitionComment, error) {
+	var comment domain.PetitionComment
+	err := r.db.WithContext(ctx).First(&comment, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+func (r *petitionCommentGormRepository) List(ctx context.Context, petitionID uint, parentID *uint, beforeID uint, limit int) ([]domain.PetitionComment, error) {
+	var comments []domain.PetitionComment
+	query := r.db.WithContext(ctx).Where("petition_id = ? AND hidden = ?", petitionID, false)
+	if parentID == nil {
+		query = query.Where("parent_id IS NULL")
+	} else {
+		query = query.Where("parent_id = ?", *parentID)
+	}
+	if beforeID > 0 {
+		query = query.Where("id < ?", beforeID)
+	}
+
+	err := query.
+		Order("id DESC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *petitionCommentGormRepository) ListVisible(ctx context.Context, afterID uint, limit int) ([]domain.PetitionComment, error) {
+	var comments []domain.PetitionComment
+	err := r.db.WithContext(ctx).
+		Where("hidden = ? AND id > ?", false, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&comments).Error
+	return comments, err
+}
+
+func (r *petitionCommentGormRepository) UpdateBody(ctx context.Context, id uint, body string, editedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.PetitionComment{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"body":      body,
+			"edited_at": editedAt,
+		}).Error
+}
+
+func (r *petitionCommentGormRepository) SetHidden(ctx context.Context, id uint, hidden bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.PetitionComment{}).
+			Where("id = ?", id).
+			Update("hidden", hidden).Error; err != nil {
+			return err
+		}
+		if hidden {
+			return nil
+		}
+		// A restored comment was reviewed, so its reports are settled
+		return tx.Where("comment_id = ?", id).Delete(&domain.CommentReport{}).Error
+	})
+}
+
+func (r *petitionCommentGormRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.PetitionComment{}, id).Error
+}
+
+func (r *petitionCommentGormRepository) Report(ctx context.Context, report *domain.CommentReport) error {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "comment_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(report)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("comment already reported")
+	}
+	return nil
+}
+
+// GetReported returns visible comments with at least one abuse report, most reported first.
+func (r *petitionCommentGormRepository) GetReported(ctx context.Context, limit, offset int) ([]domain.ReportedComment, error) {
+	var comments []domain.ReportedComment
+	err := r.db.WithContext(ctx).Model(&domain.PetitionComment{}).
+		Select("petition_comments.*, COUNT(cr.id) AS reports").
+		Joins("JOIN comment_reports cr ON cr.comment_id = petition_comments.id").
+		Where("petition_comments.hidden = ?", false).
+		Group("petition_comments.id").
+		Order("reports DESC").
+		Order("petition_comments.id DESC").
+		Limit(limit).
+		Offset(offset).
+		Scan(&comments).Error
+	return comments, err
+}
@@ -2,10 +2,15 @@ package repositories
 
 import (
 	"VoteGolang/internals/domain"
+	"context"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// trendingWindow is how far back petition votes are counted for the "trending" sort.
+const trendingWindow = 24 * time.Hour
+
 type petitionGormRepository struct {
 	db *gorm.DB
 }
@@ -14,48 +19,112 @@ func NewPetitionRepository(db *gorm.DB) domain.PetitionRepository {
 	return &petitionGormRepository{db: db}
 }
 
-func (r *petitionGormRepository) Create(petition *domain.Petition) error {
-	return r.db.Create(petition).Error
+func (r *petitionGormRepository) Create(ctx context.Context, petition *domain.Petition, events domain.EventsFunc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Tags are shared between petitions, so resolve existing ones by name
+		// instead of letting the association insert duplicates.
+		for i := range petition.Tags {
+			if err := tx.FirstOrCreate(&petition.Tags[i], domain.Tag{Name: petition.Tags[i].Name}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(petition).Error; err != nil {
+			return err
+		}
+		return insertEvents(tx, events)
+	})
 }
 
-func (r *petitionGormRepository) GetAllPaginated(limit, offset int) ([]domain.Petition, error) {
+func (r *petitionGormRepository) GetAllPaginated(ctx context.Context, filter domain.PetitionFilter, limit, offset int) ([]domain.Petition, error) {
 	var petitions []domain.Petition
-	err := r.db.
+	query := r.db.WithContext(ctx).Model(&domain.Petition{}).Preload("Tags")
+
+	if filter.Status != "" {
+		query = query.Where("petitions.status = ?", filter.Status)
+	}
+	if filter.Category != "" {
+		query = query.Where("petitions.category = ?", filter.Category)
+	}
+	if filter.Tag != "" {
+		query = query.
+			Joins("JOIN petition_tags pt ON pt.petition_id = petitions.id").
+			Joins("JOIN tags t ON t.id = pt.tag_id").
+			Where("t.name = ?", filter.Tag)
+	}
+
+	switch filter.Sort {
+	case domain.SortMostSigned:
+		query = query.Order("petitions.votes_in_favor + petitions.votes_against DESC")
+	case domain.SortClosingSoon:
+		query = query.
+			Where("petitions.voting_deadline > ?", time.Now()).
+			Order("petitions.voting_deadline ASC")
+	case domain.SortTrending:
+		query = query.
+			Select("petitions.*, (SELECT COUNT(*) FROM petition_votes pv WHERE pv.petition_id = petitions.id AND pv.created_at > ? AND pv.deleted_at IS NULL) AS recent_votes", time.Now().Add(-trendingWindow)).
+			Order("recent_votes DESC")
+	}
+	query = query.Order("petitions.created_at DESC").Order("petitions.id DESC")
+
+	err := query.
 		Limit(limit).
 		Offset(offset).
 		Find(&petitions).Error
 	return petitions, err
 }
 
-func (r *petitionGormRepository) GetAll() ([]domain.Petition, error) {
+func (r *petitionGormRepository) CountByCategory(ctx context.Context) (map[domain.PetitionCategory]int64, error) {
+	var rows []struct {
+		Category domain.PetitionCategory
+		Count    int64
+	}
+	err := r.db.WithContext(ctx).Model(&domain.Petition{}).
+		Where("status = ?", domain.PetitionApproved).
+		Select("category, COUNT(*) AS count").
+		Group("category").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[domain.PetitionCategory]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Category] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *petitionGormRepository) GetAll(ctx context.Context) ([]domain.Petition, error) {
 	var petitions []domain.Petition
-	err := r.db.Find(&petitions).Error
+	err := r.db.WithContext(ctx).Preload("Tags").
+		Where("status = ?", domain.PetitionApproved).
+		Find(&petitions).Error
 	return petitions, err
 }
 
-func (r *petitionGormRepository) GetByID(id uint) (*domain.Petition, error) {
+func (r *petitionGormRepository) GetByID(ctx context.Context, id uint) (*domain.Petition, error) {
 	var petition domain.Petition
-	err := r.db.First(&petition, id).Error
+	err := conn(ctx, r.db).Preload("Tags").First(&petition, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &petition, nil
 }
 
-func (r *petitionGormRepository) VoteInFavor(id uint) error {
-	return r.db.Model(&domain.Petition{}).
+func (r *petitionGormRepository) VoteInFavor(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&domain.Petition{}).
 		Where("id = ?", id).
 		Update("votes_in_favor", gorm.Expr("votes_in_favor + ?", 1)).
 		Error
 }
 
-func (r *petitionGormRepository) VoteAgainst(id uint) error {
-	return r.db.Model(&domain.Petition{}).
+func (r *petitionGormRepository) VoteAgainst(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&domain.Petition{}).
 		Where("id = ?", id).
 		Update("votes_against", gorm.Expr("votes_against + ?", 1)).
 		Error
 }
 
-func (r *petitionGormRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.Petition{}, id).Error
+func (r *petitionGormRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.Petition{}, id).Error
 }
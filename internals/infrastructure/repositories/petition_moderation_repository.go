@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+type petitionModerationGormRepository struct {
+	db *gorm.DB
+}
+
+func NewPetitionModerationRepository(db *gorm.DB) domain.PetitionModerationRepository {
+	return &petitionModerationGormRepository{db: db}
+}
+
+// GetPending returns petitions awaiting moderation, oldest first.
+func (r *petitionModerationGormRepository) GetPending(ctx context.Context, limit, offset int) ([]domain.Petition, error) {
+	var petitions []domain.Petition
+	err := r.db.WithContext(ctx).Preload("Tags").
+		Where("status = ?", domain.PetitionPending).
+		Order("created_at ASC").
+		Limit(limit).
+		Offset(offset).
+		Find(&petitions).Error
+	return petitions, err
+}
+
+func (r *petitionModerationGormRepository) Record(ctx context.Context, entry *domain.PetitionModeration) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *petitionModerationGormRepository) Decide(ctx context.Context, entry *domain.PetitionModeration, status domain.PetitionStatus) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rejectReason *string
+		if status == domain.PetitionRejected {
+			rejectReason = entry.Reason
+		}
+
+		// Only pending petitions can be decided, so two moderators cannot
+		// overwrite each other's decision.
+		result := tx.Model(&domain.Petition{}).
+			Where("id = ? AND status = ?", entry.PetitionID, domain.PetitionPending).
+			Updates(map[string]interface{}{
+				"status":        status,
+				"reject_reason": rejectReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("petition %d is not pending moderation", entry.PetitionID)
+		}
+
+		return tx.Create(entry).Error
+	})
+}
+
+func (r *petitionModerationGormRepository) GetHistory(ctx context.Context, petitionIDs ...uint) ([]domain.PetitionModeration, error) {
+	var entries []domain.PetitionModeration
+	if len(petitionIDs) == 0 {
+		return entries, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("petition_id IN ?", petitionIDs).
+		Order("created_at ASC").
+		Find(&entries).Error
+	return entries, err
+}
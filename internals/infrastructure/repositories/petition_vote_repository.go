@@ -2,10 +2,10 @@ package repositories
 
 import (
 	petition_data2 "VoteGolang/internals/domain"
+	"context"
 	"errors"
 
 	"gorm.io/gorm"
-	"gorm.io/gorm/clause"
 )
 
 type petitionVoteGormRepository struct {
@@ -16,43 +16,25 @@ func NewPetitionVoteRepository(db *gorm.DB) petition_data2.PetitionVoteRepositor
 	return &petitionVoteGormRepository{db: db}
 }
 
-func (r *petitionVoteGormRepository) CreateVote(vote *petition_data2.PetitionVote) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Use OnConflict for idempotency
-		result := tx.Clauses(clause.OnConflict{
-			Columns:   []clause.Column{{Name: "user_id"}, {Name: "petition_id"}},
-			DoNothing: true,
-		}).Create(vote)
-
-		if result.Error != nil {
-			return result.Error
-		}
-
-		// If no rows affected, vote already existed (idempotent)
-		if result.RowsAffected == 0 {
-			return nil
-		}
-
-		var column string
-		switch vote.VoteType {
-		case petition_data2.Favor:
-			column = "votes_in_favor"
-		case petition_data2.Against:
-			column = "votes_against"
-		default:
-			return nil
-		}
-
-		return tx.Model(&petition_data2.Petition{}).
-			Where("id = ?", vote.PetitionID).
-			UpdateColumn(column, gorm.Expr(column+" + ?", 1)).
-			Error
+func (r *petitionVoteGormRepository) CreateVote(ctx context.Context, vote *petition_data2.PetitionVote) error {
+	var column string
+	switch vote.VoteType {
+	case petition_data2.Favor:
+		column = "votes_in_favor"
+	case petition_data2.Against:
+		column = "votes_against"
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Signing is idempotent: a repeated vote changes nothing
+		_, err := sign(tx, vote, []string{"user_id", "petition_id"}, &petition_data2.Petition{}, vote.PetitionID, column)
+		return err
 	})
 }
 
-func (r *petitionVoteGormRepository) HasUserVoted(userID uint, petitionID uint) (bool, error) {
+func (r *petitionVoteGormRepository) HasUserVoted(ctx context.Context, userID uint, petitionID uint) (bool, error) {
 	var count int64
-	err := r.db.Model(&petition_data2.PetitionVote{}).
+	err := r.db.WithContext(ctx).Model(&petition_data2.PetitionVote{}).
 		Where("user_id = ? AND petition_id = ?", userID, petitionID).
 		Count(&count).Error
 
@@ -60,11 +42,11 @@ func (r *petitionVoteGormRepository) HasUserVoted(userID uint, petitionID uint)
 }
 
 // VoteWithTransaction ensures atomicity and idempotency with row locking
-func (r *petitionVoteGormRepository) VoteWithTransaction(userID uint, petitionID uint, voteType petition_data2.VoteType, afterSave func() error) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *petitionVoteGormRepository) VoteWithTransaction(ctx context.Context, userID uint, petitionID uint, voteType petition_data2.VoteType, afterSave func(ctx context.Context) error, events petition_data2.EventsFunc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Check if already voted with row lock to prevent race conditions
 		var existingVote petition_data2.PetitionVote
-		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		err := forUpdate(tx).
 			Where("user_id = ? AND petition_id = ?", userID, petitionID).
 			First(&existingVote).Error
 
@@ -91,11 +73,77 @@ func (r *petitionVoteGormRepository) VoteWithTransaction(userID uint, petitionID
 
 		// Execute callback (update vote counts, blockchain, etc.)
 		if afterSave != nil {
-			if err := afterSave(); err != nil {
+			if err := afterSave(withTx(ctx, tx)); err != nil {
 				return err
 			}
 		}
 
-		return nil
+		return insertEvents(tx, events)
+	})
+}
+
+func (r *petitionVoteGormRepository) MergeInto(ctx context.Context, sourceID uint, targetID uint, entry *petition_data2.PetitionModeration) (int64, error) {
+	var moved int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock both petitions so concurrent votes and moderation cannot change
+		// them mid-merge
+		var petitions []petition_data2.Petition
+		if err := forUpdate(tx).
+			Where("id IN ?", []uint{sourceID, targetID}).
+			Find(&petitions).Error; err != nil {
+			return err
+		}
+		if len(petitions) != 2 {
+			return errors.New("both petitions must exist to merge")
+		}
+		for _, p := range petitions {
+			if p.ID == targetID && p.Status != petition_data2.PetitionApproved {
+				return errors.New("petitions can only be merged into an approved petition")
+			}
+		}
+
+		// Users who already signed the target keep their original vote. Soft-deleted
+		// votes are included because they still occupy the unique index. MySQL
+		// refuses to read the updated table in a subquery, so the target's voters
+		// come from a derived table, which DISTINCT keeps it from merging back.
+		result := tx.Model(&petition_data2.PetitionVote{}).
+			Where("petition_id = ?", sourceID).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM (SELECT DISTINCT user_id FROM petition_votes WHERE petition_id = ?) t
+				WHERE t.user_id = petition_votes.user_id
+			)`, targetID).
+			Update("petition_id", targetID)
+		if result.Error != nil {
+			return result.Error
+		}
+		moved = result.RowsAffected
+
+		// Recount instead of adding so the counters match the vote records exactly
+		var favor, against int64
+		if err := tx.Model(&petition_data2.PetitionVote{}).
+			Where("petition_id = ? AND vote_type = ?", targetID, petition_data2.Favor).
+			Count(&favor).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&petition_data2.PetitionVote{}).
+			Where("petition_id = ? AND vote_type = ?", targetID, petition_data2.Against).
+			Count(&against).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&petition_data2.Petition{}).
+			Where("id = ?", targetID).
+			UpdateColumns(map[string]interface{}{
+				"votes_in_favor": favor,
+				"votes_against":  against,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&petition_data2.Petition{}, sourceID).Error; err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
 	})
+	return moved, err
 }
@@ -0,0 +1,240 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCreateVoteCountsEachUserOnce(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewPetitionVoteRepository(db)
+	author := createUser(t, db, "author")
+	voter := createUser(t, db, "voter")
+	petition := createPetition(t, db, author.ID, "Bike lanes")
+
+	for i := 0; i < 2; i++ {
+		vote := &domain.PetitionVote{UserID: voter.ID, PetitionID: petition.ID, VoteType: domain.Favor}
+		if err := repo.CreateVote(ctx, vote); err != nil {
+			t.Fatalf("vote %d: %v", i+1, err)
+		}
+	}
+
+	var got domain.Petition
+	if err := db.First(&got, petition.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.VotesInFavor != 1 {
+		t.Fatalf("votes in favor = %d, want 1", got.VotesInFavor)
+	}
+}
+
+func TestPetitionVoteWithTransactionCountsInTheVoteTransaction(t *testing.T) {
+	db := newTestDB(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	votes := NewPetitionVoteRepository(db)
+	petitions := NewPetitionRepository(db)
+	author := createUser(t, db, "author")
+	voter := createUser(t, db, "voter")
+	other := createUser(t, db, "other")
+	petition := createPetition(t, db, author.ID, "Bike lanes")
+
+	err := votes.VoteWithTransaction(ctx, voter.ID, petition.ID, domain.Favor, func(ctx context.Context) error {
+		return petitions.VoteInFavor(ctx, petition.ID)
+	}, nil)
+	if err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+
+	// The increment is rolled back with the vote
+	failed := errors.New("chain down")
+	err = votes.VoteWithTransaction(ctx, other.ID, petition.ID, domain.Against, func(ctx context.Context) error {
+		if err := petitions.VoteAgainst(ctx, petition.ID); err != nil {
+			return err
+		}
+		return failed
+	}, nil)
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+
+	var got domain.Petition
+	if err := db.First(&got, petition.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.VotesInFavor != 1 || got.VotesAgainst != 0 {
+		t.Fatalf("votes = %d for, %d against; want 1, 0", got.VotesInFavor, got.VotesAgainst)
+	}
+}
+
+func TestMergeIntoKeepsTargetVotes(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewPetitionVoteRepository(db)
+	author := createUser(t, db, "author")
+	both := createUser(t, db, "both")
+	sourceOnly := createUser(t, db, "source")
+	source := createPetition(t, db, author.ID, "Bike lanes")
+	target := createPetition(t, db, author.ID, "More bike lanes")
+
+	votes := []domain.PetitionVote{
+		{UserID: both.ID, PetitionID: source.ID, VoteType: domain.Favor},
+		{UserID: sourceOnly.ID, PetitionID: source.ID, VoteType: domain.Against},
+		{UserID: both.ID, PetitionID: target.ID, VoteType: domain.Against},
+	}
+	for i := range votes {
+		if err := repo.CreateVote(ctx, &votes[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := repo.MergeInto(ctx, source.ID, target.ID, mergeEntry(source.ID))
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if moved != 1 {
+		t.Fatalf("moved = %d, want 1", moved)
+	}
+
+	var got domain.Petition
+	if err := db.First(&got, target.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.VotesInFavor != 0 || got.VotesAgainst != 2 {
+		t.Fatalf("target counters = %d in favor, %d against; want 0 and 2", got.VotesInFavor, got.VotesAgainst)
+	}
+	if err := db.First(&domain.Petition{}, source.ID).Error; err == nil {
+		t.Fatal("source petition still exists after the merge")
+	}
+	var entries int64
+	db.Model(&domain.PetitionModeration{}).Where("petition_id = ? AND action = ?", source.ID, domain.ModerationMerged).Count(&entries)
+	if entries != 1 {
+		t.Fatalf("merge audit entries = %d, want 1", entries)
+	}
+}
+
+func TestMergeIntoTargetWithMoreVotersThanBindParameters(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewPetitionVoteRepository(db)
+	author := createUser(t, db, "author")
+	source := createPetition(t, db, author.ID, "Bike lanes")
+	target := createPetition(t, db, author.ID, "More bike lanes")
+
+	// More voters than SQLite accepts bind parameters in one statement
+	const voters = 40000
+	if err := db.Exec(`WITH RECURSIVE n(i) AS (SELECT 1 UNION ALL SELECT i + 1 FROM n WHERE i < ?)
+		INSERT INTO petition_votes (user_id, petition_id, vote_type) SELECT i, ?, ? FROM n`,
+		voters, target.ID, domain.Favor).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, userID := range []uint{1, voters + 1} {
+		if err := repo.CreateVote(ctx, &domain.PetitionVote{UserID: userID, PetitionID: source.ID, VoteType: domain.Against}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	moved, err := repo.MergeInto(ctx, source.ID, target.ID, mergeEntry(source.ID))
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if moved != 1 {
+		t.Fatalf("moved = %d, want only the vote of the user who did not sign the target", moved)
+	}
+	var got domain.Petition
+	if err := db.First(&got, target.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.VotesInFavor != voters || got.VotesAgainst != 1 {
+		t.Fatalf("target counters = %d in favor, %d against; want %d and 1", got.VotesInFavor, got.VotesAgainst, voters)
+	}
+}
+
+func TestMergeIntoIsAllOrNothing(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewPetitionVoteRepository(db)
+	author := createUser(t, db, "author")
+	voter := createUser(t, db, "voter")
+	source := createPetition(t, db, author.ID, "Bike lanes")
+	target := createPetition(t, db, author.ID, "More bike lanes")
+	if err := repo.CreateVote(ctx, &domain.PetitionVote{UserID: voter.ID, PetitionID: source.ID, VoteType: domain.Favor}); err != nil {
+		t.Fatal(err)
+	}
+
+	pending := createPetition(t, db, author.ID, "Even more bike lanes")
+	db.Model(pending).Update("status", domain.PetitionPending)
+	if _, err := repo.MergeInto(ctx, source.ID, pending.ID, mergeEntry(source.ID)); err == nil || !strings.Contains(err.Error(), "approved") {
+		t.Fatalf("merge into a pending petition: err = %v", err)
+	}
+
+	// The audit entry fails last, after the votes moved and the source was deleted
+	taken := mergeEntry(target.ID)
+	if err := db.Create(taken).Error; err != nil {
+		t.Fatal(err)
+	}
+	failing := mergeEntry(source.ID)
+	failing.ID = taken.ID
+	if _, err := repo.MergeInto(ctx, source.ID, target.ID, failing); err == nil {
+		t.Fatal("merge with a failing audit entry succeeded")
+	}
+
+	if err := db.First(&domain.Petition{}, source.ID).Error; err != nil {
+		t.Fatalf("source petition: %v", err)
+	}
+	var moved int64
+	db.Model(&domain.PetitionVote{}).Where("petition_id <> ?", source.ID).Count(&moved)
+	if moved != 0 {
+		t.Fatalf("%d votes moved by failed merges, want 0", moved)
+	}
+}
+
+func mergeEntry(sourceID uint) *domain.PetitionModeration {
+	return &domain.PetitionModeration{PetitionID: sourceID, Action: domain.ModerationMerged}
+}
+
+func TestPetitionVoteWithTransactionConcurrently(t *testing.T) {
+	db := newConcurrentTestDB(t)
+	ctx := context.Background()
+	repo := NewPetitionVoteRepository(db)
+	petitions := NewPetitionRepository(db)
+	author := createUser(t, db, "author")
+	petition := createPetition(t, db, author.ID, "Bike lanes")
+
+	const voters = 5
+	const attemptsPerVoter = 6
+	var wg sync.WaitGroup
+	for v := 0; v < voters; v++ {
+		voter := createUser(t, db, fmt.Sprintf("voter%d", v))
+		for i := 0; i < attemptsPerVoter; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				err := repo.VoteWithTransaction(ctx, voter.ID, petition.ID, domain.Favor, func(ctx context.Context) error {
+					return petitions.VoteInFavor(ctx, petition.ID)
+				}, nil)
+				if err != nil && !strings.Contains(err.Error(), "already voted") {
+					t.Errorf("voter %d: %v", voter.ID, err)
+				}
+			}()
+		}
+	}
+	wg.Wait()
+
+	var count int64
+	db.Model(&domain.PetitionVote{}).Where("petition_id = ?", petition.ID).Count(&count)
+	var got domain.Petition
+	if err := db.First(&got, petition.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != voters || got.VotesInFavor != voters {
+		t.Fatalf("%d petition vote rows and %d counted votes, want %d each", count, got.VotesInFavor, voters)
+	}
+}
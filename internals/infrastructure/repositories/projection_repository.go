@@ -0,0 +1,83 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type projectionGormRepository struct {
+	db *gorm.DB
+}
+
+func NewProjectionRepository(db *gorm.DB) domain.ProjectionRepository {
+	return &projectionGormRepository{db: db}
+}
+
+func (r *projectionGormRepository) ApplyVote(ctx context.Context, consumer, key string, vote domain.VoteCast, at time.Time) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "consumer"}, {Name: "event_id"}},
+			DoNothing: true,
+		}).
+			Create(&domain.ProcessedEvent{Consumer: consumer, EventID: key})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Already applied
+			return nil
+		}
+
+		region := ""
+		if vote.Region != nil {
+			region = *vote.Region
+		}
+		tally := domain.VoteTallyHourly{
+			CandidateType: vote.CandidateType,
+			CandidateID:   vote.CandidateID,
+			Region:        region,
+			Hour:          at.UTC().Truncate(time.Hour),
+			Votes:         1,
+		}
+		// PostgreSQL and SQLite need the conflicting unique index named; the
+		// counters are qualified because the proposed row has the same columns
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "candidate_type"}, {Name: "candidate_id"}, {Name: "region"}, {Name: "hour"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"votes": gorm.Expr("vote_tally_hourlies.votes + 1")}),
+		}).Create(&tally).Error; err != nil {
+			return err
+		}
+
+		turnout := domain.ElectionTurnout{CandidateType: vote.CandidateType, Voters: 1}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "candidate_type"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"voters":     gorm.Expr("election_turnouts.voters + 1"),
+				"updated_at": time.Now(),
+			}),
+		}).Create(&turnout).Error; err != nil {
+			return err
+		}
+
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+func (r *projectionGormRepository) Reset(ctx context.Context, consumer string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("1 = 1").Delete(&domain.VoteTallyHourly{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("1 = 1").Delete(&domain.ElectionTurnout{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("consumer = ?", consumer).Delete(&domain.ProcessedEvent{}).Error
+	})
+}
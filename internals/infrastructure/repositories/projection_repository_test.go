@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestApplyVoteCountsEachEventOnce(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewProjectionRepository(db)
+
+	region := "Almaty"
+	vote := domain.VoteCast{CandidateID: 7, CandidateType: domain.Presidential, Region: &region, UserID: 1}
+	at := time.Date(2026, 3, 1, 10, 15, 0, 0, time.UTC)
+
+	steps := []struct {
+		eventID string
+		at      time.Time
+		applied bool
+	}{
+		{"e1", at, true},
+		{"e1", at, false}, // redelivered
+		{"e2", at.Add(20 * time.Minute), true},
+		{"e3", at.Add(time.Hour), true},
+	}
+	for _, s := range steps {
+		applied, err := repo.ApplyVote(ctx, "test", s.eventID, vote, s.at)
+		if err != nil {
+			t.Fatalf("apply %s: %v", s.eventID, err)
+		}
+		if applied != s.applied {
+			t.Fatalf("apply %s = %v, want %v", s.eventID, applied, s.applied)
+		}
+	}
+
+	var tallies []domain.VoteTallyHourly
+	if err := db.Order("hour ASC").Find(&tallies).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(tallies) != 2 || tallies[0].Votes != 2 || tallies[1].Votes != 1 {
+		t.Fatalf("tallies = %+v, want 2 votes at 10:00 and 1 at 11:00", tallies)
+	}
+	if tallies[0].Region != region {
+		t.Fatalf("region = %q, want %q", tallies[0].Region, region)
+	}
+
+	var turnout domain.ElectionTurnout
+	if err := db.First(&turnout, "candidate_type = ?", domain.Presidential).Error; err != nil {
+		t.Fatal(err)
+	}
+	if turnout.Voters != 3 {
+		t.Fatalf("voters = %d, want 3", turnout.Voters)
+	}
+
+	if err := repo.Reset(ctx, "test"); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if applied, err := repo.ApplyVote(ctx, "test", "e1", vote, at); err != nil || !applied {
+		t.Fatalf("apply after reset = %v, %v; want applied", applied, err)
+	}
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"context"
 	"gorm.io/gorm"
 )
 
@@ -13,10 +14,10 @@ func NewRBACRepository(db *gorm.DB) *RBACRepository {
 }
 
 // HasAccess checks if a user has the required permission
-func (r *RBACRepository) HasAccess(userID uint, accessName string) bool {
+func (r *RBACRepository) HasAccess(ctx context.Context, userID uint, accessName string) bool {
 	var count int64
 
-	err := r.db.Table("users u").
+	err := r.db.WithContext(ctx).Table("users u").
 		Joins("JOIN role_access ra ON u.role_id = ra.role_id").
 		Joins("JOIN accesses a ON ra.access_id = a.id").
 		Where("u.id = ? AND a.name = ?", userID, accessName).
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"VoteGolang/internals/app/migrations"
+	"VoteGolang/internals/domain"
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB opens a migrated SQLite database in a temporary directory, so the
+// repositories are tested on the schema production uses without a server.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	// Same options as connect.ConnectDB, without its query log file
+	return openTestDB(t, "?_foreign_keys=on&_busy_timeout=5000", 1)
+}
+
+// newConcurrentTestDB is newTestDB with a pool of connections, so concurrent
+// transactions run on connections of their own and only the database's
+// locking keeps them apart, not the pool. WAL lets readers in beside the
+// writer; immediate transactions take the write lock at BEGIN, where the busy
+// timeout waits for it, instead of failing when a read turns into a write.
+func newConcurrentTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	return openTestDB(t, "?_foreign_keys=on&_busy_timeout=10000&_journal_mode=WAL&_txlock=immediate", 8)
+}
+
+func openTestDB(t *testing.T, options string, conns int) *gorm.DB {
+	t.Helper()
+	dsn := filepath.Join(t.TempDir(), "vote.db") + options
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(conns)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := migrations.Migrate(context.Background(), db, slog.New(slog.NewTextHandler(io.Discard, nil))); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	return db
+}
+
+// createUser inserts a user with the seeded "member" role.
+func createUser(t *testing.T, db *gorm.DB, username string) *domain.User {
+	t.Helper()
+	var role domain.Role
+	if err := db.Where("name = ?", "member").First(&role).Error; err != nil {
+		t.Fatalf("find role: %v", err)
+	}
+	user := &domain.User{
+		Username: username,
+		Email:    username + "@example.com",
+		Password: "hash",
+		RoleID:   role.ID,
+	}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return user
+}
+
+func createCandidate(t *testing.T, db *gorm.DB, name string, candidateType domain.CandidateType) *domain.Candidate {
+	t.Helper()
+	now := time.Now()
+	candidate := &domain.Candidate{
+		Name:           name,
+		Age:            40,
+		Type:           candidateType,
+		VotingStart:    now.Add(-time.Hour),
+		VotingDeadline: now.Add(24 * time.Hour),
+	}
+	if err := db.Create(candidate).Error; err != nil {
+		t.Fatalf("create candidate: %v", err)
+	}
+	return candidate
+}
+
+func createPetition(t *testing.T, db *gorm.DB, userID uint, title string) *domain.Petition {
+	t.Helper()
+	petition := &domain.Petition{
+		UserID:         userID,
+		Title:          title,
+		Goal:           100,
+		Category:       domain.CategoryOther,
+		Status:         domain.PetitionApproved,
+		VotingDeadline: time.Now().Add(24 * time.Hour),
+	}
+	if err := db.Create(petition).Error; err != nil {
+		t.Fatalf("create petition: %v", err)
+	}
+	return petition
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"VoteGolang/internals/domain"
+	"context"
 
 	"gorm.io/gorm"
 )
@@ -14,9 +15,9 @@ func NewRoleRepository(db *gorm.DB) *RoleRepository {
 	return &RoleRepository{db: db}
 }
 
-func (r *RoleRepository) GetByName(name string) (*domain.Role, error) {
+func (r *RoleRepository) GetByName(ctx context.Context, name string) (*domain.Role, error) {
 	var role domain.Role
-	if err := r.db.Where("name = ?", name).First(&role).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error; err != nil {
 		return nil, err
 	}
 	return &role, nil
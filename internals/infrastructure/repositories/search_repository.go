@@ -1,25 +1,62 @@
 package repositories
 
 import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/search"
 	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sync"
 
 	"github.com/elastic/go-elasticsearch/v7"
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 )
 
+// SearchRepository is the Elasticsearch implementation of
+// domain.SearchIndexer, for one index.
 type SearchRepository struct {
-	es    *elasticsearch.Client
-	index string
+	es      *elasticsearch.Client
+	index   string
+	mapping string
+
+	mu      sync.Mutex
+	created bool
 }
 
-func NewSearchRepository(es *elasticsearch.Client, index string) *SearchRepository {
-	return &SearchRepository{es: es, index: index}
+// NewSearchRepository returns the repository of index. The index is created
+// with mapping before the first document is written, rather than at start,
+// so a cluster that was down when the API started still gets its mappings
+// instead of the dynamic ones Elasticsearch would create with the document.
+func NewSearchRepository(es *elasticsearch.Client, index, mapping string) *SearchRepository {
+	return &SearchRepository{es: es, index: index, mapping: mapping}
+}
+
+// ensureIndex creates the index with its mapping unless that was done
+// already. A failed attempt is repeated on the next write.
+func (r *SearchRepository) ensureIndex() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.created {
+		return nil
+	}
+	if err := search.CreateIndexWithMapping(r.es, r.index, r.mapping); err != nil {
+		return fmt.Errorf("failed to create index %s: %w", r.index, err)
+	}
+	r.created = true
+	return nil
 }
 
 func (r *SearchRepository) Index(ctx context.Context, id string, document interface{}) error {
+	if r == nil || r.es == nil {
+		return fmt.Errorf("search service unavailable")
+	}
+
+	if err := r.ensureIndex(); err != nil {
+		return err
+	}
+
 	data, err := json.Marshal(document)
 	if err != nil {
 		return fmt.Errorf("failed to marshal document: %w", err)
@@ -44,3 +81,168 @@ func (r *SearchRepository) Index(ctx context.Context, id string, document interf
 
 	return nil
 }
+
+// TermsAggregation returns document counts per distinct value of a keyword field.
+func (r *SearchRepository) TermsAggregation(ctx context.Context, field string, size int) (map[string]int64, error) {
+	if r == nil || r.es == nil {
+		return nil, fmt.Errorf("search service unavailable")
+	}
+
+	body := map[string]interface{}{
+		"size": 0,
+		"aggs": map[string]interface{}{
+			"by_term": map[string]interface{}{
+				"terms": map[string]interface{}{
+					"field": field,
+					"size":  size,
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal aggregation: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{r.index},
+		Body:  bytes.NewReader(data),
+	}
+	res, err := req.Do(ctx, r.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run aggregation: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to run aggregation: %s", res.String())
+	}
+
+	var result struct {
+		Aggregations struct {
+			ByTerm struct {
+				Buckets []struct {
+					Key      string `json:"key"`
+					DocCount int64  `json:"doc_count"`
+				} `json:"buckets"`
+			} `json:"by_term"`
+		} `json:"aggregations"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode aggregation: %w", err)
+	}
+
+	counts := make(map[string]int64, len(result.Aggregations.ByTerm.Buckets))
+	for _, b := range result.Aggregations.ByTerm.Buckets {
+		counts[b.Key] = b.DocCount
+	}
+	return counts, nil
+}
+
+// FindSimilar returns documents that look like text, combining a
+// more_like_this query on fields with a fuzzy match on the first field so that
+// small typos in short titles still match. Hits scoring below minScore are dropped.
+func (r *SearchRepository) FindSimilar(ctx context.Context, text string, fields []string, size int, minScore float64) ([]domain.SearchHit, error) {
+	if r == nil || r.es == nil {
+		return nil, fmt.Errorf("search service unavailable")
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+
+	body := map[string]interface{}{
+		"size":      size,
+		"min_score": minScore,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"should": []interface{}{
+					map[string]interface{}{
+						"more_like_this": map[string]interface{}{
+							"fields":               fields,
+							"like":                 text,
+							"min_term_freq":        1,
+							"min_doc_freq":         1,
+							"max_query_terms":      25,
+							"minimum_should_match": "30%",
+						},
+					},
+					map[string]interface{}{
+						"match": map[string]interface{}{
+							fields[0]: map[string]interface{}{
+								"query":     text,
+								"fuzziness": "AUTO",
+								"analyzer":  "standard",
+								"operator":  "and",
+							},
+						},
+					},
+				},
+				"minimum_should_match": 1,
+			},
+		},
+	}
+	return r.searchHits(ctx, body)
+}
+
+// Delete removes a document from the index. Missing documents are not an error.
+func (r *SearchRepository) Delete(ctx context.Context, id string) error {
+	if r == nil || r.es == nil {
+		return fmt.Errorf("search service unavailable")
+	}
+
+	req := esapi.DeleteRequest{
+		Index:      r.index,
+		DocumentID: id,
+		Refresh:    "true",
+	}
+	res, err := req.Do(ctx, r.es)
+	if err != nil {
+		return fmt.Errorf("failed to delete document: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete document: %s", res.String())
+	}
+	return nil
+}
+
+func (r *SearchRepository) searchHits(ctx context.Context, body map[string]interface{}) ([]domain.SearchHit, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+
+	req := esapi.SearchRequest{
+		Index: []string{r.index},
+		Body:  bytes.NewReader(data),
+	}
+	res, err := req.Do(ctx, r.es)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run search: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return nil, fmt.Errorf("failed to run search: %s", res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				ID     string                 `json:"_id"`
+				Score  float64                `json:"_score"`
+				Source map[string]interface{} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode search response: %w", err)
+	}
+
+	hits := make([]domain.SearchHit, 0, len(result.Hits.Hits))
+	for _, h := range result.Hits.Hits {
+		hits = append(hits, domain.SearchHit{ID: h.ID, Score: h.Score, Source: h.Source})
+	}
+	return hits, nil
+}
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elastic/go-elasticsearch/v7"
+)
+
+// fakeElasticsearch answers the calls the search repository makes: index
+// existence checks, index creation and document writes.
+type fakeElasticsearch struct {
+	mu      sync.Mutex
+	down    bool
+	indices map[string]string
+	docs    []string
+}
+
+func (f *fakeElasticsearch) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w.Header().Set("X-Elastic-Product", "Elasticsearch")
+	w.Header().Set("Content-Type", "application/json")
+	if f.down {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"error":"unavailable"}`))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	_, exists := f.indices[parts[0]]
+	switch {
+	case parts[0] == "":
+		// The client checks it talks to Elasticsearch before the first call
+		w.Write([]byte(`{"version":{"number":"7.17.10","build_flavor":"default"},"tagline":"You Know, for Search"}`))
+	case len(parts) == 1 && r.Method == http.MethodHead:
+		if !exists {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.indices[parts[0]] = string(body)
+		w.Write([]byte(`{"acknowledged":true}`))
+	case len(parts) == 3 && parts[1] == "_doc":
+		if !exists {
+			// Elasticsearch creates the index with dynamic mappings
+			f.indices[parts[0]] = ""
+		}
+		f.docs = append(f.docs, parts[0]+"/"+parts[2])
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"result":"created"}`))
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestSearchRepositoryCreatesItsIndexBeforeTheFirstDocument(t *testing.T) {
+	fake := &fakeElasticsearch{down: true, indices: map[string]string{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+	es, err := elasticsearch.NewClient(elasticsearch.Config{Addresses: []string{server.URL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const mapping = `{"mappings":{"properties":{"title":{"type":"text"}}}}`
+	repo := NewSearchRepository(es, "petitions", mapping)
+	ctx := context.Background()
+
+	// Elasticsearch is down when the first document comes
+	if err := repo.Index(ctx, "1", map[string]string{"title": "Parks"}); err == nil {
+		t.Fatal("index succeeded while Elasticsearch was down")
+	}
+
+	fake.mu.Lock()
+	fake.down = false
+	fake.mu.Unlock()
+	for _, id := range []string{"1", "2"} {
+		if err := repo.Index(ctx, id, map[string]string{"title": "Parks"}); err != nil {
+			t.Fatalf("index %s: %v", id, err)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if got := fake.indices["petitions"]; got != mapping {
+		t.Fatalf("index created with %q, want the mapping", got)
+	}
+	if len(fake.docs) != 2 {
+		t.Fatalf("documents = %v, want two", fake.docs)
+	}
+}
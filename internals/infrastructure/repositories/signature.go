@@ -0,0 +1,33 @@
+package repositories
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Petition votes and nomination endorsements are signatures: one row per
+// signer, kept unique by an index, with a counter on the signed row.
+
+// sign inserts signature unless a row with the same values of the unique
+// columns exists, and increments counter of the signed row with signedID
+// only when it wrote one. An empty counter leaves the signed row alone. It
+// reports whether the signature is new.
+func sign(tx *gorm.DB, signature interface{}, unique []string, signed interface{}, signedID uint, counter string) (bool, error) {
+	columns := make([]clause.Column, len(unique))
+	for i, name := range unique {
+		columns[i] = clause.Column{Name: name}
+	}
+	result := tx.Clauses(clause.OnConflict{Columns: columns, DoNothing: true}).Create(signature)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	if counter == "" {
+		return true, nil
+	}
+	return true, tx.Model(signed).
+		Where("id = ?", signedID).
+		UpdateColumn(counter, gorm.Expr(counter+" + ?", 1)).Error
+}
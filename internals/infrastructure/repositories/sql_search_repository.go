@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// sqlSearchLimit matches the size of an empty Elasticsearch query.
+const sqlSearchLimit = 100
+
+// SQLSearchRepository answers searches with LIKE queries on the database. It is the
+// fallback while Elasticsearch is unavailable: slower and without relevance
+// ranking, but over the same documents.
+type SQLSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSQLSearchRepository(db *gorm.DB) *SQLSearchRepository {
+	return &SQLSearchRepository{db: db}
+}
+
+func (r *SQLSearchRepository) Search(ctx context.Context, searchType, query string) ([]interface{}, error) {
+	db := r.db.WithContext(ctx).Limit(sqlSearchLimit)
+	pattern := containsPattern(query)
+
+	switch searchType {
+	case "candidates":
+		var candidates []domain.Candidate
+		if query != "" {
+			db = db.Where(containsCondition("name"), pattern)
+		}
+		if err := db.Preload("Party").Order("id ASC").Find(&candidates).Error; err != nil {
+			return nil, err
+		}
+		return toResults(candidates), nil
+	case "petitions":
+		var petitions []domain.Petition
+		db = db.Where("status = ?", domain.PetitionApproved)
+		if query != "" {
+			db = db.Where(containsCondition("title"), pattern)
+		}
+		if err := db.Order("id DESC").Find(&petitions).Error; err != nil {
+			return nil, err
+		}
+		return toResults(petitions), nil
+	case "comments":
+		var comments []domain.PetitionComment
+		// Only comments on petitions the public can read
+		db = db.Select("petition_comments.*").
+			Joins("JOIN petitions ON petitions.id = petition_comments.petition_id").
+			Where("petition_comments.hidden = ?", false).
+			Where("petitions.status = ? AND petitions.deleted_at IS NULL", domain.PetitionApproved)
+		if query != "" {
+			db = db.Where(containsCondition("petition_comments.body"), pattern)
+		}
+		if err := db.Order("petition_comments.id DESC").Find(&comments).Error; err != nil {
+			return nil, err
+		}
+		return toResults(comments), nil
+	}
+	return nil, fmt.Errorf("unknown search type: %s", searchType)
+}
+
+func toResults[T any](items []T) []interface{} {
+	results := make([]interface{}, len(items))
+	for i := range items {
+		results[i] = items[i]
+	}
+	return results
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"testing"
+)
+
+func TestSQLSearchMatchesSubstringsIgnoringCase(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewSQLSearchRepository(db)
+	user := createUser(t, db, "author")
+	createPetition(t, db, user.ID, "Free Public Transport")
+	createPetition(t, db, user.ID, "100% renewable energy")
+	createPetition(t, db, user.ID, "1000 new schools")
+	draft := createPetition(t, db, user.ID, "Public parks")
+	if err := db.Model(draft).Update("status", domain.PetitionPending).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"public", []string{"Free Public Transport"}},
+		{"TRANSPORT", []string{"Free Public Transport"}},
+		{"100%", []string{"100% renewable energy"}},
+		{"0_", nil},
+		{"!", nil},
+	}
+	for _, tt := range tests {
+		results, err := repo.Search(ctx, "petitions", tt.query)
+		if err != nil {
+			t.Fatalf("search %q: %v", tt.query, err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, r.(domain.Petition).Title)
+		}
+		if len(got) != len(tt.want) {
+			t.Fatalf("search %q = %v, want %v", tt.query, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("search %q = %v, want %v", tt.query, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestSQLSearchFindsCommentsOnlyOnPublicPetitions(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewSQLSearchRepository(db)
+	user := createUser(t, db, "author")
+	approved := createPetition(t, db, user.ID, "Bike lanes")
+	pending := createPetition(t, db, user.ID, "Parks")
+	rejected := createPetition(t, db, user.ID, "Schools")
+	deleted := createPetition(t, db, user.ID, "Trams")
+	db.Model(pending).Update("status", domain.PetitionPending)
+	db.Model(rejected).Update("status", domain.PetitionRejected)
+	db.Delete(deleted)
+
+	for _, c := range []domain.PetitionComment{
+		{PetitionID: approved.ID, UserID: user.ID, Body: "Support approved"},
+		{PetitionID: approved.ID, UserID: user.ID, Body: "Support hidden", Hidden: true},
+		{PetitionID: pending.ID, UserID: user.ID, Body: "Support pending"},
+		{PetitionID: rejected.ID, UserID: user.ID, Body: "Support rejected"},
+		{PetitionID: deleted.ID, UserID: user.ID, Body: "Support deleted"},
+	} {
+		if err := db.Create(&c).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	results, err := repo.Search(ctx, "comments", "support")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].(domain.PetitionComment).Body != "Support approved" {
+		t.Fatalf("search = %v, want only the visible comment on the approved petition", results)
+	}
+}
+
+func TestSQLSearchRejectsUnknownType(t *testing.T) {
+	repo := NewSQLSearchRepository(newTestDB(t))
+	if _, err := repo.Search(context.Background(), "parties", "x"); err == nil {
+		t.Fatal("search of an unknown type succeeded")
+	}
+}
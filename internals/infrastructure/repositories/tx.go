@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction a repository callback runs in.
+type txKey struct{}
+
+// withTx returns ctx carrying tx, for the callbacks a repository runs inside
+// its transaction: the repository methods they call join it instead of
+// taking another connection.
+func withTx(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// conn returns the transaction ctx carries or, outside one, db, bound to ctx.
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -16,56 +16,64 @@ func NewUserRepository(db *gorm.DB) domain.UserRepository {
 	return &userGormRepository{db: db}
 }
 
-func (r *userGormRepository) Create(user *domain.User) error {
-	return r.db.Create(user).Error
+func (r *userGormRepository) Create(ctx context.Context, user *domain.User, events domain.EventsFunc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return insertEvents(tx, events)
+	})
 }
 
-func (r *userGormRepository) GetByID(id uint) (*domain.User, error) {
+func (r *userGormRepository) GetByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	err := r.db.First(&user, "id = ?", id).Error
+	err := r.db.WithContext(ctx).First(&user, "id = ?", id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userGormRepository) GetByUsername(username string) (*domain.User, error) {
+func (r *userGormRepository) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
 	var user domain.User
 	// Preload Role to check permissions/admin status
-	err := r.db.Preload("Role").First(&user, "username = ?", username).Error
+	err := r.db.WithContext(ctx).Preload("Role").First(&user, "username = ?", username).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userGormRepository) GetByEmail(email string) (*domain.User, error) {
+func (r *userGormRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.First(&user, "email = ?", email).Error
+	err := r.db.WithContext(ctx).First(&user, "email = ?", email).Error
 	if err != nil {
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *userGormRepository) Update(user *domain.User) error {
-	return r.db.Save(user).Error
+func (r *userGormRepository) Update(ctx context.Context, user *domain.User) error {
+	return r.db.WithContext(ctx).Save(user).Error
 }
 
-func (r *userGormRepository) Delete(id uint) error {
-	return r.db.Delete(&domain.User{}, "id = ?", id).Error
+func (r *userGormRepository) Delete(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Delete(&domain.User{}, "id = ?", id).Error
 }
 
-func (r *userGormRepository) MarkEmailVerified(ctx context.Context, userID uint) error {
-	return r.db.WithContext(ctx).
-		Model(&domain.User{}).
-		Where("id = ?", userID).
-		Update("email_verified", true).
-		Error
+func (r *userGormRepository) MarkEmailVerified(ctx context.Context, userID uint, events domain.EventsFunc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.User{}).
+			Where("id = ?", userID).
+			Update("email_verified", true).Error; err != nil {
+			return err
+		}
+		return insertEvents(tx, events)
+	})
 }
 
-func (r *userGormRepository) DeleteUnverifiedUser(cutoff time.Time) (int64, error) {
-	result := r.db.
+func (r *userGormRepository) DeleteUnverifiedUser(ctx context.Context, cutoff time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
 		Unscoped().
 		Where("email_verified = ? AND created_at < ?", false, cutoff).
 		Delete(&domain.User{})
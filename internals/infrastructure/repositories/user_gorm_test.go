@@ -0,0 +1,88 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestCreateUserRecordsEventsInItsTransaction(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewUserRepository(db)
+	var role domain.Role
+	if err := db.Where("name = ?", "member").First(&role).Error; err != nil {
+		t.Fatalf("find role: %v", err)
+	}
+
+	user := &domain.User{Username: "alice", Email: "alice@example.com", Password: "hash", RoleID: role.ID}
+	err := repo.Create(ctx, user, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.UserRegistered{UserID: user.ID, Username: user.Username, Email: user.Email})
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var messages []domain.OutboxMessage
+	if err := db.Find(&messages).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || messages[0].EventType != domain.EventUserRegistered {
+		t.Fatalf("outbox = %+v, want one %s message", messages, domain.EventUserRegistered)
+	}
+	var event domain.Event
+	var payload domain.UserRegistered
+	if err := json.Unmarshal(messages[0].Payload, &event); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(event.Data, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if user.ID == 0 || payload.UserID != user.ID {
+		t.Fatalf("event user ID = %d, want the assigned ID %d", payload.UserID, user.ID)
+	}
+
+	failed := errors.New("encode failed")
+	bob := &domain.User{Username: "bob", Email: "bob@example.com", Password: "hash", RoleID: role.ID}
+	err = repo.Create(ctx, bob, func() ([]domain.Event, error) { return nil, failed })
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+	if _, err := repo.GetByUsername(ctx, "bob"); err == nil {
+		t.Fatal("user was stored although its events failed")
+	}
+}
+
+func TestMarkEmailVerifiedRollsBackWithItsEvents(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewUserRepository(db)
+	user := createUser(t, db, "alice")
+
+	failed := errors.New("encode failed")
+	err := repo.MarkEmailVerified(ctx, user.ID, func() ([]domain.Event, error) { return nil, failed })
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+	stored, err := repo.GetByID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.EmailVerified {
+		t.Fatal("email verified although the events failed")
+	}
+
+	err = repo.MarkEmailVerified(ctx, user.ID, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.UserEmailVerified{UserID: user.ID})
+	})
+	if err != nil {
+		t.Fatalf("mark verified: %v", err)
+	}
+	var outbox int64
+	db.Model(&domain.OutboxMessage{}).Where("event_type = ?", domain.EventUserEmailVerified).Count(&outbox)
+	if outbox != 1 {
+		t.Fatalf("%s messages = %d, want 1", domain.EventUserEmailVerified, outbox)
+	}
+}
@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// appliedBatchRetention is how long applied batch IDs are kept. A batch is
+// only retried by the next flushes, so a day is plenty.
+const appliedBatchRetention = 24 * time.Hour
+
+// countedVotes counts the rows in the votes table of the candidate of the
+// outer query. Deleted votes are not counted, as gorm would not count them
+// either.
+const countedVotes = `SELECT COUNT(*) FROM votes WHERE votes.candidate_id = candidates.id AND votes.deleted_at IS NULL`
+
+type voteCountGormRepository struct {
+	db *gorm.DB
+}
+
+func NewVoteCountRepository(db *gorm.DB) domain.VoteCountRepository {
+	return &voteCountGormRepository{db: db}
+}
+
+func (r *voteCountGormRepository) ApplyVoteBatch(ctx context.Context, batch domain.VoteBatch) (bool, error) {
+	applied := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoNothing: true,
+		}).
+			Create(&domain.AppliedVoteBatch{ID: batch.ID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			// Already applied
+			return nil
+		}
+
+		// Same order in every flush, so concurrent flushes cannot deadlock
+		ids := make([]uint, 0, len(batch.Counts))
+		for id := range batch.Counts {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			if err := tx.Model(&domain.Candidate{}).
+				Where("id = ?", id).
+				UpdateColumn("votes", gorm.Expr("votes + ?", batch.Counts[id])).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("applied_at < ?", time.Now().Add(-appliedBatchRetention)).
+			Delete(&domain.AppliedVoteBatch{}).Error; err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	return applied, err
+}
+
+func (r *voteCountGormRepository) RecountVotes(ctx context.Context, dropped string) (int64, error) {
+	var corrected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if dropped != "" {
+			// A flush applying the batch concurrently commits first or finds it applied
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "id"}},
+				DoNothing: true,
+			}).
+				Create(&domain.AppliedVoteBatch{ID: dropped}).Error; err != nil {
+				return err
+			}
+		}
+
+		res := tx.Exec(`UPDATE candidates SET votes = (` + countedVotes + `) WHERE candidates.votes <> (` + countedVotes + `)`)
+		corrected = res.RowsAffected
+		return res.Error
+	})
+	return corrected, err
+}
+
+func (r *voteCountGormRepository) CountDrift(ctx context.Context) (int64, error) {
+	var drift int64
+	err := r.db.WithContext(ctx).
+		Raw(`SELECT COUNT(*) FROM candidates WHERE candidates.votes <> (` + countedVotes + `)`).
+		Scan(&drift).Error
+	return drift, err
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"testing"
+)
+
+func TestApplyVoteBatchAppliesOnce(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewVoteCountRepository(db)
+	first := createCandidate(t, db, "First", domain.Presidential)
+	second := createCandidate(t, db, "Second", domain.Presidential)
+
+	batch := domain.VoteBatch{ID: "3f1c2d7e-0000-4000-8000-000000000001", Counts: map[uint]int64{first.ID: 3, second.ID: 1}}
+	for i, want := range []bool{true, false} {
+		applied, err := repo.ApplyVoteBatch(ctx, batch)
+		if err != nil {
+			t.Fatalf("apply %d: %v", i+1, err)
+		}
+		if applied != want {
+			t.Fatalf("apply %d reported applied = %v, want %v", i+1, applied, want)
+		}
+	}
+
+	for _, tt := range []struct {
+		id   uint
+		want int
+	}{{first.ID, 3}, {second.ID, 1}} {
+		var c domain.Candidate
+		db.First(&c, tt.id)
+		if c.Votes != tt.want {
+			t.Fatalf("candidate %d has %d votes, want %d", tt.id, c.Votes, tt.want)
+		}
+	}
+}
+
+func TestRecountVotes(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewVoteCountRepository(db)
+	votes := NewVoteRepository(db)
+	counted := createCandidate(t, db, "Counted", domain.Deputy)
+	drifted := createCandidate(t, db, "Drifted", domain.Deputy)
+
+	for i, name := range []string{"a", "b", "c"} {
+		user := createUser(t, db, name)
+		candidate := counted
+		if i == 2 {
+			candidate = drifted
+		}
+		if err := votes.VoteWithTransaction(ctx, &domain.Vote{CandidateID: candidate.ID, UserID: user.ID, CandidateType: domain.Deputy}, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Model(&domain.Candidate{}).Where("id = ?", counted.ID).Update("votes", 2)
+	db.Model(&domain.Candidate{}).Where("id = ?", drifted.ID).Update("votes", 7)
+
+	if drift, err := repo.CountDrift(ctx); err != nil || drift != 1 {
+		t.Fatalf("drift before the recount = %d, %v, want 1", drift, err)
+	}
+
+	dropped := "3f1c2d7e-0000-4000-8000-000000000002"
+	corrected, err := repo.RecountVotes(ctx, dropped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corrected != 1 {
+		t.Fatalf("corrected %d candidates, want 1", corrected)
+	}
+	var c domain.Candidate
+	db.First(&c, drifted.ID)
+	if c.Votes != 1 {
+		t.Fatalf("drifted candidate has %d votes after recount, want 1", c.Votes)
+	}
+
+	if drift, err := repo.CountDrift(ctx); err != nil || drift != 0 {
+		t.Fatalf("drift after the recount = %d, %v, want 0", drift, err)
+	}
+	// The votes of the dropped batch are in the recount already
+	if applied, err := repo.ApplyVoteBatch(ctx, domain.VoteBatch{ID: dropped, Counts: map[uint]int64{drifted.ID: 1}}); err != nil || applied {
+		t.Fatalf("applying the dropped batch = %t, %v, want it skipped", applied, err)
+	}
+}
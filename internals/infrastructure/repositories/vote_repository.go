@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"VoteGolang/internals/domain"
+	"context"
 	"errors"
 
 	"gorm.io/gorm"
@@ -16,15 +17,15 @@ func NewVoteRepository(db *gorm.DB) domain.VoteRepository {
 	return &voteGormRepository{db: db}
 }
 
-func (r *voteGormRepository) HasVoted(userID uint, voteType string) (bool, error) {
+func (r *voteGormRepository) HasVoted(ctx context.Context, userID uint, voteType string) (bool, error) {
 	var count int64
-	err := r.db.Model(&domain.Vote{}).
+	err := r.db.WithContext(ctx).Model(&domain.Vote{}).
 		Where("user_id = ? AND candidate_type = ?", userID, voteType).
 		Count(&count).Error
 	return count > 0, err
 }
 
-func (r *voteGormRepository) SaveVote(candidateID uint, userID uint, candidateType string) error {
+func (r *voteGormRepository) SaveVote(ctx context.Context, candidateID uint, userID uint, candidateType string) error {
 	vote := &domain.Vote{
 		CandidateID:   candidateID,
 		UserID:        userID,
@@ -32,11 +33,10 @@ func (r *voteGormRepository) SaveVote(candidateID uint, userID uint, candidateTy
 	}
 
 	// Use OnConflict to make it idempotent
-	// This will do nothing if the record already exists
-	result := r.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}, {Name: "candidate_type"}},
-		DoNothing: true,
-	}).Create(vote)
+	// This will do nothing if the record already exists. There is no conflict
+	// target: PostgreSQL and SQLite require it to match a unique index exactly,
+	// and idx_user_candidate_type only covers user_id.
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(vote)
 
 	if result.Error != nil {
 		return result.Error
@@ -47,12 +47,12 @@ func (r *voteGormRepository) SaveVote(candidateID uint, userID uint, candidateTy
 	return nil
 }
 
-func (r *voteGormRepository) VoteWithTransaction(candidateID uint, userID uint, candidateType string, afterSave func() error) error {
-	return r.db.Transaction(func(tx *gorm.DB) error {
+func (r *voteGormRepository) VoteWithTransaction(ctx context.Context, vote *domain.Vote, afterSave func(ctx context.Context) error, events domain.EventsFunc) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		// Check if already voted (with row lock to prevent race conditions)
 		var existingVote domain.Vote
-		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-			Where("user_id = ? AND candidate_type = ?", userID, candidateType).
+		err := forUpdate(tx).
+			Where("user_id = ? AND candidate_type = ?", vote.UserID, vote.CandidateType).
 			First(&existingVote).Error
 
 		if err == nil {
@@ -66,23 +66,17 @@ func (r *voteGormRepository) VoteWithTransaction(candidateID uint, userID uint,
 		}
 
 		// Create the vote record
-		vote := &domain.Vote{
-			CandidateID:   candidateID,
-			UserID:        userID,
-			CandidateType: domain.CandidateType(candidateType),
-		}
-
 		if err := tx.Create(vote).Error; err != nil {
 			return err
 		}
 
 		// Execute callback (increment vote count, blockchain, etc.)
 		if afterSave != nil {
-			if err := afterSave(); err != nil {
+			if err := afterSave(withTx(ctx, tx)); err != nil {
 				return err
 			}
 		}
 
-		return nil
+		return insertEvents(tx, events)
 	})
 }
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVoteWithTransactionRejectsSecondVote(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+	user := createUser(t, db, "voter")
+	first := createCandidate(t, db, "First", domain.Presidential)
+	second := createCandidate(t, db, "Second", domain.Presidential)
+
+	if err := repo.VoteWithTransaction(ctx, &domain.Vote{CandidateID: first.ID, UserID: user.ID, CandidateType: domain.Presidential}, nil, nil); err != nil {
+		t.Fatalf("first vote: %v", err)
+	}
+	if err := repo.VoteWithTransaction(ctx, &domain.Vote{CandidateID: second.ID, UserID: user.ID, CandidateType: domain.Presidential}, nil, nil); err == nil {
+		t.Fatal("second vote in the same election succeeded")
+	}
+
+	voted, err := repo.HasVoted(ctx, user.ID, string(domain.Presidential))
+	if err != nil || !voted {
+		t.Fatalf("HasVoted = %v, %v; want true", voted, err)
+	}
+	var count int64
+	db.Model(&domain.Vote{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("votes = %d, want 1", count)
+	}
+}
+
+func TestVoteWithTransactionRollsBackOnCallbackError(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+	user := createUser(t, db, "voter")
+	candidate := createCandidate(t, db, "First", domain.Deputy)
+
+	vote := &domain.Vote{CandidateID: candidate.ID, UserID: user.ID, CandidateType: domain.Deputy}
+	events := func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.VoteCast{VoteID: vote.ID, CandidateID: candidate.ID, CandidateType: domain.Deputy, UserID: user.ID})
+	}
+	failed := errors.New("chain down")
+	err := repo.VoteWithTransaction(ctx, vote, func(context.Context) error { return failed }, events)
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+
+	voted, err := repo.HasVoted(ctx, user.ID, string(domain.Deputy))
+	if err != nil || voted {
+		t.Fatalf("HasVoted = %v, %v; want false after rollback", voted, err)
+	}
+	var outbox int64
+	db.Model(&domain.OutboxMessage{}).Count(&outbox)
+	if outbox != 0 {
+		t.Fatalf("outbox messages = %d, want 0 after rollback", outbox)
+	}
+}
+
+func TestVoteWithTransactionCountsInTheVoteTransaction(t *testing.T) {
+	db := newTestDB(t)
+	// A callback waiting for a second connection would block until the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	votes := NewVoteRepository(db)
+	candidates := NewCandidateRepository(db)
+	voter := createUser(t, db, "voter")
+	other := createUser(t, db, "other")
+	candidate := createCandidate(t, db, "First", domain.Deputy)
+
+	err := votes.VoteWithTransaction(ctx, &domain.Vote{CandidateID: candidate.ID, UserID: voter.ID, CandidateType: domain.Deputy}, func(ctx context.Context) error {
+		return candidates.IncrementVote(ctx, candidate.ID)
+	}, nil)
+	if err != nil {
+		t.Fatalf("vote: %v", err)
+	}
+
+	// The increment is rolled back with the vote
+	failed := errors.New("chain down")
+	err = votes.VoteWithTransaction(ctx, &domain.Vote{CandidateID: candidate.ID, UserID: other.ID, CandidateType: domain.Deputy}, func(ctx context.Context) error {
+		if err := candidates.IncrementVote(ctx, candidate.ID); err != nil {
+			return err
+		}
+		return failed
+	}, nil)
+	if !errors.Is(err, failed) {
+		t.Fatalf("err = %v, want %v", err, failed)
+	}
+
+	var got domain.Candidate
+	if err := db.First(&got, candidate.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if got.Votes != 1 {
+		t.Fatalf("candidate votes = %d, want 1", got.Votes)
+	}
+}
+
+func TestSaveVoteIsIdempotent(t *testing.T) {
+	db := newTestDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+	user := createUser(t, db, "voter")
+	candidate := createCandidate(t, db, "First", domain.Manager)
+
+	for i := 0; i < 2; i++ {
+		if err := repo.SaveVote(ctx, candidate.ID, user.ID, string(domain.Manager)); err != nil {
+			t.Fatalf("save vote %d: %v", i+1, err)
+		}
+	}
+	var count int64
+	db.Model(&domain.Vote{}).Count(&count)
+	if count != 1 {
+		t.Fatalf("votes = %d, want 1", count)
+	}
+}
+
+func TestVoteWithTransactionConcurrently(t *testing.T) {
+	db := newConcurrentTestDB(t)
+	ctx := context.Background()
+	repo := NewVoteRepository(db)
+	candidates := NewCandidateRepository(db)
+	user := createUser(t, db, "voter")
+	candidate := createCandidate(t, db, "First", domain.Presidential)
+
+	const attempts = 30
+	var wg sync.WaitGroup
+	var succeeded atomic.Int32
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.VoteWithTransaction(ctx, &domain.Vote{CandidateID: candidate.ID, UserID: user.ID, CandidateType: domain.Presidential}, func(ctx context.Context) error {
+				return candidates.IncrementVote(ctx, candidate.ID)
+			}, nil)
+			switch {
+			case err == nil:
+				succeeded.Add(1)
+			case !strings.Contains(err.Error(), "already voted"):
+				// Waiting for the lock must not fail the vote
+				t.Errorf("vote: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeeded.Load() != 1 {
+		t.Fatalf("%d votes succeeded, want 1", succeeded.Load())
+	}
+	var count int64
+	db.Model(&domain.Vote{}).Where("candidate_id = ?", candidate.ID).Count(&count)
+	var got domain.Candidate
+	if err := db.First(&got, candidate.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || got.Votes != 1 {
+		t.Fatalf("%d vote rows and %d counted votes, want 1 each", count, got.Votes)
+	}
+}
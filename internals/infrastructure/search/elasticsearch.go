@@ -2,6 +2,7 @@ package search
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -18,23 +19,27 @@ type searchConfig struct {
 // Elasticsearch is an implementation of the Search interface that uses Elasticsearch.
 type Elasticsearch struct {
 	Address          string
+	client           *http.Client
 	searchTypeConfig map[string]searchConfig
 }
 
-// NewElasticsearch creates a new Elasticsearch instance.
-func NewElasticsearch(address string) *Elasticsearch {
+// NewElasticsearch creates a new Elasticsearch instance that sends its
+// requests through transport.
+func NewElasticsearch(address string, transport http.RoundTripper) *Elasticsearch {
 	return &Elasticsearch{
 		Address: address,
+		client:  &http.Client{Transport: transport},
 		searchTypeConfig: map[string]searchConfig{
 			"candidates": {Index: "candidates", Field: "name"},
 			"petitions":  {Index: "petitions", Field: "title"},
+			"comments":   {Index: "comments", Field: "body"},
 		},
 	}
 }
 
 // Search performs a search on the specified index and field.
 // If the query is empty, it returns all documents.
-func (e *Elasticsearch) Search(searchType, query string) ([]interface{}, error) {
+func (e *Elasticsearch) Search(ctx context.Context, searchType, query string) ([]interface{}, error) {
 	config, ok := e.searchTypeConfig[searchType]
 	if !ok {
 		return nil, fmt.Errorf("unknown search type: %s", searchType)
@@ -65,7 +70,13 @@ func (e *Elasticsearch) Search(searchType, query string) ([]interface{}, error)
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
 
-	resp, err := http.Post(fmt.Sprintf("%s/%s/_search", e.Address, config.Index), "application/json", bytes.NewBuffer(reqJSON))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/%s/_search", e.Address, config.Index), bytes.NewBuffer(reqJSON))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Elasticsearch request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request to Elasticsearch: %w", err)
 	}
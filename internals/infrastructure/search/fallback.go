@@ -0,0 +1,29 @@
+package search
+
+import (
+	"context"
+	"log/slog"
+
+	"VoteGolang/internals/app/logging"
+)
+
+// Fallback searches primary and, when it fails, secondary. The API pairs
+// Elasticsearch with the database so search keeps working while the cluster is down.
+type Fallback struct {
+	primary   Search
+	secondary Search
+	logger    *slog.Logger
+}
+
+func NewFallback(primary, secondary Search, logger *slog.Logger) *Fallback {
+	return &Fallback{primary: primary, secondary: secondary, logger: logger}
+}
+
+func (f *Fallback) Search(ctx context.Context, searchType, query string) ([]interface{}, error) {
+	results, err := f.primary.Search(ctx, searchType, query)
+	if err == nil {
+		return results, nil
+	}
+	f.logger.WarnContext(ctx, "Search failed, falling back to database", "search_type", searchType, logging.Err(err))
+	return f.secondary.Search(ctx, searchType, query)
+}
@@ -38,3 +38,18 @@ func CreateIndexWithMapping(es *elasticsearch.Client, indexName, mapping string)
 
 	return nil
 }
+
+// RecreateIndex deletes an index and creates it again with mapping, for
+// mapping changes CreateIndexWithMapping cannot apply to an existing index.
+// The index is empty until its documents are indexed again.
+func RecreateIndex(es *elasticsearch.Client, indexName, mapping string) error {
+	res, err := es.Indices.Delete([]string{indexName})
+	if err != nil {
+		return fmt.Errorf("failed to delete index: %w", err)
+	}
+	res.Body.Close()
+	if res.IsError() && res.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete index: %s", res.String())
+	}
+	return CreateIndexWithMapping(es, indexName, mapping)
+}
@@ -37,6 +37,37 @@ const CandidateMapping = `
       },
       "type": {
         "type": "keyword"
+      },
+      "party_id": {
+        "type": "long"
+      },
+      "party": {
+        "type": "text",
+        "fields": {
+          "keyword": {
+            "type": "keyword"
+          }
+        }
+      },
+      "party_info": {
+        "properties": {
+          "id": {
+            "type": "long"
+          },
+          "name": {
+            "type": "keyword"
+          }
+        }
+      },
+      "manifesto_points": {
+        "properties": {
+          "topic": {
+            "type": "keyword"
+          },
+          "text": {
+            "type": "text"
+          }
+        }
       }
     }
   }
@@ -79,6 +110,48 @@ const PetitionMapping = `
       },
       "category": {
         "type": "keyword"
+      },
+      "tags": {
+        "properties": {
+          "name": {
+            "type": "keyword"
+          }
+        }
+      },
+      "votes_in_favor": {
+        "type": "integer"
+      },
+      "votes_against": {
+        "type": "integer"
+      },
+      "voting_deadline": {
+        "type": "date"
+      },
+      "created_at": {
+        "type": "date"
+      }
+    }
+  }
+}`
+
+const CommentMapping = `
+{
+  "mappings": {
+    "properties": {
+      "body": {
+        "type": "text"
+      },
+      "petition_id": {
+        "type": "long"
+      },
+      "parent_id": {
+        "type": "long"
+      },
+      "user_id": {
+        "type": "long"
+      },
+      "created_at": {
+        "type": "date"
       }
     }
   }
@@ -0,0 +1,160 @@
+package search
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MemoryIndex is an in-process domain.SearchIndexer for a single instance
+// and tests. Documents are kept as their JSON form, like Elasticsearch's
+// _source. FindSimilar scores a document by how many distinct words of the
+// text appear in its fields, a rough stand-in for relevance scores.
+type MemoryIndex struct {
+	mu   sync.RWMutex
+	docs map[string]map[string]interface{}
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{docs: make(map[string]map[string]interface{})}
+}
+
+func (m *MemoryIndex) Index(_ context.Context, id string, document interface{}) error {
+	data, err := json.Marshal(document)
+	if err != nil {
+		return fmt.Errorf("failed to marshal document: %w", err)
+	}
+	var source map[string]interface{}
+	if err := json.Unmarshal(data, &source); err != nil {
+		return fmt.Errorf("document is not an object: %w", err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.docs[id] = source
+	return nil
+}
+
+func (m *MemoryIndex) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.docs, id)
+	return nil
+}
+
+func (m *MemoryIndex) TermsAggregation(_ context.Context, field string, size int) (map[string]int64, error) {
+	m.mu.RLock()
+	all := make(map[string]int64)
+	for _, doc := range m.docs {
+		for _, v := range values(doc, field) {
+			all[fmt.Sprint(v)]++
+		}
+	}
+	m.mu.RUnlock()
+
+	// Keep the size most frequent terms, as the terms aggregation does
+	terms := make([]string, 0, len(all))
+	for term := range all {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		if all[terms[i]] != all[terms[j]] {
+			return all[terms[i]] > all[terms[j]]
+		}
+		return terms[i] < terms[j]
+	})
+	counts := make(map[string]int64, size)
+	for i := 0; i < len(terms) && i < size; i++ {
+		counts[terms[i]] = all[terms[i]]
+	}
+	return counts, nil
+}
+
+func (m *MemoryIndex) FindSimilar(_ context.Context, text string, fields []string, size int, minScore float64) ([]domain.SearchHit, error) {
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+	query := words(text)
+
+	m.mu.RLock()
+	var hits []domain.SearchHit
+	for id, doc := range m.docs {
+		found := make(map[string]bool)
+		for _, field := range fields {
+			for _, v := range values(doc, field) {
+				s, _ := v.(string)
+				for w := range words(s) {
+					if query[w] {
+						found[w] = true
+					}
+				}
+			}
+		}
+		score := float64(len(found))
+		if score > 0 && score >= minScore {
+			hits = append(hits, domain.SearchHit{ID: id, Score: score, Source: doc})
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+	if len(hits) > size {
+		hits = hits[:size]
+	}
+	return hits, nil
+}
+
+// values returns the values at a dotted path of doc, flattening arrays.
+func values(doc interface{}, path string) []interface{} {
+	if path == "" {
+		if list, ok := doc.([]interface{}); ok {
+			var out []interface{}
+			for _, item := range list {
+				out = append(out, values(item, "")...)
+			}
+			return out
+		}
+		if doc == nil {
+			return nil
+		}
+		return []interface{}{doc}
+	}
+
+	name, rest, _ := strings.Cut(path, ".")
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		return values(v[name], rest)
+	case []interface{}:
+		var out []interface{}
+		for _, item := range v {
+			out = append(out, values(item, path)...)
+		}
+		return out
+	case string:
+		// The keyword sub-field Elasticsearch maps strings with
+		if path == "keyword" {
+			return []interface{}{v}
+		}
+	}
+	return nil
+}
+
+// words returns the distinct lowercased words of s.
+func words(s string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		set[w] = true
+	}
+	return set
+}
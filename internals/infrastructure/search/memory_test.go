@@ -0,0 +1,56 @@
+package search
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"testing"
+)
+
+type testDoc struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+func TestMemoryIndexTermsAggregation(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryIndex()
+	green := &domain.Party{ID: 1, Name: "Green"}
+	m.Index(ctx, "1", domain.Candidate{Party: green})
+	m.Index(ctx, "2", domain.Candidate{Party: green})
+	m.Index(ctx, "3", domain.Candidate{Party: &domain.Party{ID: 2, Name: "Blue"}})
+	m.Index(ctx, "4", domain.Candidate{})
+	m.Index(ctx, "5", domain.Candidate{Party: &domain.Party{ID: 3, Name: "Red"}})
+
+	// Candidates carry the party name as a string and the party as an object
+	for _, field := range []string{"party.keyword", "party_info.name"} {
+		counts, err := m.TermsAggregation(ctx, field, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(counts) != 2 || counts["Green"] != 2 || counts["Blue"] != 1 {
+			t.Fatalf("%s counts = %v, want Green:2 and Blue:1", field, counts)
+		}
+	}
+}
+
+func TestMemoryIndexFindSimilar(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryIndex()
+	m.Index(ctx, "1", testDoc{Title: "More bike lanes", Description: "Safer roads for cyclists"})
+	m.Index(ctx, "2", testDoc{Title: "Bike parking", Description: "Near the station"})
+	m.Index(ctx, "3", testDoc{Title: "Longer library hours"})
+
+	hits, err := m.FindSimilar(ctx, "Bike lanes for cyclists", []string{"title", "description"}, 5, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].ID != "1" || hits[0].Score != 4 {
+		t.Fatalf("hits = %+v, want only document 1 with score 4", hits)
+	}
+
+	m.Delete(ctx, "1")
+	hits, _ = m.FindSimilar(ctx, "Bike lanes for cyclists", []string{"title"}, 5, 0)
+	if len(hits) != 1 || hits[0].ID != "2" {
+		t.Fatalf("hits after delete = %+v, want only document 2", hits)
+	}
+}
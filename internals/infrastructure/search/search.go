@@ -1,6 +1,8 @@
 package search
 
+import "context"
+
 // Search interface defines the methods for searching.
 type Search interface {
-	Search(searchType, query string) ([]interface{}, error)
+	Search(ctx context.Context, searchType, query string) ([]interface{}, error)
 }
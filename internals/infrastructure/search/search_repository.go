@@ -5,9 +5,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
-
 	"github.com/elastic/go-elasticsearch/v7"
+	"log/slog"
 )
 
 type SearchRepository struct {
@@ -20,13 +19,13 @@ func NewSearchRepository(client *elasticsearch.Client, index string) *SearchRepo
 }
 
 // IndexDocument saves or updates a document in Elasticsearch
-func (s *SearchRepository) IndexDocument(id string, data interface{}) error {
+func (s *SearchRepository) IndexDocument(ctx context.Context, id string, data interface{}) error {
 	body, _ := json.Marshal(data)
 	res, err := s.Client.Index(
 		s.Index,
 		bytes.NewReader(body),
 		s.Client.Index.WithDocumentID(id),
-		s.Client.Index.WithContext(context.Background()),
+		s.Client.Index.WithContext(ctx),
 	)
 	if err != nil {
 		return err
@@ -35,12 +34,12 @@ func (s *SearchRepository) IndexDocument(id string, data interface{}) error {
 	if res.IsError() {
 		return fmt.Errorf("error indexing document: %s", res.String())
 	}
-	log.Printf("✅ Document indexed [%s] in %s", id, s.Index)
+	slog.DebugContext(ctx, "Document indexed", "index", s.Index, "document_id", id)
 	return nil
 }
 
 // Search performs full-text search on a specific field
-func (s *SearchRepository) Search(query string, field string) ([]map[string]interface{}, error) {
+func (s *SearchRepository) Search(ctx context.Context, query string, field string) ([]map[string]interface{}, error) {
 	// Check if client is initialized
 	if s == nil || s.Client == nil {
 		return nil, fmt.Errorf("Search service unavailable")
@@ -61,7 +60,7 @@ func (s *SearchRepository) Search(query string, field string) ([]map[string]inte
 
 	// Perform search
 	res, err := s.Client.Search(
-		s.Client.Search.WithContext(context.Background()),
+		s.Client.Search.WithContext(ctx),
 		s.Client.Search.WithIndex(s.Index),
 		s.Client.Search.WithBody(&buf),
 	)
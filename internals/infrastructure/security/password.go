@@ -7,8 +7,13 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-func HashPassword(pw string) (string, error) {
-	bytes, err := bcrypt.GenerateFromPassword([]byte(pw), 14)
+// DefaultPasswordCost is the bcrypt cost of stored password hashes.
+const DefaultPasswordCost = 14
+
+// HashPassword hashes pw with bcrypt at the given cost. Hashes made at any
+// cost are checked by CheckPasswordHash, so the cost can change over time.
+func HashPassword(pw string, cost int) (string, error) {
+	bytes, err := bcrypt.GenerateFromPassword([]byte(pw), cost)
 	return string(bytes), err
 }
 
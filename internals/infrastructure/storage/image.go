@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// ThumbnailSize is the longest side of a generated thumbnail, in pixels.
+	ThumbnailSize = 320
+	// maxImagePixels guards against decompression bombs.
+	maxImagePixels = 40_000_000
+)
+
+var ErrUnsupportedImage = errors.New("unsupported image type, allowed: jpeg, png, gif")
+
+// allowedImageTypes maps sniffed content types to file extensions.
+var allowedImageTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+}
+
+// ProcessedImage is an upload ready to be stored.
+type ProcessedImage struct {
+	Data          []byte
+	ContentType   string
+	Extension     string
+	Thumbnail     []byte
+	ThumbnailType string
+	Width         int
+	Height        int
+}
+
+// ProcessImage sniffs the real content type of data, re-encodes JPEG and PNG
+// images so that EXIF and other metadata are dropped, and renders a thumbnail.
+// GIFs are kept as-is since they carry no EXIF; their thumbnail uses the
+// first frame.
+func ProcessImage(data []byte) (*ProcessedImage, error) {
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedImageTypes[contentType]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return nil, fmt.Errorf("invalid image dimensions %dx%d", cfg.Width, cfg.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image: %w", err)
+	}
+
+	out := &ProcessedImage{
+		ContentType: contentType,
+		Extension:   ext,
+		Width:       cfg.Width,
+		Height:      cfg.Height,
+	}
+
+	var buf bytes.Buffer
+	switch contentType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+		out.Data = buf.Bytes()
+	case "image/png":
+		err = png.Encode(&buf, img)
+		out.Data = buf.Bytes()
+	case "image/gif":
+		// Validate the whole animation decodes, then keep the original bytes
+		_, err = gif.DecodeAll(bytes.NewReader(data))
+		out.Data = data
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to process image: %w", err)
+	}
+
+	thumb := thumbnail(img, ThumbnailSize)
+	var thumbBuf bytes.Buffer
+	if contentType == "image/jpeg" {
+		err = jpeg.Encode(&thumbBuf, thumb, &jpeg.Options{Quality: 85})
+		out.ThumbnailType = "image/jpeg"
+	} else {
+		err = png.Encode(&thumbBuf, thumb)
+		out.ThumbnailType = "image/png"
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode thumbnail: %w", err)
+	}
+	out.Thumbnail = thumbBuf.Bytes()
+
+	return out, nil
+}
+
+// thumbnail scales img so its longest side is at most size, keeping the
+// aspect ratio. Smaller images are only copied.
+func thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			h = max(1, h*size/w)
+			w = size
+		} else {
+			w = max(1, w*size/h)
+			h = size
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Over, nil)
+	return dst
+}
@@ -0,0 +1,283 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Storage stores files in a bucket of an S3-compatible object store (AWS S3,
+// MinIO, ...). Requests use path-style addressing and AWS Signature Version 4,
+// so any endpoint speaking the S3 API can stand in for it.
+type S3Storage struct {
+	endpoint *url.URL
+	bucket   string
+	signer   signer
+	client   *http.Client
+	now      func() time.Time
+}
+
+func NewS3Storage(endpoint, region, bucket, accessKey, secretKey string) (*S3Storage, error) {
+	u, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Storage{
+		endpoint: u,
+		bucket:   bucket,
+		signer:   signer{accessKey: accessKey, secretKey: secretKey, region: region},
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := s.newRequest(ctx, http.MethodPut, key, data)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	req.Header.Set("Content-Type", contentType)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to upload object: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return s.responseError("upload", res)
+	}
+	return nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download object: %w", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, s.responseError("download", res)
+	}
+	return res.Body, nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete object: %w", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusNoContent && res.StatusCode != http.StatusOK {
+		return s.responseError("delete", res)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL valid for ttl (at most 7 days).
+func (s *S3Storage) SignedURL(key string, ttl time.Duration) (string, error) {
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	if ttl > 7*24*time.Hour {
+		ttl = 7 * 24 * time.Hour
+	}
+
+	objectPath := s.objectPath(key)
+	query := s.signer.presign(s.now(), http.MethodGet, s.endpoint.Host, objectPath, ttl)
+	return fmt.Sprintf("%s://%s%s?%s", s.endpoint.Scheme, s.endpoint.Host, objectPath, query), nil
+}
+
+// newRequest builds a request for an object and signs it with SigV4 headers.
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, payload []byte) (*http.Request, error) {
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	objectPath := s.objectPath(key)
+	target := fmt.Sprintf("%s://%s%s", s.endpoint.Scheme, s.endpoint.Host, objectPath)
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	// Keep the already-escaped path so the signed path matches what is sent
+	req.URL.RawPath = objectPath
+
+	now := s.now().UTC()
+	payloadHash := sha256Hex(payload)
+	headers := map[string]string{
+		"host":                 s.endpoint.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           now.Format(amzDateFormat),
+	}
+	req.Header.Set("X-Amz-Date", headers["x-amz-date"])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("Authorization", s.signer.authorization(now, method, objectPath, nil, headers, payloadHash))
+	return req, nil
+}
+
+func (s *S3Storage) objectPath(key string) string {
+	return s.endpoint.Path + "/" + uriEncode(s.bucket, false) + "/" + uriEncode(key, false)
+}
+
+func (s *S3Storage) responseError(action string, res *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+	return fmt.Errorf("failed to %s object: status %d: %s", action, res.StatusCode, strings.TrimSpace(string(body)))
+}
+
+const amzDateFormat = "20060102T150405Z"
+
+// unsignedPayload stands for the body in presigned URLs, which are signed
+// before there is a request.
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// signer computes AWS Signature Version 4 signatures for S3.
+type signer struct {
+	accessKey string
+	secretKey string
+	region    string
+}
+
+// authorization returns the Authorization header of a request for path,
+// signing headers, whose names must be lowercase and include host.
+func (g signer) authorization(t time.Time, method, path string, query url.Values, headers map[string]string, payloadHash string) string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		canonicalQuery(query),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	t = t.UTC()
+	return fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		g.accessKey, g.scope(t), signedHeaders, g.signature(t, canonicalRequest))
+}
+
+// presign returns the query string of a presigned URL for path on host,
+// valid for ttl from t.
+func (g signer) presign(t time.Time, method, host, path string, ttl time.Duration) string {
+	t = t.UTC()
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", g.accessKey+"/"+g.scope(t))
+	query.Set("X-Amz-Date", t.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		path,
+		canonicalQuery(query),
+		"host:" + host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	query.Set("X-Amz-Signature", g.signature(t, canonicalRequest))
+	return canonicalQuery(query)
+}
+
+func (g signer) scope(t time.Time) string {
+	return t.Format("20060102") + "/" + g.region + "/s3/aws4_request"
+}
+
+func (g signer) signature(t time.Time, canonicalRequest string) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + t.Format(amzDateFormat) + "\n" + g.scope(t) + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+g.secretKey), t.Format("20060102"))
+	key = hmacSHA256(key, g.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalQuery encodes query parameters sorted by key, as SigV4 requires.
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		for _, v := range values[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except RFC 3986 unreserved characters.
+// Slashes are kept unless encodeSlash is set.
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,19 @@
+package storage
+
+import (
+	"VoteGolang/conf"
+	"VoteGolang/internals/domain"
+	"fmt"
+)
+
+// NewFromConfig builds the MediaStorage backend selected by cfg.Backend.
+func NewFromConfig(cfg *conf.MediaConfig) (domain.MediaStorage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStorage(cfg.Dir, cfg.BaseURL, []byte(cfg.SigningKey))
+	case "s3":
+		return NewS3Storage(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	default:
+		return nil, fmt.Errorf("unknown media backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,99 @@
+package votecount
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Memory keeps the counts in process memory. It suits a single instance and
+// tests: the counts are lost with the process, so a new Memory is not intact
+// until its first Reset.
+type Memory struct {
+	mu      sync.Mutex
+	pending map[uint]int64
+	batch   *domain.VoteBatch
+	intact  bool
+}
+
+func NewMemory() *Memory {
+	return &Memory{pending: make(map[uint]int64)}
+}
+
+func (c *Memory) Add(_ context.Context, candidateID uint) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.pending[candidateID]++
+	return nil
+}
+
+func (c *Memory) Claim(_ context.Context) (*domain.VoteBatch, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.batch == nil {
+		if len(c.pending) == 0 {
+			return nil, nil
+		}
+		c.batch = &domain.VoteBatch{ID: uuid.NewString(), Counts: c.pending}
+		c.pending = make(map[uint]int64)
+	}
+	return copyBatch(c.batch), nil
+}
+
+func (c *Memory) Done(_ context.Context, batchID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.batch != nil && c.batch.ID == batchID {
+		c.batch = nil
+	}
+	return nil
+}
+
+func (c *Memory) Reset(_ context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	dropped := ""
+	if c.batch != nil {
+		dropped = c.batch.ID
+	}
+	c.pending = make(map[uint]int64)
+	c.batch = nil
+	c.intact = true
+	return dropped, nil
+}
+
+func (c *Memory) Intact(_ context.Context) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.intact, nil
+}
+
+func (c *Memory) Init(_ context.Context) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) == 0 && c.batch == nil {
+		c.intact = true
+	}
+	return c.intact, nil
+}
+
+// Pending returns the counts not claimed yet.
+func (c *Memory) Pending() map[uint]int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := make(map[uint]int64, len(c.pending))
+	for id, n := range c.pending {
+		out[id] = n
+	}
+	return out
+}
+
+func copyBatch(b *domain.VoteBatch) *domain.VoteBatch {
+	counts := make(map[uint]int64, len(b.Counts))
+	for id, n := range b.Counts {
+		counts[id] = n
+	}
+	return &domain.VoteBatch{ID: b.ID, Counts: counts}
+}
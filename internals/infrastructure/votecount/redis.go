@@ -0,0 +1,151 @@
+// Package votecount buffers candidate vote counts for the write-behind voting
+// mode, in Redis or in process memory.
+package votecount
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/dependency"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// pendingKey is the hash of buffered counts by candidate ID.
+	pendingKey = "votes:pending"
+	// batchKey is the hash of the claimed batch: the counts plus its ID
+	// under batchIDField.
+	batchKey     = "votes:batch"
+	batchIDField = "batch"
+	// epochKey exists from the first Reset on. Without it Redis has lost the
+	// buffered counts, or never held them.
+	epochKey = "votes:epoch"
+)
+
+// claimScript returns the claimed batch or, without one, renames the pending
+// counts into a new batch, so votes counted meanwhile start a fresh hash.
+var claimScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[2]) == 0 then
+	if redis.call('EXISTS', KEYS[1]) == 0 then
+		return {}
+	end
+	redis.call('RENAME', KEYS[1], KEYS[2])
+	redis.call('HSET', KEYS[2], ARGV[1], ARGV[2])
+end
+return redis.call('HGETALL', KEYS[2])
+`)
+
+// doneScript deletes the batch only if it is still the one that was applied.
+var doneScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], ARGV[1]) == ARGV[2] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// resetScript drops the counts and the claimed batch, marks the counter
+// intact and returns the ID of the dropped batch, if there was one.
+var resetScript = redis.NewScript(`
+local id = redis.call('HGET', KEYS[2], ARGV[1])
+redis.call('DEL', KEYS[1], KEYS[2])
+redis.call('SET', KEYS[3], ARGV[2])
+return id
+`)
+
+// initScript marks the counter intact unless it holds counts or a batch,
+// which were added without the epoch and may be only part of the votes.
+var initScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1], KEYS[2]) > 0 then
+	return redis.call('EXISTS', KEYS[3])
+end
+redis.call('SET', KEYS[3], ARGV[1], 'NX')
+return 1
+`)
+
+// Redis keeps the counts in Redis, shared by every API instance. Redis must
+// persist its data (AOF or RDB) for counts to survive a restart; when they
+// are lost, Intact reports it until the votes are recounted.
+type Redis struct {
+	rdb *redis.Client
+}
+
+func NewRedis(rdb *redis.Client) domain.VoteCounter {
+	return &Redis{rdb: rdb}
+}
+
+func (c *Redis) Add(ctx context.Context, candidateID uint) error {
+	err := c.rdb.HIncrBy(ctx, pendingKey, strconv.FormatUint(uint64(candidateID), 10), 1).Err()
+	if err != nil && notSent(err) {
+		return fmt.Errorf("%w: %w", domain.ErrNotCounted, err)
+	}
+	return err
+}
+
+// notSent reports whether err shows the command never reached Redis: its
+// breaker was open, or no connection could be opened or taken from the pool.
+func notSent(err error) bool {
+	var opErr *net.OpError
+	return errors.Is(err, dependency.ErrOpen) ||
+		errors.Is(err, redis.ErrPoolTimeout) ||
+		errors.Is(err, redis.ErrClosed) ||
+		(errors.As(err, &opErr) && opErr.Op == "dial")
+}
+
+func (c *Redis) Claim(ctx context.Context) (*domain.VoteBatch, error) {
+	fields, err := claimScript.Run(ctx, c.rdb, []string{pendingKey, batchKey}, batchIDField, uuid.NewString()).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	batch := &domain.VoteBatch{Counts: make(map[uint]int64, len(fields)/2-1)}
+	for i := 0; i+1 < len(fields); i += 2 {
+		field, value := fields[i], fields[i+1]
+		if field == batchIDField {
+			batch.ID = value
+			continue
+		}
+		id, err := strconv.ParseUint(field, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid candidate ID %q in vote batch", field)
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid count %q for candidate %d in vote batch", value, id)
+		}
+		batch.Counts[uint(id)] = count
+	}
+	if batch.ID == "" {
+		return nil, errors.New("vote batch has no ID")
+	}
+	return batch, nil
+}
+
+func (c *Redis) Done(ctx context.Context, batchID string) error {
+	return doneScript.Run(ctx, c.rdb, []string{batchKey}, batchIDField, batchID).Err()
+}
+
+func (c *Redis) Reset(ctx context.Context) (string, error) {
+	dropped, err := resetScript.Run(ctx, c.rdb, []string{pendingKey, batchKey, epochKey}, batchIDField, uuid.NewString()).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return dropped, err
+}
+
+func (c *Redis) Intact(ctx context.Context) (bool, error) {
+	n, err := c.rdb.Exists(ctx, epochKey).Result()
+	return n == 1, err
+}
+
+func (c *Redis) Init(ctx context.Context) (bool, error) {
+	n, err := initScript.Run(ctx, c.rdb, []string{pendingKey, batchKey, epochKey}, uuid.NewString()).Int()
+	return n == 1, err
+}
@@ -0,0 +1,195 @@
+package votecount
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestRedis returns a counter on the Redis server at REDIS_TEST_ADDR, with
+// its keys deleted before and after the test. The test is skipped without a
+// server; it must not be one whose counts matter.
+func newTestRedis(t *testing.T) (*Redis, *redis.Client) {
+	t.Helper()
+	addr := os.Getenv("REDIS_TEST_ADDR")
+	if addr == "" {
+		t.Skip("REDIS_TEST_ADDR is not set")
+	}
+	rdb := redis.NewClient(&redis.Options{Addr: addr})
+	ctx := context.Background()
+	if err := rdb.Ping(ctx).Err(); err != nil {
+		t.Fatalf("redis at %s: %v", addr, err)
+	}
+	drop := func() { rdb.Del(ctx, pendingKey, batchKey, epochKey) }
+	drop()
+	t.Cleanup(func() {
+		drop()
+		rdb.Close()
+	})
+	return NewRedis(rdb).(*Redis), rdb
+}
+
+func add(t *testing.T, c *Redis, ids ...uint) {
+	t.Helper()
+	for _, id := range ids {
+		if err := c.Add(context.Background(), id); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRedisClaimKeepsTheBatchUntilDone(t *testing.T) {
+	c, rdb := newTestRedis(t)
+	ctx := context.Background()
+
+	if batch, err := c.Claim(ctx); err != nil || batch != nil {
+		t.Fatalf("claim with nothing counted = %+v, %v; want none", batch, err)
+	}
+
+	add(t, c, 1, 1, 2)
+	batch, err := c.Claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batch.ID == "" || batch.Counts[1] != 2 || batch.Counts[2] != 1 || len(batch.Counts) != 2 {
+		t.Fatalf("claimed %+v, want 1:2 2:1 with an ID", batch)
+	}
+
+	// Votes counted after the claim wait for the next batch
+	add(t, c, 3)
+	again, err := c.Claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != batch.ID || len(again.Counts) != 2 {
+		t.Fatalf("second claim = %+v, want the unfinished batch %s", again, batch.ID)
+	}
+	if n, _ := rdb.HGet(ctx, pendingKey, strconv.Itoa(3)).Int64(); n != 1 {
+		t.Fatalf("pending count of candidate 3 = %d, want 1", n)
+	}
+
+	// Done of another batch leaves this one claimed
+	if err := c.Done(ctx, "another-batch"); err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := rdb.Exists(ctx, batchKey).Result(); n != 1 {
+		t.Fatal("Done of another batch dropped the claimed one")
+	}
+	if err := c.Done(ctx, batch.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	next, err := c.Claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.ID == batch.ID || next.Counts[3] != 1 || len(next.Counts) != 1 {
+		t.Fatalf("claim after Done = %+v, want a new batch of candidate 3", next)
+	}
+}
+
+func TestRedisResetReturnsTheDroppedBatch(t *testing.T) {
+	c, rdb := newTestRedis(t)
+	ctx := context.Background()
+
+	if intact, err := c.Intact(ctx); err != nil || intact {
+		t.Fatalf("Intact before the first reset = %t, %v; want false", intact, err)
+	}
+	if dropped, err := c.Reset(ctx); err != nil || dropped != "" {
+		t.Fatalf("reset without a batch = %q, %v; want none dropped", dropped, err)
+	}
+	if intact, err := c.Intact(ctx); err != nil || !intact {
+		t.Fatalf("Intact after a reset = %t, %v; want true", intact, err)
+	}
+
+	add(t, c, 1)
+	batch, err := c.Claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	add(t, c, 2)
+	dropped, err := c.Reset(ctx)
+	if err != nil || dropped != batch.ID {
+		t.Fatalf("reset = %q, %v; want the claimed batch %s", dropped, err, batch.ID)
+	}
+	if n, _ := rdb.Exists(ctx, pendingKey, batchKey).Result(); n != 0 {
+		t.Fatalf("%d counter keys left after a reset", n)
+	}
+
+	// The Redis data is lost
+	rdb.Del(ctx, epochKey)
+	if intact, err := c.Intact(ctx); err != nil || intact {
+		t.Fatalf("Intact after the data was lost = %t, %v; want false", intact, err)
+	}
+}
+
+func TestRedisInitOnlyMarksAnEmptyCounterIntact(t *testing.T) {
+	c, rdb := newTestRedis(t)
+	ctx := context.Background()
+
+	add(t, c, 1)
+	if intact, err := c.Init(ctx); err != nil || intact {
+		t.Fatalf("Init with counts buffered = %t, %v; want false", intact, err)
+	}
+
+	rdb.Del(ctx, pendingKey)
+	if intact, err := c.Init(ctx); err != nil || !intact {
+		t.Fatalf("Init of an empty counter = %t, %v; want true", intact, err)
+	}
+	epoch, _ := rdb.Get(ctx, epochKey).Result()
+
+	// Once intact, buffered counts do not matter and the epoch is kept
+	add(t, c, 1)
+	if intact, err := c.Init(ctx); err != nil || !intact {
+		t.Fatalf("Init of an intact counter = %t, %v; want true", intact, err)
+	}
+	if again, _ := rdb.Get(ctx, epochKey).Result(); again != epoch {
+		t.Fatalf("epoch changed from %s to %s", epoch, again)
+	}
+}
+
+func TestRedisAddReportsOnlyVotesThatNeverReachedRedis(t *testing.T) {
+	ctx := context.Background()
+
+	// Nothing listens: the connection is refused before the command is sent
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refusedAddr := closed.Addr().String()
+	closed.Close()
+	refused := redis.NewClient(&redis.Options{Addr: refusedAddr, MaxRetries: -1})
+	defer refused.Close()
+	if err := NewRedis(refused).Add(ctx, 1); !errors.Is(err, domain.ErrNotCounted) {
+		t.Fatalf("add with the connection refused = %v, want %v", err, domain.ErrNotCounted)
+	}
+
+	// The server takes the command and never answers: it may have counted it
+	silent, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer silent.Close()
+	go func() {
+		for {
+			conn, err := silent.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	timedOut := redis.NewClient(&redis.Options{Addr: silent.Addr().String(), MaxRetries: -1, ReadTimeout: 50 * time.Millisecond})
+	defer timedOut.Close()
+	err = NewRedis(timedOut).Add(ctx, 1)
+	if err == nil || errors.Is(err, domain.ErrNotCounted) {
+		t.Fatalf("add that timed out = %v, want an error not wrapping %v", err, domain.ErrNotCounted)
+	}
+}
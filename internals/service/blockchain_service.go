@@ -2,9 +2,19 @@ package service
 
 import (
 	"VoteGolang/internals/domain"
+	"context"
+	"errors"
 	"time"
 )
 
+var (
+	// ErrNotConfirmed means the transaction was broadcast but the wait for
+	// its receipt failed; it may still be mined, so it must not be resent.
+	ErrNotConfirmed = errors.New("transaction broadcast but not confirmed")
+	// ErrReverted means the transaction was mined and rejected by the contract.
+	ErrReverted = errors.New("transaction reverted by EVM")
+)
+
 // TransactionLog represents a generic log of an on-chain action.
 type TransactionLog struct {
 	TransactionID string      `json:"transactionId"`
@@ -16,10 +26,10 @@ type TransactionLog struct {
 
 // BlockchainService defines the interface for interacting with a blockchain.
 type BlockchainService interface {
-	LogCandidateCreation(candidate *domain.Candidate) (*TransactionLog, error)
-	LogCandidateVote(userID uint, candidateID uint, candidateType domain.CandidateType) (*TransactionLog, error)
-	LogPetitionCreation(petition *domain.Petition) (*TransactionLog, error)
-	LogPetitionVote(userID uint, petitionID uint, voteType domain.VoteType) (*TransactionLog, error)
+	LogCandidateCreation(ctx context.Context, candidate *domain.Candidate) (*TransactionLog, error)
+	LogCandidateVote(ctx context.Context, userID uint, candidateID uint, candidateType domain.CandidateType) (*TransactionLog, error)
+	LogPetitionCreation(ctx context.Context, petition *domain.Petition) (*TransactionLog, error)
+	LogPetitionVote(ctx context.Context, userID uint, petitionID uint, voteType domain.VoteType) (*TransactionLog, error)
 
-	GetServiceInfo() (map[string]interface{}, error)
+	GetServiceInfo(ctx context.Context) (map[string]interface{}, error)
 }
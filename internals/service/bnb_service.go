@@ -3,10 +3,12 @@ package service
 import (
 	"VoteGolang/conf"
 	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/metrics"
+	"VoteGolang/internals/infrastructure/tracing"
 	"context"
 	"crypto/ecdsa"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/big"
 	"strings"
 	"time"
@@ -18,6 +20,8 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // BnbService implements the BlockchainService interface using an EVM-compatible JSON-RPC client
@@ -69,10 +73,12 @@ func NewBnbService(config *conf.BnbConfig) (BlockchainService, error) {
 	}
 
 	chainID := big.NewInt(config.ChainID)
-	log.Println("Successfully connected to BNB RPC at", config.NodeURL)
-	log.Println("Application wallet address:", ownerAddress.Hex())
-	log.Println("Target contract address:", contractAddress.Hex())
-	log.Println("Using Chain ID:", chainID)
+	slog.Info("Connected to BNB RPC",
+		"node_url", config.NodeURL,
+		"wallet", ownerAddress.Hex(),
+		"contract", contractAddress.Hex(),
+		"chain_id", chainID.String(),
+	)
 
 	return &BnbService{
 		config:          config,
@@ -85,28 +91,28 @@ func NewBnbService(config *conf.BnbConfig) (BlockchainService, error) {
 	}, nil
 }
 
-func (s *BnbService) LogCandidateCreation(c *domain.Candidate) (*TransactionLog, error) {
+func (s *BnbService) LogCandidateCreation(ctx context.Context, c *domain.Candidate) (*TransactionLog, error) {
 	const methodSignature = "logCandidate(uint256,string,string)"
-	return s.sendEVMTx(methodSignature, new(big.Int).SetUint64(uint64(c.ID)), c.Name, string(c.Type))
+	return s.sendEVMTx(ctx, methodSignature, new(big.Int).SetUint64(uint64(c.ID)), c.Name, string(c.Type))
 }
 
-func (s *BnbService) LogCandidateVote(userID uint, candidateID uint, candidateType domain.CandidateType) (*TransactionLog, error) {
+func (s *BnbService) LogCandidateVote(ctx context.Context, userID uint, candidateID uint, candidateType domain.CandidateType) (*TransactionLog, error) {
 	const methodSignature = "logCandidateVote(uint256,uint256,string)"
-	return s.sendEVMTx(methodSignature, new(big.Int).SetUint64(uint64(userID)), new(big.Int).SetUint64(uint64(candidateID)), string(candidateType))
+	return s.sendEVMTx(ctx, methodSignature, new(big.Int).SetUint64(uint64(userID)), new(big.Int).SetUint64(uint64(candidateID)), string(candidateType))
 }
 
-func (s *BnbService) LogPetitionCreation(p *domain.Petition) (*TransactionLog, error) {
+func (s *BnbService) LogPetitionCreation(ctx context.Context, p *domain.Petition) (*TransactionLog, error) {
 	const methodSignature = "logPetition(uint256,uint256,string)"
-	return s.sendEVMTx(methodSignature, new(big.Int).SetUint64(uint64(p.ID)), new(big.Int).SetUint64(uint64(p.UserID)), p.Title)
+	return s.sendEVMTx(ctx, methodSignature, new(big.Int).SetUint64(uint64(p.ID)), new(big.Int).SetUint64(uint64(p.UserID)), p.Title)
 }
 
-func (s *BnbService) LogPetitionVote(userID uint, petitionID uint, voteType domain.VoteType) (*TransactionLog, error) {
+func (s *BnbService) LogPetitionVote(ctx context.Context, userID uint, petitionID uint, voteType domain.VoteType) (*TransactionLog, error) {
 	const methodSignature = "logPetitionVote(uint256,uint256,string)"
-	return s.sendEVMTx(methodSignature, new(big.Int).SetUint64(uint64(userID)), new(big.Int).SetUint64(uint64(petitionID)), string(voteType))
+	return s.sendEVMTx(ctx, methodSignature, new(big.Int).SetUint64(uint64(userID)), new(big.Int).SetUint64(uint64(petitionID)), string(voteType))
 }
 
-func (s *BnbService) GetServiceInfo() (map[string]interface{}, error) {
-	header, err := s.client.HeaderByNumber(context.Background(), nil)
+func (s *BnbService) GetServiceInfo(ctx context.Context) (map[string]interface{}, error) {
+	header, err := s.client.HeaderByNumber(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -122,8 +128,23 @@ func (s *BnbService) GetServiceInfo() (map[string]interface{}, error) {
 }
 
 // sendEVMTx builds, signs, and broadcasts a transaction to an EVM chain
-func (s *BnbService) sendEVMTx(functionSignature string, params ...interface{}) (*TransactionLog, error) {
-	log.Printf("[BNB RPC] Calling '%s' with params: %v", functionSignature, params)
+func (s *BnbService) sendEVMTx(ctx context.Context, functionSignature string, params ...interface{}) (txLog *TransactionLog, err error) {
+	ctx, span := tracing.Start(ctx, "service", "bnb.sendEVMTx",
+		attribute.String("bnb.function", functionSignature),
+		attribute.String("bnb.contract", s.contractAddress.Hex()),
+	)
+	start := time.Now()
+	defer func() {
+		tracing.End(span, err)
+		result := "success"
+		if err != nil {
+			result = "error"
+			metrics.BlockchainTxFailures.WithLabelValues(functionSignature).Inc()
+		}
+		metrics.BlockchainTxDuration.WithLabelValues(functionSignature, result).Observe(time.Since(start).Seconds())
+	}()
+
+	slog.DebugContext(ctx, "Calling contract function", "function", functionSignature, "params", fmt.Sprint(params...))
 	methodName := functionSignature[:strings.Index(functionSignature, "(")]
 
 	//Pack transaction data
@@ -133,13 +154,13 @@ func (s *BnbService) sendEVMTx(functionSignature string, params ...interface{})
 	}
 
 	// Get nonce
-	nonce, err := s.client.PendingNonceAt(context.Background(), s.ownerAddress)
+	nonce, err := s.client.PendingNonceAt(ctx, s.ownerAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pending nonce: %w", err)
 	}
 
 	// Get gas price
-	gasPrice, err := s.client.SuggestGasPrice(context.Background())
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to suggest gas price: %w", err)
 	}
@@ -150,7 +171,7 @@ func (s *BnbService) sendEVMTx(functionSignature string, params ...interface{})
 		To:   &s.contractAddress,
 		Data: packedData,
 	}
-	gasLimit, err := s.client.EstimateGas(context.Background(), msg)
+	gasLimit, err := s.client.EstimateGas(ctx, msg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to estimate gas: %w. Check contract and params", err)
 	}
@@ -165,37 +186,45 @@ func (s *BnbService) sendEVMTx(functionSignature string, params ...interface{})
 	}
 
 	// Send the transaction
-	err = s.client.SendTransaction(context.Background(), signedTx)
+	err = s.client.SendTransaction(ctx, signedTx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send transaction: %w", err)
 	}
 
-	log.Printf("[BNB RPC] Successfully broadcasted TX: %s | From: %s | Contract: %s | Function: %s",
-		signedTx.Hash().Hex(),
-		s.ownerAddress.Hex(),
-		s.contractAddress.Hex(),
-		functionSignature)
+	span.AddEvent("broadcast", trace.WithAttributes(attribute.String("bnb.tx_hash", signedTx.Hash().Hex())))
+	slog.InfoContext(ctx, "Transaction broadcast, waiting for it to be mined",
+		"tx_hash", signedTx.Hash().Hex(),
+		"from", s.ownerAddress.Hex(),
+		"contract", s.contractAddress.Hex(),
+		"function", functionSignature,
+	)
 
 	// Wait for the transaction to be mined
-	log.Printf("[BNB RPC] Waiting for TX %s to be mined...", signedTx.Hash().Hex())
-	receipt, err := bind.WaitMined(context.Background(), s.client, signedTx)
+	receipt, err := bind.WaitMined(ctx, s.client, signedTx)
 	if err != nil {
-		return nil, fmt.Errorf("error waiting for tx %s to be mined: %w", signedTx.Hash().Hex(), err)
+		return nil, fmt.Errorf("%w: tx %s: %w", ErrNotConfirmed, signedTx.Hash().Hex(), err)
 	}
 
 	if receipt.Status == 0 {
 		// Transaction reverted
-		return nil, fmt.Errorf("transaction %s reverted by EVM", signedTx.Hash().Hex())
+		return nil, fmt.Errorf("%w: tx %s", ErrReverted, signedTx.Hash().Hex())
 	}
 
 	// Calculate fee (Fee = GasUsed * EffectiveGasPrice)
 	feePaid := new(big.Int).Mul(receipt.EffectiveGasPrice, big.NewInt(int64(receipt.GasUsed)))
-
-	log.Printf("[BNB RPC] TX %s confirmed. Block: %d | Fee Paid: %s Wei (%.18f BNB)",
-		receipt.TxHash.Hex(),
-		receipt.BlockNumber,
-		feePaid.String(),
-		new(big.Float).Quo(new(big.Float).SetInt(feePaid), big.NewFloat(1e18)))
+	feeWei, _ := new(big.Float).SetInt(feePaid).Float64()
+	metrics.BlockchainTxFees.WithLabelValues(functionSignature).Add(feeWei)
+
+	span.SetAttributes(
+		attribute.String("bnb.tx_hash", receipt.TxHash.Hex()),
+		attribute.Int64("bnb.block", receipt.BlockNumber.Int64()),
+		attribute.Int64("bnb.gas_used", int64(receipt.GasUsed)),
+	)
+	slog.InfoContext(ctx, "Transaction confirmed",
+		"tx_hash", receipt.TxHash.Hex(),
+		"block", receipt.BlockNumber.String(),
+		"fee_wei", feePaid.String(),
+	)
 
 	return &TransactionLog{
 		TransactionID: receipt.TxHash.Hex(),
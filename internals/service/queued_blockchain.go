@@ -0,0 +1,287 @@
+package service
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/dependency"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// ErrQueued is returned when a log call was queued because the blockchain
+// is unavailable. The operation itself succeeded; the log follows later.
+var ErrQueued = errors.New("blockchain unavailable, log queued")
+
+var (
+	// errNotConfigured is returned when there is no node to connect to.
+	errNotConfigured = errors.New("blockchain service not configured")
+	// errBadEntry marks queued payloads that cannot be decoded.
+	errBadEntry = errors.New("invalid queued blockchain log")
+)
+
+const (
+	actionCandidateCreation = "candidate_creation"
+	actionCandidateVote     = "candidate_vote"
+	actionPetitionCreation  = "petition_creation"
+	actionPetitionVote      = "petition_vote"
+
+	replayBatchSize = 20
+)
+
+type candidateVoteLog struct {
+	UserID        uint                 `json:"user_id"`
+	CandidateID   uint                 `json:"candidate_id"`
+	CandidateType domain.CandidateType `json:"candidate_type"`
+}
+
+type petitionVoteLog struct {
+	UserID     uint            `json:"user_id"`
+	PetitionID uint            `json:"petition_id"`
+	VoteType   domain.VoteType `json:"vote_type"`
+}
+
+// QueuedBlockchain sends log calls to the node while its breaker is closed
+// and queues them in MySQL otherwise. Run replays the queue once the node
+// is back.
+//
+// connect sets up the node client. It is called on first use and again on
+// later calls until it succeeds, so a node that was down at start is picked
+// up once it is back; its failures count against the breaker like failed
+// calls. With a nil connect every call is queued.
+type QueuedBlockchain struct {
+	connect  func() (BlockchainService, error)
+	queue    domain.ChainLogQueue
+	breaker  *dependency.Breaker
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	inner BlockchainService
+}
+
+func NewQueuedBlockchain(connect func() (BlockchainService, error), queue domain.ChainLogQueue, breaker *dependency.Breaker, interval time.Duration, logger *slog.Logger) *QueuedBlockchain {
+	return &QueuedBlockchain{
+		connect:  connect,
+		queue:    queue,
+		breaker:  breaker,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// node returns the node client, connecting first if no attempt has
+// succeeded yet.
+func (q *QueuedBlockchain) node() (BlockchainService, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.inner != nil {
+		return q.inner, nil
+	}
+	if q.connect == nil {
+		return nil, errNotConfigured
+	}
+	inner, err := q.connect()
+	if err != nil {
+		return nil, fmt.Errorf("connect to blockchain node: %w", err)
+	}
+	q.inner = inner
+	q.logger.Info("Connected to the blockchain node")
+	return inner, nil
+}
+
+func (q *QueuedBlockchain) LogCandidateCreation(ctx context.Context, candidate *domain.Candidate) (*TransactionLog, error) {
+	return q.send(ctx, actionCandidateCreation, candidate)
+}
+
+func (q *QueuedBlockchain) LogCandidateVote(ctx context.Context, userID uint, candidateID uint, candidateType domain.CandidateType) (*TransactionLog, error) {
+	return q.send(ctx, actionCandidateVote, candidateVoteLog{UserID: userID, CandidateID: candidateID, CandidateType: candidateType})
+}
+
+func (q *QueuedBlockchain) LogPetitionCreation(ctx context.Context, petition *domain.Petition) (*TransactionLog, error) {
+	return q.send(ctx, actionPetitionCreation, petition)
+}
+
+func (q *QueuedBlockchain) LogPetitionVote(ctx context.Context, userID uint, petitionID uint, voteType domain.VoteType) (*TransactionLog, error) {
+	return q.send(ctx, actionPetitionVote, petitionVoteLog{UserID: userID, PetitionID: petitionID, VoteType: voteType})
+}
+
+func (q *QueuedBlockchain) GetServiceInfo(ctx context.Context) (map[string]interface{}, error) {
+	if q.connect == nil {
+		return nil, errNotConfigured
+	}
+	var info map[string]interface{}
+	err := q.breaker.Do(func() error {
+		inner, err := q.node()
+		if err != nil {
+			return err
+		}
+		info, err = inner.GetServiceInfo(ctx)
+		return err
+	})
+	return info, err
+}
+
+// send calls the node, or queues the call when the node is unavailable or
+// failed before the transaction was broadcast.
+func (q *QueuedBlockchain) send(ctx context.Context, action string, payload interface{}) (*TransactionLog, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if q.connect != nil && q.breaker.Allow() == nil {
+		txLog, err := q.call(ctx, action, data)
+		q.breaker.Record(nodeFailure(err))
+		if err == nil || !resendable(err) {
+			return txLog, err
+		}
+		q.logger.WarnContext(ctx, "Blockchain call failed, queueing it", "action", action, logging.Err(err))
+	}
+
+	if err := q.queue.Enqueue(ctx, action, data); err != nil {
+		return nil, fmt.Errorf("failed to queue blockchain log: %w", err)
+	}
+	return nil, ErrQueued
+}
+
+// call decodes a queued payload and makes the matching call.
+func (q *QueuedBlockchain) call(ctx context.Context, action string, data []byte) (*TransactionLog, error) {
+	inner, err := q.node()
+	if err != nil {
+		return nil, err
+	}
+	switch action {
+	case actionCandidateCreation:
+		var candidate domain.Candidate
+		if err := json.Unmarshal(data, &candidate); err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadEntry, err)
+		}
+		return inner.LogCandidateCreation(ctx, &candidate)
+	case actionCandidateVote:
+		var v candidateVoteLog
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadEntry, err)
+		}
+		return inner.LogCandidateVote(ctx, v.UserID, v.CandidateID, v.CandidateType)
+	case actionPetitionCreation:
+		var petition domain.Petition
+		if err := json.Unmarshal(data, &petition); err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadEntry, err)
+		}
+		return inner.LogPetitionCreation(ctx, &petition)
+	case actionPetitionVote:
+		var v petitionVoteLog
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("%w: %w", errBadEntry, err)
+		}
+		return inner.LogPetitionVote(ctx, v.UserID, v.PetitionID, v.VoteType)
+	}
+	return nil, fmt.Errorf("%w: unknown action %q", errBadEntry, action)
+}
+
+// Run replays queued calls every interval until ctx is cancelled. Entries
+// are replayed in order and a batch stops at the first failure, so the node
+// is not hammered while it is still down.
+func (q *QueuedBlockchain) Run(ctx context.Context) {
+	if q.connect == nil {
+		return
+	}
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if _, _, err := q.replay(ctx); err != nil && !errors.Is(err, dependency.ErrOpen) && ctx.Err() == nil {
+			q.logger.WarnContext(ctx, "Blockchain log replay stopped", logging.Err(err))
+		}
+	}
+}
+
+// Flush replays the whole queue now, batch after batch, until it is empty
+// or a call fails. It returns how many queued calls were sent.
+func (q *QueuedBlockchain) Flush(ctx context.Context) (int, error) {
+	if q.connect == nil {
+		return 0, errors.New("blockchain is not configured")
+	}
+	total := 0
+	for {
+		sent, more, err := q.replay(ctx)
+		total += sent
+		if err != nil || !more {
+			return total, err
+		}
+	}
+}
+
+// replay sends one batch of queued calls. more reports that the batch was
+// full, so entries may be left.
+func (q *QueuedBlockchain) replay(ctx context.Context) (sent int, more bool, err error) {
+	entries, err := q.queue.Pending(ctx, replayBatchSize)
+	if err != nil {
+		return 0, false, fmt.Errorf("read blockchain log queue: %w", err)
+	}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return sent, false, err
+		}
+		// An open breaker lets one trial through after its cooldown
+		if err := q.breaker.Allow(); err != nil {
+			return sent, false, err
+		}
+		err := q.replayEntry(ctx, entry)
+		q.breaker.Record(nodeFailure(err))
+		if err == nil {
+			sent++
+		} else if resendable(err) {
+			return sent, false, err
+		}
+	}
+	return sent, len(entries) == replayBatchSize, nil
+}
+
+// replayEntry makes one queued call and updates the queue with its outcome.
+func (q *QueuedBlockchain) replayEntry(ctx context.Context, entry domain.ChainLogEntry) error {
+	_, err := q.call(ctx, entry.Action, entry.Payload)
+	switch {
+	case err == nil:
+		if delErr := q.queue.Delete(ctx, entry.ID); delErr != nil {
+			q.logger.ErrorContext(ctx, "Failed to remove replayed blockchain log", "entry_id", entry.ID, logging.Err(delErr))
+		}
+		q.logger.InfoContext(ctx, "Queued blockchain log sent", "entry_id", entry.ID, "action", entry.Action)
+	case resendable(err):
+		if markErr := q.queue.MarkFailed(ctx, entry.ID, err.Error()); markErr != nil {
+			q.logger.ErrorContext(ctx, "Failed to update blockchain log queue", "entry_id", entry.ID, logging.Err(markErr))
+		}
+	default:
+		q.logger.ErrorContext(ctx, "CRITICAL: Queued blockchain log cannot be replayed, manual check needed",
+			"entry_id", entry.ID, "action", entry.Action, logging.Err(err))
+		if giveUpErr := q.queue.GiveUp(ctx, entry.ID, err.Error()); giveUpErr != nil {
+			q.logger.ErrorContext(ctx, "Failed to update blockchain log queue", "entry_id", entry.ID, logging.Err(giveUpErr))
+		}
+	}
+	return err
+}
+
+// resendable reports whether a failed call may be sent again: the
+// transaction never reached the network.
+func resendable(err error) bool {
+	return !errors.Is(err, ErrNotConfirmed) && !errors.Is(err, ErrReverted) && !errors.Is(err, errBadEntry)
+}
+
+// nodeFailure keeps the errors that mean the node is unavailable. A reverted
+// transaction was processed by a working node.
+func nodeFailure(err error) error {
+	if errors.Is(err, ErrReverted) || errors.Is(err, errBadEntry) {
+		return nil
+	}
+	return err
+}
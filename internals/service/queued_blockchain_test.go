@@ -0,0 +1,121 @@
+package service_test
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/fakes"
+	"VoteGolang/internals/infrastructure/dependency"
+	"VoteGolang/internals/service"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryQueue keeps queued chain logs in memory.
+type memoryQueue struct {
+	mu      sync.Mutex
+	nextID  uint64
+	entries []domain.ChainLogEntry
+}
+
+func (q *memoryQueue) Enqueue(_ context.Context, action string, payload []byte) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.nextID++
+	q.entries = append(q.entries, domain.ChainLogEntry{ID: q.nextID, Action: action, Payload: payload})
+	return nil
+}
+
+func (q *memoryQueue) Pending(_ context.Context, limit int) ([]domain.ChainLogEntry, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.entries) < limit {
+		limit = len(q.entries)
+	}
+	return append([]domain.ChainLogEntry(nil), q.entries[:limit]...), nil
+}
+
+func (q *memoryQueue) Delete(_ context.Context, id uint64) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, e := range q.entries {
+		if e.ID == id {
+			q.entries = append(q.entries[:i], q.entries[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (q *memoryQueue) MarkFailed(context.Context, uint64, string) error { return nil }
+func (q *memoryQueue) GiveUp(context.Context, uint64, string) error     { return nil }
+
+func (q *memoryQueue) CountPending(context.Context) (int64, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return int64(len(q.entries)), nil
+}
+
+func TestQueuedBlockchainConnectsOnceTheNodeIsUp(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	cooldown := 10 * time.Millisecond
+	breaker := dependency.NewRegistry(1, cooldown, logger).Breaker("blockchain")
+	queue := &memoryQueue{}
+	node := fakes.NewBlockchainService()
+
+	connects := 0
+	up := false
+	chain := service.NewQueuedBlockchain(func() (service.BlockchainService, error) {
+		connects++
+		if !up {
+			return nil, errors.New("dial tcp: connection refused")
+		}
+		return node, nil
+	}, queue, breaker, time.Hour, logger)
+
+	// The node is down at start: the call is queued and opens the breaker
+	if _, err := chain.LogCandidateVote(ctx, 1, 2, domain.Presidential); !errors.Is(err, service.ErrQueued) {
+		t.Fatalf("vote log while the node is down = %v, want %v", err, service.ErrQueued)
+	}
+	if breaker.Healthy() {
+		t.Fatal("a failed connection did not open the breaker")
+	}
+
+	up = true
+	time.Sleep(cooldown)
+	sent, err := chain.Flush(ctx)
+	if err != nil || sent != 1 {
+		t.Fatalf("flush = %d, %v; want the queued log sent", sent, err)
+	}
+	if _, err := chain.LogPetitionVote(ctx, 1, 3, domain.Favor); err != nil {
+		t.Fatalf("petition vote log = %v", err)
+	}
+	if n := len(node.Logs()); n != 2 {
+		t.Fatalf("node has %d logs, want 2", n)
+	}
+	if connects != 2 {
+		t.Fatalf("connected %d times, want 2: once failing, then once for good", connects)
+	}
+}
+
+func TestQueuedBlockchainWithoutNodeQueuesEverything(t *testing.T) {
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	breaker := dependency.NewRegistry(1, time.Hour, logger).Breaker("blockchain")
+	queue := &memoryQueue{}
+	chain := service.NewQueuedBlockchain(nil, queue, breaker, time.Hour, logger)
+
+	if _, err := chain.LogPetitionCreation(ctx, &domain.Petition{ID: 1}); !errors.Is(err, service.ErrQueued) {
+		t.Fatalf("log = %v, want %v", err, service.ErrQueued)
+	}
+	if n, _ := queue.CountPending(ctx); n != 1 {
+		t.Fatalf("queued %d logs, want 1", n)
+	}
+	if !breaker.Healthy() {
+		t.Fatal("a missing node opened the breaker")
+	}
+}
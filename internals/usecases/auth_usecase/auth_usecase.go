@@ -5,6 +5,7 @@ import (
 	"VoteGolang/internals/infrastructure/security"
 	"context"
 	"fmt"
+	"log/slog"
 	"time"
 )
 
@@ -14,20 +15,30 @@ type AuthUseCase struct {
 	RoleRepo      domain.RoleRepository
 	TokenManager  domain.TokenManager
 	EmailVerifier domain.EmailVerifier
+	Logger        *slog.Logger
+	// AccessTTL and RefreshTTL are the lifetimes of issued tokens.
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+	// PasswordCost is the bcrypt cost of new password hashes.
+	PasswordCost int
 }
 
-func NewAuthUseCase(userRepo domain.UserRepository, roleRepo domain.RoleRepository, tm domain.TokenManager, emailVerifier domain.EmailVerifier) *AuthUseCase {
+func NewAuthUseCase(userRepo domain.UserRepository, roleRepo domain.RoleRepository, tm domain.TokenManager, emailVerifier domain.EmailVerifier, accessTTL, refreshTTL time.Duration, logger *slog.Logger) *AuthUseCase {
 	return &AuthUseCase{
 		UserRepo:      userRepo,
 		RoleRepo:      roleRepo,
 		TokenManager:  tm,
 		EmailVerifier: emailVerifier,
+		Logger:        logger,
+		AccessTTL:     accessTTL,
+		RefreshTTL:    refreshTTL,
+		PasswordCost:  security.DefaultPasswordCost,
 	}
 }
 
 // Login authenticates a user and returns a JWT access tokens and refresh tokens.
-func (a *AuthUseCase) Login(username, password string) (string, string, bool, error) {
-	u, err := a.UserRepo.GetByUsername(username)
+func (a *AuthUseCase) Login(ctx context.Context, username, password string) (string, string, bool, error) {
+	u, err := a.UserRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return "", "", false, fmt.Errorf("user not found")
 	}
@@ -40,12 +51,12 @@ func (a *AuthUseCase) Login(username, password string) (string, string, bool, er
 		return "", "", false, fmt.Errorf("invalid credentials")
 	}
 
-	accessToken, err := a.TokenManager.CreateAccessToken(u.ID, 15*time.Minute)
+	accessToken, err := a.TokenManager.CreateAccessToken(u.ID, a.AccessTTL)
 	if err != nil {
 		return "", "", false, err
 	}
 
-	refreshToken, err := a.TokenManager.CreateRefreshToken(u.ID, 24*time.Hour)
+	refreshToken, err := a.TokenManager.CreateRefreshToken(u.ID, a.RefreshTTL)
 	if err != nil {
 		return "", "", false, err
 	}
@@ -85,19 +96,21 @@ func (a *AuthUseCase) Register(ctx context.Context, user *domain.User) (string,
 	if err := security.ValidatePassword(user.Password); err != nil {
 		return "", "", err
 	}
-	hashedPassword, err := security.HashPassword(user.Password)
+	hashedPassword, err := security.HashPassword(user.Password, a.PasswordCost)
 	if err != nil {
 		return "", "", fmt.Errorf("failed to hash password: %v", err)
 	}
 	user.Password = hashedPassword
 
-	role, err := a.RoleRepo.GetByName("member")
+	role, err := a.RoleRepo.GetByName(ctx, "member")
 	if err != nil {
 		return "", "", fmt.Errorf("default role not found: %v", err)
 	}
 	user.RoleID = role.ID
 
-	err = a.UserRepo.Create(user)
+	err = a.UserRepo.Create(ctx, user, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.UserRegistered{UserID: user.ID, Username: user.Username, Email: user.Email})
+	})
 	if err != nil {
 		return "", "", fmt.Errorf("failed to register user_repository: %v", err)
 	}
@@ -118,21 +131,21 @@ func (a *AuthUseCase) Refresh(ctx context.Context, refreshToken string) (string,
 	}
 
 	// (Optional) If you store refresh tokens in DB/Redis, check if this one is still valid
-	// Example: if !a.UserRepo.IsRefreshTokenValid(userID, refreshToken) { return "", "", fmt.Errorf("revoked refresh token") }
+	// Example: if !a.UserRepo.IsRefreshTokenValid(ctx, userID, refreshToken) { return "", "", fmt.Errorf("revoked refresh token") }
 
 	// Generate new tokens
-	accessToken, err := a.TokenManager.CreateAccessToken(userID, 15*time.Minute)
+	accessToken, err := a.TokenManager.CreateAccessToken(userID, a.AccessTTL)
 	if err != nil {
 		return "", "", err
 	}
 
-	newRefreshToken, err := a.TokenManager.CreateRefreshToken(userID, 24*time.Hour)
+	newRefreshToken, err := a.TokenManager.CreateRefreshToken(userID, a.RefreshTTL)
 	if err != nil {
 		return "", "", err
 	}
 
 	// (Optional) Save the new refresh token and revoke the old one in DB
-	// a.UserRepo.RotateRefreshToken(userID, refreshToken, newRefreshToken)
+	// a.UserRepo.RotateRefreshToken(ctx, userID, refreshToken, newRefreshToken)
 
 	return accessToken, newRefreshToken, nil
 }
@@ -144,15 +157,90 @@ func (a *AuthUseCase) VerifyEmail(ctx context.Context, token string) error {
 	}
 
 	// нужно найти юзера по email
-	user, err := a.UserRepo.GetByEmail(email)
+	user, err := a.UserRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return fmt.Errorf("user not found: %v", err)
 	}
 
-	err = a.UserRepo.MarkEmailVerified(ctx, user.ID)
+	err = a.UserRepo.MarkEmailVerified(ctx, user.ID, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.UserEmailVerified{UserID: user.ID})
+	})
 	if err != nil {
 		return fmt.Errorf("failed to mark email verified: %v", err)
 	}
 
 	return nil
 }
+
+// CreateUser creates an account with the given role and a verified email,
+// for accounts set up by operators rather than through registration.
+func (a *AuthUseCase) CreateUser(ctx context.Context, user *domain.User, roleName string) error {
+	if user.Username == "" || user.Email == "" {
+		return fmt.Errorf("username and email are required")
+	}
+	if err := security.ValidatePassword(user.Password); err != nil {
+		return err
+	}
+	hashedPassword, err := security.HashPassword(user.Password, a.PasswordCost)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %v", err)
+	}
+
+	role, err := a.RoleRepo.GetByName(ctx, roleName)
+	if err != nil {
+		return fmt.Errorf("role %q not found: %v", roleName, err)
+	}
+	user.Password = hashedPassword
+	user.RoleID = role.ID
+	user.EmailVerified = true
+
+	err = a.UserRepo.Create(ctx, user, func() ([]domain.Event, error) {
+		return domain.NewEvents(
+			domain.UserRegistered{UserID: user.ID, Username: user.Username, Email: user.Email},
+			domain.UserEmailVerified{UserID: user.ID},
+		)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create user: %v", err)
+	}
+	a.Logger.InfoContext(ctx, "User created", "user_id", user.ID, "role", roleName)
+	return nil
+}
+
+// AssignRole gives a user another role.
+func (a *AuthUseCase) AssignRole(ctx context.Context, username, roleName string) error {
+	user, err := a.UserRepo.GetByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("user %q not found: %v", username, err)
+	}
+	role, err := a.RoleRepo.GetByName(ctx, roleName)
+	if err != nil {
+		return fmt.Errorf("role %q not found: %v", roleName, err)
+	}
+
+	// Save follows the loaded association, so both are switched
+	user.RoleID = role.ID
+	user.Role = *role
+	if err := a.UserRepo.Update(ctx, user); err != nil {
+		return fmt.Errorf("failed to update user: %v", err)
+	}
+	a.Logger.InfoContext(ctx, "Role assigned", "user_id", user.ID, "role", roleName)
+	return nil
+}
+
+// ResendVerification mails a new verification link to an unverified user.
+func (a *AuthUseCase) ResendVerification(ctx context.Context, email string) (string, error) {
+	user, err := a.UserRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return "", fmt.Errorf("user not found: %v", err)
+	}
+	if user.EmailVerified {
+		return "", fmt.Errorf("email already verified")
+	}
+
+	link, _, err := a.EmailVerifier.SendVerificationMail(ctx, user.Email)
+	if err != nil {
+		return "", err
+	}
+	return link, nil
+}
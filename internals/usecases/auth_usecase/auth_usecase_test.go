@@ -0,0 +1,135 @@
+package auth_usecase
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/fakes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const password = "$Password123"
+
+func TestRegister(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    domain.User
+		wantErr string
+	}{
+		{name: "member account", user: domain.User{Username: "beks", Email: "beks@example.com", Password: password}},
+		{name: "no username", user: domain.User{Email: "beks@example.com", Password: password}, wantErr: "username is required"},
+		{name: "no email", user: domain.User{Username: "beks", Password: password}, wantErr: "email is required"},
+		{name: "weak password", user: domain.User{Username: "beks", Email: "beks@example.com", Password: "short"}, wantErr: "too short"},
+		{name: "taken username", user: domain.User{Username: "taken", Email: "new@example.com", Password: password}, wantErr: "failed to register"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := fakes.NewDeps()
+			uc := NewAuthUseCase(d.Users, d.Roles, d.Tokens, d.Mailer, 15*time.Minute, 24*time.Hour, d.Logger)
+			uc.PasswordCost = bcrypt.MinCost
+			ctx := context.Background()
+			d.Users.Create(ctx, &domain.User{Username: "taken", Email: "taken@example.com"}, nil)
+
+			_, token, err := uc.Register(ctx, &tt.user)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				if len(d.Mailer.Sent()) != 0 {
+					t.Fatal("verification mail sent for a failed registration")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			u, err := d.Users.GetByUsername(ctx, tt.user.Username)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if u.Role.Name != "member" || u.EmailVerified || u.Password == password {
+				t.Fatalf("user = role %q, verified %v; want an unverified member with a hashed password", u.Role.Name, u.EmailVerified)
+			}
+			if token == "" || len(d.Mailer.Sent()) != 1 {
+				t.Fatalf("token %q and %d mails, want a token and one mail", token, len(d.Mailer.Sent()))
+			}
+		})
+	}
+}
+
+func TestLoginRequiresVerifiedEmail(t *testing.T) {
+	d := fakes.NewDeps()
+	uc := NewAuthUseCase(d.Users, d.Roles, d.Tokens, d.Mailer, 15*time.Minute, 24*time.Hour, d.Logger)
+	uc.PasswordCost = bcrypt.MinCost
+	ctx := context.Background()
+	_, token, err := uc.Register(ctx, &domain.User{Username: "beks", Email: "beks@example.com", Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, _, err := uc.Login(ctx, "beks", password); err == nil || !strings.Contains(err.Error(), "not verified") {
+		t.Fatalf("login before verification: err = %v", err)
+	}
+	if err := uc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := uc.VerifyEmail(ctx, token); err == nil {
+		t.Fatal("a verification token worked twice")
+	}
+	var types []domain.EventType
+	for _, e := range d.Users.Events() {
+		types = append(types, e.Type)
+	}
+	if len(types) != 2 || types[0] != domain.EventUserRegistered || types[1] != domain.EventUserEmailVerified {
+		t.Fatalf("events = %v, want the registration and the verification", types)
+	}
+
+	if _, _, _, err := uc.Login(ctx, "beks", "Wrong$Password1"); err == nil {
+		t.Fatal("login with a wrong password succeeded")
+	}
+	access, refresh, isAdmin, err := uc.Login(ctx, "beks", password)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if isAdmin {
+		t.Fatal("member logged in as admin")
+	}
+	u, _ := d.Users.GetByUsername(ctx, "beks")
+	if id, err := d.Tokens.VerifyAccessToken(ctx, access); err != nil || id != u.ID {
+		t.Fatalf("access token belongs to %d (%v), want %d", id, err, u.ID)
+	}
+	if _, _, err := uc.Refresh(ctx, access); err == nil {
+		t.Fatal("an access token was accepted as a refresh token")
+	}
+	if _, _, err := uc.Refresh(ctx, refresh); err != nil {
+		t.Fatalf("refresh: %v", err)
+	}
+}
+
+func TestCreateUserAndAssignRole(t *testing.T) {
+	d := fakes.NewDeps()
+	uc := NewAuthUseCase(d.Users, d.Roles, d.Tokens, d.Mailer, 15*time.Minute, 24*time.Hour, d.Logger)
+	uc.PasswordCost = bcrypt.MinCost
+	ctx := context.Background()
+	if err := uc.CreateUser(ctx, &domain.User{Username: "ops", Email: "ops@example.com", Password: password}, "moderator"); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	if err := uc.CreateUser(ctx, &domain.User{Username: "x", Email: "x@example.com", Password: password}, "root"); err == nil {
+		t.Fatal("created a user with an unknown role")
+	}
+	if err := uc.AssignRole(ctx, "ops", "admin"); err != nil {
+		t.Fatalf("assign role: %v", err)
+	}
+
+	_, _, isAdmin, err := uc.Login(ctx, "ops", password)
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if !isAdmin {
+		t.Fatal("user with the admin role did not log in as admin")
+	}
+}
@@ -0,0 +1,109 @@
+package candidate_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Elections summarizes every election from the database, bypassing the cache.
+func (uc *CandidateUseCase) Elections(ctx context.Context) ([]domain.Election, error) {
+	now := time.Now()
+	elections := make([]domain.Election, 0, len(domain.CandidateTypes))
+	for _, t := range domain.CandidateTypes {
+		candidates, err := uc.CandidateRepo.GetAllByType(ctx, string(t))
+		if err != nil {
+			return nil, err
+		}
+		e := domain.Election{Type: t, Candidates: len(candidates)}
+		for _, c := range candidates {
+			e.Votes += c.Votes
+			if e.OpensAt.IsZero() || c.VotingStart.Before(e.OpensAt) {
+				e.OpensAt = c.VotingStart
+			}
+			if c.VotingDeadline.After(e.ClosesAt) {
+				e.ClosesAt = c.VotingDeadline
+			}
+		}
+		e.Open = now.Before(e.ClosesAt)
+		elections = append(elections, e)
+	}
+	return elections, nil
+}
+
+// CloseElection ends voting for every candidate of an election now. It
+// returns how many candidates were still open.
+func (uc *CandidateUseCase) CloseElection(ctx context.Context, candidateType domain.CandidateType) (int64, error) {
+	if !domain.IsValidCandidateType(string(candidateType)) {
+		return 0, errors.New("invalid candidate type")
+	}
+
+	closed, err := uc.CandidateRepo.CloseVoting(ctx, candidateType, time.Now())
+	if err != nil {
+		uc.Logger.ErrorContext(ctx, "Failed to close election", "candidate_type", candidateType, logging.Err(err))
+		return 0, err
+	}
+	uc.Logger.InfoContext(ctx, "Election closed", "candidate_type", candidateType, "candidates", closed)
+
+	// Every cached candidate carries the old deadline
+	uc.Cache.Invalidate(ctx, candidatesTag)
+	// Hidden results are revealed to the open streams
+	uc.publishTally(ctx, domain.TallyEvent{Election: candidateType})
+	return closed, nil
+}
+
+// Results returns the candidates of an election by votes, most first. In
+// write-behind mode votes not flushed yet are left out; flush them first for
+// final results.
+func (uc *CandidateUseCase) Results(ctx context.Context, candidateType domain.CandidateType) ([]domain.CandidateResult, error) {
+	if !domain.IsValidCandidateType(string(candidateType)) {
+		return nil, errors.New("invalid candidate type")
+	}
+	candidates, err := uc.CandidateRepo.GetAllByType(ctx, string(candidateType))
+	if err != nil {
+		return nil, err
+	}
+
+	var total int
+	for _, c := range candidates {
+		total += c.Votes
+	}
+	results := make([]domain.CandidateResult, len(candidates))
+	for i, c := range candidates {
+		results[i] = domain.CandidateResult{CandidateID: c.ID, Name: c.Name, Region: c.Region, Votes: c.Votes}
+		if c.Party != nil {
+			results[i].Party = &c.Party.Name
+		}
+		if total > 0 {
+			results[i].Share = float64(c.Votes) / float64(total)
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Votes > results[j].Votes })
+	return results, nil
+}
+
+// Reindex indexes every candidate for search again and returns how many
+// were indexed.
+func (uc *CandidateUseCase) Reindex(ctx context.Context) (int, error) {
+	if uc.Indexer == nil {
+		return 0, errors.New("search is not configured")
+	}
+	var indexed int
+	for _, t := range domain.CandidateTypes {
+		candidates, err := uc.CandidateRepo.GetAllByType(ctx, string(t))
+		if err != nil {
+			return indexed, err
+		}
+		for i := range candidates {
+			if err := uc.Indexer.Index(ctx, fmt.Sprintf("%d", candidates[i].ID), &candidates[i]); err != nil {
+				return indexed, fmt.Errorf("index candidate %d: %w", candidates[i].ID, err)
+			}
+			indexed++
+		}
+	}
+	return indexed, nil
+}
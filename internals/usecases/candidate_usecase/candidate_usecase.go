@@ -4,161 +4,152 @@ import (
 	"VoteGolang/internals/app/logging"
 	"VoteGolang/internals/domain"
 	candidate_data2 "VoteGolang/internals/domain"
-	"VoteGolang/internals/infrastructure/repositories"
+	"VoteGolang/internals/infrastructure/cache"
+	"VoteGolang/internals/infrastructure/metrics"
 	"VoteGolang/internals/service"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math/rand"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
+// candidatesTag is carried by every cached candidate entry, so changes that
+// touch many candidates drop them all with one invalidation.
+const candidatesTag = "candidates"
+
+// typeTag is carried by the cached candidate lists of an election.
+func typeTag(candidateType string) string {
+	return "candidates:type:" + candidateType
+}
+
+// candidateTag is carried by the cached candidate with id.
+func candidateTag(id uint) string {
+	return fmt.Sprintf("candidate:%d", id)
+}
+
 // CandidateUseCase handles business logic related to election candidates.
 type CandidateUseCase struct {
 	CandidateRepo domain.CandidateRepository
 	VoteRepo      domain.VoteRepository
 	Blockchain    service.BlockchainService
-	Redis         *redis.Client
-	SearchRepo    *repositories.SearchRepository
-	Logger        *logging.KafkaLogger
+	Cache         *cache.Aside
+	Indexer       domain.SearchIndexer
+	AssetRepo     domain.AssetRepository
+	PartyRepo     domain.PartyRepository
+	Tallies       domain.TallyPublisher
+	Logger        *slog.Logger
+
+	// Counter and VoteCounts are set in write-behind mode: votes are then
+	// counted in Counter and added to the candidates by FlushVotes, instead
+	// of updating the candidate row in the vote transaction.
+	Counter    domain.VoteCounter
+	VoteCounts domain.VoteCountRepository
 }
 
 func NewCandidateUseCase(
 	cRepo candidate_data2.CandidateRepository,
 	vRepo candidate_data2.VoteRepository,
 	bc service.BlockchainService,
-	rdb *redis.Client,
-	searchRepo *repositories.SearchRepository,
-	kafkaLogger *logging.KafkaLogger) *CandidateUseCase {
+	store candidate_data2.Cache,
+	indexer candidate_data2.SearchIndexer,
+	assetRepo candidate_data2.AssetRepository,
+	partyRepo candidate_data2.PartyRepository,
+	tallies candidate_data2.TallyPublisher,
+	logger *slog.Logger) *CandidateUseCase {
 	return &CandidateUseCase{
 		CandidateRepo: cRepo,
 		VoteRepo:      vRepo,
 		Blockchain:    bc,
-		Redis:         rdb,
-		SearchRepo:    searchRepo,
-		Logger:        kafkaLogger,
+		Cache:         cache.NewAside(store, gorm.ErrRecordNotFound, logger),
+		Indexer:       indexer,
+		AssetRepo:     assetRepo,
+		PartyRepo:     partyRepo,
+		Tallies:       tallies,
+		Logger:        logger,
 	}
 }
 
-func (uc *CandidateUseCase) CreateCandidate(candidate *domain.Candidate) error {
-	if err := uc.CandidateRepo.Create(candidate); err != nil {
-		uc.Logger.Log("ERROR", fmt.Sprintf("Failed to create candidate in DB: %v", err))
-		return err
-	}
-	uc.Logger.Log("INFO", fmt.Sprintf("Candidate %d created successfully in DB", candidate.ID))
+// PublishCandidate runs the follow-ups of a newly stored candidate: search
+// indexing, cache invalidation and the blockchain log. Candidates are stored
+// when a nomination is approved, together with their CandidateCreated event.
+func (uc *CandidateUseCase) PublishCandidate(ctx context.Context, candidate *domain.Candidate) {
+	uc.Logger.InfoContext(ctx, "Candidate created", "candidate_id", candidate.ID)
 
-	if uc.SearchRepo != nil {
-		go func() {
+	if uc.Indexer != nil {
+		go func(ctx context.Context) {
 			id := fmt.Sprintf("%d", candidate.ID)
-			if err := uc.SearchRepo.Index(context.Background(), id, candidate); err != nil {
-				uc.Logger.Log("WARN", fmt.Sprintf("Failed to index candidate %d: %v", candidate.ID, err))
+			if err := uc.Indexer.Index(ctx, id, candidate); err != nil {
+				uc.Logger.WarnContext(ctx, "Failed to index candidate", "candidate_id", candidate.ID, logging.Err(err))
 			} else {
-				uc.Logger.Log("DEBUG", fmt.Sprintf("Candidate %d indexed for search", candidate.ID))
+				uc.Logger.DebugContext(ctx, "Candidate indexed for search", "candidate_id", candidate.ID)
 			}
-		}()
+		}(context.WithoutCancel(ctx))
 	}
 
-	// Invalidate cache
-	pattern := fmt.Sprintf("candidates:type:%s*", candidate.Type)
-	keys, err := uc.Redis.Keys(context.Background(), pattern).Result()
-	if err != nil {
-		uc.Logger.Log("WARN", fmt.Sprintf("Failed to get keys for cache invalidation, pattern %s: %v", pattern, err))
-	}
-	for _, k := range keys {
-		uc.Redis.Del(context.Background(), k)
-	}
-	uc.Logger.Log("DEBUG", fmt.Sprintf("Cache invalidated for pattern %s (keys: %d)", pattern, len(keys)))
+	// The ID may have been looked up, and cached as missing, before it existed
+	uc.Cache.Invalidate(ctx, candidateTag(candidate.ID), typeTag(string(candidate.Type)))
 
 	// Log to blockchain
-	if _, err := uc.Blockchain.LogCandidateCreation(candidate); err != nil {
-
-		uc.Logger.Log("ERROR", fmt.Sprintf("CRITICAL: Candidate %d created in DB but failed to log to blockchain: %v", candidate.ID, err))
+	if _, err := uc.Blockchain.LogCandidateCreation(context.WithoutCancel(ctx), candidate); errors.Is(err, service.ErrQueued) {
+		uc.Logger.WarnContext(ctx, "Blockchain unavailable, candidate log queued", "candidate_id", candidate.ID)
+	} else if err != nil {
+		uc.Logger.ErrorContext(ctx, "CRITICAL: Candidate created in DB but failed to log to blockchain", "candidate_id", candidate.ID, logging.Err(err))
 	} else {
-		uc.Logger.Log("INFO", fmt.Sprintf("Candidate %d logged to blockchain", candidate.ID))
+		uc.Logger.InfoContext(ctx, "Candidate logged to blockchain", "candidate_id", candidate.ID)
 	}
-
-	return nil
 }
 
-func (uc *CandidateUseCase) GetAllByTypePaginated(candidateType string, limit, offset int) ([]domain.Candidate, error) {
-	ctx := context.Background()
+func (uc *CandidateUseCase) GetAllByTypePaginated(ctx context.Context, candidateType string, limit, offset int) ([]domain.Candidate, error) {
 	cacheKey := fmt.Sprintf("candidates:type:%s:page:%d:limit:%d", candidateType, offset/limit+1, limit)
+	tags := []string{candidatesTag, typeTag(candidateType)}
 
-	cached, err := uc.Redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var candidates []domain.Candidate
-		if err := json.Unmarshal([]byte(cached), &candidates); err == nil {
-			uc.Logger.Log("DEBUG", fmt.Sprintf("Cache hit for: %s", cacheKey))
-			return candidates, nil
-		}
-	}
-
-	uc.Logger.Log("DEBUG", fmt.Sprintf("Cache miss for: %s", cacheKey))
-
-	candidates, err := uc.CandidateRepo.GetAllByTypePaginated(candidateType, limit, offset)
-	if err != nil {
-		uc.Logger.Log("ERROR", fmt.Sprintf("Failed to get candidates from DB (type: %s): %v", candidateType, err))
-		return nil, err
-	}
-
-	data, _ := json.Marshal(candidates)
-	uc.Redis.Set(ctx, cacheKey, data, time.Duration(rand.Intn(5)+25)*time.Minute)
-
-	return candidates, nil
+	return cache.Fetch(ctx, uc.Cache, cacheKey, tags, time.Duration(rand.Intn(5)+25)*time.Minute,
+		func(ctx context.Context) ([]domain.Candidate, error) {
+			candidates, err := uc.CandidateRepo.GetAllByTypePaginated(ctx, candidateType, limit, offset)
+			if err != nil {
+				uc.Logger.ErrorContext(ctx, "Failed to get candidates from DB", "candidate_type", candidateType, logging.Err(err))
+			}
+			return candidates, err
+		})
 }
 
 // GetAllByType returns a list of candidates filtered by type.
-func (uc *CandidateUseCase) GetAllByType(candidateType string) ([]domain.Candidate, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("candidates:type:%s", candidateType)
-
-	// Try cache first
-	cached, err := uc.Redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var candidates []domain.Candidate
-		if err := json.Unmarshal([]byte(cached), &candidates); err == nil {
-			uc.Logger.Log("DEBUG", fmt.Sprintf("Cache hit for: %s", cacheKey))
-			return candidates, nil
-		}
-	}
-
-	uc.Logger.Log("DEBUG", fmt.Sprintf("Cache miss for: %s", cacheKey))
-	// Fallback to DB
-	candidates, err := uc.CandidateRepo.GetAllByType(candidateType)
-	if err != nil {
-		uc.Logger.Log("ERROR", fmt.Sprintf("Failed to get candidates from DB (type: %s): %v", candidateType, err))
-		return nil, err
-	}
-
-	// Save to Redis
-	data, _ := json.Marshal(candidates)
-	uc.Redis.Set(ctx, cacheKey, data, time.Duration(rand.Intn(5)+25)*time.Minute)
-
-	return candidates, nil
+func (uc *CandidateUseCase) GetAllByType(ctx context.Context, candidateType string) ([]domain.Candidate, error) {
+	cacheKey := typeTag(candidateType)
+	tags := []string{candidatesTag, typeTag(candidateType)}
+
+	return cache.Fetch(ctx, uc.Cache, cacheKey, tags, time.Duration(rand.Intn(5)+25)*time.Minute,
+		func(ctx context.Context) ([]domain.Candidate, error) {
+			candidates, err := uc.CandidateRepo.GetAllByType(ctx, candidateType)
+			if err != nil {
+				uc.Logger.ErrorContext(ctx, "Failed to get candidates from DB", "candidate_type", candidateType, logging.Err(err))
+			}
+			return candidates, err
+		})
 }
 
 // Vote votes for candidate by type, user_id, candidate_id.
-func (uc *CandidateUseCase) Vote(candidateID uint, userID uint, candidateType domain.CandidateType) error {
+func (uc *CandidateUseCase) Vote(ctx context.Context, candidateID uint, userID uint, candidateType domain.CandidateType) error {
 	if !domain.IsValidCandidateType(string(candidateType)) {
 		return errors.New("invalid candidate type")
 	}
 
-	voted, err := uc.VoteRepo.HasVoted(userID, string(candidateType))
+	voted, err := uc.VoteRepo.HasVoted(ctx, userID, string(candidateType))
 	if err != nil {
-		uc.Logger.Log("ERROR", fmt.Sprintf("Failed to check HasVoted (user: %d, type: %s): %v", userID, candidateType, err))
+		uc.Logger.ErrorContext(ctx, "Failed to check HasVoted", "user_id", userID, "candidate_type", candidateType, logging.Err(err))
 		return err
 	}
 	if voted {
 		return errors.New("already voted for this category")
 	}
 
-	candidate, err := uc.CandidateRepo.GetByID(candidateID)
+	candidate, err := uc.CandidateRepo.GetByID(ctx, candidateID)
 	if err != nil {
-		uc.Logger.Log("WARN", fmt.Sprintf("Failed to find candidate %d for voting: %v", candidateID, err))
+		uc.Logger.WarnContext(ctx, "Failed to find candidate for voting", "candidate_id", candidateID, logging.Err(err))
 		return err
 	}
 
@@ -172,10 +163,14 @@ func (uc *CandidateUseCase) Vote(candidateID uint, userID uint, candidateType do
 		return errors.New("voting period has ended for this candidate")
 	}
 
-	//    This callback will be executed by VoteWithTransaction.
-	dbTransactionCallback := func() error {
+	//    This callback will be executed by VoteWithTransaction, in its transaction.
+	dbTransactionCallback := func(ctx context.Context) error {
+		if uc.Counter != nil {
+			// Counted once the vote is committed
+			return nil
+		}
 		// Increment vote count in the Candidates table
-		if err := uc.CandidateRepo.IncrementVote(candidateID); err != nil {
+		if err := uc.CandidateRepo.IncrementVote(ctx, candidateID); err != nil {
 			return err
 		}
 		// The VoteWithTransaction will handle the SaveVote part.
@@ -184,63 +179,68 @@ func (uc *CandidateUseCase) Vote(candidateID uint, userID uint, candidateType do
 
 	//    This method should handle Begin, Commit, and Rollback.
 	//    It runs the callback and then saves the vote in one atomic operation.
-	err = uc.VoteRepo.VoteWithTransaction(candidateID, userID, string(candidateType), dbTransactionCallback)
+	vote := &domain.Vote{CandidateID: candidateID, UserID: userID, CandidateType: candidateType}
+	err = uc.VoteRepo.VoteWithTransaction(ctx, vote, dbTransactionCallback, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.VoteCast{
+			VoteID:        vote.ID,
+			CandidateID:   candidateID,
+			CandidateType: candidateType,
+			Region:        candidate.Region,
+			UserID:        userID,
+		})
+	})
 	if err != nil {
-		uc.Logger.Log("ERROR", fmt.Sprintf("DB transaction for vote failed (user: %d, candidate: %d): %v", userID, candidateID, err))
+		uc.Logger.ErrorContext(ctx, "DB transaction for vote failed", "user_id", userID, "candidate_id", candidateID, logging.Err(err))
 		return fmt.Errorf("database transaction failed: %w", err)
 	}
+	metrics.CandidateVotes.WithLabelValues(string(candidateType)).Inc()
+	counted := true
+	if uc.Counter != nil {
+		counted = uc.countVote(ctx, vote)
+	}
+	if counted {
+		uc.Cache.Invalidate(ctx, candidateTag(candidateID), typeTag(string(candidateType)))
+	}
 
 	//    If this fails, the vote is *still valid* in our DB.
-	if _, err := uc.Blockchain.LogCandidateVote(userID, candidateID, candidateType); err != nil {
-		uc.Logger.Log("ERROR", fmt.Sprintf("CRITICAL: Vote (user %d, candidate %d) saved to DB but failed to log to blockchain: %v", userID, candidateID, err))
+	//    The vote is committed, so the log is not cancelled with the request.
+	if _, err := uc.Blockchain.LogCandidateVote(context.WithoutCancel(ctx), userID, candidateID, candidateType); errors.Is(err, service.ErrQueued) {
+		uc.Logger.WarnContext(ctx, "Blockchain unavailable, vote log queued", "user_id", userID, "candidate_id", candidateID)
+	} else if err != nil {
+		uc.Logger.ErrorContext(ctx, "CRITICAL: Vote saved to DB but failed to log to blockchain", "user_id", userID, "candidate_id", candidateID, logging.Err(err))
 		// Do not return error, the vote was successful.
 	} else {
-		uc.Logger.Log("INFO", fmt.Sprintf("Vote (user %d, candidate %d) logged to blockchain", userID, candidateID))
+		uc.Logger.InfoContext(ctx, "Vote logged to blockchain", "user_id", userID, "candidate_id", candidateID)
 	}
 
+	if counted {
+		uc.publishTally(ctx, domain.TallyEvent{Election: candidateType})
+	}
 	return nil
 }
 
-func (uc *CandidateUseCase) GetCandidateByID(id uint) (*candidate_data2.Candidate, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("candidate:%d", id)
-
-	if cached, err := uc.Redis.Get(ctx, cacheKey).Result(); err == nil {
-		var candidate candidate_data2.Candidate
-		if json.Unmarshal([]byte(cached), &candidate) == nil {
-			log.Println("Cache hit:", cacheKey)
-			return &candidate, nil
-		}
+// publishTally tells live result streams that an election's counts changed.
+func (uc *CandidateUseCase) publishTally(ctx context.Context, event domain.TallyEvent) {
+	if uc.Tallies == nil {
+		return
 	}
-
-	log.Println("Cache miss:", cacheKey)
-	candidate, err := uc.CandidateRepo.GetByID(id)
-	if err != nil {
-		return nil, err
+	if err := uc.Tallies.PublishTally(ctx, event); err != nil {
+		uc.Logger.WarnContext(ctx, "Failed to publish tally event", "topic", event.Topic(), logging.Err(err))
 	}
+}
 
-	data, _ := json.Marshal(candidate)
-	uc.Redis.Set(ctx, cacheKey, data, 5*time.Minute)
-	return candidate, nil
+func (uc *CandidateUseCase) GetCandidateByID(ctx context.Context, id uint) (*candidate_data2.Candidate, error) {
+	tags := []string{candidatesTag, candidateTag(id)}
+	return cache.Fetch(ctx, uc.Cache, candidateTag(id), tags, 5*time.Minute, func(ctx context.Context) (*candidate_data2.Candidate, error) {
+		return uc.CandidateRepo.GetByID(ctx, id)
+	})
 }
 
-func (uc *CandidateUseCase) DeleteCandidate(id uint) error {
-	if err := uc.CandidateRepo.DeleteByID(id); err != nil {
+func (uc *CandidateUseCase) DeleteCandidate(ctx context.Context, id uint) error {
+	if err := uc.CandidateRepo.DeleteByID(ctx, id); err != nil {
 		return err
 	}
 
-	// Invalidate all candidate caches
-	ctx := context.Background()
-	var cursor uint64
-	for {
-		keys, nextCursor, _ := uc.Redis.Scan(ctx, cursor, "candidates*", 100).Result()
-		for _, k := range keys {
-			uc.Redis.Del(ctx, k)
-		}
-		if nextCursor == 0 {
-			break
-		}
-		cursor = nextCursor
-	}
+	uc.Cache.Invalidate(ctx, candidatesTag)
 	return nil
 }
@@ -0,0 +1,230 @@
+package candidate_usecase
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/fakes"
+	"VoteGolang/internals/service"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openCandidate is a candidate whose voting started an hour ago and ends in a day.
+func openCandidate(id uint, candidateType domain.CandidateType) domain.Candidate {
+	now := time.Now()
+	return domain.Candidate{
+		ID:             id,
+		Name:           "Candidate",
+		Type:           candidateType,
+		VotingStart:    now.Add(-time.Hour),
+		VotingDeadline: now.Add(24 * time.Hour),
+	}
+}
+
+func TestVote(t *testing.T) {
+	notStarted := openCandidate(2, domain.Presidential)
+	notStarted.VotingStart = time.Now().Add(time.Hour)
+	ended := openCandidate(3, domain.Presidential)
+	ended.VotingDeadline = time.Now().Add(-time.Minute)
+
+	tests := []struct {
+		name          string
+		candidateID   uint
+		candidateType domain.CandidateType
+		votedBefore   bool
+		chainErr      error
+		wantErr       string
+		wantVotes     int
+	}{
+		{name: "counts the vote", candidateID: 1, candidateType: domain.Presidential, wantVotes: 1},
+		{name: "invalid type", candidateID: 1, candidateType: "mayor", wantErr: "invalid candidate type"},
+		{name: "type mismatch", candidateID: 1, candidateType: domain.Deputy, wantErr: "candidate type mismatch"},
+		{name: "double vote", candidateID: 1, candidateType: domain.Presidential, votedBefore: true, wantErr: "already voted"},
+		{name: "before voting starts", candidateID: 2, candidateType: domain.Presidential, wantErr: "has not started"},
+		{name: "after the deadline", candidateID: 3, candidateType: domain.Presidential, wantErr: "has ended"},
+		{name: "unknown candidate", candidateID: 9, candidateType: domain.Presidential, wantErr: "record not found"},
+		{name: "blockchain down", candidateID: 1, candidateType: domain.Presidential, chainErr: errors.New("node down"), wantVotes: 1},
+		{name: "blockchain queued", candidateID: 1, candidateType: domain.Presidential, chainErr: service.ErrQueued, wantVotes: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := fakes.NewDeps()
+			d.AddCandidates(openCandidate(1, domain.Presidential), notStarted, ended)
+			uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+			d.Blockchain.Err = tt.chainErr
+			ctx := context.Background()
+			const userID = 42
+			if tt.votedBefore {
+				d.Votes.SaveVote(ctx, 1, userID, string(domain.Presidential))
+			}
+
+			err := uc.Vote(ctx, tt.candidateID, userID, tt.candidateType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if c, err := d.Candidates.GetByID(ctx, tt.candidateID); err == nil && c.Votes != tt.wantVotes {
+				t.Fatalf("candidate votes = %d, want %d", c.Votes, tt.wantVotes)
+			}
+			if tt.wantErr != "" {
+				return
+			}
+			events := d.Votes.Events()
+			if len(events) != 1 || events[0].Type != domain.EventVoteCast {
+				t.Fatalf("events = %+v, want one VoteCast", events)
+			}
+			var cast domain.VoteCast
+			if err := json.Unmarshal(events[0].Data, &cast); err != nil {
+				t.Fatal(err)
+			}
+			if votes := d.Votes.Votes(); len(votes) != 1 || cast.VoteID != votes[0].ID {
+				t.Fatalf("event vote ID = %d, want the stored vote's ID", cast.VoteID)
+			}
+			if logs := d.Blockchain.Logs(); tt.chainErr == nil && len(logs) != 1 {
+				t.Fatalf("blockchain logs = %d, want 1", len(logs))
+			}
+		})
+	}
+}
+
+// TestVoteSameUserConcurrently checks the use case under concurrent votes.
+// The fake repository serializes them the way the row lock does; the lock
+// itself is tested in the repositories package.
+func TestVoteSameUserConcurrently(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Deputy), openCandidate(2, domain.Deputy))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	ctx := context.Background()
+
+	const attempts = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(candidateID uint) {
+			defer wg.Done()
+			if err := uc.Vote(ctx, candidateID, 7, domain.Deputy); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}(uint(i%2 + 1))
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("%d votes succeeded, want 1", succeeded)
+	}
+	first, _ := d.Candidates.GetByID(ctx, 1)
+	second, _ := d.Candidates.GetByID(ctx, 2)
+	if first.Votes+second.Votes != 1 {
+		t.Fatalf("candidates counted %d votes, want 1", first.Votes+second.Votes)
+	}
+	if n := len(d.Votes.Votes()); n != 1 {
+		t.Fatalf("stored %d votes, want 1", n)
+	}
+}
+
+func TestVoteManyUsersConcurrently(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Manager))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	ctx := context.Background()
+
+	const users = 100
+	var wg sync.WaitGroup
+	for userID := uint(1); userID <= users; userID++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			if err := uc.Vote(ctx, 1, userID, domain.Manager); err != nil {
+				t.Errorf("user %d: %v", userID, err)
+			}
+		}(userID)
+	}
+	wg.Wait()
+
+	c, _ := d.Candidates.GetByID(ctx, 1)
+	if c.Votes != users {
+		t.Fatalf("candidate votes = %d, want %d", c.Votes, users)
+	}
+}
+
+func TestPublishCandidateInvalidatesTypeCache(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Presidential))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	ctx := context.Background()
+
+	if got, _ := uc.GetAllByType(ctx, string(domain.Presidential)); len(got) != 1 {
+		t.Fatalf("listed %d candidates, want 1", len(got))
+	}
+
+	c := openCandidate(2, domain.Presidential)
+	if err := d.Candidates.Create(ctx, &c); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := uc.GetAllByType(ctx, string(domain.Presidential)); len(got) != 1 {
+		t.Fatalf("listed %d candidates before publishing, want the cached 1", len(got))
+	}
+
+	uc.PublishCandidate(ctx, &c)
+	if got, _ := uc.GetAllByType(ctx, string(domain.Presidential)); len(got) != 2 {
+		t.Fatalf("listed %d candidates after publishing, want 2", len(got))
+	}
+}
+
+func TestVoteInvalidatesCachedCandidate(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Presidential))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	ctx := context.Background()
+
+	if c, err := uc.GetCandidateByID(ctx, 1); err != nil || c.Votes != 0 {
+		t.Fatalf("GetCandidateByID = %+v, %v, want 0 votes", c, err)
+	}
+	if _, err := uc.GetAllByType(ctx, string(domain.Presidential)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.Vote(ctx, 1, 7, domain.Presidential); err != nil {
+		t.Fatal(err)
+	}
+
+	if c, _ := uc.GetCandidateByID(ctx, 1); c.Votes != 1 {
+		t.Fatalf("cached candidate has %d votes after voting, want 1", c.Votes)
+	}
+	if list, _ := uc.GetAllByType(ctx, string(domain.Presidential)); list[0].Votes != 1 {
+		t.Fatalf("cached list has %d votes after voting, want 1", list[0].Votes)
+	}
+}
+
+func TestGetCandidateByIDCachesMissing(t *testing.T) {
+	d := fakes.NewDeps()
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	ctx := context.Background()
+
+	if _, err := uc.GetCandidateByID(ctx, 1); !errors.Is(err, fakes.ErrNotFound) {
+		t.Fatalf("GetCandidateByID returned %v, want not found", err)
+	}
+
+	// Publishing the candidate drops the negative entry
+	c := openCandidate(1, domain.Presidential)
+	d.Candidates.Create(ctx, &c)
+	if _, err := uc.GetCandidateByID(ctx, 1); !errors.Is(err, fakes.ErrNotFound) {
+		t.Fatalf("GetCandidateByID before publishing returned %v, want the cached not found", err)
+	}
+	uc.PublishCandidate(ctx, &c)
+	if _, err := uc.GetCandidateByID(ctx, 1); err != nil {
+		t.Fatalf("GetCandidateByID after publishing: %v", err)
+	}
+}
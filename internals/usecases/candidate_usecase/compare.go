@@ -0,0 +1,106 @@
+package candidate_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	minCompareCandidates = 2
+	maxCompareCandidates = 5
+)
+
+// CompareCandidates lines up the profiles and manifesto positions of the
+// given candidates, in the order their IDs were passed.
+func (uc *CandidateUseCase) CompareCandidates(ctx context.Context, ids []uint) (*domain.CandidateComparison, error) {
+	ids = uniqueIDs(ids)
+	if len(ids) < minCompareCandidates || len(ids) > maxCompareCandidates {
+		return nil, fmt.Errorf("between %d and %d distinct candidates can be compared", minCompareCandidates, maxCompareCandidates)
+	}
+
+	candidates, err := uc.CandidateRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		uc.Logger.ErrorContext(ctx, "Failed to load candidates for comparison", "candidate_ids", ids, logging.Err(err))
+		return nil, err
+	}
+	byID := make(map[uint]*domain.Candidate, len(candidates))
+	for i := range candidates {
+		byID[candidates[i].ID] = &candidates[i]
+	}
+
+	comparison := &domain.CandidateComparison{
+		Candidates: make([]domain.CandidateProfile, 0, len(ids)),
+	}
+	for _, id := range ids {
+		c, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("candidate %d not found", id)
+		}
+		comparison.Candidates = append(comparison.Candidates, candidateProfile(c))
+	}
+
+	// Only topics at least one candidate has a position on are listed
+	for _, topic := range domain.ManifestoTopics {
+		row := domain.TopicComparison{Topic: topic}
+		covered := false
+		for _, id := range ids {
+			points := []string{}
+			for _, p := range byID[id].ManifestoPoints {
+				if p.Topic == topic {
+					points = append(points, p.Text)
+				}
+			}
+			covered = covered || len(points) > 0
+			row.Positions = append(row.Positions, domain.CandidatePositions{CandidateID: id, Points: points})
+		}
+		if covered {
+			comparison.Topics = append(comparison.Topics, row)
+		}
+	}
+
+	return comparison, nil
+}
+
+func candidateProfile(c *domain.Candidate) domain.CandidateProfile {
+	profile := domain.CandidateProfile{
+		ID:           c.ID,
+		Name:         strings.TrimSpace(c.Name),
+		Type:         c.Type,
+		PhotoAssetID: c.PhotoAssetID,
+		Education:    trimmed(c.Education),
+		Age:          c.Age,
+		PartyID:      c.PartyID,
+		Region:       trimmed(c.Region),
+	}
+	if c.Party != nil {
+		profile.Party = &c.Party.Name
+	}
+	return profile
+}
+
+// trimmed returns s without surrounding whitespace, or nil if nothing is left.
+func trimmed(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	t := strings.TrimSpace(*s)
+	if t == "" {
+		return nil
+	}
+	return &t
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	out := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id != 0 && !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
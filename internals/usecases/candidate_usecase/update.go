@@ -0,0 +1,290 @@
+package candidate_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	maxSocialLinks     = 10
+	maxCandidatePhotos = 10
+	maxManifestoPoints = 50
+)
+
+// UpdateCandidate applies a partial profile update, records every changed
+// field in the candidate's history, reindexes it for search and drops its
+// cached copies.
+func (uc *CandidateUseCase) UpdateCandidate(ctx context.Context, id, userID uint, update domain.CandidateUpdate) (*domain.Candidate, error) {
+	if errs := validateCandidateUpdate(update); len(errs) > 0 {
+		return nil, errs
+	}
+
+	if update.PhotoAssetID != nil && strings.TrimSpace(*update.PhotoAssetID) != "" {
+		if _, err := uc.AssetRepo.GetByID(ctx, strings.TrimSpace(*update.PhotoAssetID)); err != nil {
+			return nil, domain.ValidationErrors{"photo_asset_id": "must refer to an uploaded asset"}
+		}
+	}
+
+	if update.PartyID != nil && *update.PartyID != 0 {
+		if _, err := uc.PartyRepo.GetByID(ctx, *update.PartyID); err != nil {
+			return nil, domain.ValidationErrors{"party_id": "must refer to an existing party"}
+		}
+	}
+
+	candidate, err := uc.CandidateRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []domain.CandidateChange
+	record := func(field string, oldValue, newValue *string) {
+		if oldValue == nil && newValue == nil {
+			return
+		}
+		if oldValue != nil && newValue != nil && *oldValue == *newValue {
+			return
+		}
+		changes = append(changes, domain.CandidateChange{
+			CandidateID: id,
+			UserID:      userID,
+			Field:       field,
+			OldValue:    oldValue,
+			NewValue:    newValue,
+		})
+	}
+	applyString := func(field string, target **string, value *string) {
+		if value == nil {
+			return
+		}
+		newValue := strings.TrimSpace(*value)
+		var next *string
+		if newValue != "" {
+			next = &newValue
+		}
+		record(field, *target, next)
+		*target = next
+	}
+
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		old := candidate.Name
+		record("name", &old, &name)
+		candidate.Name = name
+	}
+	if update.Age != nil {
+		old, next := strconv.Itoa(candidate.Age), strconv.Itoa(*update.Age)
+		record("age", &old, &next)
+		candidate.Age = *update.Age
+	}
+	applyString("photo", &candidate.Photo, update.Photo)
+	applyString("photo_asset_id", &candidate.PhotoAssetID, update.PhotoAssetID)
+	applyString("education", &candidate.Education, update.Education)
+	if update.PartyID != nil {
+		var next *uint
+		if *update.PartyID != 0 {
+			next = update.PartyID
+		}
+		record("party_id", uintValue(candidate.PartyID), uintValue(next))
+		candidate.PartyID = next
+		candidate.Party = nil
+	}
+	applyString("region", &candidate.Region, update.Region)
+	applyString("biography", &candidate.Biography, update.Biography)
+	applyString("manifesto", &candidate.Manifesto, update.Manifesto)
+
+	if update.VotingStart != nil {
+		old, next := candidate.VotingStart.Format(time.RFC3339), update.VotingStart.Format(time.RFC3339)
+		record("voting_start", &old, &next)
+		candidate.VotingStart = *update.VotingStart
+	}
+	if update.VotingDeadline != nil {
+		old, next := candidate.VotingDeadline.Format(time.RFC3339), update.VotingDeadline.Format(time.RFC3339)
+		record("voting_deadline", &old, &next)
+		candidate.VotingDeadline = *update.VotingDeadline
+	}
+	if !candidate.VotingStart.Before(candidate.VotingDeadline) {
+		return nil, domain.ValidationErrors{"voting_deadline": "must be after voting_start"}
+	}
+
+	if update.SocialLinks != nil {
+		links := make([]domain.SocialLink, len(*update.SocialLinks))
+		for i, l := range *update.SocialLinks {
+			links[i] = domain.SocialLink{
+				Platform: strings.ToLower(strings.TrimSpace(l.Platform)),
+				URL:      strings.TrimSpace(l.URL),
+			}
+		}
+		record("social_links", jsonValue(candidate.SocialLinks), jsonValue(links))
+		candidate.SocialLinks = links
+	}
+	if update.Photos != nil {
+		photos := make([]domain.CandidatePhoto, len(*update.Photos))
+		for i, u := range *update.Photos {
+			photos[i] = domain.CandidatePhoto{URL: strings.TrimSpace(u), Position: i}
+		}
+		record("photos", jsonValue(candidate.Photos), jsonValue(photos))
+		candidate.Photos = photos
+	}
+
+	if update.ManifestoPoints != nil {
+		points := make([]domain.ManifestoPoint, len(*update.ManifestoPoints))
+		for i, p := range *update.ManifestoPoints {
+			points[i] = domain.ManifestoPoint{
+				Topic:    domain.ManifestoTopic(strings.ToLower(strings.TrimSpace(string(p.Topic)))),
+				Text:     strings.TrimSpace(p.Text),
+				Position: i,
+			}
+		}
+		record("manifesto_points", jsonValue(candidate.ManifestoPoints), jsonValue(points))
+		candidate.ManifestoPoints = points
+	}
+
+	if len(changes) == 0 {
+		return candidate, nil
+	}
+
+	if err := uc.CandidateRepo.Update(ctx, candidate, changes); err != nil {
+		uc.Logger.ErrorContext(ctx, "Failed to update candidate", "candidate_id", id, logging.Err(err))
+		return nil, err
+	}
+	uc.Logger.InfoContext(ctx, "Candidate updated", "candidate_id", id, "user_id", userID, "fields_changed", len(changes))
+
+	// Reload so the response and search document carry the current party
+	if reloaded, err := uc.CandidateRepo.GetByID(ctx, id); err == nil {
+		candidate = reloaded
+	}
+
+	if uc.Indexer != nil {
+		indexed := *candidate
+		go func(ctx context.Context) {
+			if err := uc.Indexer.Index(ctx, fmt.Sprintf("%d", indexed.ID), &indexed); err != nil {
+				uc.Logger.WarnContext(ctx, "Failed to reindex candidate", "candidate_id", indexed.ID, logging.Err(err))
+			} else {
+				uc.Logger.DebugContext(ctx, "Candidate reindexed for search", "candidate_id", indexed.ID)
+			}
+		}(context.WithoutCancel(ctx))
+	}
+
+	uc.invalidateCandidateCaches(ctx, candidate.ID, candidate.Type)
+	return candidate, nil
+}
+
+// GetCandidateHistory returns the recorded profile changes of a candidate, newest first.
+func (uc *CandidateUseCase) GetCandidateHistory(ctx context.Context, id uint) ([]domain.CandidateChange, error) {
+	return uc.CandidateRepo.GetHistory(ctx, id)
+}
+
+// invalidateCandidateCaches drops the cached candidate and every cached list of its type.
+func (uc *CandidateUseCase) invalidateCandidateCaches(ctx context.Context, id uint, candidateType domain.CandidateType) {
+	uc.Cache.Invalidate(ctx, candidateTag(id), typeTag(string(candidateType)))
+}
+
+func validateCandidateUpdate(u domain.CandidateUpdate) domain.ValidationErrors {
+	errs := domain.ValidationErrors{}
+
+	if u.Name != nil {
+		name := strings.TrimSpace(*u.Name)
+		if name == "" {
+			errs["name"] = "must not be empty"
+		} else if utf8.RuneCountInString(name) > 255 {
+			errs["name"] = "must be at most 255 characters"
+		}
+	}
+	if u.Age != nil && (*u.Age < 18 || *u.Age > 120) {
+		errs["age"] = "must be between 18 and 120"
+	}
+	for field, value := range map[string]*string{"education": u.Education, "region": u.Region} {
+		if value != nil && utf8.RuneCountInString(strings.TrimSpace(*value)) > 255 {
+			errs[field] = "must be at most 255 characters"
+		}
+	}
+	if u.Photo != nil && strings.TrimSpace(*u.Photo) != "" && !isValidURL(*u.Photo) {
+		errs["photo"] = "must be an http(s) URL of at most 255 characters"
+	}
+	if u.Biography != nil && utf8.RuneCountInString(*u.Biography) > 5000 {
+		errs["biography"] = "must be at most 5000 characters"
+	}
+	if u.Manifesto != nil && utf8.RuneCountInString(*u.Manifesto) > 10000 {
+		errs["manifesto"] = "must be at most 10000 characters"
+	}
+
+	if u.SocialLinks != nil {
+		if len(*u.SocialLinks) > maxSocialLinks {
+			errs["social_links"] = fmt.Sprintf("at most %d links are allowed", maxSocialLinks)
+		}
+		for i, l := range *u.SocialLinks {
+			platform := strings.TrimSpace(l.Platform)
+			if platform == "" || utf8.RuneCountInString(platform) > 50 {
+				errs[fmt.Sprintf("social_links[%d].platform", i)] = "must be 1 to 50 characters"
+			}
+			if !isValidURL(l.URL) {
+				errs[fmt.Sprintf("social_links[%d].url", i)] = "must be an http(s) URL of at most 255 characters"
+			}
+		}
+	}
+	if u.Photos != nil {
+		if len(*u.Photos) > maxCandidatePhotos {
+			errs["photos"] = fmt.Sprintf("at most %d photos are allowed", maxCandidatePhotos)
+		}
+		for i, p := range *u.Photos {
+			if !isValidURL(p) {
+				errs[fmt.Sprintf("photos[%d]", i)] = "must be an http(s) URL of at most 255 characters"
+			}
+		}
+	}
+
+	if u.ManifestoPoints != nil {
+		if len(*u.ManifestoPoints) > maxManifestoPoints {
+			errs["manifesto_points"] = fmt.Sprintf("at most %d points are allowed", maxManifestoPoints)
+		}
+		for i, p := range *u.ManifestoPoints {
+			if !domain.IsValidManifestoTopic(strings.ToLower(strings.TrimSpace(string(p.Topic)))) {
+				errs[fmt.Sprintf("manifesto_points[%d].topic", i)] = "must be a known topic"
+			}
+			text := strings.TrimSpace(p.Text)
+			if text == "" || utf8.RuneCountInString(text) > 1000 {
+				errs[fmt.Sprintf("manifesto_points[%d].text", i)] = "must be 1 to 1000 characters"
+			}
+		}
+	}
+
+	return errs
+}
+
+func isValidURL(raw string) bool {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || len(raw) > 255 {
+		return false
+	}
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// jsonValue renders a list for the change history; nil and empty lists both become "[]".
+func jsonValue(v interface{}) *string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	s := string(data)
+	if s == "null" {
+		s = "[]"
+	}
+	return &s
+}
+
+func uintValue(v *uint) *string {
+	if v == nil {
+		return nil
+	}
+	s := strconv.FormatUint(uint64(*v), 10)
+	return &s
+}
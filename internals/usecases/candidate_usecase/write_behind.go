@@ -0,0 +1,200 @@
+package candidate_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// errWriteBehindOff is returned by the write-behind operations when the use
+// case counts votes in the vote transaction.
+var errWriteBehindOff = errors.New("write-behind voting is not enabled")
+
+// ErrVoteCounterLost is returned by FlushVotes while the counter has lost its
+// data: flushing what it still holds would leave the counts short, so the
+// votes must be recounted first.
+var ErrVoteCounterLost = errors.New("vote counter lost its data, pause voting and run `votectl votes recount`")
+
+// countVote adds a committed vote to the counter and reports whether the
+// candidate row changed now rather than at the next flush. When the counter
+// surely did not count the vote, the row is updated directly instead. When
+// that is not sure, a timeout for instance, the row is left alone rather
+// than risk counting the vote twice, and the vote waits for a recount. A
+// process that dies between the vote commit and this call also leaves the
+// vote stored but uncounted until the next recount.
+func (uc *CandidateUseCase) countVote(ctx context.Context, vote *domain.Vote) bool {
+	candidateID := vote.CandidateID
+	err := uc.Counter.Add(ctx, candidateID)
+	if err == nil {
+		return false
+	}
+	if !errors.Is(err, domain.ErrNotCounted) {
+		uc.Logger.ErrorContext(ctx, "CRITICAL: Vote saved but its count is unknown, recount the votes", "vote_id", vote.ID, "candidate_id", candidateID, logging.Err(err))
+		return false
+	}
+	uc.Logger.WarnContext(ctx, "Vote counter unavailable, updating the candidate directly", "candidate_id", candidateID, logging.Err(err))
+	if err := uc.CandidateRepo.IncrementVote(context.WithoutCancel(ctx), candidateID); err != nil {
+		uc.Logger.ErrorContext(ctx, "CRITICAL: Vote saved but not counted, recount the votes", "vote_id", vote.ID, "candidate_id", candidateID, logging.Err(err))
+		return false
+	}
+	return true
+}
+
+// FlushVotes adds one batch of counted votes to the candidates and returns
+// how many votes it added. A batch left by a flush that failed is retried
+// before new votes are claimed. Nothing is flushed while the counter is not
+// intact.
+func (uc *CandidateUseCase) FlushVotes(ctx context.Context) (int64, error) {
+	if uc.Counter == nil {
+		return 0, errWriteBehindOff
+	}
+	intact, err := uc.Counter.Intact(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !intact {
+		return 0, ErrVoteCounterLost
+	}
+	batch, err := uc.Counter.Claim(ctx)
+	if err != nil || batch == nil {
+		return 0, err
+	}
+
+	applied, err := uc.VoteCounts.ApplyVoteBatch(ctx, *batch)
+	if err != nil {
+		return 0, err
+	}
+	if err := uc.Counter.Done(ctx, batch.ID); err != nil {
+		return 0, err
+	}
+	if !applied {
+		uc.Logger.InfoContext(ctx, "Vote batch was already applied", "batch_id", batch.ID)
+		return 0, nil
+	}
+
+	var votes int64
+	ids := make([]uint, 0, len(batch.Counts))
+	for id, n := range batch.Counts {
+		ids = append(ids, id)
+		votes += n
+	}
+	uc.Logger.DebugContext(ctx, "Vote counts flushed", "batch_id", batch.ID, "candidates", len(ids), "votes", votes)
+	uc.refreshCounts(ctx, ids)
+	return votes, nil
+}
+
+// refreshCounts drops the cached candidates whose counts changed and tells
+// the live result streams of their elections.
+func (uc *CandidateUseCase) refreshCounts(ctx context.Context, ids []uint) {
+	candidates, err := uc.CandidateRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		uc.Logger.WarnContext(ctx, "Failed to load flushed candidates", logging.Err(err))
+		uc.Cache.Invalidate(ctx, candidatesTag)
+		return
+	}
+	elections := make(map[domain.CandidateType]bool)
+	for _, c := range candidates {
+		uc.Cache.Invalidate(ctx, candidateTag(c.ID))
+		elections[c.Type] = true
+	}
+	for election := range elections {
+		uc.Cache.Invalidate(ctx, typeTag(string(election)))
+		uc.publishTally(ctx, domain.TallyEvent{Election: election})
+	}
+}
+
+// RecountVotes rebuilds every candidate's count from the votes table and
+// drops the counted votes not flushed yet, which the recount includes. The
+// batch a flush may be applying meanwhile is marked applied with the recount,
+// so it is not added twice. A vote committed but not yet counted while this
+// runs is counted twice, so run it while voting is paused. It returns the
+// number of candidates corrected.
+func (uc *CandidateUseCase) RecountVotes(ctx context.Context) (int64, error) {
+	if uc.Counter == nil {
+		return 0, errWriteBehindOff
+	}
+	dropped, err := uc.Counter.Reset(ctx)
+	if err != nil {
+		return 0, err
+	}
+	corrected, err := uc.VoteCounts.RecountVotes(ctx, dropped)
+	if err != nil {
+		return 0, err
+	}
+	uc.Logger.InfoContext(ctx, "Votes recounted", "candidates_corrected", corrected, "dropped_batch", dropped)
+	uc.Cache.Invalidate(ctx, candidatesTag)
+	return corrected, nil
+}
+
+// InitVoteCounter marks a counter that never held counts intact, as it is
+// when write-behind voting is first enabled, so flushing can start without a
+// recount. That is only safe while every candidate's count matches the votes
+// table: otherwise the counter lost votes, and ErrVoteCounterLost is
+// returned.
+func (uc *CandidateUseCase) InitVoteCounter(ctx context.Context) error {
+	if uc.Counter == nil {
+		return errWriteBehindOff
+	}
+	intact, err := uc.Counter.Intact(ctx)
+	if err != nil || intact {
+		return err
+	}
+	drift, err := uc.VoteCounts.CountDrift(ctx)
+	if err != nil {
+		return err
+	}
+	if drift > 0 {
+		return fmt.Errorf("%w (%d candidates differ from the votes table)", ErrVoteCounterLost, drift)
+	}
+	intact, err = uc.Counter.Init(ctx)
+	if err != nil {
+		return err
+	}
+	if !intact {
+		// Other instances counted votes without the epoch
+		return ErrVoteCounterLost
+	}
+	uc.Logger.InfoContext(ctx, "Vote counter initialised")
+	return nil
+}
+
+// RunVoteFlusher flushes counted votes every interval until ctx is done.
+// Votes counted after the last flush stay in the counter for the next run.
+// A counter found not intact is initialised when it never held votes;
+// otherwise it is reported once and flushing resumes after the recount.
+func (uc *CandidateUseCase) RunVoteFlusher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	lost := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := uc.FlushVotes(ctx)
+		switch {
+		case errors.Is(err, ErrVoteCounterLost):
+			if lost {
+				continue
+			}
+			// A counter that never held votes, as when Redis was down at start
+			if err := uc.InitVoteCounter(ctx); err == nil {
+				continue
+			}
+			uc.Logger.ErrorContext(ctx, "CRITICAL: Vote counter lost its data, flushing stopped until `votectl votes recount`")
+			lost = true
+		case err != nil && ctx.Err() == nil:
+			uc.Logger.WarnContext(ctx, "Vote count flush failed", logging.Err(err))
+		case err == nil:
+			if lost {
+				uc.Logger.InfoContext(ctx, "Vote counter recounted, flushing resumed")
+			}
+			lost = false
+		}
+	}
+}
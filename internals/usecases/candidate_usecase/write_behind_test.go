@@ -0,0 +1,288 @@
+package candidate_usecase
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/fakes"
+	"VoteGolang/internals/infrastructure/votecount"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// writeBehind switches uc to write-behind voting and returns its counter.
+func writeBehind(t *testing.T, uc *CandidateUseCase, d *fakes.Deps) *votecount.Memory {
+	t.Helper()
+	counter := votecount.NewMemory()
+	uc.Counter = counter
+	uc.VoteCounts = d.VoteCounts
+	if _, err := uc.RecountVotes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return counter
+}
+
+func TestWriteBehindVoteCountedOnFlush(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Presidential), openCandidate(2, domain.Presidential))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	counter := writeBehind(t, uc, d)
+	ctx := context.Background()
+
+	if _, err := uc.GetCandidateByID(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	for userID := uint(1); userID <= 3; userID++ {
+		if err := uc.Vote(ctx, 1+userID%2, userID, domain.Presidential); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if c, _ := d.Candidates.GetByID(ctx, 2); c.Votes != 0 {
+		t.Fatalf("candidate has %d votes before the flush, want 0", c.Votes)
+	}
+	if got := counter.Pending(); got[1] != 1 || got[2] != 2 {
+		t.Fatalf("pending counts = %v, want 1:1 2:2", got)
+	}
+
+	flushed, err := uc.FlushVotes(ctx)
+	if err != nil || flushed != 3 {
+		t.Fatalf("FlushVotes = %d, %v, want 3", flushed, err)
+	}
+	if c, _ := uc.GetCandidateByID(ctx, 1); c.Votes != 1 {
+		t.Fatalf("cached candidate has %d votes after the flush, want 1", c.Votes)
+	}
+	if c, _ := d.Candidates.GetByID(ctx, 2); c.Votes != 2 {
+		t.Fatalf("candidate has %d votes after the flush, want 2", c.Votes)
+	}
+	if flushed, err := uc.FlushVotes(ctx); err != nil || flushed != 0 {
+		t.Fatalf("second FlushVotes = %d, %v, want 0", flushed, err)
+	}
+}
+
+func TestFlushVotesAppliesBatchOnce(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Deputy))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	writeBehind(t, uc, d)
+	ctx := context.Background()
+
+	if err := uc.Vote(ctx, 1, 1, domain.Deputy); err != nil {
+		t.Fatal(err)
+	}
+	// A flush that applied the batch but failed before Done leaves it claimed
+	batch, err := uc.Counter.Claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.VoteCounts.ApplyVoteBatch(ctx, *batch); err != nil {
+		t.Fatal(err)
+	}
+
+	if flushed, err := uc.FlushVotes(ctx); err != nil || flushed != 0 {
+		t.Fatalf("FlushVotes = %d, %v, want the applied batch skipped", flushed, err)
+	}
+	if c, _ := d.Candidates.GetByID(ctx, 1); c.Votes != 1 {
+		t.Fatalf("candidate votes = %d, want 1", c.Votes)
+	}
+}
+
+func TestFlushVotesWaitsForRecountOfLostCounter(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Manager), openCandidate(2, domain.Manager))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	writeBehind(t, uc, d)
+	ctx := context.Background()
+
+	for userID := uint(1); userID <= 4; userID++ {
+		if err := uc.Vote(ctx, 1+userID%2, userID, domain.Manager); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Redis restarted without its data and took the next vote
+	uc.Counter = votecount.NewMemory()
+	if err := uc.Vote(ctx, 1, 5, domain.Manager); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.FlushVotes(ctx); !errors.Is(err, ErrVoteCounterLost) {
+		t.Fatalf("FlushVotes on a lost counter = %v, want %v", err, ErrVoteCounterLost)
+	}
+	if c, _ := d.Candidates.GetByID(ctx, 1); c.Votes != 0 {
+		t.Fatalf("candidate has %d votes after a refused flush, want 0", c.Votes)
+	}
+
+	if _, err := uc.RecountVotes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[uint]int{1: 3, 2: 2} {
+		if c, _ := uc.GetCandidateByID(ctx, id); c.Votes != want {
+			t.Fatalf("candidate %d has %d votes after the recount, want %d", id, c.Votes, want)
+		}
+	}
+	if flushed, err := uc.FlushVotes(ctx); err != nil || flushed != 0 {
+		t.Fatalf("FlushVotes after the recount = %d, %v, want 0", flushed, err)
+	}
+}
+
+func TestInitVoteCounter(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) (*CandidateUseCase, *fakes.Deps) {
+		t.Helper()
+		d := fakes.NewDeps()
+		d.AddCandidates(openCandidate(1, domain.Deputy))
+		uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+		// Votes counted in the vote transaction before write-behind was enabled
+		if err := uc.Vote(ctx, 1, 1, domain.Deputy); err != nil {
+			t.Fatal(err)
+		}
+		uc.Counter = votecount.NewMemory()
+		uc.VoteCounts = d.VoteCounts
+		return uc, d
+	}
+
+	t.Run("first enabled", func(t *testing.T) {
+		uc, d := setup(t)
+		if err := uc.InitVoteCounter(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := uc.Vote(ctx, 1, 2, domain.Deputy); err != nil {
+			t.Fatal(err)
+		}
+		if flushed, err := uc.FlushVotes(ctx); err != nil || flushed != 1 {
+			t.Fatalf("FlushVotes = %d, %v, want 1", flushed, err)
+		}
+		if c, _ := d.Candidates.GetByID(ctx, 1); c.Votes != 2 {
+			t.Fatalf("candidate votes = %d, want 2", c.Votes)
+		}
+	})
+
+	t.Run("votes lost", func(t *testing.T) {
+		uc, _ := setup(t)
+		if err := uc.InitVoteCounter(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if err := uc.Vote(ctx, 1, 2, domain.Deputy); err != nil {
+			t.Fatal(err)
+		}
+		// Redis restarted without the vote
+		uc.Counter = votecount.NewMemory()
+		if err := uc.InitVoteCounter(ctx); !errors.Is(err, ErrVoteCounterLost) {
+			t.Fatalf("InitVoteCounter = %v, want %v", err, ErrVoteCounterLost)
+		}
+	})
+
+	t.Run("counted without the epoch", func(t *testing.T) {
+		uc, _ := setup(t)
+		if err := uc.Counter.Add(ctx, 1); err != nil {
+			t.Fatal(err)
+		}
+		if err := uc.InitVoteCounter(ctx); !errors.Is(err, ErrVoteCounterLost) {
+			t.Fatalf("InitVoteCounter = %v, want %v", err, ErrVoteCounterLost)
+		}
+	})
+}
+
+func TestRecountVotesExcludesTheBatchBeingFlushed(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Deputy))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	writeBehind(t, uc, d)
+	ctx := context.Background()
+
+	for userID := uint(1); userID <= 2; userID++ {
+		if err := uc.Vote(ctx, 1, userID, domain.Deputy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Another instance claimed the batch and applies it after the recount
+	batch, err := uc.Counter.Claim(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.RecountVotes(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if applied, err := uc.VoteCounts.ApplyVoteBatch(ctx, *batch); err != nil || applied {
+		t.Fatalf("ApplyVoteBatch after the recount = %t, %v, want the batch skipped", applied, err)
+	}
+	if c, _ := d.Candidates.GetByID(ctx, 1); c.Votes != 2 {
+		t.Fatalf("candidate votes = %d, want 2", c.Votes)
+	}
+}
+
+// failingCounter fails every Add with err.
+type failingCounter struct {
+	*votecount.Memory
+	err error
+}
+
+func (c failingCounter) Add(context.Context, uint) error { return c.err }
+
+func TestWriteBehindVoteUpdatesTheRowOnlyWhenTheCounterSurelyMissedIt(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		err       error
+		wantVotes int
+	}{
+		{"connection refused", fmt.Errorf("%w: dial tcp: connection refused", domain.ErrNotCounted), 1},
+		{"timeout", errors.New("i/o timeout"), 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			d := fakes.NewDeps()
+			d.AddCandidates(openCandidate(1, domain.Deputy))
+			uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+			counter := writeBehind(t, uc, d)
+			uc.Counter = failingCounter{Memory: counter, err: tc.err}
+			ctx := context.Background()
+
+			if err := uc.Vote(ctx, 1, 1, domain.Deputy); err != nil {
+				t.Fatal(err)
+			}
+			if c, _ := d.Candidates.GetByID(ctx, 1); c.Votes != tc.wantVotes {
+				t.Fatalf("candidate votes = %d, want %d", c.Votes, tc.wantVotes)
+			}
+			if _, err := uc.RecountVotes(ctx); err != nil {
+				t.Fatal(err)
+			}
+			if c, _ := d.Candidates.GetByID(ctx, 1); c.Votes != 1 {
+				t.Fatalf("candidate votes after the recount = %d, want 1", c.Votes)
+			}
+		})
+	}
+}
+
+func TestWriteBehindVoteManyUsersConcurrently(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddCandidates(openCandidate(1, domain.Manager))
+	uc := NewCandidateUseCase(d.Candidates, d.Votes, d.Blockchain, d.Cache, nil, nil, nil, nil, d.Logger)
+	writeBehind(t, uc, d)
+	ctx := context.Background()
+
+	const users = 100
+	var wg sync.WaitGroup
+	for userID := uint(1); userID <= users; userID++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			if err := uc.Vote(ctx, 1, userID, domain.Manager); err != nil {
+				t.Errorf("user %d: %v", userID, err)
+			}
+			if userID%10 == 0 {
+				if _, err := uc.FlushVotes(ctx); err != nil {
+					t.Errorf("flush: %v", err)
+				}
+			}
+		}(userID)
+	}
+	wg.Wait()
+	if _, err := uc.FlushVotes(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	c, _ := d.Candidates.GetByID(ctx, 1)
+	if c.Votes != users {
+		t.Fatalf("candidate votes = %d, want %d", c.Votes, users)
+	}
+}
@@ -0,0 +1,344 @@
+package comment_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// editWindow is how long after posting an author may still edit a comment.
+	editWindow = 15 * time.Minute
+	// maxCommentLength is the maximum comment length in characters.
+	maxCommentLength = 2000
+	// commentRateLimit is how many comments a user may post per commentRateWindow.
+	commentRateLimit  = 5
+	commentRateWindow = time.Minute
+)
+
+var (
+	ErrRateLimited      = errors.New("too many comments, please slow down")
+	ErrEditWindowClosed = errors.New("comment can no longer be edited")
+	ErrNotCommentAuthor = errors.New("only the author can change this comment")
+)
+
+// CommentUseCase manages petition discussion threads.
+type CommentUseCase interface {
+	CreateComment(ctx context.Context, c *domain.PetitionComment) error
+	ListComments(ctx context.Context, petitionID uint, parentID *uint, cursor string, limit int) (*domain.CommentPage, error)
+	EditComment(ctx context.Context, id, userID uint, body string) (*domain.PetitionComment, error)
+	DeleteComment(ctx context.Context, id, userID uint) error
+	HideComment(ctx context.Context, id uint) error
+	RestoreComment(ctx context.Context, id uint) error
+	ReportComment(ctx context.Context, id, userID uint, reason string) error
+	GetReportedComments(ctx context.Context, limit, offset int) ([]domain.ReportedComment, error)
+	// Reindex indexes every visible comment for search again.
+	Reindex(ctx context.Context) (int, error)
+}
+
+type commentUseCase struct {
+	commentRepo  domain.PetitionCommentRepository
+	petitionRepo domain.PetitionRepository
+	cache        domain.Cache
+	indexer      domain.SearchIndexer
+	logger       *slog.Logger
+}
+
+func NewCommentUseCase(
+	cr domain.PetitionCommentRepository,
+	pr domain.PetitionRepository,
+	cache domain.Cache,
+	indexer domain.SearchIndexer,
+	logger *slog.Logger,
+) CommentUseCase {
+	return &commentUseCase{
+		commentRepo:  cr,
+		petitionRepo: pr,
+		cache:        cache,
+		indexer:      indexer,
+		logger:       logger,
+	}
+}
+
+func (uc *commentUseCase) CreateComment(ctx context.Context, c *domain.PetitionComment) error {
+	body, err := validateBody(c.Body)
+	if err != nil {
+		return err
+	}
+	c.Body = body
+
+	petition, err := uc.petitionRepo.GetByID(ctx, c.PetitionID)
+	if err != nil {
+		return fmt.Errorf("petition not found: %w", err)
+	}
+	if petition.Status != domain.PetitionApproved {
+		return errors.New("petition is not open for discussion")
+	}
+
+	if c.ParentID != nil {
+		parent, err := uc.commentRepo.GetByID(ctx, *c.ParentID)
+		if err != nil {
+			return fmt.Errorf("parent comment not found: %w", err)
+		}
+		if parent.PetitionID != c.PetitionID {
+			return errors.New("parent comment belongs to another petition")
+		}
+	}
+
+	if err := uc.checkRateLimit(ctx, c.UserID); err != nil {
+		return err
+	}
+
+	c.Hidden = false
+	c.EditedAt = nil
+	if err := uc.commentRepo.Create(ctx, c); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to create comment", "petition_id", c.PetitionID, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Comment created", "comment_id", c.ID, "petition_id", c.PetitionID, "user_id", c.UserID)
+
+	uc.index(ctx, c)
+	return nil
+}
+
+func (uc *commentUseCase) ListComments(ctx context.Context, petitionID uint, parentID *uint, cursor string, limit int) (*domain.CommentPage, error) {
+	beforeID, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// Discussions are as visible as their petition
+	petition, err := uc.petitionRepo.GetByID(ctx, petitionID)
+	if err == nil && petition.Status != domain.PetitionApproved {
+		err = gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("petition not found: %w", err)
+	}
+
+	comments, err := uc.commentRepo.List(ctx, petitionID, parentID, beforeID, limit)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to list comments", "petition_id", petitionID, logging.Err(err))
+		return nil, err
+	}
+
+	page := &domain.CommentPage{Comments: comments}
+	if len(comments) == limit {
+		page.NextCursor = encodeCursor(comments[len(comments)-1].ID)
+	}
+	return page, nil
+}
+
+func (uc *commentUseCase) EditComment(ctx context.Context, id, userID uint, body string) (*domain.PetitionComment, error) {
+	body, err := validateBody(body)
+	if err != nil {
+		return nil, err
+	}
+
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, ErrNotCommentAuthor
+	}
+	if time.Since(comment.CreatedAt) > editWindow {
+		return nil, ErrEditWindowClosed
+	}
+
+	now := time.Now()
+	if err := uc.commentRepo.UpdateBody(ctx, id, body, now); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to edit comment", "comment_id", id, logging.Err(err))
+		return nil, err
+	}
+	comment.Body = body
+	comment.EditedAt = &now
+	uc.logger.InfoContext(ctx, "Comment edited", "comment_id", id, "user_id", userID)
+
+	if !comment.Hidden {
+		uc.index(ctx, comment)
+	}
+	return comment, nil
+}
+
+func (uc *commentUseCase) DeleteComment(ctx context.Context, id, userID uint) error {
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		return ErrNotCommentAuthor
+	}
+
+	if err := uc.commentRepo.Delete(ctx, id); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to delete comment", "comment_id", id, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Comment deleted", "comment_id", id, "user_id", userID)
+
+	uc.unindex(ctx, id)
+	return nil
+}
+
+func (uc *commentUseCase) HideComment(ctx context.Context, id uint) error {
+	if _, err := uc.commentRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+	if err := uc.commentRepo.SetHidden(ctx, id, true); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to hide comment", "comment_id", id, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Comment hidden", "comment_id", id)
+
+	uc.unindex(ctx, id)
+	return nil
+}
+
+func (uc *commentUseCase) RestoreComment(ctx context.Context, id uint) error {
+	comment, err := uc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := uc.commentRepo.SetHidden(ctx, id, false); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to restore comment", "comment_id", id, logging.Err(err))
+		return err
+	}
+	comment.Hidden = false
+	uc.logger.InfoContext(ctx, "Comment restored", "comment_id", id)
+
+	uc.index(ctx, comment)
+	return nil
+}
+
+func (uc *commentUseCase) ReportComment(ctx context.Context, id, userID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to report a comment")
+	}
+	if utf8.RuneCountInString(reason) > 255 {
+		return errors.New("reason must be at most 255 characters")
+	}
+
+	if _, err := uc.commentRepo.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	report := &domain.CommentReport{CommentID: id, UserID: userID, Reason: reason}
+	if err := uc.commentRepo.Report(ctx, report); err != nil {
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Comment reported", "comment_id", id, "user_id", userID, "reason", reason)
+	return nil
+}
+
+func (uc *commentUseCase) GetReportedComments(ctx context.Context, limit, offset int) ([]domain.ReportedComment, error) {
+	return uc.commentRepo.GetReported(ctx, limit, offset)
+}
+
+// checkRateLimit counts comments per user in a fixed cache window. Cache
+// failures let the comment through rather than blocking discussion.
+func (uc *commentUseCase) checkRateLimit(ctx context.Context, userID uint) error {
+	key := fmt.Sprintf("ratelimit:comment:%d", userID)
+
+	count, err := uc.cache.Increment(ctx, key, commentRateWindow)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "Comment rate limit check failed", "user_id", userID, logging.Err(err))
+		return nil
+	}
+	if count > commentRateLimit {
+		return ErrRateLimited
+	}
+	return nil
+}
+
+func (uc *commentUseCase) index(ctx context.Context, c *domain.PetitionComment) {
+	if uc.indexer == nil {
+		return
+	}
+	comment := *c
+	go func(ctx context.Context) {
+		id := fmt.Sprintf("%d", comment.ID)
+		if err := uc.indexer.Index(ctx, id, comment); err != nil {
+			uc.logger.WarnContext(ctx, "Failed to index comment", "comment_id", comment.ID, logging.Err(err))
+		} else {
+			uc.logger.DebugContext(ctx, "Comment indexed for search", "comment_id", comment.ID)
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+func (uc *commentUseCase) Reindex(ctx context.Context) (int, error) {
+	if uc.indexer == nil {
+		return 0, errors.New("search is not configured")
+	}
+	const batch = 500
+	var indexed int
+	var afterID uint
+	for {
+		comments, err := uc.commentRepo.ListVisible(ctx, afterID, batch)
+		if err != nil {
+			return indexed, err
+		}
+		for _, c := range comments {
+			if err := uc.indexer.Index(ctx, fmt.Sprintf("%d", c.ID), c); err != nil {
+				return indexed, fmt.Errorf("index comment %d: %w", c.ID, err)
+			}
+			indexed++
+			afterID = c.ID
+		}
+		if len(comments) < batch {
+			return indexed, nil
+		}
+	}
+}
+
+func (uc *commentUseCase) unindex(ctx context.Context, id uint) {
+	if uc.indexer == nil {
+		return
+	}
+	go func(ctx context.Context) {
+		if err := uc.indexer.Delete(ctx, fmt.Sprintf("%d", id)); err != nil {
+			uc.logger.WarnContext(ctx, "Failed to remove comment from search", "comment_id", id, logging.Err(err))
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+func validateBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", errors.New("comment body is required")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", fmt.Errorf("comment must be at most %d characters", maxCommentLength)
+	}
+	return body, nil
+}
+
+// Cursors are opaque to clients; they wrap the ID of the last comment returned.
+func encodeCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeCursor(cursor string) (uint, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, errors.New("invalid cursor")
+	}
+	return uint(id), nil
+}
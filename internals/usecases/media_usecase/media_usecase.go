@@ -0,0 +1,132 @@
+package media_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/storage"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrAssetNotFound = errors.New("asset not found")
+
+// MediaUseCase stores uploaded images and hands out signed links to them.
+type MediaUseCase interface {
+	Upload(ctx context.Context, userID uint, data []byte) (*domain.AssetURLs, error)
+	GetAsset(ctx context.Context, id string) (*domain.AssetURLs, error)
+}
+
+type mediaUseCase struct {
+	assetRepo domain.AssetRepository
+	storage   domain.MediaStorage
+	urlTTL    time.Duration
+	logger    *slog.Logger
+}
+
+func NewMediaUseCase(ar domain.AssetRepository, ms domain.MediaStorage, urlTTL time.Duration, logger *slog.Logger) MediaUseCase {
+	return &mediaUseCase{
+		assetRepo: ar,
+		storage:   ms,
+		urlTTL:    urlTTL,
+		logger:    logger,
+	}
+}
+
+func (uc *mediaUseCase) Upload(ctx context.Context, userID uint, data []byte) (*domain.AssetURLs, error) {
+	img, err := storage.ProcessImage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	id := uuid.NewString()
+	asset := &domain.Asset{
+		ID:           id,
+		UserID:       userID,
+		StorageKey:   fmt.Sprintf("assets/%s/original.%s", id, img.Extension),
+		ThumbnailKey: fmt.Sprintf("assets/%s/thumb.%s", id, thumbnailExtension(img.ThumbnailType)),
+		ContentType:  img.ContentType,
+		Size:         int64(len(img.Data)),
+		Width:        img.Width,
+		Height:       img.Height,
+	}
+
+	if err := uc.storage.Put(ctx, asset.StorageKey, bytes.NewReader(img.Data), asset.Size, img.ContentType); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to store asset", "asset_id", id, logging.Err(err))
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+	if err := uc.storage.Put(ctx, asset.ThumbnailKey, bytes.NewReader(img.Thumbnail), int64(len(img.Thumbnail)), img.ThumbnailType); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to store thumbnail", "asset_id", id, logging.Err(err))
+		uc.cleanup(ctx, asset.StorageKey)
+		return nil, fmt.Errorf("failed to store thumbnail: %w", err)
+	}
+
+	if err := uc.assetRepo.Create(ctx, asset); err != nil {
+		uc.cleanup(ctx, asset.StorageKey, asset.ThumbnailKey)
+		return nil, fmt.Errorf("failed to save asset: %w", err)
+	}
+
+	uc.logger.InfoContext(ctx, "Asset uploaded", "user_id", userID, "asset_id", id, "content_type", asset.ContentType, "bytes", asset.Size)
+	return uc.signedURLs(asset)
+}
+
+func (uc *mediaUseCase) GetAsset(ctx context.Context, id string) (*domain.AssetURLs, error) {
+	asset, err := uc.getAsset(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return uc.signedURLs(asset)
+}
+
+func (uc *mediaUseCase) getAsset(ctx context.Context, id string) (*domain.Asset, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrAssetNotFound
+	}
+	asset, err := uc.assetRepo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAssetNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+func (uc *mediaUseCase) signedURLs(asset *domain.Asset) (*domain.AssetURLs, error) {
+	url, err := uc.storage.SignedURL(asset.StorageKey, uc.urlTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign url: %w", err)
+	}
+	thumbURL, err := uc.storage.SignedURL(asset.ThumbnailKey, uc.urlTTL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign url: %w", err)
+	}
+	return &domain.AssetURLs{
+		Asset:        *asset,
+		URL:          url,
+		ThumbnailURL: thumbURL,
+		ExpiresAt:    time.Now().Add(uc.urlTTL),
+	}, nil
+}
+
+// cleanup removes files of an upload that could not be completed.
+func (uc *mediaUseCase) cleanup(ctx context.Context, keys ...string) {
+	for _, key := range keys {
+		if err := uc.storage.Delete(ctx, key); err != nil {
+			uc.logger.WarnContext(ctx, "Failed to remove orphaned media", "key", key, logging.Err(err))
+		}
+	}
+}
+
+func thumbnailExtension(contentType string) string {
+	if contentType == "image/jpeg" {
+		return "jpg"
+	}
+	return "png"
+}
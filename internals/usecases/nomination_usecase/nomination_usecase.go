@@ -0,0 +1,268 @@
+package nomination_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// maxNominationDocuments is how many supporting documents a nomination may carry.
+	maxNominationDocuments = 10
+	// minEndorserAge is the age an endorser must have reached, when known.
+	minEndorserAge = 18
+)
+
+var (
+	ErrNominationNotFound = errors.New("nomination not found")
+	ErrNotEligible        = errors.New("user is not eligible to endorse nominations")
+	ErrAlreadyEndorsed    = errors.New("user has already endorsed this nomination")
+	ErrNotCollecting      = domain.ErrNominationNotCollecting
+)
+
+// CandidatePublisher runs the follow-ups of a newly stored candidate.
+type CandidatePublisher interface {
+	PublishCandidate(ctx context.Context, candidate *domain.Candidate)
+}
+
+// NominationUseCase drives nominations from submission through endorsement
+// to an official's decision.
+type NominationUseCase interface {
+	Submit(ctx context.Context, n *domain.Nomination) error
+	GetByID(ctx context.Context, id uint) (*domain.Nomination, error)
+	List(ctx context.Context, status domain.NominationStatus, limit, offset int) ([]domain.Nomination, error)
+	Endorse(ctx context.Context, nominationID, userID uint) (*domain.Nomination, error)
+	Approve(ctx context.Context, nominationID, reviewerID uint) (*domain.Candidate, error)
+	Reject(ctx context.Context, nominationID, reviewerID uint, reason string) (*domain.Nomination, error)
+}
+
+type nominationUseCase struct {
+	nominationRepo domain.NominationRepository
+	userRepo       domain.UserRepository
+	assetRepo      domain.AssetRepository
+	partyRepo      domain.PartyRepository
+	candidates     CandidatePublisher
+	logger         *slog.Logger
+}
+
+func NewNominationUseCase(
+	nr domain.NominationRepository,
+	ur domain.UserRepository,
+	ar domain.AssetRepository,
+	pr domain.PartyRepository,
+	candidates CandidatePublisher,
+	logger *slog.Logger,
+) NominationUseCase {
+	return &nominationUseCase{
+		nominationRepo: nr,
+		userRepo:       ur,
+		assetRepo:      ar,
+		partyRepo:      pr,
+		candidates:     candidates,
+		logger:         logger,
+	}
+}
+
+func (uc *nominationUseCase) Submit(ctx context.Context, n *domain.Nomination) error {
+	if errs := uc.validate(ctx, n); len(errs) > 0 {
+		return errs
+	}
+
+	n.Name = strings.TrimSpace(n.Name)
+	n.Status = domain.NominationCollecting
+	n.Endorsements = 0
+	n.RequiredEndorsements = domain.RequiredEndorsements(n.Type)
+	n.RejectReason = nil
+	n.ReviewerID = nil
+	n.ReviewedAt = nil
+	n.CandidateID = nil
+
+	if err := uc.nominationRepo.Create(ctx, n); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to create nomination", logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Nomination submitted",
+		"nomination_id", n.ID, "name", n.Name, "user_id", n.UserID, "required_endorsements", n.RequiredEndorsements)
+	return nil
+}
+
+func (uc *nominationUseCase) GetByID(ctx context.Context, id uint) (*domain.Nomination, error) {
+	n, err := uc.nominationRepo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNominationNotFound
+	}
+	return n, err
+}
+
+func (uc *nominationUseCase) List(ctx context.Context, status domain.NominationStatus, limit, offset int) ([]domain.Nomination, error) {
+	return uc.nominationRepo.GetAllPaginated(ctx, status, limit, offset)
+}
+
+// Endorse adds userID's signature to a nomination. Only users with a
+// verified email who are of age may endorse, and not their own nomination.
+func (uc *nominationUseCase) Endorse(ctx context.Context, nominationID, userID uint) (*domain.Nomination, error) {
+	n, err := uc.GetByID(ctx, nominationID)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != domain.NominationCollecting {
+		return nil, ErrNotCollecting
+	}
+	if n.UserID == userID {
+		return nil, fmt.Errorf("%w: nominators cannot endorse their own nomination", ErrNotEligible)
+	}
+
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, ErrNotEligible
+	}
+	if !user.EmailVerified {
+		return nil, fmt.Errorf("%w: email is not verified", ErrNotEligible)
+	}
+	if user.BirthDate != nil && user.BirthDate.AddDate(minEndorserAge, 0, 0).After(time.Now()) {
+		return nil, fmt.Errorf("%w: endorsers must be at least %d", ErrNotEligible, minEndorserAge)
+	}
+
+	// The repository checks the status again under a lock: the nomination
+	// may have been submitted or rejected since it was read
+	created, err := uc.nominationRepo.Endorse(ctx, nominationID, userID)
+	if errors.Is(err, ErrNotCollecting) {
+		return nil, err
+	}
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to endorse nomination", "nomination_id", nominationID, "user_id", userID, logging.Err(err))
+		return nil, err
+	}
+	if !created {
+		return nil, ErrAlreadyEndorsed
+	}
+	uc.logger.InfoContext(ctx, "Nomination endorsed", "nomination_id", nominationID, "user_id", userID)
+
+	return uc.GetByID(ctx, nominationID)
+}
+
+// Approve turns a nomination that has collected its endorsements into a
+// candidate of the election it was submitted for.
+func (uc *nominationUseCase) Approve(ctx context.Context, nominationID, reviewerID uint) (*domain.Candidate, error) {
+	n, err := uc.GetByID(ctx, nominationID)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != domain.NominationSubmitted {
+		return nil, fmt.Errorf("nomination has %d of %d endorsements and cannot be approved yet",
+			n.Endorsements, n.RequiredEndorsements)
+	}
+
+	candidate := &domain.Candidate{
+		Name:           n.Name,
+		PhotoAssetID:   n.PhotoAssetID,
+		Education:      n.Education,
+		Age:            n.Age,
+		PartyID:        n.PartyID,
+		Region:         n.Region,
+		Biography:      n.Biography,
+		Manifesto:      n.Manifesto,
+		Type:           n.Type,
+		VotingStart:    n.VotingStart,
+		VotingDeadline: n.VotingDeadline,
+	}
+	err = uc.nominationRepo.Approve(ctx, n, reviewerID, candidate, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.CandidateCreated{
+			CandidateID:    candidate.ID,
+			Name:           candidate.Name,
+			Type:           candidate.Type,
+			PartyID:        candidate.PartyID,
+			Region:         candidate.Region,
+			VotingStart:    candidate.VotingStart,
+			VotingDeadline: candidate.VotingDeadline,
+		})
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to approve nomination", "nomination_id", nominationID, logging.Err(err))
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "Nomination approved", "nomination_id", nominationID, "reviewer_id", reviewerID, "candidate_id", candidate.ID)
+
+	uc.candidates.PublishCandidate(ctx, candidate)
+	return candidate, nil
+}
+
+func (uc *nominationUseCase) Reject(ctx context.Context, nominationID, reviewerID uint, reason string) (*domain.Nomination, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to reject a nomination")
+	}
+
+	n, err := uc.GetByID(ctx, nominationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.nominationRepo.Reject(ctx, n, reviewerID, reason); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to reject nomination", "nomination_id", nominationID, logging.Err(err))
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "Nomination rejected", "nomination_id", nominationID, "reviewer_id", reviewerID, "reason", reason)
+	return n, nil
+}
+
+func (uc *nominationUseCase) validate(ctx context.Context, n *domain.Nomination) domain.ValidationErrors {
+	errs := domain.ValidationErrors{}
+
+	name := strings.TrimSpace(n.Name)
+	if name == "" {
+		errs["name"] = "must not be empty"
+	} else if utf8.RuneCountInString(name) > 255 {
+		errs["name"] = "must be at most 255 characters"
+	}
+	if n.Age < 18 || n.Age > 120 {
+		errs["age"] = "must be between 18 and 120"
+	}
+	if !domain.IsValidCandidateType(string(n.Type)) {
+		errs["type"] = "must be one of presidential, deputy, manager"
+	}
+	if !n.VotingStart.Before(n.VotingDeadline) {
+		errs["voting_deadline"] = "must be after voting_start"
+	} else if n.VotingDeadline.Before(time.Now()) {
+		errs["voting_deadline"] = "must be in the future"
+	}
+	if n.PartyID != nil {
+		if _, err := uc.partyRepo.GetByID(ctx, *n.PartyID); err != nil {
+			errs["party_id"] = "must refer to an existing party"
+		}
+	}
+	if n.PhotoAssetID != nil && !uc.ownsAsset(ctx, n.UserID, *n.PhotoAssetID) {
+		errs["photo_asset_id"] = "must refer to an asset you uploaded"
+	}
+
+	if len(n.Documents) == 0 {
+		errs["documents"] = "at least one supporting document is required"
+	} else if len(n.Documents) > maxNominationDocuments {
+		errs["documents"] = fmt.Sprintf("at most %d documents are allowed", maxNominationDocuments)
+	}
+	for i, d := range n.Documents {
+		title := strings.TrimSpace(d.Title)
+		if title == "" || utf8.RuneCountInString(title) > 255 {
+			errs[fmt.Sprintf("documents[%d].title", i)] = "must be 1 to 255 characters"
+		}
+		if !uc.ownsAsset(ctx, n.UserID, d.AssetID) {
+			errs[fmt.Sprintf("documents[%d].asset_id", i)] = "must refer to an asset you uploaded"
+		}
+		n.Documents[i].ID = 0
+		n.Documents[i].Title = title
+	}
+
+	return errs
+}
+
+func (uc *nominationUseCase) ownsAsset(ctx context.Context, userID uint, assetID string) bool {
+	asset, err := uc.assetRepo.GetByID(ctx, assetID)
+	return err == nil && asset.UserID == userID
+}
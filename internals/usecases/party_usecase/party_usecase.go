@@ -0,0 +1,217 @@
+package party_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/cache"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPartyNotFound = errors.New("party not found")
+	ErrPartyExists   = errors.New("a party with this name already exists")
+	ErrPartyInUse    = errors.New("party still has candidates")
+)
+
+// PartyUseCase manages parties and party-level views of the elections.
+type PartyUseCase interface {
+	CreateParty(ctx context.Context, p *domain.Party) error
+	GetParty(ctx context.Context, id uint) (*domain.Party, error)
+	GetAllParties(ctx context.Context) ([]domain.Party, error)
+	UpdateParty(ctx context.Context, p *domain.Party) (*domain.Party, error)
+	DeleteParty(ctx context.Context, id uint) error
+	GetResults(ctx context.Context, candidateType domain.CandidateType) ([]domain.PartyResult, error)
+	// GetFacets returns the number of candidates per party, taken from the
+	// Elasticsearch aggregation when available and from the database otherwise.
+	GetFacets(ctx context.Context) (map[string]int64, error)
+}
+
+type partyUseCase struct {
+	partyRepo domain.PartyRepository
+	assetRepo domain.AssetRepository
+	indexer   domain.SearchIndexer
+	cache     *cache.Aside
+	logger    *slog.Logger
+}
+
+func NewPartyUseCase(
+	pr domain.PartyRepository,
+	ar domain.AssetRepository,
+	indexer domain.SearchIndexer,
+	store domain.Cache,
+	logger *slog.Logger,
+) PartyUseCase {
+	return &partyUseCase{
+		partyRepo: pr,
+		assetRepo: ar,
+		indexer:   indexer,
+		cache:     cache.NewAside(store, gorm.ErrRecordNotFound, logger),
+		logger:    logger,
+	}
+}
+
+func (uc *partyUseCase) CreateParty(ctx context.Context, p *domain.Party) error {
+	if errs := uc.validate(ctx, p); len(errs) > 0 {
+		return errs
+	}
+	if _, err := uc.partyRepo.GetByNameKey(ctx, p.NameKey); err == nil {
+		return ErrPartyExists
+	}
+
+	p.ID = 0
+	if err := uc.partyRepo.Create(ctx, p); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to create party", "party_name", p.Name, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Party created", "party_id", p.ID, "party_name", p.Name)
+	return nil
+}
+
+func (uc *partyUseCase) GetParty(ctx context.Context, id uint) (*domain.Party, error) {
+	p, err := uc.partyRepo.GetByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPartyNotFound
+	}
+	return p, err
+}
+
+func (uc *partyUseCase) GetAllParties(ctx context.Context) ([]domain.Party, error) {
+	return uc.partyRepo.GetAll(ctx)
+}
+
+// UpdateParty replaces a party's details. Its candidates are reindexed and
+// their cached copies dropped, since they embed the party.
+func (uc *partyUseCase) UpdateParty(ctx context.Context, p *domain.Party) (*domain.Party, error) {
+	existing, err := uc.GetParty(ctx, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if errs := uc.validate(ctx, p); len(errs) > 0 {
+		return nil, errs
+	}
+	if other, err := uc.partyRepo.GetByNameKey(ctx, p.NameKey); err == nil && other.ID != p.ID {
+		return nil, ErrPartyExists
+	}
+
+	p.CreatedAt = existing.CreatedAt
+	if err := uc.partyRepo.Update(ctx, p); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to update party", "party_id", p.ID, logging.Err(err))
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "Party updated", "party_id", p.ID)
+
+	uc.refreshCandidates(ctx, p.ID)
+	return uc.GetParty(ctx, p.ID)
+}
+
+func (uc *partyUseCase) DeleteParty(ctx context.Context, id uint) error {
+	if _, err := uc.GetParty(ctx, id); err != nil {
+		return err
+	}
+	count, err := uc.partyRepo.CountCandidates(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w (%d)", ErrPartyInUse, count)
+	}
+
+	if err := uc.partyRepo.Delete(ctx, id); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to delete party", "party_id", id, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Party deleted", "party_id", id)
+	return nil
+}
+
+// GetResults sums the votes of each party's candidates in one election and
+// computes each party's share of the total.
+func (uc *partyUseCase) GetResults(ctx context.Context, candidateType domain.CandidateType) ([]domain.PartyResult, error) {
+	results, err := uc.partyRepo.Results(ctx, candidateType)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, r := range results {
+		total += r.Votes
+	}
+	if total > 0 {
+		for i := range results {
+			results[i].Share = float64(results[i].Votes) / float64(total)
+		}
+	}
+	return results, nil
+}
+
+func (uc *partyUseCase) GetFacets(ctx context.Context) (map[string]int64, error) {
+	if uc.indexer != nil {
+		counts, err := uc.indexer.TermsAggregation(ctx, "party.keyword", 100)
+		if err == nil {
+			return counts, nil
+		}
+		uc.logger.WarnContext(ctx, "Party aggregation failed, falling back to DB", logging.Err(err))
+	}
+	return uc.partyRepo.CountCandidatesByParty(ctx)
+}
+
+func (uc *partyUseCase) validate(ctx context.Context, p *domain.Party) domain.ValidationErrors {
+	errs := domain.ValidationErrors{}
+
+	p.Name = domain.NormalizePartyName(p.Name)
+	p.NameKey = domain.PartyNameKey(p.Name)
+	if p.Name == "" {
+		errs["name"] = "must not be empty"
+	} else if utf8.RuneCountInString(p.Name) > 255 {
+		errs["name"] = "must be at most 255 characters"
+	}
+	if p.Leader != nil {
+		leader := strings.TrimSpace(*p.Leader)
+		if utf8.RuneCountInString(leader) > 255 {
+			errs["leader"] = "must be at most 255 characters"
+		}
+		p.Leader = &leader
+	}
+	if p.Description != nil && utf8.RuneCountInString(*p.Description) > 5000 {
+		errs["description"] = "must be at most 5000 characters"
+	}
+	if p.LogoAssetID != nil {
+		if _, err := uc.assetRepo.GetByID(ctx, *p.LogoAssetID); err != nil {
+			errs["logo_asset_id"] = "must refer to an uploaded asset"
+		}
+	}
+
+	return errs
+}
+
+// refreshCandidates reindexes the party's candidates and drops cached
+// candidate data so they show the updated party.
+func (uc *partyUseCase) refreshCandidates(ctx context.Context, partyID uint) {
+	candidates, err := uc.partyRepo.GetCandidates(ctx, partyID)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "Failed to load candidates of party", "party_id", partyID, logging.Err(err))
+		return
+	}
+
+	if uc.indexer != nil {
+		go func(ctx context.Context) {
+			for i := range candidates {
+				c := &candidates[i]
+				if err := uc.indexer.Index(ctx, fmt.Sprintf("%d", c.ID), c); err != nil {
+					uc.logger.WarnContext(ctx, "Failed to reindex candidate", "candidate_id", c.ID, logging.Err(err))
+				}
+			}
+		}(context.WithoutCancel(ctx))
+	}
+
+	// "candidates" is the tag the candidate use case puts on every cached
+	// candidate and candidate list
+	uc.cache.Invalidate(ctx, "candidates")
+}
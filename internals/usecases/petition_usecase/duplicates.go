@@ -0,0 +1,91 @@
+package petition_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/infrastructure/moderation"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// maxSimilarPetitions caps how many likely duplicates are suggested to the user.
+const maxSimilarPetitions = 5
+
+// FindSimilarPetitions returns approved petitions that look like the given
+// title and description, so users can sign an existing one instead.
+func (uc *petitionUseCase) FindSimilarPetitions(ctx context.Context, title, description string) ([]domain.SimilarPetition, error) {
+	text := strings.TrimSpace(title + " " + description)
+	if text == "" {
+		return nil, fmt.Errorf("title or description is required")
+	}
+
+	if uc.indexer == nil {
+		return nil, errors.New("search is not configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, screeningTimeout)
+	defer cancel()
+
+	hits, err := uc.indexer.FindSimilar(ctx, text, []string{"title", "description"}, maxSimilarPetitions, moderation.DuplicateMinScore)
+	if err != nil {
+		uc.logger.WarnContext(ctx, "Similar petition search failed", logging.Err(err))
+		return nil, err
+	}
+
+	similar := make([]domain.SimilarPetition, 0, len(hits))
+	for _, hit := range hits {
+		id, err := strconv.ParseUint(hit.ID, 10, 64)
+		if err != nil {
+			continue
+		}
+		title, _ := hit.Source["title"].(string)
+		similar = append(similar, domain.SimilarPetition{ID: uint(id), Title: title, Score: hit.Score})
+	}
+	return similar, nil
+}
+
+// MergePetitions folds the signatures of sourceID into targetID, which must
+// be approved, and deletes the source petition. Users who signed both are
+// only counted once.
+func (uc *petitionUseCase) MergePetitions(ctx context.Context, sourceID, targetID, adminID uint) (*domain.Petition, error) {
+	if sourceID == targetID {
+		return nil, fmt.Errorf("cannot merge a petition into itself")
+	}
+
+	reason := fmt.Sprintf("merged into petition %d", targetID)
+	entry := &domain.PetitionModeration{
+		PetitionID:  sourceID,
+		ModeratorID: &adminID,
+		Action:      domain.ModerationMerged,
+		Reason:      &reason,
+	}
+	moved, err := uc.petitionVoteRepo.MergeInto(ctx, sourceID, targetID, entry)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to merge petitions", "source_id", sourceID, "target_id", targetID, logging.Err(err))
+		return nil, err
+	}
+	uc.logger.InfoContext(ctx, "Petitions merged", "source_id", sourceID, "target_id", targetID, "admin_id", adminID, "signatures_moved", moved)
+
+	uc.cache.Invalidate(ctx, petitionTag(sourceID), petitionTag(targetID), petitionsTag)
+
+	target, err := uc.petitionRepo.GetByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+
+	if uc.indexer != nil {
+		go func(ctx context.Context) {
+			if err := uc.indexer.Delete(ctx, fmt.Sprintf("%d", sourceID)); err != nil {
+				uc.logger.WarnContext(ctx, "Failed to remove merged petition from search", "petition_id", sourceID, logging.Err(err))
+			}
+			if err := uc.indexer.Index(ctx, fmt.Sprintf("%d", target.ID), target); err != nil {
+				uc.logger.WarnContext(ctx, "Failed to reindex petition", "petition_id", target.ID, logging.Err(err))
+			}
+		}(context.WithoutCancel(ctx))
+	}
+
+	return target, nil
+}
@@ -0,0 +1,133 @@
+package petition_usecase
+
+import (
+	"VoteGolang/internals/app/logging"
+	"VoteGolang/internals/domain"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// screeningTimeout bounds how long automated screening may delay petition creation.
+const screeningTimeout = 3 * time.Second
+
+// screenPetition runs the automated pre-screening hooks and records any flags
+// in the moderation audit trail. Screening failures never block creation.
+func (uc *petitionUseCase) screenPetition(ctx context.Context, p *domain.Petition) {
+	ctx, cancel := context.WithTimeout(ctx, screeningTimeout)
+	defer cancel()
+
+	for _, screener := range uc.screeners {
+		flags, err := screener.Screen(ctx, p)
+		if err != nil {
+			uc.logger.WarnContext(ctx, "Petition screening failed", "petition_id", p.ID, logging.Err(err))
+			continue
+		}
+		for _, flag := range flags {
+			reason := flag
+			entry := &domain.PetitionModeration{
+				PetitionID: p.ID,
+				Action:     domain.ModerationFlagged,
+				Reason:     &reason,
+			}
+			if err := uc.moderationRepo.Record(ctx, entry); err != nil {
+				uc.logger.ErrorContext(ctx, "Failed to record screening flag", "petition_id", p.ID, logging.Err(err))
+				continue
+			}
+			uc.logger.InfoContext(ctx, "Petition flagged by screening", "petition_id", p.ID, "flag", flag)
+		}
+	}
+}
+
+func (uc *petitionUseCase) GetModerationQueue(ctx context.Context, limit, offset int) ([]domain.ModerationQueueItem, error) {
+	petitions, err := uc.moderationRepo.GetPending(ctx, limit, offset)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to get moderation queue", logging.Err(err))
+		return nil, err
+	}
+
+	ids := make([]uint, len(petitions))
+	for i, p := range petitions {
+		ids[i] = p.ID
+	}
+	history, err := uc.moderationRepo.GetHistory(ctx, ids...)
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to get moderation flags", logging.Err(err))
+		return nil, err
+	}
+
+	flags := make(map[uint][]string)
+	for _, entry := range history {
+		if entry.Action == domain.ModerationFlagged && entry.Reason != nil {
+			flags[entry.PetitionID] = append(flags[entry.PetitionID], *entry.Reason)
+		}
+	}
+
+	items := make([]domain.ModerationQueueItem, len(petitions))
+	for i, p := range petitions {
+		items[i] = domain.ModerationQueueItem{Petition: p, Flags: flags[p.ID]}
+		if items[i].Flags == nil {
+			items[i].Flags = []string{}
+		}
+	}
+	return items, nil
+}
+
+func (uc *petitionUseCase) ApprovePetition(ctx context.Context, petitionID, moderatorID uint) error {
+	entry := &domain.PetitionModeration{
+		PetitionID:  petitionID,
+		ModeratorID: &moderatorID,
+		Action:      domain.ModerationApproved,
+	}
+	if err := uc.moderationRepo.Decide(ctx, entry, domain.PetitionApproved); err != nil {
+		uc.logger.WarnContext(ctx, "Failed to approve petition", "petition_id", petitionID, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Petition approved", "petition_id", petitionID, "moderator_id", moderatorID)
+
+	uc.cache.Invalidate(ctx, petitionTag(petitionID), petitionsTag)
+
+	if uc.indexer != nil {
+		petition, err := uc.petitionRepo.GetByID(ctx, petitionID)
+		if err != nil {
+			uc.logger.WarnContext(ctx, "Failed to load approved petition for indexing", "petition_id", petitionID, logging.Err(err))
+			return nil
+		}
+		go func(ctx context.Context) {
+			id := fmt.Sprintf("%d", petition.ID)
+			if err := uc.indexer.Index(ctx, id, petition); err != nil {
+				uc.logger.WarnContext(ctx, "Failed to index petition", "petition_id", petition.ID, logging.Err(err))
+			} else {
+				uc.logger.DebugContext(ctx, "Petition indexed for search", "petition_id", petition.ID)
+			}
+		}(context.WithoutCancel(ctx))
+	}
+	return nil
+}
+
+func (uc *petitionUseCase) RejectPetition(ctx context.Context, petitionID, moderatorID uint, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return fmt.Errorf("a reason is required to reject a petition")
+	}
+
+	entry := &domain.PetitionModeration{
+		PetitionID:  petitionID,
+		ModeratorID: &moderatorID,
+		Action:      domain.ModerationRejected,
+		Reason:      &reason,
+	}
+	if err := uc.moderationRepo.Decide(ctx, entry, domain.PetitionRejected); err != nil {
+		uc.logger.WarnContext(ctx, "Failed to reject petition", "petition_id", petitionID, logging.Err(err))
+		return err
+	}
+	uc.logger.InfoContext(ctx, "Petition rejected", "petition_id", petitionID, "moderator_id", moderatorID, "reason", reason)
+
+	uc.cache.Invalidate(ctx, petitionTag(petitionID))
+	return nil
+}
+
+func (uc *petitionUseCase) GetModerationHistory(ctx context.Context, petitionID uint) ([]domain.PetitionModeration, error) {
+	return uc.moderationRepo.GetHistory(ctx, petitionID)
+}
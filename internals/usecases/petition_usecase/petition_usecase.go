@@ -3,183 +3,249 @@ package petition_usecase
 import (
 	"VoteGolang/internals/app/logging"
 	"VoteGolang/internals/domain"
-	"VoteGolang/internals/infrastructure/repositories"
+	"VoteGolang/internals/infrastructure/cache"
+	"VoteGolang/internals/infrastructure/metrics"
 	"VoteGolang/internals/service"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math/rand"
+	"strings"
 	"time"
 
-	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
 )
 
+// petitionsTag is carried by every cached petition list.
+const petitionsTag = "petitions"
+
+// petitionTag is carried by the cached petition with id.
+func petitionTag(id uint) string {
+	return fmt.Sprintf("petition:%d", id)
+}
+
 // PetitionUseCase manages petition creation and retrieval.
 type PetitionUseCase interface {
-	CreatePetition(p *domain.Petition) error
-	GetAllPetitions() ([]domain.Petition, error)
-	GetPetitionByID(id uint) (*domain.Petition, error)
-	Vote(userID uint, petitionID uint, voteType domain.VoteType) error
-	DeletePetition(id uint) error
-	HasUserVoted(userID uint, petitionID uint) (bool, error)
-	GetAllPetitionsPaginated(limit, offset int) ([]domain.Petition, error)
+	CreatePetition(ctx context.Context, p *domain.Petition) error
+	GetAllPetitions(ctx context.Context) ([]domain.Petition, error)
+	GetPetitionByID(ctx context.Context, id uint) (*domain.Petition, error)
+	Vote(ctx context.Context, userID uint, petitionID uint, voteType domain.VoteType) error
+	DeletePetition(ctx context.Context, id uint) error
+	HasUserVoted(ctx context.Context, userID uint, petitionID uint) (bool, error)
+	GetAllPetitionsPaginated(ctx context.Context, filter domain.PetitionFilter, limit, offset int) ([]domain.Petition, error)
+	GetCategoryCounts(ctx context.Context) (map[domain.PetitionCategory]int64, error)
+	GetModerationQueue(ctx context.Context, limit, offset int) ([]domain.ModerationQueueItem, error)
+	ApprovePetition(ctx context.Context, petitionID, moderatorID uint) error
+	RejectPetition(ctx context.Context, petitionID, moderatorID uint, reason string) error
+	GetModerationHistory(ctx context.Context, petitionID uint) ([]domain.PetitionModeration, error)
+	FindSimilarPetitions(ctx context.Context, title, description string) ([]domain.SimilarPetition, error)
+	MergePetitions(ctx context.Context, sourceID, targetID, adminID uint) (*domain.Petition, error)
+	// Reindex indexes every approved petition for search again.
+	Reindex(ctx context.Context) (int, error)
 }
 
+// maxPetitionTags caps how many free-form tags a single petition can carry.
+const maxPetitionTags = 10
+
 type petitionUseCase struct {
 	petitionRepo     domain.PetitionRepository
 	petitionVoteRepo domain.PetitionVoteRepository
 	blockchain       service.BlockchainService
-	redis            *redis.Client
-	logger           *logging.KafkaLogger
-	searchRepo       *repositories.SearchRepository
+	cache            *cache.Aside
+	logger           *slog.Logger
+	indexer          domain.SearchIndexer
+	moderationRepo   domain.PetitionModerationRepository
+	assetRepo        domain.AssetRepository
+	tallies          domain.TallyPublisher
+	screeners        []domain.PetitionScreener
 }
 
-// NewPetitionUseCase updated to include KafkaLogger
+// NewPetitionUseCase updated to include Logger
 func NewPetitionUseCase(
 	pr domain.PetitionRepository,
 	pvr domain.PetitionVoteRepository,
 	bc service.BlockchainService,
-	rdb *redis.Client,
-	kafkaLogger *logging.KafkaLogger,
-	searchRepo *repositories.SearchRepository,
+	store domain.Cache,
+	logger *slog.Logger,
+	indexer domain.SearchIndexer,
+	mr domain.PetitionModerationRepository,
+	ar domain.AssetRepository,
+	tallies domain.TallyPublisher,
+	screeners ...domain.PetitionScreener,
 ) PetitionUseCase {
 	return &petitionUseCase{
 		petitionRepo:     pr,
 		petitionVoteRepo: pvr,
 		blockchain:       bc,
-		redis:            rdb,
-		logger:           kafkaLogger,
-		searchRepo:       searchRepo,
+		cache:            cache.NewAside(store, gorm.ErrRecordNotFound, logger),
+		logger:           logger,
+		indexer:          indexer,
+		moderationRepo:   mr,
+		assetRepo:        ar,
+		tallies:          tallies,
+		screeners:        screeners,
 	}
 }
 
-func (uc *petitionUseCase) GetAllPetitionsPaginated(limit, offset int) ([]domain.Petition, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("petitions:page:%d:limit:%d", offset/limit+1, limit)
+func (uc *petitionUseCase) GetAllPetitionsPaginated(ctx context.Context, filter domain.PetitionFilter, limit, offset int) ([]domain.Petition, error) {
+	cacheKey := fmt.Sprintf("petitions:page:%d:limit:%d:category:%s:tag:%s:sort:%s",
+		offset/limit+1, limit, filter.Category, filter.Tag, filter.Sort)
 
-	cached, err := uc.redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var petitions []domain.Petition
-		if err := json.Unmarshal([]byte(cached), &petitions); err == nil {
-			uc.logger.Log("DEBUG", fmt.Sprintf("Cache hit for: %s", cacheKey))
-			return petitions, nil
-		}
+	// Trending depends on a sliding window, so keep it fresher than the other listings.
+	ttl := 10 * time.Minute
+	if filter.Sort == domain.SortTrending {
+		ttl = time.Minute
 	}
 
-	uc.logger.Log("DEBUG", fmt.Sprintf("Cache miss for: %s", cacheKey))
+	return cache.Fetch(ctx, uc.cache, cacheKey, []string{petitionsTag}, ttl, func(ctx context.Context) ([]domain.Petition, error) {
+		// Pending and rejected petitions are only visible through the moderation queue.
+		filter.Status = domain.PetitionApproved
+		petitions, err := uc.petitionRepo.GetAllPaginated(ctx, filter, limit, offset)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "Failed to get paginated petitions from DB", logging.Err(err))
+		}
+		return petitions, err
+	})
+}
+
+// GetCategoryCounts returns the number of petitions per category, taken from the
+// Elasticsearch aggregation when available and from the database otherwise.
+func (uc *petitionUseCase) GetCategoryCounts(ctx context.Context) (map[domain.PetitionCategory]int64, error) {
+	if uc.indexer != nil {
+		buckets, err := uc.indexer.TermsAggregation(ctx, "category", 50)
+		if err == nil {
+			counts := make(map[domain.PetitionCategory]int64, len(buckets))
+			for category, count := range buckets {
+				counts[domain.PetitionCategory(category)] = count
+			}
+			return counts, nil
+		}
+		uc.logger.WarnContext(ctx, "Category aggregation failed, falling back to DB", logging.Err(err))
+	}
 
-	petitions, err := uc.petitionRepo.GetAllPaginated(limit, offset)
+	counts, err := uc.petitionRepo.CountByCategory(ctx)
 	if err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("Failed to get paginated petitions from DB: %v", err))
+		uc.logger.ErrorContext(ctx, "Failed to count petitions by category", logging.Err(err))
 		return nil, err
 	}
-
-	bytes, _ := json.Marshal(petitions)
-	uc.redis.Set(ctx, cacheKey, bytes, 10*time.Minute)
-	return petitions, nil
+	return counts, nil
 }
 
-func (uc *petitionUseCase) CreatePetition(p *domain.Petition) error {
-	if err := uc.petitionRepo.Create(p); err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("Failed to create petition in DB: %v", err))
+func (uc *petitionUseCase) CreatePetition(ctx context.Context, p *domain.Petition) error {
+	if p.Category == "" {
+		p.Category = domain.CategoryOther
+	}
+	if !domain.IsValidPetitionCategory(string(p.Category)) {
+		return fmt.Errorf("invalid petition category: %s", p.Category)
+	}
+	tags, err := normalizeTags(p.Tags)
+	if err != nil {
 		return err
 	}
-	uc.logger.Log("INFO", fmt.Sprintf("Petition %d created successfully in DB", p.ID))
-
-	if uc.searchRepo != nil {
-		go func() {
-			id := fmt.Sprintf("%d", p.ID)
-			if err := uc.searchRepo.Index(context.Background(), id, p); err != nil {
-				uc.logger.Log("WARN", fmt.Sprintf("Failed to index petition %d: %v", p.ID, err))
-			} else {
-				uc.logger.Log("DEBUG", fmt.Sprintf("Petition %d indexed for search", p.ID))
-			}
-		}()
+	p.Tags = tags
+	if p.PhotoAssetID != nil {
+		asset, err := uc.assetRepo.GetByID(ctx, *p.PhotoAssetID)
+		if err != nil || asset.UserID != p.UserID {
+			return fmt.Errorf("photo asset %s not found", *p.PhotoAssetID)
+		}
+	}
+	p.Status = domain.PetitionPending
+	p.RejectReason = nil
+
+	err = uc.petitionRepo.Create(ctx, p, func() ([]domain.Event, error) {
+		return domain.NewEvents(domain.PetitionCreated{
+			PetitionID:     p.ID,
+			UserID:         p.UserID,
+			Title:          p.Title,
+			Category:       p.Category,
+			Status:         p.Status,
+			Goal:           p.Goal,
+			VotingDeadline: p.VotingDeadline,
+		})
+	})
+	if err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to create petition in DB", logging.Err(err))
+		return err
 	}
+	uc.logger.InfoContext(ctx, "Petition created and queued for moderation", "petition_id", p.ID)
+	// The ID may have been looked up, and cached as missing, before it existed
+	uc.cache.Invalidate(ctx, petitionTag(p.ID))
 
-	// Invalidate cache
-	uc.invalidateAllPetitionCaches()
+	// The petition is indexed for search once a moderator approves it.
+	uc.screenPetition(ctx, p)
 
 	// Log to blockchain
-	if _, err := uc.blockchain.LogPetitionCreation(p); err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("CRITICAL: Petition %d created in DB but failed to log to blockchain: %v", p.ID, err))
+	if _, err := uc.blockchain.LogPetitionCreation(context.WithoutCancel(ctx), p); errors.Is(err, service.ErrQueued) {
+		uc.logger.WarnContext(ctx, "Blockchain unavailable, petition log queued", "petition_id", p.ID)
+	} else if err != nil {
+		uc.logger.ErrorContext(ctx, "CRITICAL: Petition created in DB but failed to log to blockchain", "petition_id", p.ID, logging.Err(err))
 		// Do not return error, as the petition *was* created.
 	} else {
-		uc.logger.Log("INFO", fmt.Sprintf("Petition %d logged to blockchain", p.ID))
+		uc.logger.InfoContext(ctx, "Petition logged to blockchain", "petition_id", p.ID)
 	}
 
 	return nil
 }
 
-func (uc *petitionUseCase) GetAllPetitions() ([]domain.Petition, error) {
-	ctx := context.Background()
-	cacheKey := "petitions"
-
-	cached, err := uc.redis.Get(ctx, cacheKey).Result()
-	if err == nil {
-		var petitions []domain.Petition
-		if err := json.Unmarshal([]byte(cached), &petitions); err == nil {
-			uc.logger.Log("DEBUG", fmt.Sprintf("Cache hit for: %s", cacheKey))
-			return petitions, nil
+// GetAllPetitions returns the approved petitions.
+func (uc *petitionUseCase) GetAllPetitions(ctx context.Context) ([]domain.Petition, error) {
+	ttl := time.Duration(rand.Intn(5)+25) * time.Minute
+	return cache.Fetch(ctx, uc.cache, "petitions", []string{petitionsTag}, ttl, func(ctx context.Context) ([]domain.Petition, error) {
+		petitions, err := uc.petitionRepo.GetAll(ctx)
+		if err != nil {
+			uc.logger.ErrorContext(ctx, "Failed to get all petitions from DB", logging.Err(err))
+			return nil, err
 		}
-	}
-
-	uc.logger.Log("DEBUG", fmt.Sprintf("Cache miss for: %s", cacheKey))
-	petitions, err := uc.petitionRepo.GetAll()
-	if err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("Failed to get all petitions from DB: %v", err))
-		return nil, err
-	}
-
-	data, _ := json.Marshal(petitions)
-	uc.redis.Set(ctx, cacheKey, data, time.Duration(rand.Intn(5)+25)*time.Minute)
-	return petitions, nil
+		approved := petitions[:0]
+		for _, p := range petitions {
+			if p.Status == domain.PetitionApproved {
+				approved = append(approved, p)
+			}
+		}
+		return approved, nil
+	})
 }
 
-func (uc *petitionUseCase) GetPetitionByID(id uint) (*domain.Petition, error) {
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("petition:%d", id)
-
-	if cached, err := uc.redis.Get(ctx, cacheKey).Result(); err == nil {
-		var petition domain.Petition
-		if json.Unmarshal([]byte(cached), &petition) == nil {
-			uc.logger.Log("DEBUG", fmt.Sprintf("Cache hit for: %s", cacheKey))
-			return &petition, nil
+// GetPetitionByID returns an approved petition. Pending and rejected ones are
+// reported as not found: moderators see them in the moderation queue.
+func (uc *petitionUseCase) GetPetitionByID(ctx context.Context, id uint) (*domain.Petition, error) {
+	return cache.Fetch(ctx, uc.cache, petitionTag(id), []string{petitionTag(id)}, 5*time.Minute, func(ctx context.Context) (*domain.Petition, error) {
+		petition, err := uc.petitionRepo.GetByID(ctx, id)
+		if err != nil {
+			uc.logger.WarnContext(ctx, "Failed to get petition from DB", "petition_id", id, logging.Err(err))
+			return nil, err
 		}
-	}
-
-	uc.logger.Log("DEBUG", fmt.Sprintf("Cache miss for: %s", cacheKey))
-	petition, err := uc.petitionRepo.GetByID(id)
-	if err != nil {
-		uc.logger.Log("WARN", fmt.Sprintf("Failed to get petition %d from DB: %v", id, err))
-		return nil, err
-	}
-
-	data, _ := json.Marshal(petition)
-	uc.redis.Set(ctx, cacheKey, data, 5*time.Minute)
-	return petition, nil
+		if petition.Status != domain.PetitionApproved {
+			return nil, gorm.ErrRecordNotFound
+		}
+		return petition, nil
+	})
 }
 
-func (uc *petitionUseCase) Vote(userID uint, petitionID uint, voteType domain.VoteType) error {
+func (uc *petitionUseCase) Vote(ctx context.Context, userID uint, petitionID uint, voteType domain.VoteType) error {
 	// 1. Pre-flight checks
-	voted, err := uc.petitionVoteRepo.HasUserVoted(userID, petitionID)
+	voted, err := uc.petitionVoteRepo.HasUserVoted(ctx, userID, petitionID)
 	if err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("Failed to check HasUserVoted (user: %d, petition: %d): %v", userID, petitionID, err))
+		uc.logger.ErrorContext(ctx, "Failed to check HasUserVoted", "user_id", userID, "petition_id", petitionID, logging.Err(err))
 		return err
 	}
 	if voted {
 		return fmt.Errorf("user has already voted")
 	}
 
-	petition, err := uc.petitionRepo.GetByID(petitionID)
+	petition, err := uc.petitionRepo.GetByID(ctx, petitionID)
 	if err != nil {
-		uc.logger.Log("WARN", fmt.Sprintf("Vote attempt on non-existent petition %d: %v", petitionID, err))
+		uc.logger.WarnContext(ctx, "Vote attempt on non-existent petition", "petition_id", petitionID, logging.Err(err))
 		return err
 	}
 
 	if !domain.IsValidVoteType(string(voteType)) {
 		return fmt.Errorf("invalid petition type: must be 'favor' or 'against'")
 	}
+	if petition.Status != domain.PetitionApproved {
+		return fmt.Errorf("petition is not open for voting")
+	}
 	if time.Now().After(petition.VotingDeadline) {
 		return fmt.Errorf("voting period has ended")
 	}
@@ -188,83 +254,134 @@ func (uc *petitionUseCase) Vote(userID uint, petitionID uint, voteType domain.Vo
 		return fmt.Errorf("petition goal has been reached")
 	}
 
-	// This callback contains *only* the DB logic.
-	dbTransactionCallback := func() error {
+	// This callback contains *only* the DB logic, run in the vote transaction.
+	// The petition is read again after the increment, which locks its row, so
+	// the events see the counts of concurrent votes too.
+	var counted *domain.Petition
+	dbTransactionCallback := func(ctx context.Context) error {
 		var dbErr error
 		switch voteType {
 		case domain.Favor:
-			dbErr = uc.petitionRepo.VoteInFavor(petitionID)
+			dbErr = uc.petitionRepo.VoteInFavor(ctx, petitionID)
 		case domain.Against:
-			dbErr = uc.petitionRepo.VoteAgainst(petitionID)
+			dbErr = uc.petitionRepo.VoteAgainst(ctx, petitionID)
 		default:
 			return fmt.Errorf("invalid vote type") // Should be caught by pre-flight, but good to double check
 		}
+		if dbErr != nil {
+			return dbErr
+		}
+		counted, dbErr = uc.petitionRepo.GetByID(ctx, petitionID)
 		return dbErr
 	}
 
 	// VoteWithTransaction will Begin, execute the callback, save the vote record, and Commit/Rollback
-	err = uc.petitionVoteRepo.VoteWithTransaction(userID, petitionID, voteType, dbTransactionCallback)
+	err = uc.petitionVoteRepo.VoteWithTransaction(ctx, userID, petitionID, voteType, dbTransactionCallback, func() ([]domain.Event, error) {
+		return voteEvents(counted, userID, voteType)
+	})
 	if err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("DB transaction for vote failed (user: %d, petition: %d): %v", userID, petitionID, err))
+		uc.logger.ErrorContext(ctx, "DB transaction for vote failed", "user_id", userID, "petition_id", petitionID, logging.Err(err))
 		return fmt.Errorf("database transaction failed: %w", err)
 	}
+	metrics.PetitionVotes.WithLabelValues(string(voteType)).Inc()
 
-	if _, err := uc.blockchain.LogPetitionVote(userID, petitionID, voteType); err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("CRITICAL: Petition vote (user %d, petition %d) saved to DB but failed to log to blockchain: %v", userID, petitionID, err))
+	if _, err := uc.blockchain.LogPetitionVote(context.WithoutCancel(ctx), userID, petitionID, voteType); errors.Is(err, service.ErrQueued) {
+		uc.logger.WarnContext(ctx, "Blockchain unavailable, petition vote log queued", "user_id", userID, "petition_id", petitionID)
+	} else if err != nil {
+		uc.logger.ErrorContext(ctx, "CRITICAL: Petition vote saved to DB but failed to log to blockchain", "user_id", userID, "petition_id", petitionID, logging.Err(err))
 		// Do not return error, the vote was successful in the DB.
 	} else {
-		uc.logger.Log("INFO", fmt.Sprintf("Petition vote (user %d, petition %d) logged to blockchain", userID, petitionID))
+		uc.logger.InfoContext(ctx, "Petition vote logged to blockchain", "user_id", userID, "petition_id", petitionID)
 	}
 
-	// We can invalidate both the specific petition and the paginated lists.
-	ctx := context.Background()
-	cacheKey := fmt.Sprintf("petition:%d", petitionID)
-	uc.redis.Del(ctx, cacheKey)
-	uc.invalidateAllPetitionCaches()
+	// Both the petition and the lists show the counts
+	uc.cache.Invalidate(ctx, petitionTag(petitionID), petitionsTag)
+
+	if uc.tallies != nil {
+		if err := uc.tallies.PublishTally(ctx, domain.TallyEvent{PetitionID: petitionID}); err != nil {
+			uc.logger.WarnContext(ctx, "Failed to publish tally event", "petition_id", petitionID, logging.Err(err))
+		}
+	}
 
-	uc.logger.Log("INFO", fmt.Sprintf("Vote cast by user %d on petition %d", userID, petitionID))
+	uc.logger.InfoContext(ctx, "Petition vote cast", "user_id", userID, "petition_id", petitionID)
 	return nil
 }
 
-func (uc *petitionUseCase) DeletePetition(id uint) error {
-	if err := uc.petitionRepo.Delete(id); err != nil {
-		uc.logger.Log("ERROR", fmt.Sprintf("Failed to delete petition %d: %v", id, err))
+// voteEvents builds the events of a vote on petition, as it was read in the
+// vote transaction after counting the vote. The vote that brings the
+// petition to its goal also records PetitionGoalReached; its ID is fixed per
+// petition, so a vote past the goal cannot record it twice.
+func voteEvents(petition *domain.Petition, userID uint, voteType domain.VoteType) ([]domain.Event, error) {
+	cast, err := domain.NewEvent(domain.PetitionVoteCast{PetitionID: petition.ID, UserID: userID, VoteType: voteType})
+	if err != nil {
+		return nil, err
+	}
+	events := []domain.Event{cast}
+
+	favor, against := petition.VotesInFavor, petition.VotesAgainst
+	if favor+against >= petition.Goal {
+		reached, err := domain.NewUniqueEvent(domain.PetitionGoalReached{
+			PetitionID:   petition.ID,
+			Goal:         petition.Goal,
+			VotesInFavor: favor,
+			VotesAgainst: against,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, reached)
+	}
+	return events, nil
+}
+
+func (uc *petitionUseCase) DeletePetition(ctx context.Context, id uint) error {
+	if err := uc.petitionRepo.Delete(ctx, id); err != nil {
+		uc.logger.ErrorContext(ctx, "Failed to delete petition", "petition_id", id, logging.Err(err))
 		return err
 	}
 
-	// Invalidate all petition caches
-	uc.invalidateAllPetitionCaches()
-	uc.logger.Log("INFO", fmt.Sprintf("Petition %d deleted and cache invalidated", id))
+	uc.cache.Invalidate(ctx, petitionTag(id), petitionsTag)
+	uc.logger.InfoContext(ctx, "Petition deleted and cache invalidated", "petition_id", id)
 	return nil
 }
 
-func (uc *petitionUseCase) HasUserVoted(userID uint, petitionID uint) (bool, error) {
-	return uc.petitionVoteRepo.HasUserVoted(userID, petitionID)
+func (uc *petitionUseCase) HasUserVoted(ctx context.Context, userID uint, petitionID uint) (bool, error) {
+	return uc.petitionVoteRepo.HasUserVoted(ctx, userID, petitionID)
 }
 
-// invalidateAllPetitionCaches is a helper to clear list/paginated caches
-func (uc *petitionUseCase) invalidateAllPetitionCaches() {
-	ctx := context.Background()
-	pattern := "petitions*"
-	var cursor uint64
-	var keysFound int
-
-	for {
-		keys, nextCursor, err := uc.redis.Scan(ctx, cursor, pattern, 100).Result()
-		if err != nil {
-			uc.logger.Log("WARN", fmt.Sprintf("Failed to scan Redis keys for pattern %s: %v", pattern, err))
-			return
+func (uc *petitionUseCase) Reindex(ctx context.Context) (int, error) {
+	if uc.indexer == nil {
+		return 0, errors.New("search is not configured")
+	}
+	petitions, err := uc.petitionRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i := range petitions {
+		if err := uc.indexer.Index(ctx, fmt.Sprintf("%d", petitions[i].ID), &petitions[i]); err != nil {
+			return i, fmt.Errorf("index petition %d: %w", petitions[i].ID, err)
 		}
+	}
+	return len(petitions), nil
+}
 
-		if len(keys) > 0 {
-			uc.redis.Del(ctx, keys...)
-			keysFound += len(keys)
+// normalizeTags lowercases and trims tag names, dropping blanks and duplicates.
+func normalizeTags(tags []domain.Tag) ([]domain.Tag, error) {
+	seen := make(map[string]bool, len(tags))
+	result := make([]domain.Tag, 0, len(tags))
+	for _, t := range tags {
+		name := strings.ToLower(strings.TrimSpace(t.Name))
+		if name == "" || seen[name] {
+			continue
 		}
-
-		if nextCursor == 0 {
-			break
+		if len(name) > 50 {
+			return nil, fmt.Errorf("tag %q is longer than 50 characters", name)
 		}
-		cursor = nextCursor
+		seen[name] = true
+		result = append(result, domain.Tag{Name: name})
+	}
+	if len(result) > maxPetitionTags {
+		return nil, fmt.Errorf("a petition can have at most %d tags", maxPetitionTags)
 	}
-	uc.logger.Log("DEBUG", fmt.Sprintf("Cache invalidated for pattern '%s' (keys deleted: %d)", pattern, keysFound))
+	return result, nil
 }
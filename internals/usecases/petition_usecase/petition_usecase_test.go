@@ -0,0 +1,284 @@
+package petition_usecase
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/fakes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// openPetition is an approved petition open for a day.
+func openPetition(id uint, goal int) domain.Petition {
+	return domain.Petition{
+		ID:             id,
+		UserID:         1,
+		Title:          "Bike lanes",
+		Goal:           goal,
+		Category:       domain.CategoryTransport,
+		Status:         domain.PetitionApproved,
+		VotingDeadline: time.Now().Add(24 * time.Hour),
+	}
+}
+
+func TestVote(t *testing.T) {
+	pending := openPetition(2, 100)
+	pending.Status = domain.PetitionPending
+	ended := openPetition(3, 100)
+	ended.VotingDeadline = time.Now().Add(-time.Minute)
+	full := openPetition(4, 2)
+	full.VotesInFavor, full.VotesAgainst = 1, 1
+	lastVote := openPetition(5, 1)
+
+	tests := []struct {
+		name        string
+		petitionID  uint
+		voteType    domain.VoteType
+		votedBefore bool
+		wantErr     string
+		wantFavor   int
+		wantAgainst int
+		wantEvents  []domain.EventType
+	}{
+		{name: "in favor", petitionID: 1, voteType: domain.Favor, wantFavor: 1,
+			wantEvents: []domain.EventType{domain.EventPetitionVoteCast}},
+		{name: "against", petitionID: 1, voteType: domain.Against, wantAgainst: 1,
+			wantEvents: []domain.EventType{domain.EventPetitionVoteCast}},
+		{name: "invalid vote type", petitionID: 1, voteType: "maybe", wantErr: "invalid petition type"},
+		{name: "double vote", petitionID: 1, voteType: domain.Favor, votedBefore: true, wantErr: "already voted", wantFavor: 1},
+		{name: "not approved", petitionID: 2, voteType: domain.Favor, wantErr: "not open for voting"},
+		{name: "after the deadline", petitionID: 3, voteType: domain.Favor, wantErr: "voting period has ended"},
+		{name: "goal reached", petitionID: 4, voteType: domain.Favor, wantErr: "goal has been reached", wantFavor: 1, wantAgainst: 1},
+		{name: "vote that reaches the goal", petitionID: 5, voteType: domain.Favor, wantFavor: 1,
+			wantEvents: []domain.EventType{domain.EventPetitionVoteCast, domain.EventPetitionGoalReached}},
+		{name: "unknown petition", petitionID: 9, voteType: domain.Favor, wantErr: "record not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := fakes.NewDeps()
+			d.AddPetitions(openPetition(1, 100), pending, ended, full, lastVote)
+			uc := NewPetitionUseCase(d.Petitions, d.PetitionVotes, d.Blockchain, d.Cache, d.Logger, nil, nil, nil, nil)
+			ctx := context.Background()
+			const userID = 42
+			if tt.votedBefore {
+				d.PetitionVotes.CreateVote(ctx, &domain.PetitionVote{UserID: userID, PetitionID: tt.petitionID, VoteType: domain.Favor})
+			}
+
+			err := uc.Vote(ctx, userID, tt.petitionID, tt.voteType)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if p, err := d.Petitions.GetByID(ctx, tt.petitionID); err == nil {
+				if p.VotesInFavor != tt.wantFavor || p.VotesAgainst != tt.wantAgainst {
+					t.Fatalf("votes = %d in favor, %d against; want %d and %d",
+						p.VotesInFavor, p.VotesAgainst, tt.wantFavor, tt.wantAgainst)
+				}
+			}
+			events := d.PetitionVotes.Events()
+			if len(events) != len(tt.wantEvents) {
+				t.Fatalf("recorded %d events, want %v", len(events), tt.wantEvents)
+			}
+			for i, e := range events {
+				if e.Type != tt.wantEvents[i] {
+					t.Fatalf("event %d = %s, want %s", i, e.Type, tt.wantEvents[i])
+				}
+			}
+		})
+	}
+}
+
+// TestVoteSameUserConcurrently checks the use case under concurrent votes;
+// see the candidate use case test of the same name.
+func TestVoteSameUserConcurrently(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddPetitions(openPetition(1, 1000))
+	uc := NewPetitionUseCase(d.Petitions, d.PetitionVotes, d.Blockchain, d.Cache, d.Logger, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	const attempts = 50
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	succeeded := 0
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(voteType domain.VoteType) {
+			defer wg.Done()
+			if err := uc.Vote(ctx, 7, 1, voteType); err == nil {
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}
+		}([]domain.VoteType{domain.Favor, domain.Against}[i%2])
+	}
+	wg.Wait()
+
+	if succeeded != 1 {
+		t.Fatalf("%d votes succeeded, want 1", succeeded)
+	}
+	p, _ := d.Petitions.GetByID(ctx, 1)
+	if p.VotesInFavor+p.VotesAgainst != 1 {
+		t.Fatalf("petition counted %d votes, want 1", p.VotesInFavor+p.VotesAgainst)
+	}
+}
+
+func TestVoteManyUsersConcurrently(t *testing.T) {
+	d := fakes.NewDeps()
+	d.AddPetitions(openPetition(1, 1000))
+	uc := NewPetitionUseCase(d.Petitions, d.PetitionVotes, d.Blockchain, d.Cache, d.Logger, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	const users = 100
+	var wg sync.WaitGroup
+	for userID := uint(1); userID <= users; userID++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			if err := uc.Vote(ctx, userID, 1, domain.Favor); err != nil {
+				t.Errorf("user %d: %v", userID, err)
+			}
+		}(userID)
+	}
+	wg.Wait()
+
+	p, _ := d.Petitions.GetByID(ctx, 1)
+	if p.VotesInFavor != users {
+		t.Fatalf("votes in favor = %d, want %d", p.VotesInFavor, users)
+	}
+}
+
+// staleReads holds the first reads of a petition until all of them are in,
+// so concurrent votes see the same counts before their transactions.
+type staleReads struct {
+	domain.PetitionRepository
+	mu      sync.Mutex
+	waiting int
+	arrived sync.WaitGroup
+}
+
+func (r *staleReads) GetByID(ctx context.Context, id uint) (*domain.Petition, error) {
+	p, err := r.PetitionRepository.GetByID(ctx, id)
+	r.mu.Lock()
+	wait := r.waiting > 0
+	if wait {
+		r.waiting--
+		r.arrived.Done()
+	}
+	r.mu.Unlock()
+	if wait {
+		r.arrived.Wait()
+	}
+	return p, err
+}
+
+func TestConcurrentVotesRecordTheGoalReached(t *testing.T) {
+	d := fakes.NewDeps()
+	almost := openPetition(1, 3)
+	almost.VotesInFavor = 1
+	d.AddPetitions(almost)
+	const voters = 2
+	petitions := &staleReads{PetitionRepository: d.Petitions, waiting: voters}
+	petitions.arrived.Add(voters)
+	uc := NewPetitionUseCase(petitions, d.PetitionVotes, d.Blockchain, d.Cache, d.Logger, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	// Both votes read the petition two votes short of its goal, which neither
+	// reaches alone
+	var wg sync.WaitGroup
+	for userID := uint(1); userID <= voters; userID++ {
+		wg.Add(1)
+		go func(userID uint) {
+			defer wg.Done()
+			if err := uc.Vote(ctx, userID, 1, domain.Favor); err != nil {
+				t.Errorf("user %d: %v", userID, err)
+			}
+		}(userID)
+	}
+	wg.Wait()
+
+	// A vote past the goal records the same event again, which the outbox
+	// keeps once
+	reached := make(map[string]bool)
+	for _, e := range d.PetitionVotes.Events() {
+		if e.Type == domain.EventPetitionGoalReached {
+			reached[e.ID] = true
+		}
+	}
+	if len(reached) != 1 {
+		t.Fatalf("recorded %d distinct goal events, want 1", len(reached))
+	}
+}
+
+func TestReadsHideUnapprovedPetitions(t *testing.T) {
+	pending := openPetition(2, 100)
+	pending.Status = domain.PetitionPending
+	rejected := openPetition(3, 100)
+	rejected.Status = domain.PetitionRejected
+	d := fakes.NewDeps()
+	d.AddPetitions(openPetition(1, 100), pending, rejected)
+	uc := NewPetitionUseCase(d.Petitions, d.PetitionVotes, d.Blockchain, d.Cache, d.Logger, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	if _, err := uc.GetPetitionByID(ctx, 1); err != nil {
+		t.Fatalf("approved petition: %v", err)
+	}
+	for _, id := range []uint{2, 3} {
+		if _, err := uc.GetPetitionByID(ctx, id); !errors.Is(err, fakes.ErrNotFound) {
+			t.Fatalf("petition %d: err = %v, want not found", id, err)
+		}
+	}
+	if all, err := uc.GetAllPetitions(ctx); err != nil || len(all) != 1 || all[0].ID != 1 {
+		t.Fatalf("GetAllPetitions = %+v, %v, want only petition 1", all, err)
+	}
+}
+
+func TestMergePetitions(t *testing.T) {
+	pending := openPetition(3, 100)
+	pending.Status = domain.PetitionPending
+	d := fakes.NewDeps()
+	d.AddPetitions(openPetition(1, 100), openPetition(2, 100), pending)
+	uc := NewPetitionUseCase(d.Petitions, d.PetitionVotes, d.Blockchain, d.Cache, d.Logger, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	// User 1 signed both petitions, users 2 and 3 only the source
+	for _, v := range []struct{ userID, petitionID uint }{{1, 1}, {2, 1}, {3, 1}, {1, 2}} {
+		if err := uc.Vote(ctx, v.userID, v.petitionID, domain.Favor); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p, _ := uc.GetPetitionByID(ctx, 2); p.VotesInFavor != 1 {
+		t.Fatalf("target has %d votes before the merge, want 1", p.VotesInFavor)
+	}
+
+	if _, err := uc.MergePetitions(ctx, 1, 1, 9); err == nil {
+		t.Fatal("merged a petition into itself")
+	}
+	if _, err := uc.MergePetitions(ctx, 1, 3, 9); err == nil || !strings.Contains(err.Error(), "approved") {
+		t.Fatalf("merge into a pending petition: err = %v", err)
+	}
+
+	target, err := uc.MergePetitions(ctx, 1, 2, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.VotesInFavor != 3 {
+		t.Fatalf("merged target has %d votes, want 3", target.VotesInFavor)
+	}
+	if p, _ := uc.GetPetitionByID(ctx, 2); p.VotesInFavor != 3 {
+		t.Fatalf("cached target has %d votes after the merge, want 3", p.VotesInFavor)
+	}
+	if _, err := uc.GetPetitionByID(ctx, 1); !errors.Is(err, fakes.ErrNotFound) {
+		t.Fatalf("source petition after the merge: err = %v, want not found", err)
+	}
+	audit := d.PetitionVotes.Moderation()
+	if len(audit) != 1 || audit[0].PetitionID != 1 || audit[0].Action != domain.ModerationMerged || *audit[0].ModeratorID != 9 {
+		t.Fatalf("audit = %+v, want one merge of petition 1 by 9", audit)
+	}
+}
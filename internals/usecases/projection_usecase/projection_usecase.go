@@ -0,0 +1,103 @@
+package projection_usecase
+
+import (
+	"VoteGolang/internals/domain"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// ProjectionUseCase applies vote events to the derived projections: hourly
+// tallies per region and turnout in MySQL, leaderboards in Redis.
+type ProjectionUseCase interface {
+	// Handle applies one event. Events of other types are ignored; events it
+	// can never apply return an error wrapping domain.ErrUnprocessableEvent.
+	Handle(ctx context.Context, event domain.Event) error
+	// Reset clears every projection before a rebuild.
+	Reset(ctx context.Context) error
+}
+
+type projectionUseCase struct {
+	consumer     string
+	projections  domain.ProjectionRepository
+	leaderboards domain.LeaderboardStore
+	logger       *slog.Logger
+}
+
+// NewProjectionUseCase creates the use case; consumer names the consumer
+// group whose processed events are tracked.
+func NewProjectionUseCase(consumer string, pr domain.ProjectionRepository, ls domain.LeaderboardStore, logger *slog.Logger) ProjectionUseCase {
+	return &projectionUseCase{
+		consumer:     consumer,
+		projections:  pr,
+		leaderboards: ls,
+		logger:       logger,
+	}
+}
+
+func (uc *projectionUseCase) Handle(ctx context.Context, event domain.Event) error {
+	switch event.Type {
+	case domain.EventVoteCast:
+		var vote domain.VoteCast
+		if err := decode(event, vote.EventVersion(), &vote); err != nil {
+			return err
+		}
+		return uc.applyVote(ctx, event, vote)
+	case domain.EventPetitionVoteCast:
+		var vote domain.PetitionVoteCast
+		if err := decode(event, vote.EventVersion(), &vote); err != nil {
+			return err
+		}
+		if vote.VoteType != domain.Favor {
+			return nil
+		}
+		if _, err := uc.leaderboards.AddPetitionSignature(ctx, event.ID, vote.PetitionID); err != nil {
+			return err
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// applyVote updates MySQL and Redis separately; each skips votes it has
+// already applied, so a retry after a partial failure finishes the other one.
+// Votes are deduplicated by vote ID, so a vote recorded under another event
+// ID is not counted again.
+func (uc *projectionUseCase) applyVote(ctx context.Context, event domain.Event, vote domain.VoteCast) error {
+	key := vote.DedupKey(event.ID)
+	applied, err := uc.projections.ApplyVote(ctx, uc.consumer, key, vote, event.OccurredAt)
+	if err != nil {
+		return fmt.Errorf("apply vote %s to tallies: %w", event.ID, err)
+	}
+	if _, err := uc.leaderboards.AddCandidateVote(ctx, key, vote); err != nil {
+		return fmt.Errorf("apply vote %s to leaderboard: %w", event.ID, err)
+	}
+	if !applied {
+		uc.logger.DebugContext(ctx, "Vote already applied, skipped", "event_id", event.ID, "vote_id", vote.VoteID)
+	}
+	return nil
+}
+
+func (uc *projectionUseCase) Reset(ctx context.Context) error {
+	if err := uc.projections.Reset(ctx, uc.consumer); err != nil {
+		return fmt.Errorf("reset tallies: %w", err)
+	}
+	if err := uc.leaderboards.Reset(ctx); err != nil {
+		return fmt.Errorf("reset leaderboards: %w", err)
+	}
+	uc.logger.InfoContext(ctx, "Projections reset for rebuild")
+	return nil
+}
+
+// decode reads the payload of an event this consumer understands at version.
+func decode(event domain.Event, version int, payload interface{}) error {
+	if event.Version != version {
+		return fmt.Errorf("%w: %s version %d is not supported", domain.ErrUnprocessableEvent, event.Type, event.Version)
+	}
+	if err := json.Unmarshal(event.Data, payload); err != nil {
+		return fmt.Errorf("%w: %s %s: %v", domain.ErrUnprocessableEvent, event.Type, event.ID, err)
+	}
+	return nil
+}
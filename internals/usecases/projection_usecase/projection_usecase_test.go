@@ -0,0 +1,73 @@
+package projection_usecase
+
+import (
+	"VoteGolang/internals/domain"
+	"VoteGolang/internals/fakes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func voteEvent(t *testing.T, vote domain.VoteCast) domain.Event {
+	t.Helper()
+	event, err := domain.NewEvent(vote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return event
+}
+
+func TestHandleCountsEachVoteOnce(t *testing.T) {
+	d := fakes.NewDeps()
+	projections := fakes.NewProjectionRepository()
+	leaderboards := fakes.NewLeaderboardStore()
+	uc := NewProjectionUseCase("test", projections, leaderboards, d.Logger)
+	ctx := context.Background()
+
+	vote := domain.VoteCast{VoteID: 1, CandidateID: 7, CandidateType: domain.Presidential, UserID: 1}
+	first := voteEvent(t, vote)
+	// The same vote recorded again under another event ID
+	again := voteEvent(t, vote)
+	other := voteEvent(t, domain.VoteCast{VoteID: 2, CandidateID: 7, CandidateType: domain.Presidential, UserID: 2})
+
+	for _, event := range []domain.Event{first, first, again, other} {
+		if err := uc.Handle(ctx, event); err != nil {
+			t.Fatalf("handle %s: %v", event.ID, err)
+		}
+	}
+	if got := projections.Votes(7); got != 2 {
+		t.Fatalf("tallied votes = %d, want 2", got)
+	}
+	if got := leaderboards.CandidateVotes(7); got != 2 {
+		t.Fatalf("leaderboard votes = %d, want 2", got)
+	}
+}
+
+func TestHandleFallsBackToEventIDWithoutVoteID(t *testing.T) {
+	d := fakes.NewDeps()
+	projections := fakes.NewProjectionRepository()
+	uc := NewProjectionUseCase("test", projections, fakes.NewLeaderboardStore(), d.Logger)
+	ctx := context.Background()
+
+	vote := domain.VoteCast{CandidateID: 7, CandidateType: domain.Presidential, UserID: 1}
+	first, second := voteEvent(t, vote), voteEvent(t, vote)
+	for _, event := range []domain.Event{first, first, second} {
+		if err := uc.Handle(ctx, event); err != nil {
+			t.Fatalf("handle %s: %v", event.ID, err)
+		}
+	}
+	if got := projections.Votes(7); got != 2 {
+		t.Fatalf("tallied votes = %d, want 2: one per event ID", got)
+	}
+}
+
+func TestHandleRejectsUnknownVersions(t *testing.T) {
+	d := fakes.NewDeps()
+	uc := NewProjectionUseCase("test", fakes.NewProjectionRepository(), fakes.NewLeaderboardStore(), d.Logger)
+
+	event := voteEvent(t, domain.VoteCast{VoteID: 1, CandidateID: 7, CandidateType: domain.Presidential, UserID: 1})
+	event.Version = 99
+	if err := uc.Handle(context.Background(), event); !errors.Is(err, domain.ErrUnprocessableEvent) {
+		t.Fatalf("err = %v, want %v", err, domain.ErrUnprocessableEvent)
+	}
+}